@@ -19,6 +19,7 @@ import (
 	"net"
 	"net/http"
 	"os/signal"
+	"strings"
 	"syscall"
 
 	_ "net/http/pprof"
@@ -63,6 +64,12 @@ func main() {
 	if err != nil {
 		log.Fatal(err)
 	}
+	if err = appCfg.EnsureSecretKey(); err != nil {
+		log.Fatal(err)
+	}
+	if err = appCfg.EnsureClickSalt(); err != nil {
+		log.Fatal(err)
+	}
 
 	var storeSvc service.Store
 	var pinger dbhandlers.Pinger
@@ -79,15 +86,67 @@ func main() {
 		storeSvc = store.NewFileStore(appCfg.FileStoragePath)
 	}
 
-	urlSvc := service.NewURLService(storeSvc)
-	urlGet := service.NewGetURLService(storeSvc)
-	urlDel := service.NewURLDeleter(storeSvc)
+	codeGen, err := service.NewShortCodeGenerator(appCfg.ShortCodeStrategy, appCfg.ShortCodeLength, appCfg.ShortCodeWords, storeSvc)
+	if err != nil {
+		sugar.Fatalf("Invalid short code generator config: %v", err)
+	}
+
+	policy, err := service.NewDestinationPolicy(service.DestinationPolicyOptions{
+		AllowedSchemes:  strings.Split(appCfg.AllowedSchemes, ","),
+		DenylistPath:    appCfg.DenylistPath,
+		MaxLength:       appCfg.MaxURLLength,
+		AllowPrivateIPs: appCfg.AllowPrivateIPs,
+		BaseAddress:     appCfg.BaseAddress,
+		ShortDomains:    appCfg.DomainHosts(),
+	})
+	if err != nil {
+		sugar.Fatalf("Invalid destination policy config: %v", err)
+	}
+
+	normalizer := service.NewURLNormalizer(appCfg.StripTrackingParams)
+	if n, err := service.NormalizeLegacyURLs(context.Background(), storeSvc, normalizer); err != nil {
+		sugar.Fatalf("Failed to normalize legacy URLs: %v", err)
+	} else if n > 0 {
+		sugar.Infow("Normalized legacy URLs", "count", n)
+	}
+	urlSvc := service.NewURLService(storeSvc, codeGen, normalizer, policy, storeSvc)
+	countries, err := service.NewCountryDB(appCfg.CountryDBPath)
+	if err != nil {
+		sugar.Fatalf("Failed to load country database: %v", err)
+	}
+	urlGet := service.NewGetURLService(storeSvc, normalizer, countries)
+	urlDel := service.NewURLDeleter(storeSvc, appCfg.DeleteGracePeriod)
+	clickCh := make(chan service.ClickEvent, appCfg.ClickBufferSize)
+	clickSvc := service.NewClickService(storeSvc, clickCh, appCfg.ClickSalt)
+	editSvc := service.NewEditService(storeSvc, normalizer, policy)
+	tagSvc := service.NewTagService(storeSvc)
+	qrSvc := service.NewQRCodeService(storeSvc, appCfg)
+	expandSvc := service.NewExpandService(storeSvc)
+	ruleSvc := service.NewRuleService(storeSvc, policy)
+	splitSvc := service.NewSplitService(storeSvc, policy)
+	utmSvc := service.NewUTMService(storeSvc)
+	workspaceSvc := service.NewWorkspaceService(storeSvc)
+	apiKeySvc := service.NewAPIKeyService(storeSvc)
+	accountSvc := service.NewAccountService(storeSvc)
+	tokens := middleware.NewTokens(appCfg.SecretKey, appCfg.PreviousSecrets(), appCfg.TokenTTL, service.NewRevocationService(storeSvc))
 
 	h := http2.New(
 		appCfg,
 		urlSvc,
 		urlGet,
 		urlDel,
+		clickSvc,
+		editSvc,
+		tagSvc,
+		qrSvc,
+		expandSvc,
+		ruleSvc,
+		splitSvc,
+		utmSvc,
+		workspaceSvc,
+		apiKeySvc,
+		accountSvc,
+		tokens,
 		pinger,
 		sugar,
 	)
@@ -95,21 +154,31 @@ func main() {
 	addr := appCfg.ServerAddress
 	sugar.Infow("Starting server on", "address: ", addr)
 
-	srv := httpServer.NewServer(addr, appCfg.SecretKey, sugar, h)
+	srv := httpServer.NewServer(addr, appCfg.TrustedSubnet, tokens, apiKeySvc, sugar, h)
 
 	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, syscall.SIGINT, syscall.SIGQUIT)
 	defer stop()
-	workers.StartDeleteWorkerPool(ctx, 5, urlDel, sugar)
+	workers.StartDeleteWorkerPool(ctx, 5, urlDel, workspaceSvc, sugar)
+	workers.StartExpiryReaper(ctx, appCfg.ExpiryReapInterval, urlGet, sugar)
+	workers.StartRetentionPurger(ctx, appCfg.PurgeInterval, urlDel, sugar)
+	clickWorkers := workers.StartClickAggregator(2, clickCh, storeSvc, appCfg.ClickFlushInterval, appCfg.ClickFlushSize, sugar)
 
 	grpcImpl := grpcServer.NewServer(
 		appCfg,
 		urlSvc,
 		urlGet,
 		urlDel,
+		clickSvc,
+		editSvc,
+		qrSvc,
+		expandSvc,
+		workspaceSvc,
+		accountSvc,
+		tokens,
 		pinger)
 
 	grpcSrv := grpc.NewServer(
-		grpc.UnaryInterceptor(middleware.AuthInterceptor(appCfg.SecretKey, sugar)),
+		grpc.UnaryInterceptor(middleware.AuthInterceptor(tokens, apiKeySvc, grpcServer.MethodScopes, sugar)),
 	)
 
 	proto.RegisterURLShortenerServer(grpcSrv, grpcImpl)
@@ -131,4 +200,8 @@ func main() {
 	}
 	<-ctx.Done()
 	grpcSrv.GracefulStop()
+
+	// Серверы остановлены, новых переходов не будет: сбрасываем накопленные агрегаты.
+	close(clickCh)
+	clickWorkers.Wait()
 }
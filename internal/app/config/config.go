@@ -26,6 +26,45 @@ type ConfigType struct {
 	EnableHTTPS       *bool  `env:"ENABLE_HTTPS" json:"enable_https"`
 	ConfigFilePath    string `env:"CONFIG" json:"-"`
 	TrustedSubnet     string `env:"TRUSTED_SUBNET" json:"trusted_subnet"`
+	ShortCodeLength   int    `env:"SHORT_CODE_LENGTH" json:"short_code_length"`
+	ShortCodeStrategy string `env:"SHORT_CODE_STRATEGY" json:"short_code_strategy"`
+	// ShortCodeWords задаёт число слов в коде стратегии words.
+	ShortCodeWords int `env:"SHORT_CODE_WORDS" json:"short_code_words"`
+	// StripTrackingParams включает удаление utm_* и fbclid при поиске дубликатов ссылок.
+	StripTrackingParams bool `env:"STRIP_TRACKING_PARAMS" json:"strip_tracking_params"`
+	// ExpiryReapInterval задаёт период фонового удаления просроченных ссылок.
+	ExpiryReapInterval time.Duration `env:"EXPIRY_REAP_INTERVAL" json:"expiry_reap_interval"`
+	// ClickBufferSize задаёт ёмкость очереди переходов; при переполнении события отбрасываются.
+	ClickBufferSize int `env:"CLICK_BUFFER_SIZE" json:"click_buffer_size"`
+	// ClickFlushInterval и ClickFlushSize задают, как часто агрегатор переходов сбрасывает буфер в хранилище.
+	ClickFlushInterval time.Duration `env:"CLICK_FLUSH_INTERVAL" json:"click_flush_interval"`
+	ClickFlushSize     int           `env:"CLICK_FLUSH_SIZE" json:"click_flush_size"`
+	// AllowedSchemes — список схем через запятую, которые можно сокращать.
+	AllowedSchemes string `env:"ALLOWED_SCHEMES" json:"allowed_schemes"`
+	// DenylistPath — файл с запрещёнными доменами и регулярными выражениями /.../, по одному на строку.
+	DenylistPath string `env:"DENYLIST_PATH" json:"denylist_path"`
+	// MaxURLLength ограничивает длину сокращаемого URL; 0 снимает ограничение.
+	MaxURLLength int `env:"MAX_URL_LENGTH" json:"max_url_length"`
+	// AllowPrivateIPs разрешает сокращать ссылки на loopback и адреса внутренних сетей.
+	AllowPrivateIPs bool `env:"ALLOW_PRIVATE_IPS" json:"allow_private_ips"`
+	// DeleteGracePeriod задаёт, сколько удалённую ссылку можно восстановить до окончательного удаления.
+	DeleteGracePeriod time.Duration `env:"DELETE_GRACE_PERIOD" json:"delete_grace_period"`
+	// PurgeInterval задаёт период окончательного удаления ссылок с истёкшим льготным периодом.
+	PurgeInterval time.Duration `env:"PURGE_INTERVAL" json:"purge_interval"`
+	// CountryDBPath — файл с диапазонами адресов стран "CIDR,код" для правил перенаправления по стране.
+	CountryDBPath string `env:"COUNTRY_DB_PATH" json:"country_db_path"`
+	// Domains — дополнительные короткие домены: базовые адреса через запятую, например https://go.brand.com.
+	Domains string `env:"DOMAINS" json:"domains"`
+	// SecretKeyFile — файл, в котором хранится сгенерированный SecretKey, если ключ не задан явно.
+	SecretKeyFile string `env:"SECRET_KEY_FILE" json:"secret_key_file"`
+	// PreviousSecretKeys — прежние ключи подписи JWT через запятую; токены, подписанные ими, ещё принимаются.
+	PreviousSecretKeys string `env:"PREVIOUS_SECRET_KEYS" json:"previous_secret_keys"`
+	// TokenTTL — срок жизни JWT; активным пользователям токен продлевается автоматически.
+	TokenTTL time.Duration `env:"TOKEN_TTL" json:"token_ttl"`
+	// ClickSalt — соль хэшей IP-адресов посетителей; ClickSaltFile хранит сгенерированную соль,
+	// если она не задана явно.
+	ClickSalt     string `env:"CLICK_SALT" json:"click_salt"`
+	ClickSaltFile string `env:"CLICK_SALT_FILE" json:"click_salt_file"`
 }
 
 // NewConfig парсит флаги и переменные окружения и возвращает заполненную ConfigType.
@@ -42,6 +81,27 @@ func NewConfig() (*ConfigType, error) {
 	flag.StringVar(&config.ConfigFilePath, "c", "", "Путь к JSON файлу конфигурации")
 	flag.StringVar(&config.ConfigFilePath, "config", "", "Конфигурация с помощью JSON файла")
 	flag.StringVar(&config.TrustedSubnet, "t", "", "CIDR доверенной подсети")
+	flag.IntVar(&config.ShortCodeLength, "code-length", 6, "Длина генерируемого короткого кода")
+	flag.IntVar(&config.ShortCodeWords, "code-words", 2, "Число слов в коде стратегии words")
+	flag.StringVar(&config.ShortCodeStrategy, "code-strategy", "random", "Стратегия генерации коротких кодов: random, sequence, hash, words")
+	flag.BoolVar(&config.StripTrackingParams, "strip-tracking", false, "Не учитывать utm_* и fbclid при поиске дубликатов ссылок")
+	flag.DurationVar(&config.ExpiryReapInterval, "reap-interval", time.Minute, "Период удаления просроченных ссылок")
+	flag.IntVar(&config.ClickBufferSize, "click-buffer", 10000, "Ёмкость очереди переходов")
+	flag.DurationVar(&config.ClickFlushInterval, "click-flush-interval", 5*time.Second, "Период сброса агрегатов переходов")
+	flag.IntVar(&config.ClickFlushSize, "click-flush-size", 500, "Число переходов, после которого буфер сбрасывается досрочно")
+	flag.StringVar(&config.AllowedSchemes, "allowed-schemes", "http,https", "Разрешённые схемы сокращаемых URL через запятую")
+	flag.StringVar(&config.DenylistPath, "denylist", "", "Путь к файлу с запрещёнными доменами и регулярными выражениями")
+	flag.IntVar(&config.MaxURLLength, "max-url-length", 2048, "Максимальная длина сокращаемого URL")
+	flag.BoolVar(&config.AllowPrivateIPs, "allow-private-ips", false, "Разрешить ссылки на loopback и адреса внутренних сетей")
+	flag.DurationVar(&config.DeleteGracePeriod, "delete-grace", 7*24*time.Hour, "Срок, в течение которого удалённую ссылку можно восстановить")
+	flag.DurationVar(&config.PurgeInterval, "purge-interval", time.Hour, "Период окончательного удаления ссылок после льготного срока")
+	flag.StringVar(&config.CountryDBPath, "country-db", "", "Путь к файлу с диапазонами IP-адресов стран для правил перенаправления")
+	flag.StringVar(&config.Domains, "domains", "", "Дополнительные короткие домены: базовые адреса через запятую")
+	flag.StringVar(&config.SecretKeyFile, "secret-key-file", "secret.key", "Файл для сгенерированного ключа, если ключ не задан")
+	flag.StringVar(&config.PreviousSecretKeys, "previous-keys", "", "Прежние ключи подписи JWT через запятую")
+	flag.DurationVar(&config.TokenTTL, "token-ttl", 30*24*time.Hour, "Срок жизни JWT пользователя")
+	flag.StringVar(&config.ClickSalt, "click-salt", "", "Соль хэшей IP-адресов посетителей")
+	flag.StringVar(&config.ClickSaltFile, "click-salt-file", "click.salt", "Файл для сгенерированной соли, если соль не задана")
 
 	flag.Parse()
 
@@ -56,9 +116,57 @@ func NewConfig() (*ConfigType, error) {
 		return nil, err
 	}
 
+	if _, err := parseDomains(config.Domains); err != nil {
+		return nil, err
+	}
+	if config.TokenTTL <= 0 {
+		return nil, fmt.Errorf("token TTL must be positive, got %s", config.TokenTTL)
+	}
+
 	return &config, nil
 }
 
+// fileConfig описывает JSON-файл конфигурации. Длительности в нём записываются
+// строками в формате time.ParseDuration, например "720h".
+type fileConfig struct {
+	ConfigType
+	ExpiryReapInterval duration `json:"expiry_reap_interval"`
+	ClickFlushInterval duration `json:"click_flush_interval"`
+	DeleteGracePeriod  duration `json:"delete_grace_period"`
+	PurgeInterval      duration `json:"purge_interval"`
+	TokenTTL           duration `json:"token_ttl"`
+}
+
+// duration — длительность в JSON-файле конфигурации: строка вроде "720h"
+// или число наносекунд, как у time.Duration.
+type duration time.Duration
+
+// UnmarshalJSON разбирает длительность из строки или числа наносекунд.
+func (d *duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		var ns int64
+		if err := json.Unmarshal(data, &ns); err != nil {
+			return fmt.Errorf("invalid duration %s: expected a string like \"720h\"", data)
+		}
+		*d = duration(ns)
+		return nil
+	}
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = duration(v)
+	return nil
+}
+
+// flagIsDefault сообщает, что флаг name не задан в командной строке
+// и значение из файла конфигурации может его заменить.
+func flagIsDefault(name string) bool {
+	f := flag.Lookup(name)
+	return f == nil || f.Value.String() == f.DefValue
+}
+
 func applyJSONConfig(path string, config *ConfigType) error {
 	file, err := os.ReadFile(path)
 	if err != nil {
@@ -66,33 +174,99 @@ func applyJSONConfig(path string, config *ConfigType) error {
 		return err
 	}
 
-	var fileConf ConfigType
+	var fileConf fileConfig
 
 	if err = json.Unmarshal(file, &fileConf); err != nil {
 		log.Printf("Failed to parse config file: %v\n", err)
 		return err
 	}
 
-	switch {
-	case fileConf.GRPCServerAddress != "" && config.GRPCServerAddress != "":
+	if fileConf.GRPCServerAddress != "" && flagIsDefault("ag") {
 		config.GRPCServerAddress = fileConf.GRPCServerAddress
-	case fileConf.ServerAddress != "" && config.ServerAddress != "":
+	}
+	if fileConf.ServerAddress != "" && flagIsDefault("a") {
 		config.ServerAddress = fileConf.ServerAddress
-	case fileConf.BaseAddress != "" && config.BaseAddress != "":
+	}
+	if fileConf.BaseAddress != "" && flagIsDefault("b") {
 		config.BaseAddress = fileConf.BaseAddress
-	case fileConf.FileStoragePath != "" && config.FileStoragePath != "":
+	}
+	if fileConf.FileStoragePath != "" && flagIsDefault("f") {
 		config.FileStoragePath = fileConf.FileStoragePath
-	case fileConf.DSN != "" && config.DSN != "":
+	}
+	if fileConf.DSN != "" && flagIsDefault("d") {
 		config.DSN = fileConf.DSN
-	case fileConf.SecretKey != "" && config.SecretKey != "":
+	}
+	if fileConf.SecretKey != "" && flagIsDefault("k") {
 		config.SecretKey = fileConf.SecretKey
-	case fileConf.TrustedSubnet != "" && config.TrustedSubnet != "":
+	}
+	if fileConf.TrustedSubnet != "" && flagIsDefault("t") {
 		config.TrustedSubnet = fileConf.TrustedSubnet
-	case fileConf.EnableHTTPS != nil && config.EnableHTTPS != nil:
-		f := flag.Lookup("s")
-		if f == nil || f.Value.String() == f.DefValue {
-			config.EnableHTTPS = fileConf.EnableHTTPS
-		}
+	}
+	if fileConf.EnableHTTPS != nil && config.EnableHTTPS != nil && flagIsDefault("s") {
+		config.EnableHTTPS = fileConf.EnableHTTPS
+	}
+	if fileConf.ShortCodeLength != 0 && flagIsDefault("code-length") {
+		config.ShortCodeLength = fileConf.ShortCodeLength
+	}
+	if fileConf.ShortCodeStrategy != "" && flagIsDefault("code-strategy") {
+		config.ShortCodeStrategy = fileConf.ShortCodeStrategy
+	}
+	if fileConf.ShortCodeWords != 0 && flagIsDefault("code-words") {
+		config.ShortCodeWords = fileConf.ShortCodeWords
+	}
+	if fileConf.StripTrackingParams && flagIsDefault("strip-tracking") {
+		config.StripTrackingParams = fileConf.StripTrackingParams
+	}
+	if fileConf.ExpiryReapInterval != 0 && flagIsDefault("reap-interval") {
+		config.ExpiryReapInterval = time.Duration(fileConf.ExpiryReapInterval)
+	}
+	if fileConf.ClickBufferSize != 0 && flagIsDefault("click-buffer") {
+		config.ClickBufferSize = fileConf.ClickBufferSize
+	}
+	if fileConf.ClickFlushInterval != 0 && flagIsDefault("click-flush-interval") {
+		config.ClickFlushInterval = time.Duration(fileConf.ClickFlushInterval)
+	}
+	if fileConf.ClickFlushSize != 0 && flagIsDefault("click-flush-size") {
+		config.ClickFlushSize = fileConf.ClickFlushSize
+	}
+	if fileConf.AllowedSchemes != "" && flagIsDefault("allowed-schemes") {
+		config.AllowedSchemes = fileConf.AllowedSchemes
+	}
+	if fileConf.DenylistPath != "" && flagIsDefault("denylist") {
+		config.DenylistPath = fileConf.DenylistPath
+	}
+	if fileConf.MaxURLLength != 0 && flagIsDefault("max-url-length") {
+		config.MaxURLLength = fileConf.MaxURLLength
+	}
+	if fileConf.AllowPrivateIPs && flagIsDefault("allow-private-ips") {
+		config.AllowPrivateIPs = fileConf.AllowPrivateIPs
+	}
+	if fileConf.DeleteGracePeriod != 0 && flagIsDefault("delete-grace") {
+		config.DeleteGracePeriod = time.Duration(fileConf.DeleteGracePeriod)
+	}
+	if fileConf.PurgeInterval != 0 && flagIsDefault("purge-interval") {
+		config.PurgeInterval = time.Duration(fileConf.PurgeInterval)
+	}
+	if fileConf.CountryDBPath != "" && flagIsDefault("country-db") {
+		config.CountryDBPath = fileConf.CountryDBPath
+	}
+	if fileConf.Domains != "" && flagIsDefault("domains") {
+		config.Domains = fileConf.Domains
+	}
+	if fileConf.SecretKeyFile != "" && flagIsDefault("secret-key-file") {
+		config.SecretKeyFile = fileConf.SecretKeyFile
+	}
+	if fileConf.PreviousSecretKeys != "" && flagIsDefault("previous-keys") {
+		config.PreviousSecretKeys = fileConf.PreviousSecretKeys
+	}
+	if fileConf.TokenTTL != 0 && flagIsDefault("token-ttl") {
+		config.TokenTTL = time.Duration(fileConf.TokenTTL)
+	}
+	if fileConf.ClickSalt != "" && flagIsDefault("click-salt") {
+		config.ClickSalt = fileConf.ClickSalt
+	}
+	if fileConf.ClickSaltFile != "" && flagIsDefault("click-salt-file") {
+		config.ClickSaltFile = fileConf.ClickSaltFile
 	}
 	return nil
 }
@@ -0,0 +1,32 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestApplyJSONConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	data := `{
+		"database_dsn": "postgres://localhost/shortener",
+		"secret_key": "file secret",
+		"trusted_subnet": "10.0.0.0/8",
+		"token_ttl": "720h"
+	}`
+	if err := os.WriteFile(path, []byte(data), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	config := ConfigType{TokenTTL: time.Hour}
+	if err := applyJSONConfig(path, &config); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if config.DSN != "postgres://localhost/shortener" || config.SecretKey != "file secret" || config.TrustedSubnet != "10.0.0.0/8" {
+		t.Errorf("expected DSN, secret key and trusted subnet from the file, got %q, %q and %q", config.DSN, config.SecretKey, config.TrustedSubnet)
+	}
+	if config.TokenTTL != 720*time.Hour {
+		t.Errorf("expected token TTL 720h, got %s", config.TokenTTL)
+	}
+}
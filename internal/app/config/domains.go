@@ -0,0 +1,85 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// parseDomains разбирает список дополнительных коротких доменов и возвращает
+// их базовые адреса по хостам в нижнем регистре.
+func parseDomains(raw string) (map[string]string, error) {
+	domains := make(map[string]string)
+	for _, base := range strings.Split(raw, ",") {
+		base = strings.TrimRight(strings.TrimSpace(base), "/")
+		if base == "" {
+			continue
+		}
+		u, err := url.Parse(base)
+		if err != nil || u.Scheme == "" || u.Host == "" || u.Path != "" {
+			return nil, fmt.Errorf("invalid short domain %q: expected a base address like https://go.example.com", base)
+		}
+		domains[strings.ToLower(u.Host)] = base
+	}
+	return domains, nil
+}
+
+// DomainHosts возвращает отсортированные хосты дополнительных коротких доменов.
+func (c *ConfigType) DomainHosts() []string {
+	domains, _ := parseDomains(c.Domains)
+	hosts := make([]string, 0, len(domains))
+	for host := range domains {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+	return hosts
+}
+
+// LookupDomain возвращает домен ссылок по имени name: хост дополнительного домена
+// или пустую строку для основного домена (пустое имя или хост BaseAddress).
+// Второе значение равно false, если такой домен не настроен.
+func (c *ConfigType) LookupDomain(name string) (string, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" || name == c.baseHost() {
+		return "", true
+	}
+	domains, _ := parseDomains(c.Domains)
+	_, ok := domains[name]
+	return name, ok
+}
+
+// DomainByHost возвращает домен ссылок для заголовка Host запроса.
+// Хосты, не относящиеся к дополнительным доменам, обслуживаются как основной домен.
+func (c *ConfigType) DomainByHost(host string) string {
+	domain, ok := c.LookupDomain(host)
+	if !ok {
+		return ""
+	}
+	return domain
+}
+
+// BaseURL возвращает базовый адрес коротких ссылок домена domain;
+// для основного и неизвестного домена — BaseAddress.
+func (c *ConfigType) BaseURL(domain string) string {
+	if domain != "" {
+		domains, _ := parseDomains(c.Domains)
+		if base, ok := domains[domain]; ok {
+			return base
+		}
+	}
+	return c.BaseAddress
+}
+
+// ShortURL возвращает полный адрес ссылки с кодом code на домене domain.
+func (c *ConfigType) ShortURL(domain, code string) string {
+	return c.BaseURL(domain) + "/" + code
+}
+
+func (c *ConfigType) baseHost() string {
+	u, err := url.Parse(c.BaseAddress)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(u.Host)
+}
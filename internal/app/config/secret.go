@@ -0,0 +1,72 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/aseptimu/url-shortener/internal/app/utils"
+)
+
+// EnsureSecretKey задаёт SecretKey, если он не указан явно: читает ключ из SecretKeyFile,
+// а при отсутствии файла генерирует новый и сохраняет его туда, чтобы токены
+// оставались действительными после перезапуска. Несколько экземпляров сервиса
+// должны получать общий ключ через конфигурацию.
+func (c *ConfigType) EnsureSecretKey() error {
+	return ensureSecret(&c.SecretKey, c.SecretKeyFile, "secret key")
+}
+
+// EnsureClickSalt задаёт ClickSalt, если соль не указана явно, так же, как EnsureSecretKey:
+// соль хэшей адресов посетителей хранится в ClickSaltFile отдельно от ключа подписи JWT,
+// поэтому хэши не меняются при смене ключа и не раскрывают его.
+func (c *ConfigType) EnsureClickSalt() error {
+	return ensureSecret(&c.ClickSalt, c.ClickSaltFile, "click salt")
+}
+
+// ensureSecret читает секрет name из файла path в value, если он не задан, а при отсутствии
+// файла генерирует новый секрет и сохраняет его туда.
+func ensureSecret(value *string, path, name string) error {
+	if *value != "" {
+		return nil
+	}
+	if path == "" {
+		return fmt.Errorf("%s is not configured and %s file is empty", name, name)
+	}
+
+	data, err := os.ReadFile(path)
+	switch {
+	case err == nil:
+		*value = strings.TrimSpace(string(data))
+		if *value == "" {
+			return fmt.Errorf("%s file %s is empty", name, path)
+		}
+		return nil
+	case !errors.Is(err, os.ErrNotExist):
+		return fmt.Errorf("read %s file: %w", name, err)
+	}
+
+	secret := utils.GenerateRandomSecretKey()
+	// O_EXCL не даёт затереть секрет, созданный параллельно запущенным экземпляром.
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("create %s file: %w", name, err)
+	}
+	defer file.Close()
+	if _, err = file.WriteString(secret + "\n"); err != nil {
+		return fmt.Errorf("write %s file: %w", name, err)
+	}
+	*value = secret
+	return nil
+}
+
+// PreviousSecrets возвращает прежние ключи подписи JWT из PreviousSecretKeys.
+func (c *ConfigType) PreviousSecrets() []string {
+	var secrets []string
+	for _, secret := range strings.Split(c.PreviousSecretKeys, ",") {
+		if secret = strings.TrimSpace(secret); secret != "" {
+			secrets = append(secrets, secret)
+		}
+	}
+	return secrets
+}
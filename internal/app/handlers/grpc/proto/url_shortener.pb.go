@@ -24,6 +24,11 @@ const (
 type GetURLRequest struct {
 	state                  protoimpl.MessageState `protogen:"opaque.v1"`
 	xxx_hidden_Url         *string                `protobuf:"bytes,1,opt,name=url"`
+	xxx_hidden_Password    *string                `protobuf:"bytes,2,opt,name=password"`
+	xxx_hidden_Path        *string                `protobuf:"bytes,3,opt,name=path"`
+	xxx_hidden_Query       *string                `protobuf:"bytes,4,opt,name=query"`
+	xxx_hidden_Variant     *string                `protobuf:"bytes,5,opt,name=variant"`
+	xxx_hidden_Domain      *string                `protobuf:"bytes,6,opt,name=domain"`
 	XXX_raceDetectHookData protoimpl.RaceDetectHookData
 	XXX_presence           [1]uint32
 	unknownFields          protoimpl.UnknownFields
@@ -65,9 +70,84 @@ func (x *GetURLRequest) GetUrl() string {
 	return ""
 }
 
+func (x *GetURLRequest) GetPassword() string {
+	if x != nil {
+		if x.xxx_hidden_Password != nil {
+			return *x.xxx_hidden_Password
+		}
+		return ""
+	}
+	return ""
+}
+
+func (x *GetURLRequest) GetPath() string {
+	if x != nil {
+		if x.xxx_hidden_Path != nil {
+			return *x.xxx_hidden_Path
+		}
+		return ""
+	}
+	return ""
+}
+
+func (x *GetURLRequest) GetQuery() string {
+	if x != nil {
+		if x.xxx_hidden_Query != nil {
+			return *x.xxx_hidden_Query
+		}
+		return ""
+	}
+	return ""
+}
+
+func (x *GetURLRequest) GetVariant() string {
+	if x != nil {
+		if x.xxx_hidden_Variant != nil {
+			return *x.xxx_hidden_Variant
+		}
+		return ""
+	}
+	return ""
+}
+
+func (x *GetURLRequest) GetDomain() string {
+	if x != nil {
+		if x.xxx_hidden_Domain != nil {
+			return *x.xxx_hidden_Domain
+		}
+		return ""
+	}
+	return ""
+}
+
 func (x *GetURLRequest) SetUrl(v string) {
 	x.xxx_hidden_Url = &v
-	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 0, 1)
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 0, 6)
+}
+
+func (x *GetURLRequest) SetPassword(v string) {
+	x.xxx_hidden_Password = &v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 1, 6)
+}
+
+func (x *GetURLRequest) SetPath(v string) {
+	x.xxx_hidden_Path = &v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 2, 6)
+}
+
+func (x *GetURLRequest) SetQuery(v string) {
+	x.xxx_hidden_Query = &v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 3, 6)
+}
+
+func (x *GetURLRequest) SetVariant(v string) {
+	x.xxx_hidden_Variant = &v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 4, 6)
+}
+
+func (x *GetURLRequest) SetDomain(v string) {
+	x.xxx_hidden_Domain = &v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 5, 6)
 }
 
 func (x *GetURLRequest) HasUrl() bool {
@@ -77,15 +157,83 @@ func (x *GetURLRequest) HasUrl() bool {
 	return protoimpl.X.Present(&(x.XXX_presence[0]), 0)
 }
 
+func (x *GetURLRequest) HasPassword() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 1)
+}
+
+func (x *GetURLRequest) HasPath() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 2)
+}
+
+func (x *GetURLRequest) HasQuery() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 3)
+}
+
+func (x *GetURLRequest) HasVariant() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 4)
+}
+
+func (x *GetURLRequest) HasDomain() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 5)
+}
+
 func (x *GetURLRequest) ClearUrl() {
 	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 0)
 	x.xxx_hidden_Url = nil
 }
 
+func (x *GetURLRequest) ClearPassword() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 1)
+	x.xxx_hidden_Password = nil
+}
+
+func (x *GetURLRequest) ClearPath() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 2)
+	x.xxx_hidden_Path = nil
+}
+
+func (x *GetURLRequest) ClearQuery() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 3)
+	x.xxx_hidden_Query = nil
+}
+
+func (x *GetURLRequest) ClearVariant() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 4)
+	x.xxx_hidden_Variant = nil
+}
+
+func (x *GetURLRequest) ClearDomain() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 5)
+	x.xxx_hidden_Domain = nil
+}
+
 type GetURLRequest_builder struct {
 	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
 
-	Url *string
+	Url      *string
+	Password *string
+	// path и query переносятся в адрес назначения, если это включено для ссылки.
+	Path  *string
+	Query *string
+	// variant — вариант A/B-сплита, выданный клиенту раньше; передаётся, чтобы получить тот же вариант.
+	Variant *string
+	// domain — короткий домен ссылки; пустой означает основной домен.
+	Domain *string
 }
 
 func (b0 GetURLRequest_builder) Build() *GetURLRequest {
@@ -93,19 +241,41 @@ func (b0 GetURLRequest_builder) Build() *GetURLRequest {
 	b, x := &b0, m0
 	_, _ = b, x
 	if b.Url != nil {
-		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 0, 1)
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 0, 6)
 		x.xxx_hidden_Url = b.Url
 	}
+	if b.Password != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 1, 6)
+		x.xxx_hidden_Password = b.Password
+	}
+	if b.Path != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 2, 6)
+		x.xxx_hidden_Path = b.Path
+	}
+	if b.Query != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 3, 6)
+		x.xxx_hidden_Query = b.Query
+	}
+	if b.Variant != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 4, 6)
+		x.xxx_hidden_Variant = b.Variant
+	}
+	if b.Domain != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 5, 6)
+		x.xxx_hidden_Domain = b.Domain
+	}
 	return m0
 }
 
 type GetURLResponse struct {
-	state                  protoimpl.MessageState `protogen:"opaque.v1"`
-	xxx_hidden_OriginalUrl *string                `protobuf:"bytes,1,opt,name=original_url,json=originalUrl"`
-	XXX_raceDetectHookData protoimpl.RaceDetectHookData
-	XXX_presence           [1]uint32
-	unknownFields          protoimpl.UnknownFields
-	sizeCache              protoimpl.SizeCache
+	state                   protoimpl.MessageState `protogen:"opaque.v1"`
+	xxx_hidden_OriginalUrl  *string                `protobuf:"bytes,1,opt,name=original_url,json=originalUrl"`
+	xxx_hidden_RedirectCode int32                  `protobuf:"varint,2,opt,name=redirect_code,json=redirectCode"`
+	xxx_hidden_Variant      *string                `protobuf:"bytes,3,opt,name=variant"`
+	XXX_raceDetectHookData  protoimpl.RaceDetectHookData
+	XXX_presence            [1]uint32
+	unknownFields           protoimpl.UnknownFields
+	sizeCache               protoimpl.SizeCache
 }
 
 func (x *GetURLResponse) Reset() {
@@ -143,9 +313,36 @@ func (x *GetURLResponse) GetOriginalUrl() string {
 	return ""
 }
 
+func (x *GetURLResponse) GetRedirectCode() int32 {
+	if x != nil {
+		return x.xxx_hidden_RedirectCode
+	}
+	return 0
+}
+
+func (x *GetURLResponse) GetVariant() string {
+	if x != nil {
+		if x.xxx_hidden_Variant != nil {
+			return *x.xxx_hidden_Variant
+		}
+		return ""
+	}
+	return ""
+}
+
 func (x *GetURLResponse) SetOriginalUrl(v string) {
 	x.xxx_hidden_OriginalUrl = &v
-	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 0, 1)
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 0, 3)
+}
+
+func (x *GetURLResponse) SetRedirectCode(v int32) {
+	x.xxx_hidden_RedirectCode = v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 1, 3)
+}
+
+func (x *GetURLResponse) SetVariant(v string) {
+	x.xxx_hidden_Variant = &v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 2, 3)
 }
 
 func (x *GetURLResponse) HasOriginalUrl() bool {
@@ -155,15 +352,43 @@ func (x *GetURLResponse) HasOriginalUrl() bool {
 	return protoimpl.X.Present(&(x.XXX_presence[0]), 0)
 }
 
+func (x *GetURLResponse) HasRedirectCode() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 1)
+}
+
+func (x *GetURLResponse) HasVariant() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 2)
+}
+
 func (x *GetURLResponse) ClearOriginalUrl() {
 	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 0)
 	x.xxx_hidden_OriginalUrl = nil
 }
 
+func (x *GetURLResponse) ClearRedirectCode() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 1)
+	x.xxx_hidden_RedirectCode = 0
+}
+
+func (x *GetURLResponse) ClearVariant() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 2)
+	x.xxx_hidden_Variant = nil
+}
+
 type GetURLResponse_builder struct {
 	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
 
 	OriginalUrl *string
+	// redirect_code — код ответа, с которым ссылка перенаправляет по HTTP.
+	RedirectCode *int32
+	// variant — выданный вариант A/B-сплита; пуст для ссылок без сплита.
+	Variant *string
 }
 
 func (b0 GetURLResponse_builder) Build() *GetURLResponse {
@@ -171,9 +396,17 @@ func (b0 GetURLResponse_builder) Build() *GetURLResponse {
 	b, x := &b0, m0
 	_, _ = b, x
 	if b.OriginalUrl != nil {
-		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 0, 1)
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 0, 3)
 		x.xxx_hidden_OriginalUrl = b.OriginalUrl
 	}
+	if b.RedirectCode != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 1, 3)
+		x.xxx_hidden_RedirectCode = *b.RedirectCode
+	}
+	if b.Variant != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 2, 3)
+		x.xxx_hidden_Variant = b.Variant
+	}
 	return m0
 }
 
@@ -298,29 +531,34 @@ func (b0 PingRequest_builder) Build() *PingRequest {
 	return m0
 }
 
-type URLCreatorRequest struct {
+// UTM содержит UTM-параметры, которые дописываются к адресу назначения при создании ссылки.
+type UTM struct {
 	state                  protoimpl.MessageState `protogen:"opaque.v1"`
-	xxx_hidden_OriginalUrl *string                `protobuf:"bytes,2,opt,name=original_url,json=originalUrl"`
+	xxx_hidden_Source      *string                `protobuf:"bytes,1,opt,name=source"`
+	xxx_hidden_Medium      *string                `protobuf:"bytes,2,opt,name=medium"`
+	xxx_hidden_Campaign    *string                `protobuf:"bytes,3,opt,name=campaign"`
+	xxx_hidden_Term        *string                `protobuf:"bytes,4,opt,name=term"`
+	xxx_hidden_Content     *string                `protobuf:"bytes,5,opt,name=content"`
 	XXX_raceDetectHookData protoimpl.RaceDetectHookData
 	XXX_presence           [1]uint32
 	unknownFields          protoimpl.UnknownFields
 	sizeCache              protoimpl.SizeCache
 }
 
-func (x *URLCreatorRequest) Reset() {
-	*x = URLCreatorRequest{}
+func (x *UTM) Reset() {
+	*x = UTM{}
 	mi := &file_url_shortener_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *URLCreatorRequest) String() string {
+func (x *UTM) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*URLCreatorRequest) ProtoMessage() {}
+func (*UTM) ProtoMessage() {}
 
-func (x *URLCreatorRequest) ProtoReflect() protoreflect.Message {
+func (x *UTM) ProtoReflect() protoreflect.Message {
 	mi := &file_url_shortener_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -332,73 +570,214 @@ func (x *URLCreatorRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-func (x *URLCreatorRequest) GetOriginalUrl() string {
+func (x *UTM) GetSource() string {
 	if x != nil {
-		if x.xxx_hidden_OriginalUrl != nil {
-			return *x.xxx_hidden_OriginalUrl
+		if x.xxx_hidden_Source != nil {
+			return *x.xxx_hidden_Source
 		}
 		return ""
 	}
 	return ""
 }
 
-func (x *URLCreatorRequest) SetOriginalUrl(v string) {
-	x.xxx_hidden_OriginalUrl = &v
-	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 0, 1)
+func (x *UTM) GetMedium() string {
+	if x != nil {
+		if x.xxx_hidden_Medium != nil {
+			return *x.xxx_hidden_Medium
+		}
+		return ""
+	}
+	return ""
 }
 
-func (x *URLCreatorRequest) HasOriginalUrl() bool {
+func (x *UTM) GetCampaign() string {
+	if x != nil {
+		if x.xxx_hidden_Campaign != nil {
+			return *x.xxx_hidden_Campaign
+		}
+		return ""
+	}
+	return ""
+}
+
+func (x *UTM) GetTerm() string {
+	if x != nil {
+		if x.xxx_hidden_Term != nil {
+			return *x.xxx_hidden_Term
+		}
+		return ""
+	}
+	return ""
+}
+
+func (x *UTM) GetContent() string {
+	if x != nil {
+		if x.xxx_hidden_Content != nil {
+			return *x.xxx_hidden_Content
+		}
+		return ""
+	}
+	return ""
+}
+
+func (x *UTM) SetSource(v string) {
+	x.xxx_hidden_Source = &v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 0, 5)
+}
+
+func (x *UTM) SetMedium(v string) {
+	x.xxx_hidden_Medium = &v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 1, 5)
+}
+
+func (x *UTM) SetCampaign(v string) {
+	x.xxx_hidden_Campaign = &v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 2, 5)
+}
+
+func (x *UTM) SetTerm(v string) {
+	x.xxx_hidden_Term = &v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 3, 5)
+}
+
+func (x *UTM) SetContent(v string) {
+	x.xxx_hidden_Content = &v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 4, 5)
+}
+
+func (x *UTM) HasSource() bool {
 	if x == nil {
 		return false
 	}
 	return protoimpl.X.Present(&(x.XXX_presence[0]), 0)
 }
 
-func (x *URLCreatorRequest) ClearOriginalUrl() {
+func (x *UTM) HasMedium() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 1)
+}
+
+func (x *UTM) HasCampaign() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 2)
+}
+
+func (x *UTM) HasTerm() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 3)
+}
+
+func (x *UTM) HasContent() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 4)
+}
+
+func (x *UTM) ClearSource() {
 	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 0)
-	x.xxx_hidden_OriginalUrl = nil
+	x.xxx_hidden_Source = nil
 }
 
-type URLCreatorRequest_builder struct {
+func (x *UTM) ClearMedium() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 1)
+	x.xxx_hidden_Medium = nil
+}
+
+func (x *UTM) ClearCampaign() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 2)
+	x.xxx_hidden_Campaign = nil
+}
+
+func (x *UTM) ClearTerm() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 3)
+	x.xxx_hidden_Term = nil
+}
+
+func (x *UTM) ClearContent() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 4)
+	x.xxx_hidden_Content = nil
+}
+
+type UTM_builder struct {
 	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
 
-	OriginalUrl *string
+	Source   *string
+	Medium   *string
+	Campaign *string
+	Term     *string
+	Content  *string
 }
 
-func (b0 URLCreatorRequest_builder) Build() *URLCreatorRequest {
-	m0 := &URLCreatorRequest{}
+func (b0 UTM_builder) Build() *UTM {
+	m0 := &UTM{}
 	b, x := &b0, m0
 	_, _ = b, x
-	if b.OriginalUrl != nil {
-		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 0, 1)
-		x.xxx_hidden_OriginalUrl = b.OriginalUrl
+	if b.Source != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 0, 5)
+		x.xxx_hidden_Source = b.Source
+	}
+	if b.Medium != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 1, 5)
+		x.xxx_hidden_Medium = b.Medium
+	}
+	if b.Campaign != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 2, 5)
+		x.xxx_hidden_Campaign = b.Campaign
+	}
+	if b.Term != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 3, 5)
+		x.xxx_hidden_Term = b.Term
+	}
+	if b.Content != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 4, 5)
+		x.xxx_hidden_Content = b.Content
 	}
 	return m0
 }
 
-type URLCreatorResponse struct {
-	state                  protoimpl.MessageState `protogen:"opaque.v1"`
-	xxx_hidden_ShortenUrl  *string                `protobuf:"bytes,1,opt,name=shortenUrl"`
-	XXX_raceDetectHookData protoimpl.RaceDetectHookData
-	XXX_presence           [1]uint32
-	unknownFields          protoimpl.UnknownFields
-	sizeCache              protoimpl.SizeCache
+type URLCreatorRequest struct {
+	state                   protoimpl.MessageState `protogen:"opaque.v1"`
+	xxx_hidden_OriginalUrl  *string                `protobuf:"bytes,2,opt,name=original_url,json=originalUrl"`
+	xxx_hidden_Alias        *string                `protobuf:"bytes,3,opt,name=alias"`
+	xxx_hidden_ExpiresAt    *string                `protobuf:"bytes,4,opt,name=expires_at,json=expiresAt"`
+	xxx_hidden_Ttl          int64                  `protobuf:"varint,5,opt,name=ttl"`
+	xxx_hidden_MaxUses      int32                  `protobuf:"varint,6,opt,name=max_uses,json=maxUses"`
+	xxx_hidden_Password     *string                `protobuf:"bytes,7,opt,name=password"`
+	xxx_hidden_Tags         []string               `protobuf:"bytes,8,rep,name=tags"`
+	xxx_hidden_Title        *string                `protobuf:"bytes,9,opt,name=title"`
+	xxx_hidden_RedirectCode int32                  `protobuf:"varint,10,opt,name=redirect_code,json=redirectCode"`
+	xxx_hidden_PassQuery    bool                   `protobuf:"varint,11,opt,name=pass_query,json=passQuery"`
+	xxx_hidden_PassPath     bool                   `protobuf:"varint,12,opt,name=pass_path,json=passPath"`
+	xxx_hidden_UtmTemplate  *string                `protobuf:"bytes,13,opt,name=utm_template,json=utmTemplate"`
+	xxx_hidden_Utm          *UTM                   `protobuf:"bytes,14,opt,name=utm"`
+	xxx_hidden_Domain       *string                `protobuf:"bytes,15,opt,name=domain"`
+	XXX_raceDetectHookData  protoimpl.RaceDetectHookData
+	XXX_presence            [1]uint32
+	unknownFields           protoimpl.UnknownFields
+	sizeCache               protoimpl.SizeCache
 }
 
-func (x *URLCreatorResponse) Reset() {
-	*x = URLCreatorResponse{}
+func (x *URLCreatorRequest) Reset() {
+	*x = URLCreatorRequest{}
 	mi := &file_url_shortener_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *URLCreatorResponse) String() string {
+func (x *URLCreatorRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*URLCreatorResponse) ProtoMessage() {}
+func (*URLCreatorRequest) ProtoMessage() {}
 
-func (x *URLCreatorResponse) ProtoReflect() protoreflect.Message {
+func (x *URLCreatorRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_url_shortener_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -410,74 +789,3491 @@ func (x *URLCreatorResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-func (x *URLCreatorResponse) GetShortenUrl() string {
+func (x *URLCreatorRequest) GetOriginalUrl() string {
 	if x != nil {
-		if x.xxx_hidden_ShortenUrl != nil {
-			return *x.xxx_hidden_ShortenUrl
+		if x.xxx_hidden_OriginalUrl != nil {
+			return *x.xxx_hidden_OriginalUrl
 		}
 		return ""
 	}
 	return ""
 }
 
-func (x *URLCreatorResponse) SetShortenUrl(v string) {
-	x.xxx_hidden_ShortenUrl = &v
-	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 0, 1)
+func (x *URLCreatorRequest) GetAlias() string {
+	if x != nil {
+		if x.xxx_hidden_Alias != nil {
+			return *x.xxx_hidden_Alias
+		}
+		return ""
+	}
+	return ""
 }
 
-func (x *URLCreatorResponse) HasShortenUrl() bool {
+func (x *URLCreatorRequest) GetExpiresAt() string {
+	if x != nil {
+		if x.xxx_hidden_ExpiresAt != nil {
+			return *x.xxx_hidden_ExpiresAt
+		}
+		return ""
+	}
+	return ""
+}
+
+func (x *URLCreatorRequest) GetTtl() int64 {
+	if x != nil {
+		return x.xxx_hidden_Ttl
+	}
+	return 0
+}
+
+func (x *URLCreatorRequest) GetMaxUses() int32 {
+	if x != nil {
+		return x.xxx_hidden_MaxUses
+	}
+	return 0
+}
+
+func (x *URLCreatorRequest) GetPassword() string {
+	if x != nil {
+		if x.xxx_hidden_Password != nil {
+			return *x.xxx_hidden_Password
+		}
+		return ""
+	}
+	return ""
+}
+
+func (x *URLCreatorRequest) GetTags() []string {
+	if x != nil {
+		return x.xxx_hidden_Tags
+	}
+	return nil
+}
+
+func (x *URLCreatorRequest) GetTitle() string {
+	if x != nil {
+		if x.xxx_hidden_Title != nil {
+			return *x.xxx_hidden_Title
+		}
+		return ""
+	}
+	return ""
+}
+
+func (x *URLCreatorRequest) GetRedirectCode() int32 {
+	if x != nil {
+		return x.xxx_hidden_RedirectCode
+	}
+	return 0
+}
+
+func (x *URLCreatorRequest) GetPassQuery() bool {
+	if x != nil {
+		return x.xxx_hidden_PassQuery
+	}
+	return false
+}
+
+func (x *URLCreatorRequest) GetPassPath() bool {
+	if x != nil {
+		return x.xxx_hidden_PassPath
+	}
+	return false
+}
+
+func (x *URLCreatorRequest) GetUtmTemplate() string {
+	if x != nil {
+		if x.xxx_hidden_UtmTemplate != nil {
+			return *x.xxx_hidden_UtmTemplate
+		}
+		return ""
+	}
+	return ""
+}
+
+func (x *URLCreatorRequest) GetUtm() *UTM {
+	if x != nil {
+		return x.xxx_hidden_Utm
+	}
+	return nil
+}
+
+func (x *URLCreatorRequest) GetDomain() string {
+	if x != nil {
+		if x.xxx_hidden_Domain != nil {
+			return *x.xxx_hidden_Domain
+		}
+		return ""
+	}
+	return ""
+}
+
+func (x *URLCreatorRequest) SetOriginalUrl(v string) {
+	x.xxx_hidden_OriginalUrl = &v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 0, 14)
+}
+
+func (x *URLCreatorRequest) SetAlias(v string) {
+	x.xxx_hidden_Alias = &v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 1, 14)
+}
+
+func (x *URLCreatorRequest) SetExpiresAt(v string) {
+	x.xxx_hidden_ExpiresAt = &v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 2, 14)
+}
+
+func (x *URLCreatorRequest) SetTtl(v int64) {
+	x.xxx_hidden_Ttl = v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 3, 14)
+}
+
+func (x *URLCreatorRequest) SetMaxUses(v int32) {
+	x.xxx_hidden_MaxUses = v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 4, 14)
+}
+
+func (x *URLCreatorRequest) SetPassword(v string) {
+	x.xxx_hidden_Password = &v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 5, 14)
+}
+
+func (x *URLCreatorRequest) SetTags(v []string) {
+	x.xxx_hidden_Tags = v
+}
+
+func (x *URLCreatorRequest) SetTitle(v string) {
+	x.xxx_hidden_Title = &v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 7, 14)
+}
+
+func (x *URLCreatorRequest) SetRedirectCode(v int32) {
+	x.xxx_hidden_RedirectCode = v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 8, 14)
+}
+
+func (x *URLCreatorRequest) SetPassQuery(v bool) {
+	x.xxx_hidden_PassQuery = v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 9, 14)
+}
+
+func (x *URLCreatorRequest) SetPassPath(v bool) {
+	x.xxx_hidden_PassPath = v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 10, 14)
+}
+
+func (x *URLCreatorRequest) SetUtmTemplate(v string) {
+	x.xxx_hidden_UtmTemplate = &v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 11, 14)
+}
+
+func (x *URLCreatorRequest) SetUtm(v *UTM) {
+	x.xxx_hidden_Utm = v
+}
+
+func (x *URLCreatorRequest) SetDomain(v string) {
+	x.xxx_hidden_Domain = &v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 13, 14)
+}
+
+func (x *URLCreatorRequest) HasOriginalUrl() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 0)
+}
+
+func (x *URLCreatorRequest) HasAlias() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 1)
+}
+
+func (x *URLCreatorRequest) HasExpiresAt() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 2)
+}
+
+func (x *URLCreatorRequest) HasTtl() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 3)
+}
+
+func (x *URLCreatorRequest) HasMaxUses() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 4)
+}
+
+func (x *URLCreatorRequest) HasPassword() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 5)
+}
+
+func (x *URLCreatorRequest) HasTitle() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 7)
+}
+
+func (x *URLCreatorRequest) HasRedirectCode() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 8)
+}
+
+func (x *URLCreatorRequest) HasPassQuery() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 9)
+}
+
+func (x *URLCreatorRequest) HasPassPath() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 10)
+}
+
+func (x *URLCreatorRequest) HasUtmTemplate() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 11)
+}
+
+func (x *URLCreatorRequest) HasUtm() bool {
+	if x == nil {
+		return false
+	}
+	return x.xxx_hidden_Utm != nil
+}
+
+func (x *URLCreatorRequest) HasDomain() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 13)
+}
+
+func (x *URLCreatorRequest) ClearOriginalUrl() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 0)
+	x.xxx_hidden_OriginalUrl = nil
+}
+
+func (x *URLCreatorRequest) ClearAlias() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 1)
+	x.xxx_hidden_Alias = nil
+}
+
+func (x *URLCreatorRequest) ClearExpiresAt() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 2)
+	x.xxx_hidden_ExpiresAt = nil
+}
+
+func (x *URLCreatorRequest) ClearTtl() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 3)
+	x.xxx_hidden_Ttl = 0
+}
+
+func (x *URLCreatorRequest) ClearMaxUses() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 4)
+	x.xxx_hidden_MaxUses = 0
+}
+
+func (x *URLCreatorRequest) ClearPassword() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 5)
+	x.xxx_hidden_Password = nil
+}
+
+func (x *URLCreatorRequest) ClearTitle() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 7)
+	x.xxx_hidden_Title = nil
+}
+
+func (x *URLCreatorRequest) ClearRedirectCode() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 8)
+	x.xxx_hidden_RedirectCode = 0
+}
+
+func (x *URLCreatorRequest) ClearPassQuery() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 9)
+	x.xxx_hidden_PassQuery = false
+}
+
+func (x *URLCreatorRequest) ClearPassPath() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 10)
+	x.xxx_hidden_PassPath = false
+}
+
+func (x *URLCreatorRequest) ClearUtmTemplate() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 11)
+	x.xxx_hidden_UtmTemplate = nil
+}
+
+func (x *URLCreatorRequest) ClearUtm() {
+	x.xxx_hidden_Utm = nil
+}
+
+func (x *URLCreatorRequest) ClearDomain() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 13)
+	x.xxx_hidden_Domain = nil
+}
+
+type URLCreatorRequest_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+	OriginalUrl *string
+	Alias       *string
+	ExpiresAt   *string
+	Ttl         *int64
+	MaxUses     *int32
+	Password    *string
+	Tags        []string
+	Title       *string
+	// redirect_code — 301, 302, 307 или 308; 0 означает 307.
+	RedirectCode *int32
+	PassQuery    *bool
+	PassPath     *bool
+	// utm_template — имя UTM-шаблона пользователя; значения utm переопределяют значения шаблона.
+	UtmTemplate *string
+	Utm         *UTM
+	// domain — короткий домен ссылки из настроенных; пустой означает основной домен.
+	Domain *string
+}
+
+func (b0 URLCreatorRequest_builder) Build() *URLCreatorRequest {
+	m0 := &URLCreatorRequest{}
+	b, x := &b0, m0
+	_, _ = b, x
+	if b.OriginalUrl != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 0, 14)
+		x.xxx_hidden_OriginalUrl = b.OriginalUrl
+	}
+	if b.Alias != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 1, 14)
+		x.xxx_hidden_Alias = b.Alias
+	}
+	if b.ExpiresAt != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 2, 14)
+		x.xxx_hidden_ExpiresAt = b.ExpiresAt
+	}
+	if b.Ttl != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 3, 14)
+		x.xxx_hidden_Ttl = *b.Ttl
+	}
+	if b.MaxUses != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 4, 14)
+		x.xxx_hidden_MaxUses = *b.MaxUses
+	}
+	if b.Password != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 5, 14)
+		x.xxx_hidden_Password = b.Password
+	}
+	x.xxx_hidden_Tags = b.Tags
+	if b.Title != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 7, 14)
+		x.xxx_hidden_Title = b.Title
+	}
+	if b.RedirectCode != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 8, 14)
+		x.xxx_hidden_RedirectCode = *b.RedirectCode
+	}
+	if b.PassQuery != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 9, 14)
+		x.xxx_hidden_PassQuery = *b.PassQuery
+	}
+	if b.PassPath != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 10, 14)
+		x.xxx_hidden_PassPath = *b.PassPath
+	}
+	if b.UtmTemplate != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 11, 14)
+		x.xxx_hidden_UtmTemplate = b.UtmTemplate
+	}
+	x.xxx_hidden_Utm = b.Utm
+	if b.Domain != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 13, 14)
+		x.xxx_hidden_Domain = b.Domain
+	}
+	return m0
+}
+
+type URLCreatorResponse struct {
+	state                  protoimpl.MessageState `protogen:"opaque.v1"`
+	xxx_hidden_ShortenUrl  *string                `protobuf:"bytes,1,opt,name=shortenUrl"`
+	XXX_raceDetectHookData protoimpl.RaceDetectHookData
+	XXX_presence           [1]uint32
+	unknownFields          protoimpl.UnknownFields
+	sizeCache              protoimpl.SizeCache
+}
+
+func (x *URLCreatorResponse) Reset() {
+	*x = URLCreatorResponse{}
+	mi := &file_url_shortener_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *URLCreatorResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*URLCreatorResponse) ProtoMessage() {}
+
+func (x *URLCreatorResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (x *URLCreatorResponse) GetShortenUrl() string {
+	if x != nil {
+		if x.xxx_hidden_ShortenUrl != nil {
+			return *x.xxx_hidden_ShortenUrl
+		}
+		return ""
+	}
+	return ""
+}
+
+func (x *URLCreatorResponse) SetShortenUrl(v string) {
+	x.xxx_hidden_ShortenUrl = &v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 0, 1)
+}
+
+func (x *URLCreatorResponse) HasShortenUrl() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 0)
+}
+
+func (x *URLCreatorResponse) ClearShortenUrl() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 0)
+	x.xxx_hidden_ShortenUrl = nil
+}
+
+type URLCreatorResponse_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+	ShortenUrl *string
+}
+
+func (b0 URLCreatorResponse_builder) Build() *URLCreatorResponse {
+	m0 := &URLCreatorResponse{}
+	b, x := &b0, m0
+	_, _ = b, x
+	if b.ShortenUrl != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 0, 1)
+		x.xxx_hidden_ShortenUrl = b.ShortenUrl
+	}
+	return m0
+}
+
+type URLCreatorJSONRequest struct {
+	state                      protoimpl.MessageState `protogen:"opaque.v1"`
+	xxx_hidden_JsonOriginalUrl *string                `protobuf:"bytes,2,opt,name=json_original_url,json=jsonOriginalUrl"`
+	xxx_hidden_Alias           *string                `protobuf:"bytes,3,opt,name=alias"`
+	xxx_hidden_ExpiresAt       *string                `protobuf:"bytes,4,opt,name=expires_at,json=expiresAt"`
+	xxx_hidden_Ttl             int64                  `protobuf:"varint,5,opt,name=ttl"`
+	xxx_hidden_MaxUses         int32                  `protobuf:"varint,6,opt,name=max_uses,json=maxUses"`
+	xxx_hidden_Password        *string                `protobuf:"bytes,7,opt,name=password"`
+	xxx_hidden_Tags            []string               `protobuf:"bytes,8,rep,name=tags"`
+	xxx_hidden_Title           *string                `protobuf:"bytes,9,opt,name=title"`
+	xxx_hidden_RedirectCode    int32                  `protobuf:"varint,10,opt,name=redirect_code,json=redirectCode"`
+	xxx_hidden_PassQuery       bool                   `protobuf:"varint,11,opt,name=pass_query,json=passQuery"`
+	xxx_hidden_PassPath        bool                   `protobuf:"varint,12,opt,name=pass_path,json=passPath"`
+	xxx_hidden_UtmTemplate     *string                `protobuf:"bytes,13,opt,name=utm_template,json=utmTemplate"`
+	xxx_hidden_Utm             *UTM                   `protobuf:"bytes,14,opt,name=utm"`
+	xxx_hidden_Domain          *string                `protobuf:"bytes,15,opt,name=domain"`
+	XXX_raceDetectHookData     protoimpl.RaceDetectHookData
+	XXX_presence               [1]uint32
+	unknownFields              protoimpl.UnknownFields
+	sizeCache                  protoimpl.SizeCache
+}
+
+func (x *URLCreatorJSONRequest) Reset() {
+	*x = URLCreatorJSONRequest{}
+	mi := &file_url_shortener_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *URLCreatorJSONRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*URLCreatorJSONRequest) ProtoMessage() {}
+
+func (x *URLCreatorJSONRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (x *URLCreatorJSONRequest) GetJsonOriginalUrl() string {
+	if x != nil {
+		if x.xxx_hidden_JsonOriginalUrl != nil {
+			return *x.xxx_hidden_JsonOriginalUrl
+		}
+		return ""
+	}
+	return ""
+}
+
+func (x *URLCreatorJSONRequest) GetAlias() string {
+	if x != nil {
+		if x.xxx_hidden_Alias != nil {
+			return *x.xxx_hidden_Alias
+		}
+		return ""
+	}
+	return ""
+}
+
+func (x *URLCreatorJSONRequest) GetExpiresAt() string {
+	if x != nil {
+		if x.xxx_hidden_ExpiresAt != nil {
+			return *x.xxx_hidden_ExpiresAt
+		}
+		return ""
+	}
+	return ""
+}
+
+func (x *URLCreatorJSONRequest) GetTtl() int64 {
+	if x != nil {
+		return x.xxx_hidden_Ttl
+	}
+	return 0
+}
+
+func (x *URLCreatorJSONRequest) GetMaxUses() int32 {
+	if x != nil {
+		return x.xxx_hidden_MaxUses
+	}
+	return 0
+}
+
+func (x *URLCreatorJSONRequest) GetPassword() string {
+	if x != nil {
+		if x.xxx_hidden_Password != nil {
+			return *x.xxx_hidden_Password
+		}
+		return ""
+	}
+	return ""
+}
+
+func (x *URLCreatorJSONRequest) GetTags() []string {
+	if x != nil {
+		return x.xxx_hidden_Tags
+	}
+	return nil
+}
+
+func (x *URLCreatorJSONRequest) GetTitle() string {
+	if x != nil {
+		if x.xxx_hidden_Title != nil {
+			return *x.xxx_hidden_Title
+		}
+		return ""
+	}
+	return ""
+}
+
+func (x *URLCreatorJSONRequest) GetRedirectCode() int32 {
+	if x != nil {
+		return x.xxx_hidden_RedirectCode
+	}
+	return 0
+}
+
+func (x *URLCreatorJSONRequest) GetPassQuery() bool {
+	if x != nil {
+		return x.xxx_hidden_PassQuery
+	}
+	return false
+}
+
+func (x *URLCreatorJSONRequest) GetPassPath() bool {
+	if x != nil {
+		return x.xxx_hidden_PassPath
+	}
+	return false
+}
+
+func (x *URLCreatorJSONRequest) GetUtmTemplate() string {
+	if x != nil {
+		if x.xxx_hidden_UtmTemplate != nil {
+			return *x.xxx_hidden_UtmTemplate
+		}
+		return ""
+	}
+	return ""
+}
+
+func (x *URLCreatorJSONRequest) GetUtm() *UTM {
+	if x != nil {
+		return x.xxx_hidden_Utm
+	}
+	return nil
+}
+
+func (x *URLCreatorJSONRequest) GetDomain() string {
+	if x != nil {
+		if x.xxx_hidden_Domain != nil {
+			return *x.xxx_hidden_Domain
+		}
+		return ""
+	}
+	return ""
+}
+
+func (x *URLCreatorJSONRequest) SetJsonOriginalUrl(v string) {
+	x.xxx_hidden_JsonOriginalUrl = &v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 0, 14)
+}
+
+func (x *URLCreatorJSONRequest) SetAlias(v string) {
+	x.xxx_hidden_Alias = &v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 1, 14)
+}
+
+func (x *URLCreatorJSONRequest) SetExpiresAt(v string) {
+	x.xxx_hidden_ExpiresAt = &v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 2, 14)
+}
+
+func (x *URLCreatorJSONRequest) SetTtl(v int64) {
+	x.xxx_hidden_Ttl = v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 3, 14)
+}
+
+func (x *URLCreatorJSONRequest) SetMaxUses(v int32) {
+	x.xxx_hidden_MaxUses = v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 4, 14)
+}
+
+func (x *URLCreatorJSONRequest) SetPassword(v string) {
+	x.xxx_hidden_Password = &v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 5, 14)
+}
+
+func (x *URLCreatorJSONRequest) SetTags(v []string) {
+	x.xxx_hidden_Tags = v
+}
+
+func (x *URLCreatorJSONRequest) SetTitle(v string) {
+	x.xxx_hidden_Title = &v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 7, 14)
+}
+
+func (x *URLCreatorJSONRequest) SetRedirectCode(v int32) {
+	x.xxx_hidden_RedirectCode = v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 8, 14)
+}
+
+func (x *URLCreatorJSONRequest) SetPassQuery(v bool) {
+	x.xxx_hidden_PassQuery = v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 9, 14)
+}
+
+func (x *URLCreatorJSONRequest) SetPassPath(v bool) {
+	x.xxx_hidden_PassPath = v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 10, 14)
+}
+
+func (x *URLCreatorJSONRequest) SetUtmTemplate(v string) {
+	x.xxx_hidden_UtmTemplate = &v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 11, 14)
+}
+
+func (x *URLCreatorJSONRequest) SetUtm(v *UTM) {
+	x.xxx_hidden_Utm = v
+}
+
+func (x *URLCreatorJSONRequest) SetDomain(v string) {
+	x.xxx_hidden_Domain = &v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 13, 14)
+}
+
+func (x *URLCreatorJSONRequest) HasJsonOriginalUrl() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 0)
+}
+
+func (x *URLCreatorJSONRequest) HasAlias() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 1)
+}
+
+func (x *URLCreatorJSONRequest) HasExpiresAt() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 2)
+}
+
+func (x *URLCreatorJSONRequest) HasTtl() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 3)
+}
+
+func (x *URLCreatorJSONRequest) HasMaxUses() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 4)
+}
+
+func (x *URLCreatorJSONRequest) HasPassword() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 5)
+}
+
+func (x *URLCreatorJSONRequest) HasTitle() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 7)
+}
+
+func (x *URLCreatorJSONRequest) HasRedirectCode() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 8)
+}
+
+func (x *URLCreatorJSONRequest) HasPassQuery() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 9)
+}
+
+func (x *URLCreatorJSONRequest) HasPassPath() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 10)
+}
+
+func (x *URLCreatorJSONRequest) HasUtmTemplate() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 11)
+}
+
+func (x *URLCreatorJSONRequest) HasUtm() bool {
+	if x == nil {
+		return false
+	}
+	return x.xxx_hidden_Utm != nil
+}
+
+func (x *URLCreatorJSONRequest) HasDomain() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 13)
+}
+
+func (x *URLCreatorJSONRequest) ClearJsonOriginalUrl() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 0)
+	x.xxx_hidden_JsonOriginalUrl = nil
+}
+
+func (x *URLCreatorJSONRequest) ClearAlias() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 1)
+	x.xxx_hidden_Alias = nil
+}
+
+func (x *URLCreatorJSONRequest) ClearExpiresAt() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 2)
+	x.xxx_hidden_ExpiresAt = nil
+}
+
+func (x *URLCreatorJSONRequest) ClearTtl() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 3)
+	x.xxx_hidden_Ttl = 0
+}
+
+func (x *URLCreatorJSONRequest) ClearMaxUses() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 4)
+	x.xxx_hidden_MaxUses = 0
+}
+
+func (x *URLCreatorJSONRequest) ClearPassword() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 5)
+	x.xxx_hidden_Password = nil
+}
+
+func (x *URLCreatorJSONRequest) ClearTitle() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 7)
+	x.xxx_hidden_Title = nil
+}
+
+func (x *URLCreatorJSONRequest) ClearRedirectCode() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 8)
+	x.xxx_hidden_RedirectCode = 0
+}
+
+func (x *URLCreatorJSONRequest) ClearPassQuery() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 9)
+	x.xxx_hidden_PassQuery = false
+}
+
+func (x *URLCreatorJSONRequest) ClearPassPath() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 10)
+	x.xxx_hidden_PassPath = false
+}
+
+func (x *URLCreatorJSONRequest) ClearUtmTemplate() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 11)
+	x.xxx_hidden_UtmTemplate = nil
+}
+
+func (x *URLCreatorJSONRequest) ClearUtm() {
+	x.xxx_hidden_Utm = nil
+}
+
+func (x *URLCreatorJSONRequest) ClearDomain() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 13)
+	x.xxx_hidden_Domain = nil
+}
+
+type URLCreatorJSONRequest_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+	JsonOriginalUrl *string
+	Alias           *string
+	ExpiresAt       *string
+	Ttl             *int64
+	MaxUses         *int32
+	Password        *string
+	Tags            []string
+	Title           *string
+	// redirect_code — 301, 302, 307 или 308; 0 означает 307.
+	RedirectCode *int32
+	PassQuery    *bool
+	PassPath     *bool
+	// utm_template — имя UTM-шаблона пользователя; значения utm переопределяют значения шаблона.
+	UtmTemplate *string
+	Utm         *UTM
+	// domain — короткий домен ссылки из настроенных; пустой означает основной домен.
+	Domain *string
+}
+
+func (b0 URLCreatorJSONRequest_builder) Build() *URLCreatorJSONRequest {
+	m0 := &URLCreatorJSONRequest{}
+	b, x := &b0, m0
+	_, _ = b, x
+	if b.JsonOriginalUrl != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 0, 14)
+		x.xxx_hidden_JsonOriginalUrl = b.JsonOriginalUrl
+	}
+	if b.Alias != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 1, 14)
+		x.xxx_hidden_Alias = b.Alias
+	}
+	if b.ExpiresAt != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 2, 14)
+		x.xxx_hidden_ExpiresAt = b.ExpiresAt
+	}
+	if b.Ttl != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 3, 14)
+		x.xxx_hidden_Ttl = *b.Ttl
+	}
+	if b.MaxUses != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 4, 14)
+		x.xxx_hidden_MaxUses = *b.MaxUses
+	}
+	if b.Password != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 5, 14)
+		x.xxx_hidden_Password = b.Password
+	}
+	x.xxx_hidden_Tags = b.Tags
+	if b.Title != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 7, 14)
+		x.xxx_hidden_Title = b.Title
+	}
+	if b.RedirectCode != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 8, 14)
+		x.xxx_hidden_RedirectCode = *b.RedirectCode
+	}
+	if b.PassQuery != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 9, 14)
+		x.xxx_hidden_PassQuery = *b.PassQuery
+	}
+	if b.PassPath != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 10, 14)
+		x.xxx_hidden_PassPath = *b.PassPath
+	}
+	if b.UtmTemplate != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 11, 14)
+		x.xxx_hidden_UtmTemplate = b.UtmTemplate
+	}
+	x.xxx_hidden_Utm = b.Utm
+	if b.Domain != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 13, 14)
+		x.xxx_hidden_Domain = b.Domain
+	}
+	return m0
+}
+
+type URLCreatorJSONResponse struct {
+	state                  protoimpl.MessageState `protogen:"opaque.v1"`
+	xxx_hidden_JsonResult  *string                `protobuf:"bytes,1,opt,name=json_result,json=jsonResult"`
+	XXX_raceDetectHookData protoimpl.RaceDetectHookData
+	XXX_presence           [1]uint32
+	unknownFields          protoimpl.UnknownFields
+	sizeCache              protoimpl.SizeCache
+}
+
+func (x *URLCreatorJSONResponse) Reset() {
+	*x = URLCreatorJSONResponse{}
+	mi := &file_url_shortener_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *URLCreatorJSONResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*URLCreatorJSONResponse) ProtoMessage() {}
+
+func (x *URLCreatorJSONResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (x *URLCreatorJSONResponse) GetJsonResult() string {
+	if x != nil {
+		if x.xxx_hidden_JsonResult != nil {
+			return *x.xxx_hidden_JsonResult
+		}
+		return ""
+	}
+	return ""
+}
+
+func (x *URLCreatorJSONResponse) SetJsonResult(v string) {
+	x.xxx_hidden_JsonResult = &v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 0, 1)
+}
+
+func (x *URLCreatorJSONResponse) HasJsonResult() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 0)
+}
+
+func (x *URLCreatorJSONResponse) ClearJsonResult() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 0)
+	x.xxx_hidden_JsonResult = nil
+}
+
+type URLCreatorJSONResponse_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+	JsonResult *string
+}
+
+func (b0 URLCreatorJSONResponse_builder) Build() *URLCreatorJSONResponse {
+	m0 := &URLCreatorJSONResponse{}
+	b, x := &b0, m0
+	_, _ = b, x
+	if b.JsonResult != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 0, 1)
+		x.xxx_hidden_JsonResult = b.JsonResult
+	}
+	return m0
+}
+
+type URLRequest struct {
+	state                    protoimpl.MessageState `protogen:"opaque.v1"`
+	xxx_hidden_CorrelationId *string                `protobuf:"bytes,1,opt,name=correlation_id,json=correlationId"`
+	xxx_hidden_OriginalUrl   *string                `protobuf:"bytes,2,opt,name=original_url,json=originalUrl"`
+	xxx_hidden_Alias         *string                `protobuf:"bytes,3,opt,name=alias"`
+	xxx_hidden_ExpiresAt     *string                `protobuf:"bytes,4,opt,name=expires_at,json=expiresAt"`
+	xxx_hidden_Ttl           int64                  `protobuf:"varint,5,opt,name=ttl"`
+	xxx_hidden_MaxUses       int32                  `protobuf:"varint,6,opt,name=max_uses,json=maxUses"`
+	xxx_hidden_Password      *string                `protobuf:"bytes,7,opt,name=password"`
+	xxx_hidden_Tags          []string               `protobuf:"bytes,8,rep,name=tags"`
+	xxx_hidden_Title         *string                `protobuf:"bytes,9,opt,name=title"`
+	xxx_hidden_RedirectCode  int32                  `protobuf:"varint,10,opt,name=redirect_code,json=redirectCode"`
+	xxx_hidden_PassQuery     bool                   `protobuf:"varint,11,opt,name=pass_query,json=passQuery"`
+	xxx_hidden_PassPath      bool                   `protobuf:"varint,12,opt,name=pass_path,json=passPath"`
+	xxx_hidden_UtmTemplate   *string                `protobuf:"bytes,13,opt,name=utm_template,json=utmTemplate"`
+	xxx_hidden_Utm           *UTM                   `protobuf:"bytes,14,opt,name=utm"`
+	xxx_hidden_Domain        *string                `protobuf:"bytes,15,opt,name=domain"`
+	XXX_raceDetectHookData   protoimpl.RaceDetectHookData
+	XXX_presence             [1]uint32
+	unknownFields            protoimpl.UnknownFields
+	sizeCache                protoimpl.SizeCache
+}
+
+func (x *URLRequest) Reset() {
+	*x = URLRequest{}
+	mi := &file_url_shortener_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *URLRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*URLRequest) ProtoMessage() {}
+
+func (x *URLRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (x *URLRequest) GetCorrelationId() string {
+	if x != nil {
+		if x.xxx_hidden_CorrelationId != nil {
+			return *x.xxx_hidden_CorrelationId
+		}
+		return ""
+	}
+	return ""
+}
+
+func (x *URLRequest) GetOriginalUrl() string {
+	if x != nil {
+		if x.xxx_hidden_OriginalUrl != nil {
+			return *x.xxx_hidden_OriginalUrl
+		}
+		return ""
+	}
+	return ""
+}
+
+func (x *URLRequest) GetAlias() string {
+	if x != nil {
+		if x.xxx_hidden_Alias != nil {
+			return *x.xxx_hidden_Alias
+		}
+		return ""
+	}
+	return ""
+}
+
+func (x *URLRequest) GetExpiresAt() string {
+	if x != nil {
+		if x.xxx_hidden_ExpiresAt != nil {
+			return *x.xxx_hidden_ExpiresAt
+		}
+		return ""
+	}
+	return ""
+}
+
+func (x *URLRequest) GetTtl() int64 {
+	if x != nil {
+		return x.xxx_hidden_Ttl
+	}
+	return 0
+}
+
+func (x *URLRequest) GetMaxUses() int32 {
+	if x != nil {
+		return x.xxx_hidden_MaxUses
+	}
+	return 0
+}
+
+func (x *URLRequest) GetPassword() string {
+	if x != nil {
+		if x.xxx_hidden_Password != nil {
+			return *x.xxx_hidden_Password
+		}
+		return ""
+	}
+	return ""
+}
+
+func (x *URLRequest) GetTags() []string {
+	if x != nil {
+		return x.xxx_hidden_Tags
+	}
+	return nil
+}
+
+func (x *URLRequest) GetTitle() string {
+	if x != nil {
+		if x.xxx_hidden_Title != nil {
+			return *x.xxx_hidden_Title
+		}
+		return ""
+	}
+	return ""
+}
+
+func (x *URLRequest) GetRedirectCode() int32 {
+	if x != nil {
+		return x.xxx_hidden_RedirectCode
+	}
+	return 0
+}
+
+func (x *URLRequest) GetPassQuery() bool {
+	if x != nil {
+		return x.xxx_hidden_PassQuery
+	}
+	return false
+}
+
+func (x *URLRequest) GetPassPath() bool {
+	if x != nil {
+		return x.xxx_hidden_PassPath
+	}
+	return false
+}
+
+func (x *URLRequest) GetUtmTemplate() string {
+	if x != nil {
+		if x.xxx_hidden_UtmTemplate != nil {
+			return *x.xxx_hidden_UtmTemplate
+		}
+		return ""
+	}
+	return ""
+}
+
+func (x *URLRequest) GetUtm() *UTM {
+	if x != nil {
+		return x.xxx_hidden_Utm
+	}
+	return nil
+}
+
+func (x *URLRequest) GetDomain() string {
+	if x != nil {
+		if x.xxx_hidden_Domain != nil {
+			return *x.xxx_hidden_Domain
+		}
+		return ""
+	}
+	return ""
+}
+
+func (x *URLRequest) SetCorrelationId(v string) {
+	x.xxx_hidden_CorrelationId = &v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 0, 15)
+}
+
+func (x *URLRequest) SetOriginalUrl(v string) {
+	x.xxx_hidden_OriginalUrl = &v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 1, 15)
+}
+
+func (x *URLRequest) SetAlias(v string) {
+	x.xxx_hidden_Alias = &v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 2, 15)
+}
+
+func (x *URLRequest) SetExpiresAt(v string) {
+	x.xxx_hidden_ExpiresAt = &v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 3, 15)
+}
+
+func (x *URLRequest) SetTtl(v int64) {
+	x.xxx_hidden_Ttl = v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 4, 15)
+}
+
+func (x *URLRequest) SetMaxUses(v int32) {
+	x.xxx_hidden_MaxUses = v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 5, 15)
+}
+
+func (x *URLRequest) SetPassword(v string) {
+	x.xxx_hidden_Password = &v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 6, 15)
+}
+
+func (x *URLRequest) SetTags(v []string) {
+	x.xxx_hidden_Tags = v
+}
+
+func (x *URLRequest) SetTitle(v string) {
+	x.xxx_hidden_Title = &v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 8, 15)
+}
+
+func (x *URLRequest) SetRedirectCode(v int32) {
+	x.xxx_hidden_RedirectCode = v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 9, 15)
+}
+
+func (x *URLRequest) SetPassQuery(v bool) {
+	x.xxx_hidden_PassQuery = v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 10, 15)
+}
+
+func (x *URLRequest) SetPassPath(v bool) {
+	x.xxx_hidden_PassPath = v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 11, 15)
+}
+
+func (x *URLRequest) SetUtmTemplate(v string) {
+	x.xxx_hidden_UtmTemplate = &v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 12, 15)
+}
+
+func (x *URLRequest) SetUtm(v *UTM) {
+	x.xxx_hidden_Utm = v
+}
+
+func (x *URLRequest) SetDomain(v string) {
+	x.xxx_hidden_Domain = &v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 14, 15)
+}
+
+func (x *URLRequest) HasCorrelationId() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 0)
+}
+
+func (x *URLRequest) HasOriginalUrl() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 1)
+}
+
+func (x *URLRequest) HasAlias() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 2)
+}
+
+func (x *URLRequest) HasExpiresAt() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 3)
+}
+
+func (x *URLRequest) HasTtl() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 4)
+}
+
+func (x *URLRequest) HasMaxUses() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 5)
+}
+
+func (x *URLRequest) HasPassword() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 6)
+}
+
+func (x *URLRequest) HasTitle() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 8)
+}
+
+func (x *URLRequest) HasRedirectCode() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 9)
+}
+
+func (x *URLRequest) HasPassQuery() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 10)
+}
+
+func (x *URLRequest) HasPassPath() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 11)
+}
+
+func (x *URLRequest) HasUtmTemplate() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 12)
+}
+
+func (x *URLRequest) HasUtm() bool {
+	if x == nil {
+		return false
+	}
+	return x.xxx_hidden_Utm != nil
+}
+
+func (x *URLRequest) HasDomain() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 14)
+}
+
+func (x *URLRequest) ClearCorrelationId() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 0)
+	x.xxx_hidden_CorrelationId = nil
+}
+
+func (x *URLRequest) ClearOriginalUrl() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 1)
+	x.xxx_hidden_OriginalUrl = nil
+}
+
+func (x *URLRequest) ClearAlias() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 2)
+	x.xxx_hidden_Alias = nil
+}
+
+func (x *URLRequest) ClearExpiresAt() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 3)
+	x.xxx_hidden_ExpiresAt = nil
+}
+
+func (x *URLRequest) ClearTtl() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 4)
+	x.xxx_hidden_Ttl = 0
+}
+
+func (x *URLRequest) ClearMaxUses() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 5)
+	x.xxx_hidden_MaxUses = 0
+}
+
+func (x *URLRequest) ClearPassword() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 6)
+	x.xxx_hidden_Password = nil
+}
+
+func (x *URLRequest) ClearTitle() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 8)
+	x.xxx_hidden_Title = nil
+}
+
+func (x *URLRequest) ClearRedirectCode() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 9)
+	x.xxx_hidden_RedirectCode = 0
+}
+
+func (x *URLRequest) ClearPassQuery() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 10)
+	x.xxx_hidden_PassQuery = false
+}
+
+func (x *URLRequest) ClearPassPath() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 11)
+	x.xxx_hidden_PassPath = false
+}
+
+func (x *URLRequest) ClearUtmTemplate() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 12)
+	x.xxx_hidden_UtmTemplate = nil
+}
+
+func (x *URLRequest) ClearUtm() {
+	x.xxx_hidden_Utm = nil
+}
+
+func (x *URLRequest) ClearDomain() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 14)
+	x.xxx_hidden_Domain = nil
+}
+
+type URLRequest_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+	CorrelationId *string
+	OriginalUrl   *string
+	Alias         *string
+	ExpiresAt     *string
+	Ttl           *int64
+	MaxUses       *int32
+	Password      *string
+	Tags          []string
+	Title         *string
+	// redirect_code — 301, 302, 307 или 308; 0 означает 307.
+	RedirectCode *int32
+	PassQuery    *bool
+	PassPath     *bool
+	// utm_template — имя UTM-шаблона пользователя; значения utm переопределяют значения шаблона.
+	UtmTemplate *string
+	Utm         *UTM
+	// domain — короткий домен ссылки из настроенных; пустой означает основной домен.
+	Domain *string
+}
+
+func (b0 URLRequest_builder) Build() *URLRequest {
+	m0 := &URLRequest{}
+	b, x := &b0, m0
+	_, _ = b, x
+	if b.CorrelationId != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 0, 15)
+		x.xxx_hidden_CorrelationId = b.CorrelationId
+	}
+	if b.OriginalUrl != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 1, 15)
+		x.xxx_hidden_OriginalUrl = b.OriginalUrl
+	}
+	if b.Alias != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 2, 15)
+		x.xxx_hidden_Alias = b.Alias
+	}
+	if b.ExpiresAt != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 3, 15)
+		x.xxx_hidden_ExpiresAt = b.ExpiresAt
+	}
+	if b.Ttl != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 4, 15)
+		x.xxx_hidden_Ttl = *b.Ttl
+	}
+	if b.MaxUses != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 5, 15)
+		x.xxx_hidden_MaxUses = *b.MaxUses
+	}
+	if b.Password != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 6, 15)
+		x.xxx_hidden_Password = b.Password
+	}
+	x.xxx_hidden_Tags = b.Tags
+	if b.Title != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 8, 15)
+		x.xxx_hidden_Title = b.Title
+	}
+	if b.RedirectCode != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 9, 15)
+		x.xxx_hidden_RedirectCode = *b.RedirectCode
+	}
+	if b.PassQuery != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 10, 15)
+		x.xxx_hidden_PassQuery = *b.PassQuery
+	}
+	if b.PassPath != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 11, 15)
+		x.xxx_hidden_PassPath = *b.PassPath
+	}
+	if b.UtmTemplate != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 12, 15)
+		x.xxx_hidden_UtmTemplate = b.UtmTemplate
+	}
+	x.xxx_hidden_Utm = b.Utm
+	if b.Domain != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 14, 15)
+		x.xxx_hidden_Domain = b.Domain
+	}
+	return m0
+}
+
+type URLResponse struct {
+	state                    protoimpl.MessageState `protogen:"opaque.v1"`
+	xxx_hidden_CorrelationId *string                `protobuf:"bytes,1,opt,name=correlation_id,json=correlationId"`
+	xxx_hidden_ShortUrl      *string                `protobuf:"bytes,2,opt,name=short_url,json=shortUrl"`
+	XXX_raceDetectHookData   protoimpl.RaceDetectHookData
+	XXX_presence             [1]uint32
+	unknownFields            protoimpl.UnknownFields
+	sizeCache                protoimpl.SizeCache
+}
+
+func (x *URLResponse) Reset() {
+	*x = URLResponse{}
+	mi := &file_url_shortener_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *URLResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*URLResponse) ProtoMessage() {}
+
+func (x *URLResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (x *URLResponse) GetCorrelationId() string {
+	if x != nil {
+		if x.xxx_hidden_CorrelationId != nil {
+			return *x.xxx_hidden_CorrelationId
+		}
+		return ""
+	}
+	return ""
+}
+
+func (x *URLResponse) GetShortUrl() string {
+	if x != nil {
+		if x.xxx_hidden_ShortUrl != nil {
+			return *x.xxx_hidden_ShortUrl
+		}
+		return ""
+	}
+	return ""
+}
+
+func (x *URLResponse) SetCorrelationId(v string) {
+	x.xxx_hidden_CorrelationId = &v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 0, 2)
+}
+
+func (x *URLResponse) SetShortUrl(v string) {
+	x.xxx_hidden_ShortUrl = &v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 1, 2)
+}
+
+func (x *URLResponse) HasCorrelationId() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 0)
+}
+
+func (x *URLResponse) HasShortUrl() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 1)
+}
+
+func (x *URLResponse) ClearCorrelationId() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 0)
+	x.xxx_hidden_CorrelationId = nil
+}
+
+func (x *URLResponse) ClearShortUrl() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 1)
+	x.xxx_hidden_ShortUrl = nil
+}
+
+type URLResponse_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+	CorrelationId *string
+	ShortUrl      *string
+}
+
+func (b0 URLResponse_builder) Build() *URLResponse {
+	m0 := &URLResponse{}
+	b, x := &b0, m0
+	_, _ = b, x
+	if b.CorrelationId != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 0, 2)
+		x.xxx_hidden_CorrelationId = b.CorrelationId
+	}
+	if b.ShortUrl != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 1, 2)
+		x.xxx_hidden_ShortUrl = b.ShortUrl
+	}
+	return m0
+}
+
+type URLCreatorBatchRequest struct {
+	state               protoimpl.MessageState `protogen:"opaque.v1"`
+	xxx_hidden_Requests *[]*URLRequest         `protobuf:"bytes,1,rep,name=requests"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *URLCreatorBatchRequest) Reset() {
+	*x = URLCreatorBatchRequest{}
+	mi := &file_url_shortener_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *URLCreatorBatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*URLCreatorBatchRequest) ProtoMessage() {}
+
+func (x *URLCreatorBatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (x *URLCreatorBatchRequest) GetRequests() []*URLRequest {
+	if x != nil {
+		if x.xxx_hidden_Requests != nil {
+			return *x.xxx_hidden_Requests
+		}
+	}
+	return nil
+}
+
+func (x *URLCreatorBatchRequest) SetRequests(v []*URLRequest) {
+	x.xxx_hidden_Requests = &v
+}
+
+type URLCreatorBatchRequest_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+	Requests []*URLRequest
+}
+
+func (b0 URLCreatorBatchRequest_builder) Build() *URLCreatorBatchRequest {
+	m0 := &URLCreatorBatchRequest{}
+	b, x := &b0, m0
+	_, _ = b, x
+	x.xxx_hidden_Requests = &b.Requests
+	return m0
+}
+
+type URLCreatorBatchResponse struct {
+	state                protoimpl.MessageState `protogen:"opaque.v1"`
+	xxx_hidden_Responses *[]*URLResponse        `protobuf:"bytes,1,rep,name=responses"`
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
+}
+
+func (x *URLCreatorBatchResponse) Reset() {
+	*x = URLCreatorBatchResponse{}
+	mi := &file_url_shortener_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *URLCreatorBatchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*URLCreatorBatchResponse) ProtoMessage() {}
+
+func (x *URLCreatorBatchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (x *URLCreatorBatchResponse) GetResponses() []*URLResponse {
+	if x != nil {
+		if x.xxx_hidden_Responses != nil {
+			return *x.xxx_hidden_Responses
+		}
+	}
+	return nil
+}
+
+func (x *URLCreatorBatchResponse) SetResponses(v []*URLResponse) {
+	x.xxx_hidden_Responses = &v
+}
+
+type URLCreatorBatchResponse_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+	Responses []*URLResponse
+}
+
+func (b0 URLCreatorBatchResponse_builder) Build() *URLCreatorBatchResponse {
+	m0 := &URLCreatorBatchResponse{}
+	b, x := &b0, m0
+	_, _ = b, x
+	x.xxx_hidden_Responses = &b.Responses
+	return m0
+}
+
+type GetUserURLsRequest struct {
+	state           protoimpl.MessageState `protogen:"opaque.v1"`
+	xxx_hidden_Tags []string               `protobuf:"bytes,1,rep,name=tags"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *GetUserURLsRequest) Reset() {
+	*x = GetUserURLsRequest{}
+	mi := &file_url_shortener_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUserURLsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUserURLsRequest) ProtoMessage() {}
+
+func (x *GetUserURLsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (x *GetUserURLsRequest) GetTags() []string {
+	if x != nil {
+		return x.xxx_hidden_Tags
+	}
+	return nil
+}
+
+func (x *GetUserURLsRequest) SetTags(v []string) {
+	x.xxx_hidden_Tags = v
+}
+
+type GetUserURLsRequest_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+	// tags оставляет только ссылки, у которых есть все перечисленные теги.
+	Tags []string
+}
+
+func (b0 GetUserURLsRequest_builder) Build() *GetUserURLsRequest {
+	m0 := &GetUserURLsRequest{}
+	b, x := &b0, m0
+	_, _ = b, x
+	x.xxx_hidden_Tags = b.Tags
+	return m0
+}
+
+type UserURL struct {
+	state                    protoimpl.MessageState `protogen:"opaque.v1"`
+	xxx_hidden_ShortUrl      *string                `protobuf:"bytes,1,opt,name=short_url,json=shortUrl"`
+	xxx_hidden_OriginalUrl   *string                `protobuf:"bytes,2,opt,name=original_url,json=originalUrl"`
+	xxx_hidden_ExpiresAt     *string                `protobuf:"bytes,3,opt,name=expires_at,json=expiresAt"`
+	xxx_hidden_RemainingUses int32                  `protobuf:"varint,4,opt,name=remaining_uses,json=remainingUses"`
+	xxx_hidden_Clicks        int64                  `protobuf:"varint,5,opt,name=clicks"`
+	xxx_hidden_Tags          []string               `protobuf:"bytes,6,rep,name=tags"`
+	xxx_hidden_CreatedAt     *string                `protobuf:"bytes,7,opt,name=created_at,json=createdAt"`
+	xxx_hidden_Title         *string                `protobuf:"bytes,8,opt,name=title"`
+	XXX_raceDetectHookData   protoimpl.RaceDetectHookData
+	XXX_presence             [1]uint32
+	unknownFields            protoimpl.UnknownFields
+	sizeCache                protoimpl.SizeCache
+}
+
+func (x *UserURL) Reset() {
+	*x = UserURL{}
+	mi := &file_url_shortener_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UserURL) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserURL) ProtoMessage() {}
+
+func (x *UserURL) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (x *UserURL) GetShortUrl() string {
+	if x != nil {
+		if x.xxx_hidden_ShortUrl != nil {
+			return *x.xxx_hidden_ShortUrl
+		}
+		return ""
+	}
+	return ""
+}
+
+func (x *UserURL) GetOriginalUrl() string {
+	if x != nil {
+		if x.xxx_hidden_OriginalUrl != nil {
+			return *x.xxx_hidden_OriginalUrl
+		}
+		return ""
+	}
+	return ""
+}
+
+func (x *UserURL) GetExpiresAt() string {
+	if x != nil {
+		if x.xxx_hidden_ExpiresAt != nil {
+			return *x.xxx_hidden_ExpiresAt
+		}
+		return ""
+	}
+	return ""
+}
+
+func (x *UserURL) GetRemainingUses() int32 {
+	if x != nil {
+		return x.xxx_hidden_RemainingUses
+	}
+	return 0
+}
+
+func (x *UserURL) GetClicks() int64 {
+	if x != nil {
+		return x.xxx_hidden_Clicks
+	}
+	return 0
+}
+
+func (x *UserURL) GetTags() []string {
+	if x != nil {
+		return x.xxx_hidden_Tags
+	}
+	return nil
+}
+
+func (x *UserURL) GetCreatedAt() string {
+	if x != nil {
+		if x.xxx_hidden_CreatedAt != nil {
+			return *x.xxx_hidden_CreatedAt
+		}
+		return ""
+	}
+	return ""
+}
+
+func (x *UserURL) GetTitle() string {
+	if x != nil {
+		if x.xxx_hidden_Title != nil {
+			return *x.xxx_hidden_Title
+		}
+		return ""
+	}
+	return ""
+}
+
+func (x *UserURL) SetShortUrl(v string) {
+	x.xxx_hidden_ShortUrl = &v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 0, 8)
+}
+
+func (x *UserURL) SetOriginalUrl(v string) {
+	x.xxx_hidden_OriginalUrl = &v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 1, 8)
+}
+
+func (x *UserURL) SetExpiresAt(v string) {
+	x.xxx_hidden_ExpiresAt = &v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 2, 8)
+}
+
+func (x *UserURL) SetRemainingUses(v int32) {
+	x.xxx_hidden_RemainingUses = v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 3, 8)
+}
+
+func (x *UserURL) SetClicks(v int64) {
+	x.xxx_hidden_Clicks = v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 4, 8)
+}
+
+func (x *UserURL) SetTags(v []string) {
+	x.xxx_hidden_Tags = v
+}
+
+func (x *UserURL) SetCreatedAt(v string) {
+	x.xxx_hidden_CreatedAt = &v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 6, 8)
+}
+
+func (x *UserURL) SetTitle(v string) {
+	x.xxx_hidden_Title = &v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 7, 8)
+}
+
+func (x *UserURL) HasShortUrl() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 0)
+}
+
+func (x *UserURL) HasOriginalUrl() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 1)
+}
+
+func (x *UserURL) HasExpiresAt() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 2)
+}
+
+func (x *UserURL) HasRemainingUses() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 3)
+}
+
+func (x *UserURL) HasClicks() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 4)
+}
+
+func (x *UserURL) HasCreatedAt() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 6)
+}
+
+func (x *UserURL) HasTitle() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 7)
+}
+
+func (x *UserURL) ClearShortUrl() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 0)
+	x.xxx_hidden_ShortUrl = nil
+}
+
+func (x *UserURL) ClearOriginalUrl() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 1)
+	x.xxx_hidden_OriginalUrl = nil
+}
+
+func (x *UserURL) ClearExpiresAt() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 2)
+	x.xxx_hidden_ExpiresAt = nil
+}
+
+func (x *UserURL) ClearRemainingUses() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 3)
+	x.xxx_hidden_RemainingUses = 0
+}
+
+func (x *UserURL) ClearClicks() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 4)
+	x.xxx_hidden_Clicks = 0
+}
+
+func (x *UserURL) ClearCreatedAt() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 6)
+	x.xxx_hidden_CreatedAt = nil
+}
+
+func (x *UserURL) ClearTitle() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 7)
+	x.xxx_hidden_Title = nil
+}
+
+type UserURL_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+	ShortUrl      *string
+	OriginalUrl   *string
+	ExpiresAt     *string
+	RemainingUses *int32
+	Clicks        *int64
+	Tags          []string
+	CreatedAt     *string
+	Title         *string
+}
+
+func (b0 UserURL_builder) Build() *UserURL {
+	m0 := &UserURL{}
+	b, x := &b0, m0
+	_, _ = b, x
+	if b.ShortUrl != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 0, 8)
+		x.xxx_hidden_ShortUrl = b.ShortUrl
+	}
+	if b.OriginalUrl != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 1, 8)
+		x.xxx_hidden_OriginalUrl = b.OriginalUrl
+	}
+	if b.ExpiresAt != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 2, 8)
+		x.xxx_hidden_ExpiresAt = b.ExpiresAt
+	}
+	if b.RemainingUses != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 3, 8)
+		x.xxx_hidden_RemainingUses = *b.RemainingUses
+	}
+	if b.Clicks != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 4, 8)
+		x.xxx_hidden_Clicks = *b.Clicks
+	}
+	x.xxx_hidden_Tags = b.Tags
+	if b.CreatedAt != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 6, 8)
+		x.xxx_hidden_CreatedAt = b.CreatedAt
+	}
+	if b.Title != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 7, 8)
+		x.xxx_hidden_Title = b.Title
+	}
+	return m0
+}
+
+type GetUserURLsResponse struct {
+	state           protoimpl.MessageState `protogen:"opaque.v1"`
+	xxx_hidden_Urls *[]*UserURL            `protobuf:"bytes,1,rep,name=urls"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *GetUserURLsResponse) Reset() {
+	*x = GetUserURLsResponse{}
+	mi := &file_url_shortener_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUserURLsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUserURLsResponse) ProtoMessage() {}
+
+func (x *GetUserURLsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (x *GetUserURLsResponse) GetUrls() []*UserURL {
+	if x != nil {
+		if x.xxx_hidden_Urls != nil {
+			return *x.xxx_hidden_Urls
+		}
+	}
+	return nil
+}
+
+func (x *GetUserURLsResponse) SetUrls(v []*UserURL) {
+	x.xxx_hidden_Urls = &v
+}
+
+type GetUserURLsResponse_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+	Urls []*UserURL
+}
+
+func (b0 GetUserURLsResponse_builder) Build() *GetUserURLsResponse {
+	m0 := &GetUserURLsResponse{}
+	b, x := &b0, m0
+	_, _ = b, x
+	x.xxx_hidden_Urls = &b.Urls
+	return m0
+}
+
+type ListUserURLsRequest struct {
+	state                  protoimpl.MessageState `protogen:"opaque.v1"`
+	xxx_hidden_PageSize    int32                  `protobuf:"varint,1,opt,name=page_size,json=pageSize"`
+	xxx_hidden_PageToken   *string                `protobuf:"bytes,2,opt,name=page_token,json=pageToken"`
+	xxx_hidden_Sort        *string                `protobuf:"bytes,3,opt,name=sort"`
+	xxx_hidden_Order       *string                `protobuf:"bytes,4,opt,name=order"`
+	xxx_hidden_Search      *string                `protobuf:"bytes,5,opt,name=search"`
+	xxx_hidden_Url         *string                `protobuf:"bytes,6,opt,name=url"`
+	xxx_hidden_Tags        []string               `protobuf:"bytes,7,rep,name=tags"`
+	XXX_raceDetectHookData protoimpl.RaceDetectHookData
+	XXX_presence           [1]uint32
+	unknownFields          protoimpl.UnknownFields
+	sizeCache              protoimpl.SizeCache
+}
+
+func (x *ListUserURLsRequest) Reset() {
+	*x = ListUserURLsRequest{}
+	mi := &file_url_shortener_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListUserURLsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListUserURLsRequest) ProtoMessage() {}
+
+func (x *ListUserURLsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (x *ListUserURLsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.xxx_hidden_PageSize
+	}
+	return 0
+}
+
+func (x *ListUserURLsRequest) GetPageToken() string {
+	if x != nil {
+		if x.xxx_hidden_PageToken != nil {
+			return *x.xxx_hidden_PageToken
+		}
+		return ""
+	}
+	return ""
+}
+
+func (x *ListUserURLsRequest) GetSort() string {
+	if x != nil {
+		if x.xxx_hidden_Sort != nil {
+			return *x.xxx_hidden_Sort
+		}
+		return ""
+	}
+	return ""
+}
+
+func (x *ListUserURLsRequest) GetOrder() string {
+	if x != nil {
+		if x.xxx_hidden_Order != nil {
+			return *x.xxx_hidden_Order
+		}
+		return ""
+	}
+	return ""
+}
+
+func (x *ListUserURLsRequest) GetSearch() string {
+	if x != nil {
+		if x.xxx_hidden_Search != nil {
+			return *x.xxx_hidden_Search
+		}
+		return ""
+	}
+	return ""
+}
+
+func (x *ListUserURLsRequest) GetUrl() string {
+	if x != nil {
+		if x.xxx_hidden_Url != nil {
+			return *x.xxx_hidden_Url
+		}
+		return ""
+	}
+	return ""
+}
+
+func (x *ListUserURLsRequest) GetTags() []string {
+	if x != nil {
+		return x.xxx_hidden_Tags
+	}
+	return nil
+}
+
+func (x *ListUserURLsRequest) SetPageSize(v int32) {
+	x.xxx_hidden_PageSize = v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 0, 7)
+}
+
+func (x *ListUserURLsRequest) SetPageToken(v string) {
+	x.xxx_hidden_PageToken = &v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 1, 7)
+}
+
+func (x *ListUserURLsRequest) SetSort(v string) {
+	x.xxx_hidden_Sort = &v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 2, 7)
+}
+
+func (x *ListUserURLsRequest) SetOrder(v string) {
+	x.xxx_hidden_Order = &v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 3, 7)
+}
+
+func (x *ListUserURLsRequest) SetSearch(v string) {
+	x.xxx_hidden_Search = &v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 4, 7)
+}
+
+func (x *ListUserURLsRequest) SetUrl(v string) {
+	x.xxx_hidden_Url = &v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 5, 7)
+}
+
+func (x *ListUserURLsRequest) SetTags(v []string) {
+	x.xxx_hidden_Tags = v
+}
+
+func (x *ListUserURLsRequest) HasPageSize() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 0)
+}
+
+func (x *ListUserURLsRequest) HasPageToken() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 1)
+}
+
+func (x *ListUserURLsRequest) HasSort() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 2)
+}
+
+func (x *ListUserURLsRequest) HasOrder() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 3)
+}
+
+func (x *ListUserURLsRequest) HasSearch() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 4)
+}
+
+func (x *ListUserURLsRequest) HasUrl() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 5)
+}
+
+func (x *ListUserURLsRequest) ClearPageSize() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 0)
+	x.xxx_hidden_PageSize = 0
+}
+
+func (x *ListUserURLsRequest) ClearPageToken() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 1)
+	x.xxx_hidden_PageToken = nil
+}
+
+func (x *ListUserURLsRequest) ClearSort() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 2)
+	x.xxx_hidden_Sort = nil
+}
+
+func (x *ListUserURLsRequest) ClearOrder() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 3)
+	x.xxx_hidden_Order = nil
+}
+
+func (x *ListUserURLsRequest) ClearSearch() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 4)
+	x.xxx_hidden_Search = nil
+}
+
+func (x *ListUserURLsRequest) ClearUrl() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 5)
+	x.xxx_hidden_Url = nil
+}
+
+type ListUserURLsRequest_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+	// page_size — размер страницы; 0 означает размер по умолчанию.
+	PageSize *int32
+	// page_token — next_page_token из предыдущего ответа.
+	PageToken *string
+	// sort — "created" (по умолчанию) или "destination"; order — "asc" или "desc".
+	Sort  *string
+	Order *string
+	// search — подстрока оригинального URL без учёта регистра.
+	Search *string
+	// url — обратный поиск: ссылка пользователя на этот URL.
+	Url  *string
+	Tags []string
+}
+
+func (b0 ListUserURLsRequest_builder) Build() *ListUserURLsRequest {
+	m0 := &ListUserURLsRequest{}
+	b, x := &b0, m0
+	_, _ = b, x
+	if b.PageSize != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 0, 7)
+		x.xxx_hidden_PageSize = *b.PageSize
+	}
+	if b.PageToken != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 1, 7)
+		x.xxx_hidden_PageToken = b.PageToken
+	}
+	if b.Sort != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 2, 7)
+		x.xxx_hidden_Sort = b.Sort
+	}
+	if b.Order != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 3, 7)
+		x.xxx_hidden_Order = b.Order
+	}
+	if b.Search != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 4, 7)
+		x.xxx_hidden_Search = b.Search
+	}
+	if b.Url != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 5, 7)
+		x.xxx_hidden_Url = b.Url
+	}
+	x.xxx_hidden_Tags = b.Tags
+	return m0
+}
+
+type ListUserURLsResponse struct {
+	state                    protoimpl.MessageState `protogen:"opaque.v1"`
+	xxx_hidden_Urls          *[]*UserURL            `protobuf:"bytes,1,rep,name=urls"`
+	xxx_hidden_NextPageToken *string                `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken"`
+	XXX_raceDetectHookData   protoimpl.RaceDetectHookData
+	XXX_presence             [1]uint32
+	unknownFields            protoimpl.UnknownFields
+	sizeCache                protoimpl.SizeCache
+}
+
+func (x *ListUserURLsResponse) Reset() {
+	*x = ListUserURLsResponse{}
+	mi := &file_url_shortener_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListUserURLsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListUserURLsResponse) ProtoMessage() {}
+
+func (x *ListUserURLsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (x *ListUserURLsResponse) GetUrls() []*UserURL {
+	if x != nil {
+		if x.xxx_hidden_Urls != nil {
+			return *x.xxx_hidden_Urls
+		}
+	}
+	return nil
+}
+
+func (x *ListUserURLsResponse) GetNextPageToken() string {
+	if x != nil {
+		if x.xxx_hidden_NextPageToken != nil {
+			return *x.xxx_hidden_NextPageToken
+		}
+		return ""
+	}
+	return ""
+}
+
+func (x *ListUserURLsResponse) SetUrls(v []*UserURL) {
+	x.xxx_hidden_Urls = &v
+}
+
+func (x *ListUserURLsResponse) SetNextPageToken(v string) {
+	x.xxx_hidden_NextPageToken = &v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 1, 2)
+}
+
+func (x *ListUserURLsResponse) HasNextPageToken() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 1)
+}
+
+func (x *ListUserURLsResponse) ClearNextPageToken() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 1)
+	x.xxx_hidden_NextPageToken = nil
+}
+
+type ListUserURLsResponse_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+	Urls []*UserURL
+	// next_page_token пуст на последней странице.
+	NextPageToken *string
+}
+
+func (b0 ListUserURLsResponse_builder) Build() *ListUserURLsResponse {
+	m0 := &ListUserURLsResponse{}
+	b, x := &b0, m0
+	_, _ = b, x
+	x.xxx_hidden_Urls = &b.Urls
+	if b.NextPageToken != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 1, 2)
+		x.xxx_hidden_NextPageToken = b.NextPageToken
+	}
+	return m0
+}
+
+type DeleteUserURLsRequest struct {
+	state           protoimpl.MessageState `protogen:"opaque.v1"`
+	xxx_hidden_Urls []string               `protobuf:"bytes,1,rep,name=urls"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *DeleteUserURLsRequest) Reset() {
+	*x = DeleteUserURLsRequest{}
+	mi := &file_url_shortener_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteUserURLsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteUserURLsRequest) ProtoMessage() {}
+
+func (x *DeleteUserURLsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (x *DeleteUserURLsRequest) GetUrls() []string {
+	if x != nil {
+		return x.xxx_hidden_Urls
+	}
+	return nil
+}
+
+func (x *DeleteUserURLsRequest) SetUrls(v []string) {
+	x.xxx_hidden_Urls = v
+}
+
+type DeleteUserURLsRequest_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+	// urls — коды ссылок; ссылки дополнительных доменов передаются как "домен/код".
+	// То же относится к short_url в остальных запросах управления ссылками.
+	Urls []string
+}
+
+func (b0 DeleteUserURLsRequest_builder) Build() *DeleteUserURLsRequest {
+	m0 := &DeleteUserURLsRequest{}
+	b, x := &b0, m0
+	_, _ = b, x
+	x.xxx_hidden_Urls = b.Urls
+	return m0
+}
+
+type DeleteUserURLsResponse struct {
+	state         protoimpl.MessageState `protogen:"opaque.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteUserURLsResponse) Reset() {
+	*x = DeleteUserURLsResponse{}
+	mi := &file_url_shortener_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteUserURLsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteUserURLsResponse) ProtoMessage() {}
+
+func (x *DeleteUserURLsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+type DeleteUserURLsResponse_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+}
+
+func (b0 DeleteUserURLsResponse_builder) Build() *DeleteUserURLsResponse {
+	m0 := &DeleteUserURLsResponse{}
+	b, x := &b0, m0
+	_, _ = b, x
+	return m0
+}
+
+type RestoreUserURLsRequest struct {
+	state           protoimpl.MessageState `protogen:"opaque.v1"`
+	xxx_hidden_Urls []string               `protobuf:"bytes,1,rep,name=urls"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *RestoreUserURLsRequest) Reset() {
+	*x = RestoreUserURLsRequest{}
+	mi := &file_url_shortener_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestoreUserURLsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreUserURLsRequest) ProtoMessage() {}
+
+func (x *RestoreUserURLsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (x *RestoreUserURLsRequest) GetUrls() []string {
+	if x != nil {
+		return x.xxx_hidden_Urls
+	}
+	return nil
+}
+
+func (x *RestoreUserURLsRequest) SetUrls(v []string) {
+	x.xxx_hidden_Urls = v
+}
+
+type RestoreUserURLsRequest_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+	Urls []string
+}
+
+func (b0 RestoreUserURLsRequest_builder) Build() *RestoreUserURLsRequest {
+	m0 := &RestoreUserURLsRequest{}
+	b, x := &b0, m0
+	_, _ = b, x
+	x.xxx_hidden_Urls = b.Urls
+	return m0
+}
+
+type RestoreUserURLsResponse struct {
+	state               protoimpl.MessageState `protogen:"opaque.v1"`
+	xxx_hidden_Restored []string               `protobuf:"bytes,1,rep,name=restored"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *RestoreUserURLsResponse) Reset() {
+	*x = RestoreUserURLsResponse{}
+	mi := &file_url_shortener_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestoreUserURLsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreUserURLsResponse) ProtoMessage() {}
+
+func (x *RestoreUserURLsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (x *RestoreUserURLsResponse) GetRestored() []string {
+	if x != nil {
+		return x.xxx_hidden_Restored
+	}
+	return nil
+}
+
+func (x *RestoreUserURLsResponse) SetRestored(v []string) {
+	x.xxx_hidden_Restored = v
+}
+
+type RestoreUserURLsResponse_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+	Restored []string
+}
+
+func (b0 RestoreUserURLsResponse_builder) Build() *RestoreUserURLsResponse {
+	m0 := &RestoreUserURLsResponse{}
+	b, x := &b0, m0
+	_, _ = b, x
+	x.xxx_hidden_Restored = b.Restored
+	return m0
+}
+
+type GetStatsRequest struct {
+	state         protoimpl.MessageState `protogen:"opaque.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetStatsRequest) Reset() {
+	*x = GetStatsRequest{}
+	mi := &file_url_shortener_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStatsRequest) ProtoMessage() {}
+
+func (x *GetStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+type GetStatsRequest_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+}
+
+func (b0 GetStatsRequest_builder) Build() *GetStatsRequest {
+	m0 := &GetStatsRequest{}
+	b, x := &b0, m0
+	_, _ = b, x
+	return m0
+}
+
+type GetStatsResponse struct {
+	state                  protoimpl.MessageState `protogen:"opaque.v1"`
+	xxx_hidden_TotalUrls   int32                  `protobuf:"varint,1,opt,name=total_urls,json=totalUrls"`
+	xxx_hidden_TotalUsers  int32                  `protobuf:"varint,2,opt,name=total_users,json=totalUsers"`
+	xxx_hidden_TotalClicks int64                  `protobuf:"varint,3,opt,name=total_clicks,json=totalClicks"`
+	XXX_raceDetectHookData protoimpl.RaceDetectHookData
+	XXX_presence           [1]uint32
+	unknownFields          protoimpl.UnknownFields
+	sizeCache              protoimpl.SizeCache
+}
+
+func (x *GetStatsResponse) Reset() {
+	*x = GetStatsResponse{}
+	mi := &file_url_shortener_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetStatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStatsResponse) ProtoMessage() {}
+
+func (x *GetStatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (x *GetStatsResponse) GetTotalUrls() int32 {
+	if x != nil {
+		return x.xxx_hidden_TotalUrls
+	}
+	return 0
+}
+
+func (x *GetStatsResponse) GetTotalUsers() int32 {
+	if x != nil {
+		return x.xxx_hidden_TotalUsers
+	}
+	return 0
+}
+
+func (x *GetStatsResponse) GetTotalClicks() int64 {
+	if x != nil {
+		return x.xxx_hidden_TotalClicks
+	}
+	return 0
+}
+
+func (x *GetStatsResponse) SetTotalUrls(v int32) {
+	x.xxx_hidden_TotalUrls = v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 0, 3)
+}
+
+func (x *GetStatsResponse) SetTotalUsers(v int32) {
+	x.xxx_hidden_TotalUsers = v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 1, 3)
+}
+
+func (x *GetStatsResponse) SetTotalClicks(v int64) {
+	x.xxx_hidden_TotalClicks = v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 2, 3)
+}
+
+func (x *GetStatsResponse) HasTotalUrls() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 0)
+}
+
+func (x *GetStatsResponse) HasTotalUsers() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 1)
+}
+
+func (x *GetStatsResponse) HasTotalClicks() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 2)
+}
+
+func (x *GetStatsResponse) ClearTotalUrls() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 0)
+	x.xxx_hidden_TotalUrls = 0
+}
+
+func (x *GetStatsResponse) ClearTotalUsers() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 1)
+	x.xxx_hidden_TotalUsers = 0
+}
+
+func (x *GetStatsResponse) ClearTotalClicks() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 2)
+	x.xxx_hidden_TotalClicks = 0
+}
+
+type GetStatsResponse_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+	TotalUrls   *int32
+	TotalUsers  *int32
+	TotalClicks *int64
+}
+
+func (b0 GetStatsResponse_builder) Build() *GetStatsResponse {
+	m0 := &GetStatsResponse{}
+	b, x := &b0, m0
+	_, _ = b, x
+	if b.TotalUrls != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 0, 3)
+		x.xxx_hidden_TotalUrls = *b.TotalUrls
+	}
+	if b.TotalUsers != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 1, 3)
+		x.xxx_hidden_TotalUsers = *b.TotalUsers
+	}
+	if b.TotalClicks != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 2, 3)
+		x.xxx_hidden_TotalClicks = *b.TotalClicks
+	}
+	return m0
+}
+
+type GetURLStatsRequest struct {
+	state                  protoimpl.MessageState `protogen:"opaque.v1"`
+	xxx_hidden_ShortUrl    *string                `protobuf:"bytes,1,opt,name=short_url,json=shortUrl"`
+	xxx_hidden_Domain      *string                `protobuf:"bytes,2,opt,name=domain"`
+	XXX_raceDetectHookData protoimpl.RaceDetectHookData
+	XXX_presence           [1]uint32
+	unknownFields          protoimpl.UnknownFields
+	sizeCache              protoimpl.SizeCache
+}
+
+func (x *GetURLStatsRequest) Reset() {
+	*x = GetURLStatsRequest{}
+	mi := &file_url_shortener_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetURLStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetURLStatsRequest) ProtoMessage() {}
+
+func (x *GetURLStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (x *GetURLStatsRequest) GetShortUrl() string {
+	if x != nil {
+		if x.xxx_hidden_ShortUrl != nil {
+			return *x.xxx_hidden_ShortUrl
+		}
+		return ""
+	}
+	return ""
+}
+
+func (x *GetURLStatsRequest) GetDomain() string {
+	if x != nil {
+		if x.xxx_hidden_Domain != nil {
+			return *x.xxx_hidden_Domain
+		}
+		return ""
+	}
+	return ""
+}
+
+func (x *GetURLStatsRequest) SetShortUrl(v string) {
+	x.xxx_hidden_ShortUrl = &v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 0, 2)
+}
+
+func (x *GetURLStatsRequest) SetDomain(v string) {
+	x.xxx_hidden_Domain = &v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 1, 2)
+}
+
+func (x *GetURLStatsRequest) HasShortUrl() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 0)
+}
+
+func (x *GetURLStatsRequest) HasDomain() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 1)
+}
+
+func (x *GetURLStatsRequest) ClearShortUrl() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 0)
+	x.xxx_hidden_ShortUrl = nil
+}
+
+func (x *GetURLStatsRequest) ClearDomain() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 1)
+	x.xxx_hidden_Domain = nil
+}
+
+type GetURLStatsRequest_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+	ShortUrl *string
+	// domain — короткий домен ссылки из настроенных; пустой означает основной домен.
+	Domain *string
+}
+
+func (b0 GetURLStatsRequest_builder) Build() *GetURLStatsRequest {
+	m0 := &GetURLStatsRequest{}
+	b, x := &b0, m0
+	_, _ = b, x
+	if b.ShortUrl != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 0, 2)
+		x.xxx_hidden_ShortUrl = b.ShortUrl
+	}
+	if b.Domain != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 1, 2)
+		x.xxx_hidden_Domain = b.Domain
+	}
+	return m0
+}
+
+type ClickCount struct {
+	state                  protoimpl.MessageState `protogen:"opaque.v1"`
+	xxx_hidden_Key         *string                `protobuf:"bytes,1,opt,name=key"`
+	xxx_hidden_Count       int64                  `protobuf:"varint,2,opt,name=count"`
+	XXX_raceDetectHookData protoimpl.RaceDetectHookData
+	XXX_presence           [1]uint32
+	unknownFields          protoimpl.UnknownFields
+	sizeCache              protoimpl.SizeCache
+}
+
+func (x *ClickCount) Reset() {
+	*x = ClickCount{}
+	mi := &file_url_shortener_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClickCount) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClickCount) ProtoMessage() {}
+
+func (x *ClickCount) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (x *ClickCount) GetKey() string {
+	if x != nil {
+		if x.xxx_hidden_Key != nil {
+			return *x.xxx_hidden_Key
+		}
+		return ""
+	}
+	return ""
+}
+
+func (x *ClickCount) GetCount() int64 {
+	if x != nil {
+		return x.xxx_hidden_Count
+	}
+	return 0
+}
+
+func (x *ClickCount) SetKey(v string) {
+	x.xxx_hidden_Key = &v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 0, 2)
+}
+
+func (x *ClickCount) SetCount(v int64) {
+	x.xxx_hidden_Count = v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 1, 2)
+}
+
+func (x *ClickCount) HasKey() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 0)
+}
+
+func (x *ClickCount) HasCount() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 1)
+}
+
+func (x *ClickCount) ClearKey() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 0)
+	x.xxx_hidden_Key = nil
+}
+
+func (x *ClickCount) ClearCount() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 1)
+	x.xxx_hidden_Count = 0
+}
+
+type ClickCount_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+	Key   *string
+	Count *int64
+}
+
+func (b0 ClickCount_builder) Build() *ClickCount {
+	m0 := &ClickCount{}
+	b, x := &b0, m0
+	_, _ = b, x
+	if b.Key != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 0, 2)
+		x.xxx_hidden_Key = b.Key
+	}
+	if b.Count != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 1, 2)
+		x.xxx_hidden_Count = *b.Count
+	}
+	return m0
+}
+
+type GetURLStatsResponse struct {
+	state                  protoimpl.MessageState `protogen:"opaque.v1"`
+	xxx_hidden_Total       int64                  `protobuf:"varint,1,opt,name=total"`
+	xxx_hidden_ByDay       *[]*ClickCount         `protobuf:"bytes,2,rep,name=by_day,json=byDay"`
+	xxx_hidden_ByReferrer  *[]*ClickCount         `protobuf:"bytes,3,rep,name=by_referrer,json=byReferrer"`
+	xxx_hidden_ByUserAgent *[]*ClickCount         `protobuf:"bytes,4,rep,name=by_user_agent,json=byUserAgent"`
+	xxx_hidden_ByVariant   *[]*ClickCount         `protobuf:"bytes,5,rep,name=by_variant,json=byVariant"`
+	XXX_raceDetectHookData protoimpl.RaceDetectHookData
+	XXX_presence           [1]uint32
+	unknownFields          protoimpl.UnknownFields
+	sizeCache              protoimpl.SizeCache
+}
+
+func (x *GetURLStatsResponse) Reset() {
+	*x = GetURLStatsResponse{}
+	mi := &file_url_shortener_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetURLStatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetURLStatsResponse) ProtoMessage() {}
+
+func (x *GetURLStatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (x *GetURLStatsResponse) GetTotal() int64 {
+	if x != nil {
+		return x.xxx_hidden_Total
+	}
+	return 0
+}
+
+func (x *GetURLStatsResponse) GetByDay() []*ClickCount {
+	if x != nil {
+		if x.xxx_hidden_ByDay != nil {
+			return *x.xxx_hidden_ByDay
+		}
+	}
+	return nil
+}
+
+func (x *GetURLStatsResponse) GetByReferrer() []*ClickCount {
+	if x != nil {
+		if x.xxx_hidden_ByReferrer != nil {
+			return *x.xxx_hidden_ByReferrer
+		}
+	}
+	return nil
+}
+
+func (x *GetURLStatsResponse) GetByUserAgent() []*ClickCount {
+	if x != nil {
+		if x.xxx_hidden_ByUserAgent != nil {
+			return *x.xxx_hidden_ByUserAgent
+		}
+	}
+	return nil
+}
+
+func (x *GetURLStatsResponse) GetByVariant() []*ClickCount {
+	if x != nil {
+		if x.xxx_hidden_ByVariant != nil {
+			return *x.xxx_hidden_ByVariant
+		}
+	}
+	return nil
+}
+
+func (x *GetURLStatsResponse) SetTotal(v int64) {
+	x.xxx_hidden_Total = v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 0, 5)
+}
+
+func (x *GetURLStatsResponse) SetByDay(v []*ClickCount) {
+	x.xxx_hidden_ByDay = &v
+}
+
+func (x *GetURLStatsResponse) SetByReferrer(v []*ClickCount) {
+	x.xxx_hidden_ByReferrer = &v
+}
+
+func (x *GetURLStatsResponse) SetByUserAgent(v []*ClickCount) {
+	x.xxx_hidden_ByUserAgent = &v
+}
+
+func (x *GetURLStatsResponse) SetByVariant(v []*ClickCount) {
+	x.xxx_hidden_ByVariant = &v
+}
+
+func (x *GetURLStatsResponse) HasTotal() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 0)
+}
+
+func (x *GetURLStatsResponse) ClearTotal() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 0)
+	x.xxx_hidden_Total = 0
+}
+
+type GetURLStatsResponse_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+	Total       *int64
+	ByDay       []*ClickCount
+	ByReferrer  []*ClickCount
+	ByUserAgent []*ClickCount
+	ByVariant   []*ClickCount
+}
+
+func (b0 GetURLStatsResponse_builder) Build() *GetURLStatsResponse {
+	m0 := &GetURLStatsResponse{}
+	b, x := &b0, m0
+	_, _ = b, x
+	if b.Total != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 0, 5)
+		x.xxx_hidden_Total = *b.Total
+	}
+	x.xxx_hidden_ByDay = &b.ByDay
+	x.xxx_hidden_ByReferrer = &b.ByReferrer
+	x.xxx_hidden_ByUserAgent = &b.ByUserAgent
+	x.xxx_hidden_ByVariant = &b.ByVariant
+	return m0
+}
+
+type UpdateURLRequest struct {
+	state                  protoimpl.MessageState `protogen:"opaque.v1"`
+	xxx_hidden_ShortUrl    *string                `protobuf:"bytes,1,opt,name=short_url,json=shortUrl"`
+	xxx_hidden_OriginalUrl *string                `protobuf:"bytes,2,opt,name=original_url,json=originalUrl"`
+	xxx_hidden_IfVersion   int32                  `protobuf:"varint,3,opt,name=if_version,json=ifVersion"`
+	xxx_hidden_Domain      *string                `protobuf:"bytes,4,opt,name=domain"`
+	XXX_raceDetectHookData protoimpl.RaceDetectHookData
+	XXX_presence           [1]uint32
+	unknownFields          protoimpl.UnknownFields
+	sizeCache              protoimpl.SizeCache
+}
+
+func (x *UpdateURLRequest) Reset() {
+	*x = UpdateURLRequest{}
+	mi := &file_url_shortener_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateURLRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateURLRequest) ProtoMessage() {}
+
+func (x *UpdateURLRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (x *UpdateURLRequest) GetShortUrl() string {
+	if x != nil {
+		if x.xxx_hidden_ShortUrl != nil {
+			return *x.xxx_hidden_ShortUrl
+		}
+		return ""
+	}
+	return ""
+}
+
+func (x *UpdateURLRequest) GetOriginalUrl() string {
+	if x != nil {
+		if x.xxx_hidden_OriginalUrl != nil {
+			return *x.xxx_hidden_OriginalUrl
+		}
+		return ""
+	}
+	return ""
+}
+
+func (x *UpdateURLRequest) GetIfVersion() int32 {
+	if x != nil {
+		return x.xxx_hidden_IfVersion
+	}
+	return 0
+}
+
+func (x *UpdateURLRequest) GetDomain() string {
+	if x != nil {
+		if x.xxx_hidden_Domain != nil {
+			return *x.xxx_hidden_Domain
+		}
+		return ""
+	}
+	return ""
+}
+
+func (x *UpdateURLRequest) SetShortUrl(v string) {
+	x.xxx_hidden_ShortUrl = &v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 0, 4)
+}
+
+func (x *UpdateURLRequest) SetOriginalUrl(v string) {
+	x.xxx_hidden_OriginalUrl = &v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 1, 4)
+}
+
+func (x *UpdateURLRequest) SetIfVersion(v int32) {
+	x.xxx_hidden_IfVersion = v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 2, 4)
+}
+
+func (x *UpdateURLRequest) SetDomain(v string) {
+	x.xxx_hidden_Domain = &v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 3, 4)
+}
+
+func (x *UpdateURLRequest) HasShortUrl() bool {
 	if x == nil {
 		return false
 	}
 	return protoimpl.X.Present(&(x.XXX_presence[0]), 0)
 }
 
-func (x *URLCreatorResponse) ClearShortenUrl() {
-	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 0)
-	x.xxx_hidden_ShortenUrl = nil
+func (x *UpdateURLRequest) HasOriginalUrl() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 1)
+}
+
+func (x *UpdateURLRequest) HasIfVersion() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 2)
+}
+
+func (x *UpdateURLRequest) HasDomain() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 3)
+}
+
+func (x *UpdateURLRequest) ClearShortUrl() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 0)
+	x.xxx_hidden_ShortUrl = nil
+}
+
+func (x *UpdateURLRequest) ClearOriginalUrl() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 1)
+	x.xxx_hidden_OriginalUrl = nil
 }
 
-type URLCreatorResponse_builder struct {
+func (x *UpdateURLRequest) ClearIfVersion() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 2)
+	x.xxx_hidden_IfVersion = 0
+}
+
+func (x *UpdateURLRequest) ClearDomain() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 3)
+	x.xxx_hidden_Domain = nil
+}
+
+type UpdateURLRequest_builder struct {
 	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
 
-	ShortenUrl *string
+	ShortUrl    *string
+	OriginalUrl *string
+	// if_version — ожидаемая текущая версия ссылки; 0 отключает проверку.
+	IfVersion *int32
+	// domain — короткий домен ссылки из настроенных; пустой означает основной домен.
+	Domain *string
 }
 
-func (b0 URLCreatorResponse_builder) Build() *URLCreatorResponse {
-	m0 := &URLCreatorResponse{}
+func (b0 UpdateURLRequest_builder) Build() *UpdateURLRequest {
+	m0 := &UpdateURLRequest{}
 	b, x := &b0, m0
 	_, _ = b, x
-	if b.ShortenUrl != nil {
-		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 0, 1)
-		x.xxx_hidden_ShortenUrl = b.ShortenUrl
+	if b.ShortUrl != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 0, 4)
+		x.xxx_hidden_ShortUrl = b.ShortUrl
+	}
+	if b.OriginalUrl != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 1, 4)
+		x.xxx_hidden_OriginalUrl = b.OriginalUrl
+	}
+	if b.IfVersion != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 2, 4)
+		x.xxx_hidden_IfVersion = *b.IfVersion
+	}
+	if b.Domain != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 3, 4)
+		x.xxx_hidden_Domain = b.Domain
 	}
 	return m0
 }
 
-type URLCreatorJSONRequest struct {
-	state                      protoimpl.MessageState `protogen:"opaque.v1"`
-	xxx_hidden_JsonOriginalUrl *string                `protobuf:"bytes,2,opt,name=json_original_url,json=jsonOriginalUrl"`
-	XXX_raceDetectHookData     protoimpl.RaceDetectHookData
-	XXX_presence               [1]uint32
-	unknownFields              protoimpl.UnknownFields
-	sizeCache                  protoimpl.SizeCache
+type UpdateURLResponse struct {
+	state                  protoimpl.MessageState `protogen:"opaque.v1"`
+	xxx_hidden_ShortUrl    *string                `protobuf:"bytes,1,opt,name=short_url,json=shortUrl"`
+	xxx_hidden_OriginalUrl *string                `protobuf:"bytes,2,opt,name=original_url,json=originalUrl"`
+	xxx_hidden_Version     int32                  `protobuf:"varint,3,opt,name=version"`
+	XXX_raceDetectHookData protoimpl.RaceDetectHookData
+	XXX_presence           [1]uint32
+	unknownFields          protoimpl.UnknownFields
+	sizeCache              protoimpl.SizeCache
 }
 
-func (x *URLCreatorJSONRequest) Reset() {
-	*x = URLCreatorJSONRequest{}
-	mi := &file_url_shortener_proto_msgTypes[6]
+func (x *UpdateURLResponse) Reset() {
+	*x = UpdateURLResponse{}
+	mi := &file_url_shortener_proto_msgTypes[28]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *URLCreatorJSONRequest) String() string {
+func (x *UpdateURLResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*URLCreatorJSONRequest) ProtoMessage() {}
+func (*UpdateURLResponse) ProtoMessage() {}
 
-func (x *URLCreatorJSONRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_url_shortener_proto_msgTypes[6]
+func (x *UpdateURLResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[28]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -488,74 +4284,140 @@ func (x *URLCreatorJSONRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-func (x *URLCreatorJSONRequest) GetJsonOriginalUrl() string {
+func (x *UpdateURLResponse) GetShortUrl() string {
 	if x != nil {
-		if x.xxx_hidden_JsonOriginalUrl != nil {
-			return *x.xxx_hidden_JsonOriginalUrl
+		if x.xxx_hidden_ShortUrl != nil {
+			return *x.xxx_hidden_ShortUrl
 		}
 		return ""
 	}
 	return ""
 }
 
-func (x *URLCreatorJSONRequest) SetJsonOriginalUrl(v string) {
-	x.xxx_hidden_JsonOriginalUrl = &v
-	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 0, 1)
+func (x *UpdateURLResponse) GetOriginalUrl() string {
+	if x != nil {
+		if x.xxx_hidden_OriginalUrl != nil {
+			return *x.xxx_hidden_OriginalUrl
+		}
+		return ""
+	}
+	return ""
 }
 
-func (x *URLCreatorJSONRequest) HasJsonOriginalUrl() bool {
+func (x *UpdateURLResponse) GetVersion() int32 {
+	if x != nil {
+		return x.xxx_hidden_Version
+	}
+	return 0
+}
+
+func (x *UpdateURLResponse) SetShortUrl(v string) {
+	x.xxx_hidden_ShortUrl = &v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 0, 3)
+}
+
+func (x *UpdateURLResponse) SetOriginalUrl(v string) {
+	x.xxx_hidden_OriginalUrl = &v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 1, 3)
+}
+
+func (x *UpdateURLResponse) SetVersion(v int32) {
+	x.xxx_hidden_Version = v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 2, 3)
+}
+
+func (x *UpdateURLResponse) HasShortUrl() bool {
 	if x == nil {
 		return false
 	}
 	return protoimpl.X.Present(&(x.XXX_presence[0]), 0)
 }
 
-func (x *URLCreatorJSONRequest) ClearJsonOriginalUrl() {
+func (x *UpdateURLResponse) HasOriginalUrl() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 1)
+}
+
+func (x *UpdateURLResponse) HasVersion() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 2)
+}
+
+func (x *UpdateURLResponse) ClearShortUrl() {
 	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 0)
-	x.xxx_hidden_JsonOriginalUrl = nil
+	x.xxx_hidden_ShortUrl = nil
 }
 
-type URLCreatorJSONRequest_builder struct {
+func (x *UpdateURLResponse) ClearOriginalUrl() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 1)
+	x.xxx_hidden_OriginalUrl = nil
+}
+
+func (x *UpdateURLResponse) ClearVersion() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 2)
+	x.xxx_hidden_Version = 0
+}
+
+type UpdateURLResponse_builder struct {
 	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
 
-	JsonOriginalUrl *string
+	ShortUrl    *string
+	OriginalUrl *string
+	Version     *int32
 }
 
-func (b0 URLCreatorJSONRequest_builder) Build() *URLCreatorJSONRequest {
-	m0 := &URLCreatorJSONRequest{}
+func (b0 UpdateURLResponse_builder) Build() *UpdateURLResponse {
+	m0 := &UpdateURLResponse{}
 	b, x := &b0, m0
 	_, _ = b, x
-	if b.JsonOriginalUrl != nil {
-		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 0, 1)
-		x.xxx_hidden_JsonOriginalUrl = b.JsonOriginalUrl
+	if b.ShortUrl != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 0, 3)
+		x.xxx_hidden_ShortUrl = b.ShortUrl
+	}
+	if b.OriginalUrl != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 1, 3)
+		x.xxx_hidden_OriginalUrl = b.OriginalUrl
+	}
+	if b.Version != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 2, 3)
+		x.xxx_hidden_Version = *b.Version
 	}
 	return m0
 }
 
-type URLCreatorJSONResponse struct {
+type GetQRCodeRequest struct {
 	state                  protoimpl.MessageState `protogen:"opaque.v1"`
-	xxx_hidden_JsonResult  *string                `protobuf:"bytes,1,opt,name=json_result,json=jsonResult"`
+	xxx_hidden_ShortUrl    *string                `protobuf:"bytes,1,opt,name=short_url,json=shortUrl"`
+	xxx_hidden_Format      *string                `protobuf:"bytes,2,opt,name=format"`
+	xxx_hidden_Size        int32                  `protobuf:"varint,3,opt,name=size"`
+	xxx_hidden_Margin      int32                  `protobuf:"varint,4,opt,name=margin"`
+	xxx_hidden_Level       *string                `protobuf:"bytes,5,opt,name=level"`
+	xxx_hidden_Domain      *string                `protobuf:"bytes,6,opt,name=domain"`
 	XXX_raceDetectHookData protoimpl.RaceDetectHookData
 	XXX_presence           [1]uint32
 	unknownFields          protoimpl.UnknownFields
 	sizeCache              protoimpl.SizeCache
 }
 
-func (x *URLCreatorJSONResponse) Reset() {
-	*x = URLCreatorJSONResponse{}
-	mi := &file_url_shortener_proto_msgTypes[7]
+func (x *GetQRCodeRequest) Reset() {
+	*x = GetQRCodeRequest{}
+	mi := &file_url_shortener_proto_msgTypes[29]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *URLCreatorJSONResponse) String() string {
+func (x *GetQRCodeRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*URLCreatorJSONResponse) ProtoMessage() {}
+func (*GetQRCodeRequest) ProtoMessage() {}
 
-func (x *URLCreatorJSONResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_url_shortener_proto_msgTypes[7]
+func (x *GetQRCodeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[29]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -566,75 +4428,234 @@ func (x *URLCreatorJSONResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-func (x *URLCreatorJSONResponse) GetJsonResult() string {
+func (x *GetQRCodeRequest) GetShortUrl() string {
 	if x != nil {
-		if x.xxx_hidden_JsonResult != nil {
-			return *x.xxx_hidden_JsonResult
+		if x.xxx_hidden_ShortUrl != nil {
+			return *x.xxx_hidden_ShortUrl
 		}
 		return ""
 	}
 	return ""
 }
 
-func (x *URLCreatorJSONResponse) SetJsonResult(v string) {
-	x.xxx_hidden_JsonResult = &v
-	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 0, 1)
+func (x *GetQRCodeRequest) GetFormat() string {
+	if x != nil {
+		if x.xxx_hidden_Format != nil {
+			return *x.xxx_hidden_Format
+		}
+		return ""
+	}
+	return ""
 }
 
-func (x *URLCreatorJSONResponse) HasJsonResult() bool {
+func (x *GetQRCodeRequest) GetSize() int32 {
+	if x != nil {
+		return x.xxx_hidden_Size
+	}
+	return 0
+}
+
+func (x *GetQRCodeRequest) GetMargin() int32 {
+	if x != nil {
+		return x.xxx_hidden_Margin
+	}
+	return 0
+}
+
+func (x *GetQRCodeRequest) GetLevel() string {
+	if x != nil {
+		if x.xxx_hidden_Level != nil {
+			return *x.xxx_hidden_Level
+		}
+		return ""
+	}
+	return ""
+}
+
+func (x *GetQRCodeRequest) GetDomain() string {
+	if x != nil {
+		if x.xxx_hidden_Domain != nil {
+			return *x.xxx_hidden_Domain
+		}
+		return ""
+	}
+	return ""
+}
+
+func (x *GetQRCodeRequest) SetShortUrl(v string) {
+	x.xxx_hidden_ShortUrl = &v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 0, 6)
+}
+
+func (x *GetQRCodeRequest) SetFormat(v string) {
+	x.xxx_hidden_Format = &v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 1, 6)
+}
+
+func (x *GetQRCodeRequest) SetSize(v int32) {
+	x.xxx_hidden_Size = v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 2, 6)
+}
+
+func (x *GetQRCodeRequest) SetMargin(v int32) {
+	x.xxx_hidden_Margin = v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 3, 6)
+}
+
+func (x *GetQRCodeRequest) SetLevel(v string) {
+	x.xxx_hidden_Level = &v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 4, 6)
+}
+
+func (x *GetQRCodeRequest) SetDomain(v string) {
+	x.xxx_hidden_Domain = &v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 5, 6)
+}
+
+func (x *GetQRCodeRequest) HasShortUrl() bool {
 	if x == nil {
 		return false
 	}
 	return protoimpl.X.Present(&(x.XXX_presence[0]), 0)
 }
 
-func (x *URLCreatorJSONResponse) ClearJsonResult() {
+func (x *GetQRCodeRequest) HasFormat() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 1)
+}
+
+func (x *GetQRCodeRequest) HasSize() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 2)
+}
+
+func (x *GetQRCodeRequest) HasMargin() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 3)
+}
+
+func (x *GetQRCodeRequest) HasLevel() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 4)
+}
+
+func (x *GetQRCodeRequest) HasDomain() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 5)
+}
+
+func (x *GetQRCodeRequest) ClearShortUrl() {
 	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 0)
-	x.xxx_hidden_JsonResult = nil
+	x.xxx_hidden_ShortUrl = nil
 }
 
-type URLCreatorJSONResponse_builder struct {
-	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+func (x *GetQRCodeRequest) ClearFormat() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 1)
+	x.xxx_hidden_Format = nil
+}
 
-	JsonResult *string
+func (x *GetQRCodeRequest) ClearSize() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 2)
+	x.xxx_hidden_Size = 0
 }
 
-func (b0 URLCreatorJSONResponse_builder) Build() *URLCreatorJSONResponse {
-	m0 := &URLCreatorJSONResponse{}
+func (x *GetQRCodeRequest) ClearMargin() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 3)
+	x.xxx_hidden_Margin = 0
+}
+
+func (x *GetQRCodeRequest) ClearLevel() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 4)
+	x.xxx_hidden_Level = nil
+}
+
+func (x *GetQRCodeRequest) ClearDomain() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 5)
+	x.xxx_hidden_Domain = nil
+}
+
+type GetQRCodeRequest_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+	ShortUrl *string
+	// format — "png" (по умолчанию) или "svg".
+	Format *string
+	// size — размер изображения в пикселях; 0 означает 256.
+	Size *int32
+	// margin — ширина рамки в модулях; если не задано, используется 4.
+	Margin *int32
+	// level — уровень коррекции ошибок: L, M (по умолчанию), Q или H.
+	Level *string
+	// domain — короткий домен ссылки из настроенных; пустой означает основной домен.
+	Domain *string
+}
+
+func (b0 GetQRCodeRequest_builder) Build() *GetQRCodeRequest {
+	m0 := &GetQRCodeRequest{}
 	b, x := &b0, m0
 	_, _ = b, x
-	if b.JsonResult != nil {
-		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 0, 1)
-		x.xxx_hidden_JsonResult = b.JsonResult
+	if b.ShortUrl != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 0, 6)
+		x.xxx_hidden_ShortUrl = b.ShortUrl
+	}
+	if b.Format != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 1, 6)
+		x.xxx_hidden_Format = b.Format
+	}
+	if b.Size != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 2, 6)
+		x.xxx_hidden_Size = *b.Size
+	}
+	if b.Margin != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 3, 6)
+		x.xxx_hidden_Margin = *b.Margin
+	}
+	if b.Level != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 4, 6)
+		x.xxx_hidden_Level = b.Level
+	}
+	if b.Domain != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 5, 6)
+		x.xxx_hidden_Domain = b.Domain
 	}
 	return m0
 }
 
-type URLRequest struct {
-	state                    protoimpl.MessageState `protogen:"opaque.v1"`
-	xxx_hidden_CorrelationId *string                `protobuf:"bytes,1,opt,name=correlation_id,json=correlationId"`
-	xxx_hidden_OriginalUrl   *string                `protobuf:"bytes,2,opt,name=original_url,json=originalUrl"`
-	XXX_raceDetectHookData   protoimpl.RaceDetectHookData
-	XXX_presence             [1]uint32
-	unknownFields            protoimpl.UnknownFields
-	sizeCache                protoimpl.SizeCache
+type GetQRCodeResponse struct {
+	state                  protoimpl.MessageState `protogen:"opaque.v1"`
+	xxx_hidden_Image       []byte                 `protobuf:"bytes,1,opt,name=image"`
+	xxx_hidden_ContentType *string                `protobuf:"bytes,2,opt,name=content_type,json=contentType"`
+	XXX_raceDetectHookData protoimpl.RaceDetectHookData
+	XXX_presence           [1]uint32
+	unknownFields          protoimpl.UnknownFields
+	sizeCache              protoimpl.SizeCache
 }
 
-func (x *URLRequest) Reset() {
-	*x = URLRequest{}
-	mi := &file_url_shortener_proto_msgTypes[8]
+func (x *GetQRCodeResponse) Reset() {
+	*x = GetQRCodeResponse{}
+	mi := &file_url_shortener_proto_msgTypes[30]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *URLRequest) String() string {
+func (x *GetQRCodeResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*URLRequest) ProtoMessage() {}
+func (*GetQRCodeResponse) ProtoMessage() {}
 
-func (x *URLRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_url_shortener_proto_msgTypes[8]
+func (x *GetQRCodeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[30]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -645,107 +4666,107 @@ func (x *URLRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-func (x *URLRequest) GetCorrelationId() string {
+func (x *GetQRCodeResponse) GetImage() []byte {
 	if x != nil {
-		if x.xxx_hidden_CorrelationId != nil {
-			return *x.xxx_hidden_CorrelationId
-		}
-		return ""
+		return x.xxx_hidden_Image
 	}
-	return ""
+	return nil
 }
 
-func (x *URLRequest) GetOriginalUrl() string {
-	if x != nil {
-		if x.xxx_hidden_OriginalUrl != nil {
-			return *x.xxx_hidden_OriginalUrl
+func (x *GetQRCodeResponse) GetContentType() string {
+	if x != nil {
+		if x.xxx_hidden_ContentType != nil {
+			return *x.xxx_hidden_ContentType
 		}
 		return ""
 	}
 	return ""
 }
 
-func (x *URLRequest) SetCorrelationId(v string) {
-	x.xxx_hidden_CorrelationId = &v
+func (x *GetQRCodeResponse) SetImage(v []byte) {
+	if v == nil {
+		v = []byte{}
+	}
+	x.xxx_hidden_Image = v
 	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 0, 2)
 }
 
-func (x *URLRequest) SetOriginalUrl(v string) {
-	x.xxx_hidden_OriginalUrl = &v
+func (x *GetQRCodeResponse) SetContentType(v string) {
+	x.xxx_hidden_ContentType = &v
 	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 1, 2)
 }
 
-func (x *URLRequest) HasCorrelationId() bool {
+func (x *GetQRCodeResponse) HasImage() bool {
 	if x == nil {
 		return false
 	}
 	return protoimpl.X.Present(&(x.XXX_presence[0]), 0)
 }
 
-func (x *URLRequest) HasOriginalUrl() bool {
+func (x *GetQRCodeResponse) HasContentType() bool {
 	if x == nil {
 		return false
 	}
 	return protoimpl.X.Present(&(x.XXX_presence[0]), 1)
 }
 
-func (x *URLRequest) ClearCorrelationId() {
+func (x *GetQRCodeResponse) ClearImage() {
 	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 0)
-	x.xxx_hidden_CorrelationId = nil
+	x.xxx_hidden_Image = nil
 }
 
-func (x *URLRequest) ClearOriginalUrl() {
+func (x *GetQRCodeResponse) ClearContentType() {
 	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 1)
-	x.xxx_hidden_OriginalUrl = nil
+	x.xxx_hidden_ContentType = nil
 }
 
-type URLRequest_builder struct {
+type GetQRCodeResponse_builder struct {
 	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
 
-	CorrelationId *string
-	OriginalUrl   *string
+	Image       []byte
+	ContentType *string
 }
 
-func (b0 URLRequest_builder) Build() *URLRequest {
-	m0 := &URLRequest{}
+func (b0 GetQRCodeResponse_builder) Build() *GetQRCodeResponse {
+	m0 := &GetQRCodeResponse{}
 	b, x := &b0, m0
 	_, _ = b, x
-	if b.CorrelationId != nil {
+	if b.Image != nil {
 		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 0, 2)
-		x.xxx_hidden_CorrelationId = b.CorrelationId
+		x.xxx_hidden_Image = b.Image
 	}
-	if b.OriginalUrl != nil {
+	if b.ContentType != nil {
 		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 1, 2)
-		x.xxx_hidden_OriginalUrl = b.OriginalUrl
+		x.xxx_hidden_ContentType = b.ContentType
 	}
 	return m0
 }
 
-type URLResponse struct {
-	state                    protoimpl.MessageState `protogen:"opaque.v1"`
-	xxx_hidden_CorrelationId *string                `protobuf:"bytes,1,opt,name=correlation_id,json=correlationId"`
-	xxx_hidden_ShortUrl      *string                `protobuf:"bytes,2,opt,name=short_url,json=shortUrl"`
-	XXX_raceDetectHookData   protoimpl.RaceDetectHookData
-	XXX_presence             [1]uint32
-	unknownFields            protoimpl.UnknownFields
-	sizeCache                protoimpl.SizeCache
+type ExpandURLRequest struct {
+	state                  protoimpl.MessageState `protogen:"opaque.v1"`
+	xxx_hidden_ShortUrl    *string                `protobuf:"bytes,1,opt,name=short_url,json=shortUrl"`
+	xxx_hidden_Domain      *string                `protobuf:"bytes,2,opt,name=domain"`
+	XXX_raceDetectHookData protoimpl.RaceDetectHookData
+	XXX_presence           [1]uint32
+	unknownFields          protoimpl.UnknownFields
+	sizeCache              protoimpl.SizeCache
 }
 
-func (x *URLResponse) Reset() {
-	*x = URLResponse{}
-	mi := &file_url_shortener_proto_msgTypes[9]
+func (x *ExpandURLRequest) Reset() {
+	*x = ExpandURLRequest{}
+	mi := &file_url_shortener_proto_msgTypes[31]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *URLResponse) String() string {
+func (x *ExpandURLRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*URLResponse) ProtoMessage() {}
+func (*ExpandURLRequest) ProtoMessage() {}
 
-func (x *URLResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_url_shortener_proto_msgTypes[9]
+func (x *ExpandURLRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[31]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -756,104 +4777,113 @@ func (x *URLResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-func (x *URLResponse) GetCorrelationId() string {
+func (x *ExpandURLRequest) GetShortUrl() string {
 	if x != nil {
-		if x.xxx_hidden_CorrelationId != nil {
-			return *x.xxx_hidden_CorrelationId
+		if x.xxx_hidden_ShortUrl != nil {
+			return *x.xxx_hidden_ShortUrl
 		}
 		return ""
 	}
 	return ""
 }
 
-func (x *URLResponse) GetShortUrl() string {
+func (x *ExpandURLRequest) GetDomain() string {
 	if x != nil {
-		if x.xxx_hidden_ShortUrl != nil {
-			return *x.xxx_hidden_ShortUrl
+		if x.xxx_hidden_Domain != nil {
+			return *x.xxx_hidden_Domain
 		}
 		return ""
 	}
 	return ""
 }
 
-func (x *URLResponse) SetCorrelationId(v string) {
-	x.xxx_hidden_CorrelationId = &v
+func (x *ExpandURLRequest) SetShortUrl(v string) {
+	x.xxx_hidden_ShortUrl = &v
 	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 0, 2)
 }
 
-func (x *URLResponse) SetShortUrl(v string) {
-	x.xxx_hidden_ShortUrl = &v
+func (x *ExpandURLRequest) SetDomain(v string) {
+	x.xxx_hidden_Domain = &v
 	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 1, 2)
 }
 
-func (x *URLResponse) HasCorrelationId() bool {
+func (x *ExpandURLRequest) HasShortUrl() bool {
 	if x == nil {
 		return false
 	}
 	return protoimpl.X.Present(&(x.XXX_presence[0]), 0)
 }
 
-func (x *URLResponse) HasShortUrl() bool {
+func (x *ExpandURLRequest) HasDomain() bool {
 	if x == nil {
 		return false
 	}
 	return protoimpl.X.Present(&(x.XXX_presence[0]), 1)
 }
 
-func (x *URLResponse) ClearCorrelationId() {
+func (x *ExpandURLRequest) ClearShortUrl() {
 	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 0)
-	x.xxx_hidden_CorrelationId = nil
+	x.xxx_hidden_ShortUrl = nil
 }
 
-func (x *URLResponse) ClearShortUrl() {
+func (x *ExpandURLRequest) ClearDomain() {
 	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 1)
-	x.xxx_hidden_ShortUrl = nil
+	x.xxx_hidden_Domain = nil
 }
 
-type URLResponse_builder struct {
+type ExpandURLRequest_builder struct {
 	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
 
-	CorrelationId *string
-	ShortUrl      *string
+	ShortUrl *string
+	// domain — короткий домен ссылки из настроенных; пустой означает основной домен.
+	Domain *string
 }
 
-func (b0 URLResponse_builder) Build() *URLResponse {
-	m0 := &URLResponse{}
+func (b0 ExpandURLRequest_builder) Build() *ExpandURLRequest {
+	m0 := &ExpandURLRequest{}
 	b, x := &b0, m0
 	_, _ = b, x
-	if b.CorrelationId != nil {
+	if b.ShortUrl != nil {
 		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 0, 2)
-		x.xxx_hidden_CorrelationId = b.CorrelationId
+		x.xxx_hidden_ShortUrl = b.ShortUrl
 	}
-	if b.ShortUrl != nil {
+	if b.Domain != nil {
 		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 1, 2)
-		x.xxx_hidden_ShortUrl = b.ShortUrl
+		x.xxx_hidden_Domain = b.Domain
 	}
 	return m0
 }
 
-type URLCreatorBatchRequest struct {
-	state               protoimpl.MessageState `protogen:"opaque.v1"`
-	xxx_hidden_Requests *[]*URLRequest         `protobuf:"bytes,1,rep,name=requests"`
-	unknownFields       protoimpl.UnknownFields
-	sizeCache           protoimpl.SizeCache
-}
-
-func (x *URLCreatorBatchRequest) Reset() {
-	*x = URLCreatorBatchRequest{}
-	mi := &file_url_shortener_proto_msgTypes[10]
+type ExpandURLResponse struct {
+	state                        protoimpl.MessageState `protogen:"opaque.v1"`
+	xxx_hidden_ShortUrl          *string                `protobuf:"bytes,1,opt,name=short_url,json=shortUrl"`
+	xxx_hidden_OriginalUrl       *string                `protobuf:"bytes,2,opt,name=original_url,json=originalUrl"`
+	xxx_hidden_Title             *string                `protobuf:"bytes,3,opt,name=title"`
+	xxx_hidden_CreatedAt         *string                `protobuf:"bytes,4,opt,name=created_at,json=createdAt"`
+	xxx_hidden_Status            *string                `protobuf:"bytes,5,opt,name=status"`
+	xxx_hidden_PasswordProtected bool                   `protobuf:"varint,6,opt,name=password_protected,json=passwordProtected"`
+	xxx_hidden_Clicks            int64                  `protobuf:"varint,7,opt,name=clicks"`
+	XXX_raceDetectHookData       protoimpl.RaceDetectHookData
+	XXX_presence                 [1]uint32
+	unknownFields                protoimpl.UnknownFields
+	sizeCache                    protoimpl.SizeCache
+}
+
+func (x *ExpandURLResponse) Reset() {
+	*x = ExpandURLResponse{}
+	mi := &file_url_shortener_proto_msgTypes[32]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *URLCreatorBatchRequest) String() string {
+func (x *ExpandURLResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*URLCreatorBatchRequest) ProtoMessage() {}
+func (*ExpandURLResponse) ProtoMessage() {}
 
-func (x *URLCreatorBatchRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_url_shortener_proto_msgTypes[10]
+func (x *ExpandURLResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[32]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -864,160 +4894,264 @@ func (x *URLCreatorBatchRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-func (x *URLCreatorBatchRequest) GetRequests() []*URLRequest {
+func (x *ExpandURLResponse) GetShortUrl() string {
 	if x != nil {
-		if x.xxx_hidden_Requests != nil {
-			return *x.xxx_hidden_Requests
+		if x.xxx_hidden_ShortUrl != nil {
+			return *x.xxx_hidden_ShortUrl
 		}
+		return ""
 	}
-	return nil
+	return ""
 }
 
-func (x *URLCreatorBatchRequest) SetRequests(v []*URLRequest) {
-	x.xxx_hidden_Requests = &v
+func (x *ExpandURLResponse) GetOriginalUrl() string {
+	if x != nil {
+		if x.xxx_hidden_OriginalUrl != nil {
+			return *x.xxx_hidden_OriginalUrl
+		}
+		return ""
+	}
+	return ""
 }
 
-type URLCreatorBatchRequest_builder struct {
-	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+func (x *ExpandURLResponse) GetTitle() string {
+	if x != nil {
+		if x.xxx_hidden_Title != nil {
+			return *x.xxx_hidden_Title
+		}
+		return ""
+	}
+	return ""
+}
 
-	Requests []*URLRequest
+func (x *ExpandURLResponse) GetCreatedAt() string {
+	if x != nil {
+		if x.xxx_hidden_CreatedAt != nil {
+			return *x.xxx_hidden_CreatedAt
+		}
+		return ""
+	}
+	return ""
 }
 
-func (b0 URLCreatorBatchRequest_builder) Build() *URLCreatorBatchRequest {
-	m0 := &URLCreatorBatchRequest{}
-	b, x := &b0, m0
-	_, _ = b, x
-	x.xxx_hidden_Requests = &b.Requests
-	return m0
+func (x *ExpandURLResponse) GetStatus() string {
+	if x != nil {
+		if x.xxx_hidden_Status != nil {
+			return *x.xxx_hidden_Status
+		}
+		return ""
+	}
+	return ""
 }
 
-type URLCreatorBatchResponse struct {
-	state                protoimpl.MessageState `protogen:"opaque.v1"`
-	xxx_hidden_Responses *[]*URLResponse        `protobuf:"bytes,1,rep,name=responses"`
-	unknownFields        protoimpl.UnknownFields
-	sizeCache            protoimpl.SizeCache
+func (x *ExpandURLResponse) GetPasswordProtected() bool {
+	if x != nil {
+		return x.xxx_hidden_PasswordProtected
+	}
+	return false
 }
 
-func (x *URLCreatorBatchResponse) Reset() {
-	*x = URLCreatorBatchResponse{}
-	mi := &file_url_shortener_proto_msgTypes[11]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+func (x *ExpandURLResponse) GetClicks() int64 {
+	if x != nil {
+		return x.xxx_hidden_Clicks
+	}
+	return 0
 }
 
-func (x *URLCreatorBatchResponse) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *ExpandURLResponse) SetShortUrl(v string) {
+	x.xxx_hidden_ShortUrl = &v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 0, 7)
 }
 
-func (*URLCreatorBatchResponse) ProtoMessage() {}
+func (x *ExpandURLResponse) SetOriginalUrl(v string) {
+	x.xxx_hidden_OriginalUrl = &v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 1, 7)
+}
 
-func (x *URLCreatorBatchResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_url_shortener_proto_msgTypes[11]
-	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+func (x *ExpandURLResponse) SetTitle(v string) {
+	x.xxx_hidden_Title = &v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 2, 7)
+}
+
+func (x *ExpandURLResponse) SetCreatedAt(v string) {
+	x.xxx_hidden_CreatedAt = &v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 3, 7)
+}
+
+func (x *ExpandURLResponse) SetStatus(v string) {
+	x.xxx_hidden_Status = &v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 4, 7)
+}
+
+func (x *ExpandURLResponse) SetPasswordProtected(v bool) {
+	x.xxx_hidden_PasswordProtected = v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 5, 7)
+}
+
+func (x *ExpandURLResponse) SetClicks(v int64) {
+	x.xxx_hidden_Clicks = v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 6, 7)
+}
+
+func (x *ExpandURLResponse) HasShortUrl() bool {
+	if x == nil {
+		return false
 	}
-	return mi.MessageOf(x)
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 0)
 }
 
-func (x *URLCreatorBatchResponse) GetResponses() []*URLResponse {
-	if x != nil {
-		if x.xxx_hidden_Responses != nil {
-			return *x.xxx_hidden_Responses
-		}
+func (x *ExpandURLResponse) HasOriginalUrl() bool {
+	if x == nil {
+		return false
 	}
-	return nil
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 1)
 }
 
-func (x *URLCreatorBatchResponse) SetResponses(v []*URLResponse) {
-	x.xxx_hidden_Responses = &v
+func (x *ExpandURLResponse) HasTitle() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 2)
 }
 
-type URLCreatorBatchResponse_builder struct {
-	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+func (x *ExpandURLResponse) HasCreatedAt() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 3)
+}
 
-	Responses []*URLResponse
+func (x *ExpandURLResponse) HasStatus() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 4)
 }
 
-func (b0 URLCreatorBatchResponse_builder) Build() *URLCreatorBatchResponse {
-	m0 := &URLCreatorBatchResponse{}
-	b, x := &b0, m0
-	_, _ = b, x
-	x.xxx_hidden_Responses = &b.Responses
-	return m0
+func (x *ExpandURLResponse) HasPasswordProtected() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 5)
 }
 
-type GetUserURLsRequest struct {
-	state         protoimpl.MessageState `protogen:"opaque.v1"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *ExpandURLResponse) HasClicks() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 6)
 }
 
-func (x *GetUserURLsRequest) Reset() {
-	*x = GetUserURLsRequest{}
-	mi := &file_url_shortener_proto_msgTypes[12]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+func (x *ExpandURLResponse) ClearShortUrl() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 0)
+	x.xxx_hidden_ShortUrl = nil
 }
 
-func (x *GetUserURLsRequest) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *ExpandURLResponse) ClearOriginalUrl() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 1)
+	x.xxx_hidden_OriginalUrl = nil
 }
 
-func (*GetUserURLsRequest) ProtoMessage() {}
+func (x *ExpandURLResponse) ClearTitle() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 2)
+	x.xxx_hidden_Title = nil
+}
 
-func (x *GetUserURLsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_url_shortener_proto_msgTypes[12]
-	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
-	}
-	return mi.MessageOf(x)
+func (x *ExpandURLResponse) ClearCreatedAt() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 3)
+	x.xxx_hidden_CreatedAt = nil
 }
 
-type GetUserURLsRequest_builder struct {
+func (x *ExpandURLResponse) ClearStatus() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 4)
+	x.xxx_hidden_Status = nil
+}
+
+func (x *ExpandURLResponse) ClearPasswordProtected() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 5)
+	x.xxx_hidden_PasswordProtected = false
+}
+
+func (x *ExpandURLResponse) ClearClicks() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 6)
+	x.xxx_hidden_Clicks = 0
+}
+
+type ExpandURLResponse_builder struct {
 	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
 
+	ShortUrl *string
+	// original_url пуст для удалённых и защищённых паролем ссылок.
+	OriginalUrl *string
+	Title       *string
+	CreatedAt   *string
+	// status — "active", "deleted" или "expired".
+	Status            *string
+	PasswordProtected *bool
+	Clicks            *int64
 }
 
-func (b0 GetUserURLsRequest_builder) Build() *GetUserURLsRequest {
-	m0 := &GetUserURLsRequest{}
+func (b0 ExpandURLResponse_builder) Build() *ExpandURLResponse {
+	m0 := &ExpandURLResponse{}
 	b, x := &b0, m0
 	_, _ = b, x
+	if b.ShortUrl != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 0, 7)
+		x.xxx_hidden_ShortUrl = b.ShortUrl
+	}
+	if b.OriginalUrl != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 1, 7)
+		x.xxx_hidden_OriginalUrl = b.OriginalUrl
+	}
+	if b.Title != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 2, 7)
+		x.xxx_hidden_Title = b.Title
+	}
+	if b.CreatedAt != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 3, 7)
+		x.xxx_hidden_CreatedAt = b.CreatedAt
+	}
+	if b.Status != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 4, 7)
+		x.xxx_hidden_Status = b.Status
+	}
+	if b.PasswordProtected != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 5, 7)
+		x.xxx_hidden_PasswordProtected = *b.PasswordProtected
+	}
+	if b.Clicks != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 6, 7)
+		x.xxx_hidden_Clicks = *b.Clicks
+	}
 	return m0
 }
 
-type UserURL struct {
+// AuthRequest содержит логин и пароль учётной записи.
+type AuthRequest struct {
 	state                  protoimpl.MessageState `protogen:"opaque.v1"`
-	xxx_hidden_ShortUrl    *string                `protobuf:"bytes,1,opt,name=short_url,json=shortUrl"`
-	xxx_hidden_OriginalUrl *string                `protobuf:"bytes,2,opt,name=original_url,json=originalUrl"`
+	xxx_hidden_Login       *string                `protobuf:"bytes,1,opt,name=login"`
+	xxx_hidden_Password    *string                `protobuf:"bytes,2,opt,name=password"`
 	XXX_raceDetectHookData protoimpl.RaceDetectHookData
 	XXX_presence           [1]uint32
 	unknownFields          protoimpl.UnknownFields
 	sizeCache              protoimpl.SizeCache
 }
 
-func (x *UserURL) Reset() {
-	*x = UserURL{}
-	mi := &file_url_shortener_proto_msgTypes[13]
+func (x *AuthRequest) Reset() {
+	*x = AuthRequest{}
+	mi := &file_url_shortener_proto_msgTypes[33]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UserURL) String() string {
+func (x *AuthRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UserURL) ProtoMessage() {}
+func (*AuthRequest) ProtoMessage() {}
 
-func (x *UserURL) ProtoReflect() protoreflect.Message {
-	mi := &file_url_shortener_proto_msgTypes[13]
+func (x *AuthRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[33]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1028,104 +5162,108 @@ func (x *UserURL) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-func (x *UserURL) GetShortUrl() string {
+func (x *AuthRequest) GetLogin() string {
 	if x != nil {
-		if x.xxx_hidden_ShortUrl != nil {
-			return *x.xxx_hidden_ShortUrl
+		if x.xxx_hidden_Login != nil {
+			return *x.xxx_hidden_Login
 		}
 		return ""
 	}
 	return ""
 }
 
-func (x *UserURL) GetOriginalUrl() string {
+func (x *AuthRequest) GetPassword() string {
 	if x != nil {
-		if x.xxx_hidden_OriginalUrl != nil {
-			return *x.xxx_hidden_OriginalUrl
+		if x.xxx_hidden_Password != nil {
+			return *x.xxx_hidden_Password
 		}
 		return ""
 	}
 	return ""
 }
 
-func (x *UserURL) SetShortUrl(v string) {
-	x.xxx_hidden_ShortUrl = &v
+func (x *AuthRequest) SetLogin(v string) {
+	x.xxx_hidden_Login = &v
 	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 0, 2)
 }
 
-func (x *UserURL) SetOriginalUrl(v string) {
-	x.xxx_hidden_OriginalUrl = &v
+func (x *AuthRequest) SetPassword(v string) {
+	x.xxx_hidden_Password = &v
 	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 1, 2)
 }
 
-func (x *UserURL) HasShortUrl() bool {
+func (x *AuthRequest) HasLogin() bool {
 	if x == nil {
 		return false
 	}
 	return protoimpl.X.Present(&(x.XXX_presence[0]), 0)
 }
 
-func (x *UserURL) HasOriginalUrl() bool {
+func (x *AuthRequest) HasPassword() bool {
 	if x == nil {
 		return false
 	}
 	return protoimpl.X.Present(&(x.XXX_presence[0]), 1)
 }
 
-func (x *UserURL) ClearShortUrl() {
+func (x *AuthRequest) ClearLogin() {
 	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 0)
-	x.xxx_hidden_ShortUrl = nil
+	x.xxx_hidden_Login = nil
 }
 
-func (x *UserURL) ClearOriginalUrl() {
+func (x *AuthRequest) ClearPassword() {
 	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 1)
-	x.xxx_hidden_OriginalUrl = nil
+	x.xxx_hidden_Password = nil
 }
 
-type UserURL_builder struct {
+type AuthRequest_builder struct {
 	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
 
-	ShortUrl    *string
-	OriginalUrl *string
+	Login    *string
+	Password *string
 }
 
-func (b0 UserURL_builder) Build() *UserURL {
-	m0 := &UserURL{}
+func (b0 AuthRequest_builder) Build() *AuthRequest {
+	m0 := &AuthRequest{}
 	b, x := &b0, m0
 	_, _ = b, x
-	if b.ShortUrl != nil {
+	if b.Login != nil {
 		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 0, 2)
-		x.xxx_hidden_ShortUrl = b.ShortUrl
+		x.xxx_hidden_Login = b.Login
 	}
-	if b.OriginalUrl != nil {
+	if b.Password != nil {
 		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 1, 2)
-		x.xxx_hidden_OriginalUrl = b.OriginalUrl
+		x.xxx_hidden_Password = b.Password
 	}
 	return m0
 }
 
-type GetUserURLsResponse struct {
-	state           protoimpl.MessageState `protogen:"opaque.v1"`
-	xxx_hidden_Urls *[]*UserURL            `protobuf:"bytes,1,rep,name=urls"`
-	unknownFields   protoimpl.UnknownFields
-	sizeCache       protoimpl.SizeCache
+type AuthResponse struct {
+	state                  protoimpl.MessageState `protogen:"opaque.v1"`
+	xxx_hidden_UserId      *string                `protobuf:"bytes,1,opt,name=user_id,json=userId"`
+	xxx_hidden_Login       *string                `protobuf:"bytes,2,opt,name=login"`
+	xxx_hidden_Token       *string                `protobuf:"bytes,3,opt,name=token"`
+	XXX_raceDetectHookData protoimpl.RaceDetectHookData
+	XXX_presence           [1]uint32
+	unknownFields          protoimpl.UnknownFields
+	sizeCache              protoimpl.SizeCache
 }
 
-func (x *GetUserURLsResponse) Reset() {
-	*x = GetUserURLsResponse{}
-	mi := &file_url_shortener_proto_msgTypes[14]
+func (x *AuthResponse) Reset() {
+	*x = AuthResponse{}
+	mi := &file_url_shortener_proto_msgTypes[34]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetUserURLsResponse) String() string {
+func (x *AuthResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetUserURLsResponse) ProtoMessage() {}
+func (*AuthResponse) ProtoMessage() {}
 
-func (x *GetUserURLsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_url_shortener_proto_msgTypes[14]
+func (x *AuthResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[34]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1136,154 +5274,136 @@ func (x *GetUserURLsResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-func (x *GetUserURLsResponse) GetUrls() []*UserURL {
+func (x *AuthResponse) GetUserId() string {
 	if x != nil {
-		if x.xxx_hidden_Urls != nil {
-			return *x.xxx_hidden_Urls
+		if x.xxx_hidden_UserId != nil {
+			return *x.xxx_hidden_UserId
 		}
+		return ""
 	}
-	return nil
-}
-
-func (x *GetUserURLsResponse) SetUrls(v []*UserURL) {
-	x.xxx_hidden_Urls = &v
-}
-
-type GetUserURLsResponse_builder struct {
-	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
-
-	Urls []*UserURL
-}
-
-func (b0 GetUserURLsResponse_builder) Build() *GetUserURLsResponse {
-	m0 := &GetUserURLsResponse{}
-	b, x := &b0, m0
-	_, _ = b, x
-	x.xxx_hidden_Urls = &b.Urls
-	return m0
-}
-
-type DeleteUserURLsRequest struct {
-	state           protoimpl.MessageState `protogen:"opaque.v1"`
-	xxx_hidden_Urls []string               `protobuf:"bytes,1,rep,name=urls"`
-	unknownFields   protoimpl.UnknownFields
-	sizeCache       protoimpl.SizeCache
-}
-
-func (x *DeleteUserURLsRequest) Reset() {
-	*x = DeleteUserURLsRequest{}
-	mi := &file_url_shortener_proto_msgTypes[15]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
-}
-
-func (x *DeleteUserURLsRequest) String() string {
-	return protoimpl.X.MessageStringOf(x)
+	return ""
 }
 
-func (*DeleteUserURLsRequest) ProtoMessage() {}
-
-func (x *DeleteUserURLsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_url_shortener_proto_msgTypes[15]
+func (x *AuthResponse) GetLogin() string {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
+		if x.xxx_hidden_Login != nil {
+			return *x.xxx_hidden_Login
 		}
-		return ms
+		return ""
 	}
-	return mi.MessageOf(x)
+	return ""
 }
 
-func (x *DeleteUserURLsRequest) GetUrls() []string {
+func (x *AuthResponse) GetToken() string {
 	if x != nil {
-		return x.xxx_hidden_Urls
+		if x.xxx_hidden_Token != nil {
+			return *x.xxx_hidden_Token
+		}
+		return ""
 	}
-	return nil
+	return ""
 }
 
-func (x *DeleteUserURLsRequest) SetUrls(v []string) {
-	x.xxx_hidden_Urls = v
+func (x *AuthResponse) SetUserId(v string) {
+	x.xxx_hidden_UserId = &v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 0, 3)
 }
 
-type DeleteUserURLsRequest_builder struct {
-	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+func (x *AuthResponse) SetLogin(v string) {
+	x.xxx_hidden_Login = &v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 1, 3)
+}
 
-	Urls []string
+func (x *AuthResponse) SetToken(v string) {
+	x.xxx_hidden_Token = &v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 2, 3)
 }
 
-func (b0 DeleteUserURLsRequest_builder) Build() *DeleteUserURLsRequest {
-	m0 := &DeleteUserURLsRequest{}
-	b, x := &b0, m0
-	_, _ = b, x
-	x.xxx_hidden_Urls = b.Urls
-	return m0
+func (x *AuthResponse) HasUserId() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 0)
 }
 
-type DeleteUserURLsResponse struct {
-	state         protoimpl.MessageState `protogen:"opaque.v1"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *AuthResponse) HasLogin() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 1)
 }
 
-func (x *DeleteUserURLsResponse) Reset() {
-	*x = DeleteUserURLsResponse{}
-	mi := &file_url_shortener_proto_msgTypes[16]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+func (x *AuthResponse) HasToken() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 2)
 }
 
-func (x *DeleteUserURLsResponse) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *AuthResponse) ClearUserId() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 0)
+	x.xxx_hidden_UserId = nil
 }
 
-func (*DeleteUserURLsResponse) ProtoMessage() {}
+func (x *AuthResponse) ClearLogin() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 1)
+	x.xxx_hidden_Login = nil
+}
 
-func (x *DeleteUserURLsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_url_shortener_proto_msgTypes[16]
-	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
-	}
-	return mi.MessageOf(x)
+func (x *AuthResponse) ClearToken() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 2)
+	x.xxx_hidden_Token = nil
 }
 
-type DeleteUserURLsResponse_builder struct {
+type AuthResponse_builder struct {
 	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
 
+	UserId *string
+	Login  *string
+	// token — JWT учётной записи; передаётся в метаданных userID следующих вызовов.
+	Token *string
 }
 
-func (b0 DeleteUserURLsResponse_builder) Build() *DeleteUserURLsResponse {
-	m0 := &DeleteUserURLsResponse{}
+func (b0 AuthResponse_builder) Build() *AuthResponse {
+	m0 := &AuthResponse{}
 	b, x := &b0, m0
 	_, _ = b, x
+	if b.UserId != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 0, 3)
+		x.xxx_hidden_UserId = b.UserId
+	}
+	if b.Login != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 1, 3)
+		x.xxx_hidden_Login = b.Login
+	}
+	if b.Token != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 2, 3)
+		x.xxx_hidden_Token = b.Token
+	}
 	return m0
 }
 
-type GetStatsRequest struct {
+type WhoAmIRequest struct {
 	state         protoimpl.MessageState `protogen:"opaque.v1"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetStatsRequest) Reset() {
-	*x = GetStatsRequest{}
-	mi := &file_url_shortener_proto_msgTypes[17]
+func (x *WhoAmIRequest) Reset() {
+	*x = WhoAmIRequest{}
+	mi := &file_url_shortener_proto_msgTypes[35]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetStatsRequest) String() string {
+func (x *WhoAmIRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetStatsRequest) ProtoMessage() {}
+func (*WhoAmIRequest) ProtoMessage() {}
 
-func (x *GetStatsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_url_shortener_proto_msgTypes[17]
+func (x *WhoAmIRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[35]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1294,43 +5414,44 @@ func (x *GetStatsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-type GetStatsRequest_builder struct {
+type WhoAmIRequest_builder struct {
 	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
 
 }
 
-func (b0 GetStatsRequest_builder) Build() *GetStatsRequest {
-	m0 := &GetStatsRequest{}
+func (b0 WhoAmIRequest_builder) Build() *WhoAmIRequest {
+	m0 := &WhoAmIRequest{}
 	b, x := &b0, m0
 	_, _ = b, x
 	return m0
 }
 
-type GetStatsResponse struct {
+type WhoAmIResponse struct {
 	state                  protoimpl.MessageState `protogen:"opaque.v1"`
-	xxx_hidden_TotalUrls   int32                  `protobuf:"varint,1,opt,name=total_urls,json=totalUrls"`
-	xxx_hidden_TotalUsers  int32                  `protobuf:"varint,2,opt,name=total_users,json=totalUsers"`
+	xxx_hidden_UserId      *string                `protobuf:"bytes,1,opt,name=user_id,json=userId"`
+	xxx_hidden_Login       *string                `protobuf:"bytes,2,opt,name=login"`
+	xxx_hidden_Anonymous   bool                   `protobuf:"varint,3,opt,name=anonymous"`
 	XXX_raceDetectHookData protoimpl.RaceDetectHookData
 	XXX_presence           [1]uint32
 	unknownFields          protoimpl.UnknownFields
 	sizeCache              protoimpl.SizeCache
 }
 
-func (x *GetStatsResponse) Reset() {
-	*x = GetStatsResponse{}
-	mi := &file_url_shortener_proto_msgTypes[18]
+func (x *WhoAmIResponse) Reset() {
+	*x = WhoAmIResponse{}
+	mi := &file_url_shortener_proto_msgTypes[36]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetStatsResponse) String() string {
+func (x *WhoAmIResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetStatsResponse) ProtoMessage() {}
+func (*WhoAmIResponse) ProtoMessage() {}
 
-func (x *GetStatsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_url_shortener_proto_msgTypes[18]
+func (x *WhoAmIResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_url_shortener_proto_msgTypes[36]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1341,72 +5462,108 @@ func (x *GetStatsResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-func (x *GetStatsResponse) GetTotalUrls() int32 {
+func (x *WhoAmIResponse) GetUserId() string {
 	if x != nil {
-		return x.xxx_hidden_TotalUrls
+		if x.xxx_hidden_UserId != nil {
+			return *x.xxx_hidden_UserId
+		}
+		return ""
 	}
-	return 0
+	return ""
 }
 
-func (x *GetStatsResponse) GetTotalUsers() int32 {
+func (x *WhoAmIResponse) GetLogin() string {
 	if x != nil {
-		return x.xxx_hidden_TotalUsers
+		if x.xxx_hidden_Login != nil {
+			return *x.xxx_hidden_Login
+		}
+		return ""
 	}
-	return 0
+	return ""
 }
 
-func (x *GetStatsResponse) SetTotalUrls(v int32) {
-	x.xxx_hidden_TotalUrls = v
-	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 0, 2)
+func (x *WhoAmIResponse) GetAnonymous() bool {
+	if x != nil {
+		return x.xxx_hidden_Anonymous
+	}
+	return false
 }
 
-func (x *GetStatsResponse) SetTotalUsers(v int32) {
-	x.xxx_hidden_TotalUsers = v
-	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 1, 2)
+func (x *WhoAmIResponse) SetUserId(v string) {
+	x.xxx_hidden_UserId = &v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 0, 3)
 }
 
-func (x *GetStatsResponse) HasTotalUrls() bool {
+func (x *WhoAmIResponse) SetLogin(v string) {
+	x.xxx_hidden_Login = &v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 1, 3)
+}
+
+func (x *WhoAmIResponse) SetAnonymous(v bool) {
+	x.xxx_hidden_Anonymous = v
+	protoimpl.X.SetPresent(&(x.XXX_presence[0]), 2, 3)
+}
+
+func (x *WhoAmIResponse) HasUserId() bool {
 	if x == nil {
 		return false
 	}
 	return protoimpl.X.Present(&(x.XXX_presence[0]), 0)
 }
 
-func (x *GetStatsResponse) HasTotalUsers() bool {
+func (x *WhoAmIResponse) HasLogin() bool {
 	if x == nil {
 		return false
 	}
 	return protoimpl.X.Present(&(x.XXX_presence[0]), 1)
 }
 
-func (x *GetStatsResponse) ClearTotalUrls() {
+func (x *WhoAmIResponse) HasAnonymous() bool {
+	if x == nil {
+		return false
+	}
+	return protoimpl.X.Present(&(x.XXX_presence[0]), 2)
+}
+
+func (x *WhoAmIResponse) ClearUserId() {
 	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 0)
-	x.xxx_hidden_TotalUrls = 0
+	x.xxx_hidden_UserId = nil
 }
 
-func (x *GetStatsResponse) ClearTotalUsers() {
+func (x *WhoAmIResponse) ClearLogin() {
 	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 1)
-	x.xxx_hidden_TotalUsers = 0
+	x.xxx_hidden_Login = nil
 }
 
-type GetStatsResponse_builder struct {
+func (x *WhoAmIResponse) ClearAnonymous() {
+	protoimpl.X.ClearPresent(&(x.XXX_presence[0]), 2)
+	x.xxx_hidden_Anonymous = false
+}
+
+type WhoAmIResponse_builder struct {
 	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
 
-	TotalUrls  *int32
-	TotalUsers *int32
+	UserId *string
+	// login пуст для анонимного пользователя.
+	Login     *string
+	Anonymous *bool
 }
 
-func (b0 GetStatsResponse_builder) Build() *GetStatsResponse {
-	m0 := &GetStatsResponse{}
+func (b0 WhoAmIResponse_builder) Build() *WhoAmIResponse {
+	m0 := &WhoAmIResponse{}
 	b, x := &b0, m0
 	_, _ = b, x
-	if b.TotalUrls != nil {
-		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 0, 2)
-		x.xxx_hidden_TotalUrls = *b.TotalUrls
+	if b.UserId != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 0, 3)
+		x.xxx_hidden_UserId = b.UserId
 	}
-	if b.TotalUsers != nil {
-		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 1, 2)
-		x.xxx_hidden_TotalUsers = *b.TotalUsers
+	if b.Login != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 1, 3)
+		x.xxx_hidden_Login = b.Login
+	}
+	if b.Anonymous != nil {
+		protoimpl.X.SetPresentNonAtomic(&(x.XXX_presence[0]), 2, 3)
+		x.xxx_hidden_Anonymous = *b.Anonymous
 	}
 	return m0
 }
@@ -1415,51 +5572,197 @@ var File_url_shortener_proto protoreflect.FileDescriptor
 
 const file_url_shortener_proto_rawDesc = "" +
 	"\n" +
-	"\x13url_shortener.proto\x12\x04grpc\x1a!google/protobuf/go_features.proto\"!\n" +
+	"\x13url_shortener.proto\x12\x04grpc\x1a!google/protobuf/go_features.proto\"\x99\x01\n" +
 	"\rGetURLRequest\x12\x10\n" +
-	"\x03url\x18\x01 \x01(\tR\x03url\"3\n" +
+	"\x03url\x18\x01 \x01(\tR\x03url\x12\x1a\n" +
+	"\bpassword\x18\x02 \x01(\tR\bpassword\x12\x12\n" +
+	"\x04path\x18\x03 \x01(\tR\x04path\x12\x14\n" +
+	"\x05query\x18\x04 \x01(\tR\x05query\x12\x18\n" +
+	"\avariant\x18\x05 \x01(\tR\avariant\x12\x16\n" +
+	"\x06domain\x18\x06 \x01(\tR\x06domain\"r\n" +
 	"\x0eGetURLResponse\x12!\n" +
-	"\foriginal_url\x18\x01 \x01(\tR\voriginalUrl\"&\n" +
+	"\foriginal_url\x18\x01 \x01(\tR\voriginalUrl\x12#\n" +
+	"\rredirect_code\x18\x02 \x01(\x05R\fredirectCode\x12\x18\n" +
+	"\avariant\x18\x03 \x01(\tR\avariant\"&\n" +
 	"\fPingResponse\x12\x16\n" +
 	"\x06status\x18\x01 \x01(\tR\x06status\"\r\n" +
-	"\vPingRequest\"6\n" +
+	"\vPingRequest\"\x7f\n" +
+	"\x03UTM\x12\x16\n" +
+	"\x06source\x18\x01 \x01(\tR\x06source\x12\x16\n" +
+	"\x06medium\x18\x02 \x01(\tR\x06medium\x12\x1a\n" +
+	"\bcampaign\x18\x03 \x01(\tR\bcampaign\x12\x12\n" +
+	"\x04term\x18\x04 \x01(\tR\x04term\x12\x18\n" +
+	"\acontent\x18\x05 \x01(\tR\acontent\"\x97\x03\n" +
 	"\x11URLCreatorRequest\x12!\n" +
-	"\foriginal_url\x18\x02 \x01(\tR\voriginalUrl\"4\n" +
+	"\foriginal_url\x18\x02 \x01(\tR\voriginalUrl\x12\x14\n" +
+	"\x05alias\x18\x03 \x01(\tR\x05alias\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\x04 \x01(\tR\texpiresAt\x12\x10\n" +
+	"\x03ttl\x18\x05 \x01(\x03R\x03ttl\x12\x19\n" +
+	"\bmax_uses\x18\x06 \x01(\x05R\amaxUses\x12\x1a\n" +
+	"\bpassword\x18\a \x01(\tR\bpassword\x12\x12\n" +
+	"\x04tags\x18\b \x03(\tR\x04tags\x12\x14\n" +
+	"\x05title\x18\t \x01(\tR\x05title\x12#\n" +
+	"\rredirect_code\x18\n" +
+	" \x01(\x05R\fredirectCode\x12\x1d\n" +
+	"\n" +
+	"pass_query\x18\v \x01(\bR\tpassQuery\x12\x1b\n" +
+	"\tpass_path\x18\f \x01(\bR\bpassPath\x12!\n" +
+	"\futm_template\x18\r \x01(\tR\vutmTemplate\x12\x1b\n" +
+	"\x03utm\x18\x0e \x01(\v2\t.grpc.UTMR\x03utm\x12\x16\n" +
+	"\x06domain\x18\x0f \x01(\tR\x06domain\"4\n" +
 	"\x12URLCreatorResponse\x12\x1e\n" +
 	"\n" +
 	"shortenUrl\x18\x01 \x01(\tR\n" +
-	"shortenUrl\"C\n" +
+	"shortenUrl\"\xa4\x03\n" +
 	"\x15URLCreatorJSONRequest\x12*\n" +
-	"\x11json_original_url\x18\x02 \x01(\tR\x0fjsonOriginalUrl\"9\n" +
+	"\x11json_original_url\x18\x02 \x01(\tR\x0fjsonOriginalUrl\x12\x14\n" +
+	"\x05alias\x18\x03 \x01(\tR\x05alias\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\x04 \x01(\tR\texpiresAt\x12\x10\n" +
+	"\x03ttl\x18\x05 \x01(\x03R\x03ttl\x12\x19\n" +
+	"\bmax_uses\x18\x06 \x01(\x05R\amaxUses\x12\x1a\n" +
+	"\bpassword\x18\a \x01(\tR\bpassword\x12\x12\n" +
+	"\x04tags\x18\b \x03(\tR\x04tags\x12\x14\n" +
+	"\x05title\x18\t \x01(\tR\x05title\x12#\n" +
+	"\rredirect_code\x18\n" +
+	" \x01(\x05R\fredirectCode\x12\x1d\n" +
+	"\n" +
+	"pass_query\x18\v \x01(\bR\tpassQuery\x12\x1b\n" +
+	"\tpass_path\x18\f \x01(\bR\bpassPath\x12!\n" +
+	"\futm_template\x18\r \x01(\tR\vutmTemplate\x12\x1b\n" +
+	"\x03utm\x18\x0e \x01(\v2\t.grpc.UTMR\x03utm\x12\x16\n" +
+	"\x06domain\x18\x0f \x01(\tR\x06domain\"9\n" +
 	"\x16URLCreatorJSONResponse\x12\x1f\n" +
 	"\vjson_result\x18\x01 \x01(\tR\n" +
-	"jsonResult\"V\n" +
+	"jsonResult\"\xb7\x03\n" +
 	"\n" +
 	"URLRequest\x12%\n" +
 	"\x0ecorrelation_id\x18\x01 \x01(\tR\rcorrelationId\x12!\n" +
-	"\foriginal_url\x18\x02 \x01(\tR\voriginalUrl\"Q\n" +
+	"\foriginal_url\x18\x02 \x01(\tR\voriginalUrl\x12\x14\n" +
+	"\x05alias\x18\x03 \x01(\tR\x05alias\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\x04 \x01(\tR\texpiresAt\x12\x10\n" +
+	"\x03ttl\x18\x05 \x01(\x03R\x03ttl\x12\x19\n" +
+	"\bmax_uses\x18\x06 \x01(\x05R\amaxUses\x12\x1a\n" +
+	"\bpassword\x18\a \x01(\tR\bpassword\x12\x12\n" +
+	"\x04tags\x18\b \x03(\tR\x04tags\x12\x14\n" +
+	"\x05title\x18\t \x01(\tR\x05title\x12#\n" +
+	"\rredirect_code\x18\n" +
+	" \x01(\x05R\fredirectCode\x12\x1d\n" +
+	"\n" +
+	"pass_query\x18\v \x01(\bR\tpassQuery\x12\x1b\n" +
+	"\tpass_path\x18\f \x01(\bR\bpassPath\x12!\n" +
+	"\futm_template\x18\r \x01(\tR\vutmTemplate\x12\x1b\n" +
+	"\x03utm\x18\x0e \x01(\v2\t.grpc.UTMR\x03utm\x12\x16\n" +
+	"\x06domain\x18\x0f \x01(\tR\x06domain\"Q\n" +
 	"\vURLResponse\x12%\n" +
 	"\x0ecorrelation_id\x18\x01 \x01(\tR\rcorrelationId\x12\x1b\n" +
 	"\tshort_url\x18\x02 \x01(\tR\bshortUrl\"F\n" +
 	"\x16URLCreatorBatchRequest\x12,\n" +
 	"\brequests\x18\x01 \x03(\v2\x10.grpc.URLRequestR\brequests\"J\n" +
 	"\x17URLCreatorBatchResponse\x12/\n" +
-	"\tresponses\x18\x01 \x03(\v2\x11.grpc.URLResponseR\tresponses\"\x14\n" +
-	"\x12GetUserURLsRequest\"I\n" +
+	"\tresponses\x18\x01 \x03(\v2\x11.grpc.URLResponseR\tresponses\"(\n" +
+	"\x12GetUserURLsRequest\x12\x12\n" +
+	"\x04tags\x18\x01 \x03(\tR\x04tags\"\xf0\x01\n" +
 	"\aUserURL\x12\x1b\n" +
 	"\tshort_url\x18\x01 \x01(\tR\bshortUrl\x12!\n" +
-	"\foriginal_url\x18\x02 \x01(\tR\voriginalUrl\"8\n" +
+	"\foriginal_url\x18\x02 \x01(\tR\voriginalUrl\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\x03 \x01(\tR\texpiresAt\x12%\n" +
+	"\x0eremaining_uses\x18\x04 \x01(\x05R\rremainingUses\x12\x16\n" +
+	"\x06clicks\x18\x05 \x01(\x03R\x06clicks\x12\x12\n" +
+	"\x04tags\x18\x06 \x03(\tR\x04tags\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\a \x01(\tR\tcreatedAt\x12\x14\n" +
+	"\x05title\x18\b \x01(\tR\x05title\"8\n" +
 	"\x13GetUserURLsResponse\x12!\n" +
-	"\x04urls\x18\x01 \x03(\v2\r.grpc.UserURLR\x04urls\"+\n" +
+	"\x04urls\x18\x01 \x03(\v2\r.grpc.UserURLR\x04urls\"\xb9\x01\n" +
+	"\x13ListUserURLsRequest\x12\x1b\n" +
+	"\tpage_size\x18\x01 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x02 \x01(\tR\tpageToken\x12\x12\n" +
+	"\x04sort\x18\x03 \x01(\tR\x04sort\x12\x14\n" +
+	"\x05order\x18\x04 \x01(\tR\x05order\x12\x16\n" +
+	"\x06search\x18\x05 \x01(\tR\x06search\x12\x10\n" +
+	"\x03url\x18\x06 \x01(\tR\x03url\x12\x12\n" +
+	"\x04tags\x18\a \x03(\tR\x04tags\"a\n" +
+	"\x14ListUserURLsResponse\x12!\n" +
+	"\x04urls\x18\x01 \x03(\v2\r.grpc.UserURLR\x04urls\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"+\n" +
 	"\x15DeleteUserURLsRequest\x12\x12\n" +
 	"\x04urls\x18\x01 \x03(\tR\x04urls\"\x18\n" +
-	"\x16DeleteUserURLsResponse\"\x11\n" +
-	"\x0fGetStatsRequest\"R\n" +
+	"\x16DeleteUserURLsResponse\",\n" +
+	"\x16RestoreUserURLsRequest\x12\x12\n" +
+	"\x04urls\x18\x01 \x03(\tR\x04urls\"5\n" +
+	"\x17RestoreUserURLsResponse\x12\x1a\n" +
+	"\brestored\x18\x01 \x03(\tR\brestored\"\x11\n" +
+	"\x0fGetStatsRequest\"u\n" +
 	"\x10GetStatsResponse\x12\x1d\n" +
 	"\n" +
 	"total_urls\x18\x01 \x01(\x05R\ttotalUrls\x12\x1f\n" +
 	"\vtotal_users\x18\x02 \x01(\x05R\n" +
-	"totalUsers2\x9c\x04\n" +
+	"totalUsers\x12!\n" +
+	"\ftotal_clicks\x18\x03 \x01(\x03R\vtotalClicks\"I\n" +
+	"\x12GetURLStatsRequest\x12\x1b\n" +
+	"\tshort_url\x18\x01 \x01(\tR\bshortUrl\x12\x16\n" +
+	"\x06domain\x18\x02 \x01(\tR\x06domain\"4\n" +
+	"\n" +
+	"ClickCount\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\x03R\x05count\"\xee\x01\n" +
+	"\x13GetURLStatsResponse\x12\x14\n" +
+	"\x05total\x18\x01 \x01(\x03R\x05total\x12'\n" +
+	"\x06by_day\x18\x02 \x03(\v2\x10.grpc.ClickCountR\x05byDay\x121\n" +
+	"\vby_referrer\x18\x03 \x03(\v2\x10.grpc.ClickCountR\n" +
+	"byReferrer\x124\n" +
+	"\rby_user_agent\x18\x04 \x03(\v2\x10.grpc.ClickCountR\vbyUserAgent\x12/\n" +
+	"\n" +
+	"by_variant\x18\x05 \x03(\v2\x10.grpc.ClickCountR\tbyVariant\"\x89\x01\n" +
+	"\x10UpdateURLRequest\x12\x1b\n" +
+	"\tshort_url\x18\x01 \x01(\tR\bshortUrl\x12!\n" +
+	"\foriginal_url\x18\x02 \x01(\tR\voriginalUrl\x12\x1d\n" +
+	"\n" +
+	"if_version\x18\x03 \x01(\x05R\tifVersion\x12\x16\n" +
+	"\x06domain\x18\x04 \x01(\tR\x06domain\"m\n" +
+	"\x11UpdateURLResponse\x12\x1b\n" +
+	"\tshort_url\x18\x01 \x01(\tR\bshortUrl\x12!\n" +
+	"\foriginal_url\x18\x02 \x01(\tR\voriginalUrl\x12\x18\n" +
+	"\aversion\x18\x03 \x01(\x05R\aversion\"\xa1\x01\n" +
+	"\x10GetQRCodeRequest\x12\x1b\n" +
+	"\tshort_url\x18\x01 \x01(\tR\bshortUrl\x12\x16\n" +
+	"\x06format\x18\x02 \x01(\tR\x06format\x12\x12\n" +
+	"\x04size\x18\x03 \x01(\x05R\x04size\x12\x16\n" +
+	"\x06margin\x18\x04 \x01(\x05R\x06margin\x12\x14\n" +
+	"\x05level\x18\x05 \x01(\tR\x05level\x12\x16\n" +
+	"\x06domain\x18\x06 \x01(\tR\x06domain\"L\n" +
+	"\x11GetQRCodeResponse\x12\x14\n" +
+	"\x05image\x18\x01 \x01(\fR\x05image\x12!\n" +
+	"\fcontent_type\x18\x02 \x01(\tR\vcontentType\"G\n" +
+	"\x10ExpandURLRequest\x12\x1b\n" +
+	"\tshort_url\x18\x01 \x01(\tR\bshortUrl\x12\x16\n" +
+	"\x06domain\x18\x02 \x01(\tR\x06domain\"\xe7\x01\n" +
+	"\x11ExpandURLResponse\x12\x1b\n" +
+	"\tshort_url\x18\x01 \x01(\tR\bshortUrl\x12!\n" +
+	"\foriginal_url\x18\x02 \x01(\tR\voriginalUrl\x12\x14\n" +
+	"\x05title\x18\x03 \x01(\tR\x05title\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x04 \x01(\tR\tcreatedAt\x12\x16\n" +
+	"\x06status\x18\x05 \x01(\tR\x06status\x12-\n" +
+	"\x12password_protected\x18\x06 \x01(\bR\x11passwordProtected\x12\x16\n" +
+	"\x06clicks\x18\a \x01(\x03R\x06clicks\"?\n" +
+	"\vAuthRequest\x12\x14\n" +
+	"\x05login\x18\x01 \x01(\tR\x05login\x12\x1a\n" +
+	"\bpassword\x18\x02 \x01(\tR\bpassword\"S\n" +
+	"\fAuthResponse\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x14\n" +
+	"\x05login\x18\x02 \x01(\tR\x05login\x12\x14\n" +
+	"\x05token\x18\x03 \x01(\tR\x05token\"\x0f\n" +
+	"\rWhoAmIRequest\"]\n" +
+	"\x0eWhoAmIResponse\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x14\n" +
+	"\x05login\x18\x02 \x01(\tR\x05login\x12\x1c\n" +
+	"\tanonymous\x18\x03 \x01(\bR\tanonymous2\xc9\b\n" +
 	"\fURLShortener\x123\n" +
 	"\x06GetURL\x12\x13.grpc.GetURLRequest\x1a\x14.grpc.GetURLResponse\x12-\n" +
 	"\x04Ping\x12\x11.grpc.PingRequest\x1a\x12.grpc.PingResponse\x12?\n" +
@@ -1467,57 +5770,110 @@ const file_url_shortener_proto_rawDesc = "" +
 	"URLCreator\x12\x17.grpc.URLCreatorRequest\x1a\x18.grpc.URLCreatorResponse\x12K\n" +
 	"\x0eURLCreatorJSON\x12\x1b.grpc.URLCreatorJSONRequest\x1a\x1c.grpc.URLCreatorJSONResponse\x12N\n" +
 	"\x0fURLCreatorBatch\x12\x1c.grpc.URLCreatorBatchRequest\x1a\x1d.grpc.URLCreatorBatchResponse\x12B\n" +
-	"\vGetUserURLs\x12\x18.grpc.GetUserURLsRequest\x1a\x19.grpc.GetUserURLsResponse\x12K\n" +
-	"\x0eDeleteUserURLs\x12\x1b.grpc.DeleteUserURLsRequest\x1a\x1c.grpc.DeleteUserURLsResponse\x129\n" +
-	"\bGetStats\x12\x15.grpc.GetStatsRequest\x1a\x16.grpc.GetStatsResponseB\x11Z\a./proto\x92\x03\x05\xd2>\x02\x10\x03b\beditionsp\xe8\a"
-
-var file_url_shortener_proto_msgTypes = make([]protoimpl.MessageInfo, 19)
+	"\vGetUserURLs\x12\x18.grpc.GetUserURLsRequest\x1a\x19.grpc.GetUserURLsResponse\x12E\n" +
+	"\fListUserURLs\x12\x19.grpc.ListUserURLsRequest\x1a\x1a.grpc.ListUserURLsResponse\x12K\n" +
+	"\x0eDeleteUserURLs\x12\x1b.grpc.DeleteUserURLsRequest\x1a\x1c.grpc.DeleteUserURLsResponse\x12N\n" +
+	"\x0fRestoreUserURLs\x12\x1c.grpc.RestoreUserURLsRequest\x1a\x1d.grpc.RestoreUserURLsResponse\x129\n" +
+	"\bGetStats\x12\x15.grpc.GetStatsRequest\x1a\x16.grpc.GetStatsResponse\x12B\n" +
+	"\vGetURLStats\x12\x18.grpc.GetURLStatsRequest\x1a\x19.grpc.GetURLStatsResponse\x12<\n" +
+	"\tUpdateURL\x12\x16.grpc.UpdateURLRequest\x1a\x17.grpc.UpdateURLResponse\x12<\n" +
+	"\tGetQRCode\x12\x16.grpc.GetQRCodeRequest\x1a\x17.grpc.GetQRCodeResponse\x12<\n" +
+	"\tExpandURL\x12\x16.grpc.ExpandURLRequest\x1a\x17.grpc.ExpandURLResponse\x121\n" +
+	"\bRegister\x12\x11.grpc.AuthRequest\x1a\x12.grpc.AuthResponse\x12.\n" +
+	"\x05Login\x12\x11.grpc.AuthRequest\x1a\x12.grpc.AuthResponse\x123\n" +
+	"\x06WhoAmI\x12\x13.grpc.WhoAmIRequest\x1a\x14.grpc.WhoAmIResponseB\x11Z\a./proto\x92\x03\x05\xd2>\x02\x10\x03b\beditionsp\xe8\a"
+
+var file_url_shortener_proto_msgTypes = make([]protoimpl.MessageInfo, 37)
 var file_url_shortener_proto_goTypes = []any{
 	(*GetURLRequest)(nil),           // 0: grpc.GetURLRequest
 	(*GetURLResponse)(nil),          // 1: grpc.GetURLResponse
 	(*PingResponse)(nil),            // 2: grpc.PingResponse
 	(*PingRequest)(nil),             // 3: grpc.PingRequest
-	(*URLCreatorRequest)(nil),       // 4: grpc.URLCreatorRequest
-	(*URLCreatorResponse)(nil),      // 5: grpc.URLCreatorResponse
-	(*URLCreatorJSONRequest)(nil),   // 6: grpc.URLCreatorJSONRequest
-	(*URLCreatorJSONResponse)(nil),  // 7: grpc.URLCreatorJSONResponse
-	(*URLRequest)(nil),              // 8: grpc.URLRequest
-	(*URLResponse)(nil),             // 9: grpc.URLResponse
-	(*URLCreatorBatchRequest)(nil),  // 10: grpc.URLCreatorBatchRequest
-	(*URLCreatorBatchResponse)(nil), // 11: grpc.URLCreatorBatchResponse
-	(*GetUserURLsRequest)(nil),      // 12: grpc.GetUserURLsRequest
-	(*UserURL)(nil),                 // 13: grpc.UserURL
-	(*GetUserURLsResponse)(nil),     // 14: grpc.GetUserURLsResponse
-	(*DeleteUserURLsRequest)(nil),   // 15: grpc.DeleteUserURLsRequest
-	(*DeleteUserURLsResponse)(nil),  // 16: grpc.DeleteUserURLsResponse
-	(*GetStatsRequest)(nil),         // 17: grpc.GetStatsRequest
-	(*GetStatsResponse)(nil),        // 18: grpc.GetStatsResponse
+	(*UTM)(nil),                     // 4: grpc.UTM
+	(*URLCreatorRequest)(nil),       // 5: grpc.URLCreatorRequest
+	(*URLCreatorResponse)(nil),      // 6: grpc.URLCreatorResponse
+	(*URLCreatorJSONRequest)(nil),   // 7: grpc.URLCreatorJSONRequest
+	(*URLCreatorJSONResponse)(nil),  // 8: grpc.URLCreatorJSONResponse
+	(*URLRequest)(nil),              // 9: grpc.URLRequest
+	(*URLResponse)(nil),             // 10: grpc.URLResponse
+	(*URLCreatorBatchRequest)(nil),  // 11: grpc.URLCreatorBatchRequest
+	(*URLCreatorBatchResponse)(nil), // 12: grpc.URLCreatorBatchResponse
+	(*GetUserURLsRequest)(nil),      // 13: grpc.GetUserURLsRequest
+	(*UserURL)(nil),                 // 14: grpc.UserURL
+	(*GetUserURLsResponse)(nil),     // 15: grpc.GetUserURLsResponse
+	(*ListUserURLsRequest)(nil),     // 16: grpc.ListUserURLsRequest
+	(*ListUserURLsResponse)(nil),    // 17: grpc.ListUserURLsResponse
+	(*DeleteUserURLsRequest)(nil),   // 18: grpc.DeleteUserURLsRequest
+	(*DeleteUserURLsResponse)(nil),  // 19: grpc.DeleteUserURLsResponse
+	(*RestoreUserURLsRequest)(nil),  // 20: grpc.RestoreUserURLsRequest
+	(*RestoreUserURLsResponse)(nil), // 21: grpc.RestoreUserURLsResponse
+	(*GetStatsRequest)(nil),         // 22: grpc.GetStatsRequest
+	(*GetStatsResponse)(nil),        // 23: grpc.GetStatsResponse
+	(*GetURLStatsRequest)(nil),      // 24: grpc.GetURLStatsRequest
+	(*ClickCount)(nil),              // 25: grpc.ClickCount
+	(*GetURLStatsResponse)(nil),     // 26: grpc.GetURLStatsResponse
+	(*UpdateURLRequest)(nil),        // 27: grpc.UpdateURLRequest
+	(*UpdateURLResponse)(nil),       // 28: grpc.UpdateURLResponse
+	(*GetQRCodeRequest)(nil),        // 29: grpc.GetQRCodeRequest
+	(*GetQRCodeResponse)(nil),       // 30: grpc.GetQRCodeResponse
+	(*ExpandURLRequest)(nil),        // 31: grpc.ExpandURLRequest
+	(*ExpandURLResponse)(nil),       // 32: grpc.ExpandURLResponse
+	(*AuthRequest)(nil),             // 33: grpc.AuthRequest
+	(*AuthResponse)(nil),            // 34: grpc.AuthResponse
+	(*WhoAmIRequest)(nil),           // 35: grpc.WhoAmIRequest
+	(*WhoAmIResponse)(nil),          // 36: grpc.WhoAmIResponse
 }
 var file_url_shortener_proto_depIdxs = []int32{
-	8,  // 0: grpc.URLCreatorBatchRequest.requests:type_name -> grpc.URLRequest
-	9,  // 1: grpc.URLCreatorBatchResponse.responses:type_name -> grpc.URLResponse
-	13, // 2: grpc.GetUserURLsResponse.urls:type_name -> grpc.UserURL
-	0,  // 3: grpc.URLShortener.GetURL:input_type -> grpc.GetURLRequest
-	3,  // 4: grpc.URLShortener.Ping:input_type -> grpc.PingRequest
-	4,  // 5: grpc.URLShortener.URLCreator:input_type -> grpc.URLCreatorRequest
-	6,  // 6: grpc.URLShortener.URLCreatorJSON:input_type -> grpc.URLCreatorJSONRequest
-	10, // 7: grpc.URLShortener.URLCreatorBatch:input_type -> grpc.URLCreatorBatchRequest
-	12, // 8: grpc.URLShortener.GetUserURLs:input_type -> grpc.GetUserURLsRequest
-	15, // 9: grpc.URLShortener.DeleteUserURLs:input_type -> grpc.DeleteUserURLsRequest
-	17, // 10: grpc.URLShortener.GetStats:input_type -> grpc.GetStatsRequest
-	1,  // 11: grpc.URLShortener.GetURL:output_type -> grpc.GetURLResponse
-	2,  // 12: grpc.URLShortener.Ping:output_type -> grpc.PingResponse
-	5,  // 13: grpc.URLShortener.URLCreator:output_type -> grpc.URLCreatorResponse
-	7,  // 14: grpc.URLShortener.URLCreatorJSON:output_type -> grpc.URLCreatorJSONResponse
-	11, // 15: grpc.URLShortener.URLCreatorBatch:output_type -> grpc.URLCreatorBatchResponse
-	14, // 16: grpc.URLShortener.GetUserURLs:output_type -> grpc.GetUserURLsResponse
-	16, // 17: grpc.URLShortener.DeleteUserURLs:output_type -> grpc.DeleteUserURLsResponse
-	18, // 18: grpc.URLShortener.GetStats:output_type -> grpc.GetStatsResponse
-	11, // [11:19] is the sub-list for method output_type
-	3,  // [3:11] is the sub-list for method input_type
-	3,  // [3:3] is the sub-list for extension type_name
-	3,  // [3:3] is the sub-list for extension extendee
-	0,  // [0:3] is the sub-list for field type_name
+	4,  // 0: grpc.URLCreatorRequest.utm:type_name -> grpc.UTM
+	4,  // 1: grpc.URLCreatorJSONRequest.utm:type_name -> grpc.UTM
+	4,  // 2: grpc.URLRequest.utm:type_name -> grpc.UTM
+	9,  // 3: grpc.URLCreatorBatchRequest.requests:type_name -> grpc.URLRequest
+	10, // 4: grpc.URLCreatorBatchResponse.responses:type_name -> grpc.URLResponse
+	14, // 5: grpc.GetUserURLsResponse.urls:type_name -> grpc.UserURL
+	14, // 6: grpc.ListUserURLsResponse.urls:type_name -> grpc.UserURL
+	25, // 7: grpc.GetURLStatsResponse.by_day:type_name -> grpc.ClickCount
+	25, // 8: grpc.GetURLStatsResponse.by_referrer:type_name -> grpc.ClickCount
+	25, // 9: grpc.GetURLStatsResponse.by_user_agent:type_name -> grpc.ClickCount
+	25, // 10: grpc.GetURLStatsResponse.by_variant:type_name -> grpc.ClickCount
+	0,  // 11: grpc.URLShortener.GetURL:input_type -> grpc.GetURLRequest
+	3,  // 12: grpc.URLShortener.Ping:input_type -> grpc.PingRequest
+	5,  // 13: grpc.URLShortener.URLCreator:input_type -> grpc.URLCreatorRequest
+	7,  // 14: grpc.URLShortener.URLCreatorJSON:input_type -> grpc.URLCreatorJSONRequest
+	11, // 15: grpc.URLShortener.URLCreatorBatch:input_type -> grpc.URLCreatorBatchRequest
+	13, // 16: grpc.URLShortener.GetUserURLs:input_type -> grpc.GetUserURLsRequest
+	16, // 17: grpc.URLShortener.ListUserURLs:input_type -> grpc.ListUserURLsRequest
+	18, // 18: grpc.URLShortener.DeleteUserURLs:input_type -> grpc.DeleteUserURLsRequest
+	20, // 19: grpc.URLShortener.RestoreUserURLs:input_type -> grpc.RestoreUserURLsRequest
+	22, // 20: grpc.URLShortener.GetStats:input_type -> grpc.GetStatsRequest
+	24, // 21: grpc.URLShortener.GetURLStats:input_type -> grpc.GetURLStatsRequest
+	27, // 22: grpc.URLShortener.UpdateURL:input_type -> grpc.UpdateURLRequest
+	29, // 23: grpc.URLShortener.GetQRCode:input_type -> grpc.GetQRCodeRequest
+	31, // 24: grpc.URLShortener.ExpandURL:input_type -> grpc.ExpandURLRequest
+	33, // 25: grpc.URLShortener.Register:input_type -> grpc.AuthRequest
+	33, // 26: grpc.URLShortener.Login:input_type -> grpc.AuthRequest
+	35, // 27: grpc.URLShortener.WhoAmI:input_type -> grpc.WhoAmIRequest
+	1,  // 28: grpc.URLShortener.GetURL:output_type -> grpc.GetURLResponse
+	2,  // 29: grpc.URLShortener.Ping:output_type -> grpc.PingResponse
+	6,  // 30: grpc.URLShortener.URLCreator:output_type -> grpc.URLCreatorResponse
+	8,  // 31: grpc.URLShortener.URLCreatorJSON:output_type -> grpc.URLCreatorJSONResponse
+	12, // 32: grpc.URLShortener.URLCreatorBatch:output_type -> grpc.URLCreatorBatchResponse
+	15, // 33: grpc.URLShortener.GetUserURLs:output_type -> grpc.GetUserURLsResponse
+	17, // 34: grpc.URLShortener.ListUserURLs:output_type -> grpc.ListUserURLsResponse
+	19, // 35: grpc.URLShortener.DeleteUserURLs:output_type -> grpc.DeleteUserURLsResponse
+	21, // 36: grpc.URLShortener.RestoreUserURLs:output_type -> grpc.RestoreUserURLsResponse
+	23, // 37: grpc.URLShortener.GetStats:output_type -> grpc.GetStatsResponse
+	26, // 38: grpc.URLShortener.GetURLStats:output_type -> grpc.GetURLStatsResponse
+	28, // 39: grpc.URLShortener.UpdateURL:output_type -> grpc.UpdateURLResponse
+	30, // 40: grpc.URLShortener.GetQRCode:output_type -> grpc.GetQRCodeResponse
+	32, // 41: grpc.URLShortener.ExpandURL:output_type -> grpc.ExpandURLResponse
+	34, // 42: grpc.URLShortener.Register:output_type -> grpc.AuthResponse
+	34, // 43: grpc.URLShortener.Login:output_type -> grpc.AuthResponse
+	36, // 44: grpc.URLShortener.WhoAmI:output_type -> grpc.WhoAmIResponse
+	28, // [28:45] is the sub-list for method output_type
+	11, // [11:28] is the sub-list for method input_type
+	11, // [11:11] is the sub-list for extension type_name
+	11, // [11:11] is the sub-list for extension extendee
+	0,  // [0:11] is the sub-list for field type_name
 }
 
 func init() { file_url_shortener_proto_init() }
@@ -1531,7 +5887,7 @@ func file_url_shortener_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_url_shortener_proto_rawDesc), len(file_url_shortener_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   19,
+			NumMessages:   37,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -4,6 +4,7 @@ import (
 	"github.com/aseptimu/url-shortener/internal/app/config"
 	"github.com/aseptimu/url-shortener/internal/app/handlers/http/dbhandlers"
 	"github.com/aseptimu/url-shortener/internal/app/handlers/http/shortenurlhandlers"
+	"github.com/aseptimu/url-shortener/internal/app/middleware"
 	"github.com/aseptimu/url-shortener/internal/app/service"
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
@@ -18,6 +19,18 @@ type handlersImpl struct {
 	urlSvc       service.URLShortener
 	urlGetSvc    shortenurlhandlers.URLGetter
 	urlDeleteSvc service.URLDeleter
+	clickSvc     service.ClickTracker
+	editSvc      service.URLEditor
+	tagSvc       service.URLTagger
+	qrSvc        service.QRCoder
+	expandSvc    service.URLExpander
+	ruleSvc      service.RuleManager
+	splitSvc     service.URLSplitter
+	utmSvc       service.UTMTemplater
+	workspaceSvc service.WorkspaceManager
+	apiKeySvc    service.APIKeyManager
+	accountSvc   service.AccountManager
+	tokens       *middleware.Tokens
 	pinger       dbhandlers.Pinger
 	logger       *zap.SugaredLogger
 }
@@ -27,6 +40,18 @@ func New(
 	urlSvc service.URLShortener,
 	urlGetSvc shortenurlhandlers.URLGetter,
 	urlDeleteSvc service.URLDeleter,
+	clickSvc service.ClickTracker,
+	editSvc service.URLEditor,
+	tagSvc service.URLTagger,
+	qrSvc service.QRCoder,
+	expandSvc service.URLExpander,
+	ruleSvc service.RuleManager,
+	splitSvc service.URLSplitter,
+	utmSvc service.UTMTemplater,
+	workspaceSvc service.WorkspaceManager,
+	apiKeySvc service.APIKeyManager,
+	accountSvc service.AccountManager,
+	tokens *middleware.Tokens,
 	pinger dbhandlers.Pinger,
 	logger *zap.SugaredLogger,
 ) Handlers {
@@ -35,18 +60,80 @@ func New(
 		urlSvc:       urlSvc,
 		urlGetSvc:    urlGetSvc,
 		urlDeleteSvc: urlDeleteSvc,
+		clickSvc:     clickSvc,
+		editSvc:      editSvc,
+		tagSvc:       tagSvc,
+		qrSvc:        qrSvc,
+		expandSvc:    expandSvc,
+		ruleSvc:      ruleSvc,
+		splitSvc:     splitSvc,
+		utmSvc:       utmSvc,
+		workspaceSvc: workspaceSvc,
+		apiKeySvc:    apiKeySvc,
+		accountSvc:   accountSvc,
+		tokens:       tokens,
 		pinger:       pinger,
 		logger:       logger,
 	}
 }
 
 func (h *handlersImpl) RegisterRoutes(r *gin.Engine) {
-	r.GET("/:url", shortenurlhandlers.NewGetURLHandler(h.cfg, h.urlGetSvc, h.logger).GetURL)
+	// Маршруты ссылок пользователя принимают параметр workspace и проверяют роль в рабочем пространстве.
+	viewer := middleware.WorkspaceMiddleware(h.workspaceSvc, service.RoleViewer, h.logger)
+	editor := middleware.WorkspaceMiddleware(h.workspaceSvc, service.RoleEditor, h.logger)
+	// Запросы по API-ключу ограничены его областями действия; управление учётной записью им недоступно.
+	shorten := middleware.RequireScope(service.ScopeShorten)
+	read := middleware.RequireScope(service.ScopeRead)
+	remove := middleware.RequireScope(service.ScopeDelete)
+	stats := middleware.RequireScope(service.ScopeStats)
+	session := middleware.RequireSession()
+
+	shortLink := shortenurlhandlers.ShortLink(
+		shortenurlhandlers.NewGetURLHandler(h.cfg, h.urlGetSvc, h.clickSvc, h.logger).GetURL,
+		shortenurlhandlers.NewExpandHandler(h.cfg, h.expandSvc, h.logger).PreviewURL,
+		shortenurlhandlers.NewQRCodeHandler(h.cfg, h.qrSvc, h.logger).GetQRCode,
+	)
+	r.GET("/:url", shortLink)
+	r.GET("/:url/*path", shortLink)
+	r.POST("/:url", shortenurlhandlers.NewGetURLHandler(h.cfg, h.urlGetSvc, h.clickSvc, h.logger).UnlockURL)
+	r.POST("/:url/*path", shortenurlhandlers.NewGetURLHandler(h.cfg, h.urlGetSvc, h.clickSvc, h.logger).UnlockURL)
 	r.GET("/ping", dbhandlers.NewPingHandler(h.pinger).Ping)
-	r.POST("/", shortenurlhandlers.NewShortenHandler(h.cfg, h.urlSvc, h.logger).URLCreator)
-	r.POST("/api/shorten", shortenurlhandlers.NewShortenHandler(h.cfg, h.urlSvc, h.logger).URLCreatorJSON)
-	r.POST("/api/shorten/batch", shortenurlhandlers.NewShortenHandler(h.cfg, h.urlSvc, h.logger).URLCreatorBatch)
-	r.GET("/api/user/urls", shortenurlhandlers.NewGetURLHandler(h.cfg, h.urlGetSvc, h.logger).GetUserURLs)
-	r.DELETE("/api/user/urls", shortenurlhandlers.NewDeleteURLHandler(h.cfg, h.urlDeleteSvc, h.logger).DeleteUserURLs)
-	r.GET("/api/internal/stats", shortenurlhandlers.NewGetURLHandler(h.cfg, h.urlGetSvc, h.logger).GetStats)
+	r.POST("/", shorten, editor, shortenurlhandlers.NewShortenHandler(h.cfg, h.urlSvc, h.logger).URLCreator)
+	r.POST("/api/shorten", shorten, editor, shortenurlhandlers.NewShortenHandler(h.cfg, h.urlSvc, h.logger).URLCreatorJSON)
+	r.POST("/api/shorten/batch", shorten, editor, shortenurlhandlers.NewShortenHandler(h.cfg, h.urlSvc, h.logger).URLCreatorBatch)
+	r.GET("/api/expand/:short", shortenurlhandlers.NewExpandHandler(h.cfg, h.expandSvc, h.logger).ExpandURL)
+	r.GET("/api/user/urls", read, viewer, shortenurlhandlers.NewGetURLHandler(h.cfg, h.urlGetSvc, h.clickSvc, h.logger).GetUserURLs)
+	r.DELETE("/api/user/urls", remove, editor, shortenurlhandlers.NewDeleteURLHandler(h.cfg, h.urlDeleteSvc, h.logger).DeleteUserURLs)
+	r.POST("/api/user/urls/restore", remove, editor, shortenurlhandlers.NewDeleteURLHandler(h.cfg, h.urlDeleteSvc, h.logger).RestoreUserURLs)
+	r.PATCH("/api/user/urls/:short", shorten, editor, shortenurlhandlers.NewEditURLHandler(h.cfg, h.editSvc, h.logger).UpdateURL)
+	r.GET("/api/user/urls/:short/history", read, viewer, shortenurlhandlers.NewEditURLHandler(h.cfg, h.editSvc, h.logger).GetURLHistory)
+	r.POST("/api/user/urls/:short/rollback", shorten, editor, shortenurlhandlers.NewEditURLHandler(h.cfg, h.editSvc, h.logger).RollbackURL)
+	r.GET("/api/user/tags", read, viewer, shortenurlhandlers.NewTagHandler(h.cfg, h.tagSvc, h.logger).ListTags)
+	r.PUT("/api/user/urls/:short/tags", shorten, editor, shortenurlhandlers.NewTagHandler(h.cfg, h.tagSvc, h.logger).SetTags)
+	r.POST("/api/user/urls/:short/tags", shorten, editor, shortenurlhandlers.NewTagHandler(h.cfg, h.tagSvc, h.logger).AddTags)
+	r.DELETE("/api/user/urls/:short/tags/:tag", shorten, editor, shortenurlhandlers.NewTagHandler(h.cfg, h.tagSvc, h.logger).RemoveTag)
+	r.GET("/api/user/urls/:short/rules", read, viewer, shortenurlhandlers.NewRuleHandler(h.cfg, h.ruleSvc, h.logger).GetRules)
+	r.PUT("/api/user/urls/:short/rules", shorten, editor, shortenurlhandlers.NewRuleHandler(h.cfg, h.ruleSvc, h.logger).SetRules)
+	r.GET("/api/user/urls/:short/variants", read, viewer, shortenurlhandlers.NewSplitHandler(h.cfg, h.splitSvc, h.logger).GetVariants)
+	r.PUT("/api/user/urls/:short/variants", shorten, editor, shortenurlhandlers.NewSplitHandler(h.cfg, h.splitSvc, h.logger).SetVariants)
+	r.GET("/api/user/utm-templates", read, viewer, shortenurlhandlers.NewUTMTemplateHandler(h.cfg, h.utmSvc, h.logger).ListTemplates)
+	r.PUT("/api/user/utm-templates/:name", shorten, editor, shortenurlhandlers.NewUTMTemplateHandler(h.cfg, h.utmSvc, h.logger).SaveTemplate)
+	r.DELETE("/api/user/utm-templates/:name", shorten, editor, shortenurlhandlers.NewUTMTemplateHandler(h.cfg, h.utmSvc, h.logger).DeleteTemplate)
+	r.GET("/api/user/urls/:short/stats", stats, viewer, shortenurlhandlers.NewClickStatsHandler(h.cfg, h.clickSvc, h.logger).GetURLStats)
+	r.GET("/api/user/urls/:short/clicks.csv", stats, viewer, shortenurlhandlers.NewClickStatsHandler(h.cfg, h.clickSvc, h.logger).ExportClicks)
+	r.POST("/api/user/workspaces", session, shortenurlhandlers.NewWorkspaceHandler(h.cfg, h.workspaceSvc, h.logger).CreateWorkspace)
+	r.GET("/api/user/workspaces", session, shortenurlhandlers.NewWorkspaceHandler(h.cfg, h.workspaceSvc, h.logger).ListWorkspaces)
+	r.GET("/api/user/workspaces/:workspace/members", session, shortenurlhandlers.NewWorkspaceHandler(h.cfg, h.workspaceSvc, h.logger).ListMembers)
+	r.PUT("/api/user/workspaces/:workspace/members/:member", session, shortenurlhandlers.NewWorkspaceHandler(h.cfg, h.workspaceSvc, h.logger).SetMember)
+	r.DELETE("/api/user/workspaces/:workspace/members/:member", session, shortenurlhandlers.NewWorkspaceHandler(h.cfg, h.workspaceSvc, h.logger).RemoveMember)
+	r.POST("/api/user/api-keys", session, shortenurlhandlers.NewAPIKeyHandler(h.cfg, h.apiKeySvc, h.logger).CreateKey)
+	r.GET("/api/user/api-keys", session, shortenurlhandlers.NewAPIKeyHandler(h.cfg, h.apiKeySvc, h.logger).ListKeys)
+	r.DELETE("/api/user/api-keys/:id", session, shortenurlhandlers.NewAPIKeyHandler(h.cfg, h.apiKeySvc, h.logger).RevokeKey)
+	r.POST("/api/user/register", session, shortenurlhandlers.NewAccountHandler(h.cfg, h.accountSvc, h.tokens, h.logger).Register)
+	r.POST("/api/user/login", session, shortenurlhandlers.NewAccountHandler(h.cfg, h.accountSvc, h.tokens, h.logger).Login)
+	r.POST("/api/user/logout", session, shortenurlhandlers.NewAccountHandler(h.cfg, h.accountSvc, h.tokens, h.logger).Logout)
+	r.GET("/api/user/me", shortenurlhandlers.NewAccountHandler(h.cfg, h.accountSvc, h.tokens, h.logger).Me)
+	r.GET("/api/internal/stats", shortenurlhandlers.NewGetURLHandler(h.cfg, h.urlGetSvc, h.clickSvc, h.logger).GetStats)
+	r.GET("/api/internal/domains", shortenurlhandlers.NewDomainHandler(h.cfg, h.logger).ListDomains)
+	r.GET(service.DomainVerificationPath, shortenurlhandlers.NewDomainHandler(h.cfg, h.logger).VerificationFile)
 }
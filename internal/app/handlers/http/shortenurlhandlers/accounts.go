@@ -0,0 +1,149 @@
+package shortenurlhandlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/aseptimu/url-shortener/internal/app/config"
+	"github.com/aseptimu/url-shortener/internal/app/middleware"
+	"github.com/aseptimu/url-shortener/internal/app/service"
+	"github.com/aseptimu/url-shortener/internal/app/utils"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// AccountHandler обрабатывает регистрацию, вход и выход из учётной записи.
+type AccountHandler struct {
+	cfg     *config.ConfigType
+	service service.AccountManager
+	tokens  *middleware.Tokens
+	logger  *zap.SugaredLogger
+}
+
+// NewAccountHandler создаёт новый экземпляр AccountHandler. tokens выпускает и отзывает JWT учётных записей.
+func NewAccountHandler(cfg *config.ConfigType, service service.AccountManager, tokens *middleware.Tokens, logger *zap.SugaredLogger) *AccountHandler {
+	return &AccountHandler{cfg: cfg, service: service, tokens: tokens, logger: logger}
+}
+
+type credentials struct {
+	Login    string `json:"login"`
+	Password string `json:"password"`
+}
+
+type accountResponse struct {
+	UserID    string     `json:"user_id"`
+	Login     string     `json:"login,omitempty"`
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+	Anonymous bool       `json:"anonymous"`
+}
+
+// Register обрабатывает POST /api/user/register.
+// Принимает JSON {"login": "...", "password": "..."}, создаёт учётную запись и входит в неё.
+// Ссылки анонимного пользователя остаются за учётной записью. Возвращает 201 Created,
+// для занятого логина — 409 Conflict.
+func (h *AccountHandler) Register(c *gin.Context) {
+	utils.LogRequest(c, h.logger)
+
+	var req credentials
+	if err := json.NewDecoder(c.Request.Body).Decode(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON format"})
+		return
+	}
+
+	account, err := h.service.Register(c.Request.Context(), c.GetString("userID"), req.Login, req.Password)
+	if h.abortOnError(c, err, "Failed to register account") {
+		return
+	}
+	h.startSession(c, http.StatusCreated, account)
+}
+
+// Login обрабатывает POST /api/user/login.
+// Принимает JSON {"login": "...", "password": "..."} и выдаёт cookie учётной записи;
+// ссылки анонимного пользователя переходят в неё. Неверный логин или пароль — 401 Unauthorized,
+// превышение лимита попыток для логина с адреса клиента — 429 Too Many Requests.
+func (h *AccountHandler) Login(c *gin.Context) {
+	utils.LogRequest(c, h.logger)
+
+	var req credentials
+	if err := json.NewDecoder(c.Request.Body).Decode(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON format"})
+		return
+	}
+
+	account, err := h.service.Login(c.Request.Context(), c.GetString("userID"), req.Login, req.Password, c.ClientIP())
+	if h.abortOnError(c, err, "Failed to log in") {
+		return
+	}
+	h.startSession(c, http.StatusOK, account)
+}
+
+// Logout обрабатывает POST /api/user/logout: отзывает JWT из cookie, удаляет cookie
+// и возвращает 204 No Content.
+func (h *AccountHandler) Logout(c *gin.Context) {
+	utils.LogRequest(c, h.logger)
+
+	if err := middleware.EndSession(c, h.tokens); err != nil {
+		h.logger.Errorw("Failed to revoke JWT", "error", err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// Me обрабатывает GET /api/user/me и возвращает текущего пользователя:
+// логин для учётной записи или признак anonymous для анонимного пользователя.
+func (h *AccountHandler) Me(c *gin.Context) {
+	utils.LogRequest(c, h.logger)
+
+	userID := c.GetString("userID")
+	if userID == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	account, err := h.service.Account(c.Request.Context(), userID)
+	if errors.Is(err, service.ErrAccountNotFound) {
+		c.JSON(http.StatusOK, accountResponse{UserID: userID, Anonymous: true})
+		return
+	}
+	if h.abortOnError(c, err, "Failed to get account") {
+		return
+	}
+	c.JSON(http.StatusOK, newAccountResponse(account))
+}
+
+// startSession выдаёт cookie учётной записи и отвечает её описанием со статусом code.
+func (h *AccountHandler) startSession(c *gin.Context, code int, account service.Account) {
+	if err := middleware.StartSession(c, h.tokens, account.UserID); err != nil {
+		h.logger.Errorw("Failed to generate JWT", "error", err)
+		c.AbortWithStatus(http.StatusInternalServerError)
+		return
+	}
+	c.JSON(code, newAccountResponse(account))
+}
+
+// abortOnError отвечает клиенту статусом, соответствующим ошибке err, и сообщает, была ли ошибка.
+func (h *AccountHandler) abortOnError(c *gin.Context, err error, msg string) bool {
+	switch {
+	case err == nil:
+		return false
+	case errors.Is(err, service.ErrInvalidAccount):
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrInvalidCredentials):
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrLoginTaken):
+		c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrTooManyAttempts):
+		c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+	default:
+		h.logger.Errorw(msg, "error", err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+	return true
+}
+
+func newAccountResponse(a service.Account) accountResponse {
+	return accountResponse{UserID: a.UserID, Login: a.Login, CreatedAt: &a.CreatedAt}
+}
@@ -0,0 +1,131 @@
+package shortenurlhandlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aseptimu/url-shortener/internal/app/config"
+	"github.com/aseptimu/url-shortener/internal/app/middleware"
+	"github.com/aseptimu/url-shortener/internal/app/service"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+// mockAccountManager знает учётную запись "alice" с паролем "secret-password" и идентификатором "u1".
+type mockAccountManager struct{}
+
+var testAccount = service.Account{UserID: "u1", Login: "alice", CreatedAt: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)}
+
+func (m *mockAccountManager) Register(_ context.Context, _, login, password string) (service.Account, error) {
+	switch {
+	case login == "" || password == "":
+		return service.Account{}, service.ErrInvalidAccount
+	case login == "alice":
+		return service.Account{}, service.ErrLoginTaken
+	}
+	return service.Account{UserID: "u2", Login: login, CreatedAt: testAccount.CreatedAt}, nil
+}
+
+func (m *mockAccountManager) Login(_ context.Context, _, login, password, _ string) (service.Account, error) {
+	if login != "alice" || password != "secret-password" {
+		return service.Account{}, service.ErrInvalidCredentials
+	}
+	return testAccount, nil
+}
+
+func (m *mockAccountManager) Account(_ context.Context, userID string) (service.Account, error) {
+	if userID != "u1" {
+		return service.Account{}, service.ErrAccountNotFound
+	}
+	return testAccount, nil
+}
+
+// memRevokedTokens хранит отозванные JWT в памяти.
+type memRevokedTokens map[string]time.Time
+
+func newMemRevokedTokens() memRevokedTokens {
+	return make(memRevokedTokens)
+}
+
+func (m memRevokedTokens) RevokeToken(_ context.Context, id string, expiresAt time.Time) error {
+	m[id] = expiresAt
+	return nil
+}
+
+func (m memRevokedTokens) IsTokenRevoked(_ context.Context, id string) (bool, error) {
+	_, ok := m[id]
+	return ok, nil
+}
+
+func TestAccountHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		userID     string
+		method     string
+		path       string
+		body       string
+		wantStatus int
+		wantBody   string
+		wantCookie string
+	}{
+		{name: "register", userID: "anon", method: http.MethodPost, path: "/api/user/register", body: `{"login":"bob","password":"secret-password"}`,
+			wantStatus: http.StatusCreated, wantBody: `{"user_id":"u2","login":"bob","created_at":"2024-05-01T12:00:00Z","anonymous":false}`, wantCookie: "userID="},
+		{name: "register taken", userID: "anon", method: http.MethodPost, path: "/api/user/register", body: `{"login":"alice","password":"secret-password"}`, wantStatus: http.StatusConflict},
+		{name: "register invalid", userID: "anon", method: http.MethodPost, path: "/api/user/register", body: `{}`, wantStatus: http.StatusBadRequest},
+		{name: "login", userID: "anon", method: http.MethodPost, path: "/api/user/login", body: `{"login":"alice","password":"secret-password"}`,
+			wantStatus: http.StatusOK, wantBody: `{"user_id":"u1","login":"alice","created_at":"2024-05-01T12:00:00Z","anonymous":false}`, wantCookie: "userID="},
+		{name: "login wrong password", userID: "anon", method: http.MethodPost, path: "/api/user/login", body: `{"login":"alice","password":"nope"}`, wantStatus: http.StatusUnauthorized},
+		{name: "logout", userID: "u1", method: http.MethodPost, path: "/api/user/logout", wantStatus: http.StatusNoContent, wantCookie: "Max-Age=0"},
+		{name: "me", userID: "u1", method: http.MethodGet, path: "/api/user/me",
+			wantStatus: http.StatusOK, wantBody: `{"user_id":"u1","login":"alice","created_at":"2024-05-01T12:00:00Z","anonymous":false}`},
+		{name: "me anonymous", userID: "anon", method: http.MethodGet, path: "/api/user/me", wantStatus: http.StatusOK, wantBody: `{"user_id":"anon","anonymous":true}`},
+		{name: "me unauthorized", method: http.MethodGet, path: "/api/user/me", wantStatus: http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewAccountHandler(&config.ConfigType{}, &mockAccountManager{}, middleware.NewTokens("secret", nil, time.Hour, service.NewRevocationService(newMemRevokedTokens())), zap.NewNop().Sugar())
+			router := newTestRouter(tt.userID, func(r *gin.Engine) {
+				r.POST("/api/user/register", handler.Register)
+				r.POST("/api/user/login", handler.Login)
+				r.POST("/api/user/logout", handler.Logout)
+				r.GET("/api/user/me", handler.Me)
+			})
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body)))
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantBody != "" {
+				assert.JSONEq(t, tt.wantBody, w.Body.String())
+			}
+			if tt.wantCookie != "" {
+				assert.Contains(t, w.Header().Get("Set-Cookie"), tt.wantCookie)
+			}
+		})
+	}
+}
+
+func TestAccountHandler_LogoutRevokesToken(t *testing.T) {
+	tokens := middleware.NewTokens("secret", nil, time.Hour, service.NewRevocationService(newMemRevokedTokens()))
+	handler := NewAccountHandler(&config.ConfigType{}, &mockAccountManager{}, tokens, zap.NewNop().Sugar())
+	router := gin.New()
+	router.POST("/api/user/logout", handler.Logout)
+
+	token, err := tokens.Issue("u1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	r := httptest.NewRequest(http.MethodPost, "/api/user/logout", nil)
+	r.AddCookie(&http.Cookie{Name: "userID", Value: token})
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	_, _, err = tokens.Verify(context.Background(), token)
+	assert.Error(t, err, "expected the token to be revoked after logout")
+}
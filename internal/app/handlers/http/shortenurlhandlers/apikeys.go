@@ -0,0 +1,136 @@
+package shortenurlhandlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/aseptimu/url-shortener/internal/app/config"
+	"github.com/aseptimu/url-shortener/internal/app/service"
+	"github.com/aseptimu/url-shortener/internal/app/utils"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// APIKeyHandler обрабатывает выпуск, просмотр и отзыв API-ключей для межсерверных клиентов.
+type APIKeyHandler struct {
+	cfg     *config.ConfigType
+	service service.APIKeyManager
+	logger  *zap.SugaredLogger
+}
+
+// NewAPIKeyHandler создаёт новый экземпляр APIKeyHandler.
+func NewAPIKeyHandler(cfg *config.ConfigType, service service.APIKeyManager, logger *zap.SugaredLogger) *APIKeyHandler {
+	return &APIKeyHandler{cfg: cfg, service: service, logger: logger}
+}
+
+type apiKeyResponse struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Prefix    string    `json:"prefix"`
+	Scopes    []string  `json:"scopes"`
+	CreatedAt time.Time `json:"created_at"`
+	Key       string    `json:"key,omitempty"`
+}
+
+// CreateKey обрабатывает POST /api/user/api-keys.
+// Принимает JSON {"name": "...", "scopes": ["shorten", "read", "delete", "stats"]}
+// и возвращает 201 Created с описанием ключа и самим ключом в поле key.
+// Ключ показывается только здесь: сервер хранит лишь его хеш.
+func (h *APIKeyHandler) CreateKey(c *gin.Context) {
+	utils.LogRequest(c, h.logger)
+
+	userID, ok := h.userID(c)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Name   string   `json:"name"`
+		Scopes []string `json:"scopes"`
+	}
+	if err := json.NewDecoder(c.Request.Body).Decode(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON format"})
+		return
+	}
+
+	key, token, err := h.service.CreateKey(c.Request.Context(), userID, req.Name, req.Scopes)
+	if h.abortOnError(c, err, "Failed to create API key") {
+		return
+	}
+	resp := newAPIKeyResponse(key)
+	resp.Key = token
+	c.JSON(http.StatusCreated, resp)
+}
+
+// ListKeys обрабатывает GET /api/user/api-keys и возвращает ключи пользователя без самих ключей.
+func (h *APIKeyHandler) ListKeys(c *gin.Context) {
+	utils.LogRequest(c, h.logger)
+
+	userID, ok := h.userID(c)
+	if !ok {
+		return
+	}
+
+	keys, err := h.service.ListKeys(c.Request.Context(), userID)
+	if h.abortOnError(c, err, "Failed to list API keys") {
+		return
+	}
+	resp := make([]apiKeyResponse, len(keys))
+	for i, k := range keys {
+		resp[i] = newAPIKeyResponse(k)
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// RevokeKey обрабатывает DELETE /api/user/api-keys/{id} и возвращает 204 No Content.
+// Отозванный ключ сразу перестаёт приниматься; неизвестный ключ — 404 Not Found.
+func (h *APIKeyHandler) RevokeKey(c *gin.Context) {
+	utils.LogRequest(c, h.logger)
+
+	userID, ok := h.userID(c)
+	if !ok {
+		return
+	}
+
+	err := h.service.RevokeKey(c.Request.Context(), userID, c.Param("id"))
+	if h.abortOnError(c, err, "Failed to revoke API key") {
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// abortOnError отвечает клиенту статусом, соответствующим ошибке err, и сообщает, была ли ошибка.
+func (h *APIKeyHandler) abortOnError(c *gin.Context, err error, msg string) bool {
+	switch {
+	case err == nil:
+		return false
+	case errors.Is(err, service.ErrInvalidAPIKey):
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrAPIKeyNotFound):
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		h.logger.Errorw(msg, "error", err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+	return true
+}
+
+func (h *APIKeyHandler) userID(c *gin.Context) (string, bool) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return "", false
+	}
+	userIDStr, ok := userID.(string)
+	if !ok || userIDStr == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return "", false
+	}
+	return userIDStr, true
+}
+
+func newAPIKeyResponse(k service.APIKey) apiKeyResponse {
+	return apiKeyResponse{ID: k.ID, Name: k.Name, Prefix: k.Prefix, Scopes: k.Scopes, CreatedAt: k.CreatedAt}
+}
@@ -0,0 +1,88 @@
+package shortenurlhandlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aseptimu/url-shortener/internal/app/config"
+	"github.com/aseptimu/url-shortener/internal/app/service"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+// mockAPIKeyManager знает ключ "k1" пользователя "alice".
+type mockAPIKeyManager struct{}
+
+var testAPIKey = service.APIKey{
+	ID:        "k1",
+	Name:      "CI",
+	Prefix:    "usk_abcdefgh",
+	Scopes:    []string{service.ScopeShorten},
+	CreatedAt: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+}
+
+func (m *mockAPIKeyManager) CreateKey(_ context.Context, _, name string, scopes []string) (service.APIKey, string, error) {
+	if name == "" || len(scopes) == 0 {
+		return service.APIKey{}, "", service.ErrInvalidAPIKey
+	}
+	return testAPIKey, "usk_abcdefghsecret", nil
+}
+
+func (m *mockAPIKeyManager) ListKeys(_ context.Context, userID string) ([]service.APIKey, error) {
+	if userID != "alice" {
+		return []service.APIKey{}, nil
+	}
+	return []service.APIKey{testAPIKey}, nil
+}
+
+func (m *mockAPIKeyManager) RevokeKey(_ context.Context, userID, id string) error {
+	if userID != "alice" || id != "k1" {
+		return service.ErrAPIKeyNotFound
+	}
+	return nil
+}
+
+func TestAPIKeyHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		userID     string
+		method     string
+		path       string
+		body       string
+		wantStatus int
+		wantBody   string
+	}{
+		{name: "create", userID: "alice", method: http.MethodPost, path: "/api/user/api-keys", body: `{"name":"CI","scopes":["shorten"]}`,
+			wantStatus: http.StatusCreated, wantBody: `{"id":"k1","name":"CI","prefix":"usk_abcdefgh","scopes":["shorten"],"created_at":"2024-05-01T12:00:00Z","key":"usk_abcdefghsecret"}`},
+		{name: "create without scopes", userID: "alice", method: http.MethodPost, path: "/api/user/api-keys", body: `{"name":"CI"}`, wantStatus: http.StatusBadRequest},
+		{name: "invalid json", userID: "alice", method: http.MethodPost, path: "/api/user/api-keys", body: `{`, wantStatus: http.StatusBadRequest},
+		{name: "list hides keys", userID: "alice", method: http.MethodGet, path: "/api/user/api-keys",
+			wantStatus: http.StatusOK, wantBody: `[{"id":"k1","name":"CI","prefix":"usk_abcdefgh","scopes":["shorten"],"created_at":"2024-05-01T12:00:00Z"}]`},
+		{name: "revoke", userID: "alice", method: http.MethodDelete, path: "/api/user/api-keys/k1", wantStatus: http.StatusNoContent},
+		{name: "revoke foreign key", userID: "bob", method: http.MethodDelete, path: "/api/user/api-keys/k1", wantStatus: http.StatusNotFound},
+		{name: "unauthorized", method: http.MethodGet, path: "/api/user/api-keys", wantStatus: http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewAPIKeyHandler(&config.ConfigType{}, &mockAPIKeyManager{}, zap.NewNop().Sugar())
+			router := newTestRouter(tt.userID, func(r *gin.Engine) {
+				r.POST("/api/user/api-keys", handler.CreateKey)
+				r.GET("/api/user/api-keys", handler.ListKeys)
+				r.DELETE("/api/user/api-keys/:id", handler.RevokeKey)
+			})
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body)))
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantBody != "" {
+				assert.JSONEq(t, tt.wantBody, w.Body.String())
+			}
+		})
+	}
+}
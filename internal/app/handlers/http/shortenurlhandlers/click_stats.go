@@ -0,0 +1,132 @@
+package shortenurlhandlers
+
+import (
+	"encoding/csv"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/aseptimu/url-shortener/internal/app/config"
+	"github.com/aseptimu/url-shortener/internal/app/service"
+	"github.com/aseptimu/url-shortener/internal/app/utils"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// ClickStatsHandler выдаёт владельцу ссылки статистику переходов по ней.
+type ClickStatsHandler struct {
+	cfg     *config.ConfigType
+	service service.ClickTracker
+	logger  *zap.SugaredLogger
+}
+
+// NewClickStatsHandler создаёт новый экземпляр ClickStatsHandler.
+func NewClickStatsHandler(cfg *config.ConfigType, service service.ClickTracker, logger *zap.SugaredLogger) *ClickStatsHandler {
+	return &ClickStatsHandler{cfg: cfg, service: service, logger: logger}
+}
+
+type clickCount struct {
+	Key   string `json:"key"`
+	Count int    `json:"count"`
+}
+
+type urlStatsResponse struct {
+	ShortURL    string       `json:"short_url"`
+	Total       int          `json:"total"`
+	ByDay       []clickCount `json:"by_day"`
+	ByReferrer  []clickCount `json:"by_referrer"`
+	ByUserAgent []clickCount `json:"by_user_agent"`
+	ByVariant   []clickCount `json:"by_variant"`
+}
+
+func toClickCounts(counts []service.ClickCount) []clickCount {
+	result := make([]clickCount, len(counts))
+	for i, c := range counts {
+		result[i] = clickCount{Key: c.Key, Count: c.Count}
+	}
+	return result
+}
+
+// GetURLStats обрабатывает GET /api/user/urls/{short}/stats.
+// Возвращает общее число переходов и разбивку по дням, источникам, семействам браузеров
+// и вариантам A/B-сплита.
+// Для чужих и несуществующих ссылок возвращает 404 Not Found.
+func (h *ClickStatsHandler) GetURLStats(c *gin.Context) {
+	utils.LogRequest(c, h.logger)
+
+	userID, ok := h.userID(c)
+	if !ok {
+		return
+	}
+
+	key := ownedKey(c, h.cfg)
+	stats, err := h.service.GetURLStats(c.Request.Context(), key, userID)
+	if err != nil {
+		h.abortWithError(c, key, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, urlStatsResponse{
+		ShortURL:    linkURL(h.cfg, key),
+		Total:       stats.Total,
+		ByDay:       toClickCounts(stats.ByDay),
+		ByReferrer:  toClickCounts(stats.ByReferrer),
+		ByUserAgent: toClickCounts(stats.ByUserAgent),
+		ByVariant:   toClickCounts(stats.ByVariant),
+	})
+}
+
+// ExportClicks обрабатывает GET /api/user/urls/{short}/clicks.csv
+// и отдаёт все переходы по ссылке в формате CSV.
+func (h *ClickStatsHandler) ExportClicks(c *gin.Context) {
+	utils.LogRequest(c, h.logger)
+
+	userID, ok := h.userID(c)
+	if !ok {
+		return
+	}
+
+	key := ownedKey(c, h.cfg)
+	clicks, err := h.service.GetClicks(c.Request.Context(), key, userID)
+	if err != nil {
+		h.abortWithError(c, key, err)
+		return
+	}
+
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", `attachment; filename="`+key+`-clicks.csv"`)
+	c.Status(http.StatusOK)
+
+	w := csv.NewWriter(c.Writer)
+	_ = w.Write([]string{"clicked_at", "referrer", "user_agent", "ip_hash", "variant"})
+	for _, click := range clicks {
+		_ = w.Write([]string{click.ClickedAt.UTC().Format(time.RFC3339), click.Referrer, click.UserAgent, click.IPHash, click.Variant})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		h.logger.Errorw("Failed to write clicks CSV", "shortURL", key, "error", err)
+	}
+}
+
+func (h *ClickStatsHandler) userID(c *gin.Context) (string, bool) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return "", false
+	}
+	userIDStr, ok := userID.(string)
+	if !ok || userIDStr == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return "", false
+	}
+	return userIDStr, true
+}
+
+func (h *ClickStatsHandler) abortWithError(c *gin.Context, key string, err error) {
+	if errors.Is(err, service.ErrURLNotFound) {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	h.logger.Errorw("Failed to get clicks", "shortURL", key, "error", err)
+	c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+}
@@ -0,0 +1,119 @@
+package shortenurlhandlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aseptimu/url-shortener/internal/app/config"
+	"github.com/aseptimu/url-shortener/internal/app/service"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+type mockClicks struct {
+	recorded []string
+}
+
+func (m *mockClicks) RecordClick(_ context.Context, shortURL, referrer, _, _, _ string) error {
+	m.recorded = append(m.recorded, shortURL+" "+referrer)
+	return nil
+}
+
+func (m *mockClicks) GetURLStats(_ context.Context, shortURL, userID string) (service.ClickStats, error) {
+	if shortURL != "abcdef" || userID != "owner" {
+		return service.ClickStats{}, service.ErrURLNotFound
+	}
+	return service.ClickStats{
+		Total:       2,
+		ByDay:       []service.ClickCount{{Key: "2025-01-01", Count: 2}},
+		ByReferrer:  []service.ClickCount{{Key: "direct", Count: 2}},
+		ByUserAgent: []service.ClickCount{{Key: "Firefox", Count: 2}},
+		ByVariant:   []service.ClickCount{{Key: "a", Count: 1}},
+	}, nil
+}
+
+func (m *mockClicks) GetClicks(_ context.Context, shortURL, userID string) ([]service.ClickEvent, error) {
+	if shortURL != "abcdef" || userID != "owner" {
+		return nil, service.ErrURLNotFound
+	}
+	return []service.ClickEvent{{
+		ShortURL:  "abcdef",
+		ClickedAt: time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC),
+		Referrer:  "https://ref.example/a,b",
+		UserAgent: "Firefox/120.0",
+		IPHash:    "hash",
+		Variant:   "a",
+	}}, nil
+}
+
+func newTestClickRouter(userID string) *gin.Engine {
+	cfg := &config.ConfigType{BaseAddress: "http://localhost:8080"}
+	handler := NewClickStatsHandler(cfg, &mockClicks{}, zap.NewNop().Sugar())
+	return newTestRouter(userID, func(r *gin.Engine) {
+		r.GET("/api/user/urls/:short/stats", handler.GetURLStats)
+		r.GET("/api/user/urls/:short/clicks.csv", handler.ExportClicks)
+	})
+}
+
+func TestGetURL_RecordsClick(t *testing.T) {
+	clicks := &mockClicks{}
+	handler := NewGetURLHandler(&config.ConfigType{}, &mockService{}, clicks, zap.NewNop().Sugar())
+	router := gin.New()
+	router.GET("/:url", handler.GetURL)
+
+	r := httptest.NewRequest(http.MethodGet, "/abcdef", nil)
+	r.Header.Set("Referer", "https://ref.example")
+	router.ServeHTTP(httptest.NewRecorder(), r)
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/expired", nil))
+
+	assert.Equal(t, []string{"abcdef https://ref.example"}, clicks.recorded)
+}
+
+func TestGetURLStats(t *testing.T) {
+	tests := []struct {
+		name       string
+		userID     string
+		short      string
+		wantStatus int
+	}{
+		{name: "owner", userID: "owner", short: "abcdef", wantStatus: http.StatusOK},
+		{name: "other user", userID: "other", short: "abcdef", wantStatus: http.StatusNotFound},
+		{name: "no user", short: "abcdef", wantStatus: http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			newTestClickRouter(tt.userID).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/user/urls/"+tt.short+"/stats", nil))
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantStatus == http.StatusOK {
+				assert.JSONEq(t, `{
+					"short_url": "http://localhost:8080/abcdef",
+					"total": 2,
+					"by_day": [{"key": "2025-01-01", "count": 2}],
+					"by_referrer": [{"key": "direct", "count": 2}],
+					"by_user_agent": [{"key": "Firefox", "count": 2}],
+					"by_variant": [{"key": "a", "count": 1}]
+				}`, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestExportClicks(t *testing.T) {
+	w := httptest.NewRecorder()
+	newTestClickRouter("owner").ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/user/urls/abcdef/clicks.csv", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), "text/csv")
+	lines := strings.Split(strings.TrimSpace(w.Body.String()), "\n")
+	assert.Equal(t, []string{
+		"clicked_at,referrer,user_agent,ip_hash,variant",
+		`2025-01-01T12:00:00Z,"https://ref.example/a,b",Firefox/120.0,hash,a`,
+	}, lines)
+}
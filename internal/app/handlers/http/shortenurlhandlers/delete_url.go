@@ -6,6 +6,7 @@ import (
 	"net/http"
 
 	"github.com/aseptimu/url-shortener/internal/app/config"
+	"github.com/aseptimu/url-shortener/internal/app/middleware"
 	"github.com/aseptimu/url-shortener/internal/app/service"
 	"github.com/aseptimu/url-shortener/internal/app/utils"
 	"github.com/gin-gonic/gin"
@@ -13,10 +14,13 @@ import (
 )
 
 // DeleteTask представляет задачу пакетного удаления списка коротких URL
-// для конкретного пользователя.
+// владельца UserID — пользователя или рабочего пространства.
+// MemberID — пользователь, запросивший удаление: воркер повторно проверяет его права
+// перед удалением. Пустой MemberID означает системную задачу без проверки прав.
 type DeleteTask struct {
-	URLs   []string
-	UserID string
+	URLs     []string
+	UserID   string
+	MemberID string
 }
 
 // DeleteTaskCh — буферизированный канал для передачи задач удаления.
@@ -38,7 +42,7 @@ func NewDeleteURLHandler(cfg *config.ConfigType, service service.URLDeleter, log
 }
 
 // DeleteUserURLs обрабатывает DELETE /api/user/urls.
-// Читает JSON-массив коротких ссылок из тела запроса,
+// Читает JSON-массив коротких ссылок из тела запроса (ссылки дополнительных доменов — в виде "домен/код"),
 // формирует задачу DeleteTask и отправляет её в канал DeleteTaskCh.
 // Требует наличия валидного userID в контексте (JWT в куках).
 // В случае успеха возвращает HTTP 202 Accepted.
@@ -63,11 +67,46 @@ func (h *DeleteURLHandler) DeleteUserURLs(c *gin.Context) {
 	}
 
 	task := DeleteTask{
-		URLs:   urls,
-		UserID: userIDStr,
+		URLs:     urls,
+		UserID:   userIDStr,
+		MemberID: c.GetString(middleware.MemberIDKey),
 	}
 
 	DeleteTaskCh <- task
 
 	c.Status(http.StatusAccepted)
 }
+
+// RestoreUserURLs обрабатывает POST /api/user/urls/restore.
+// Читает JSON-массив коротких ссылок и синхронно восстанавливает те из них,
+// что были удалены пользователем в пределах льготного периода.
+// Возвращает 200 OK и JSON-массив восстановленных ссылок; остальные ссылки пропускаются.
+func (h *DeleteURLHandler) RestoreUserURLs(c *gin.Context) {
+	utils.LogRequest(c, h.logger)
+
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	userIDStr, ok := userID.(string)
+	if !ok || userIDStr == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var urls []string
+	if err := json.NewDecoder(c.Request.Body).Decode(&urls); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON format"})
+		return
+	}
+
+	restored, err := h.Service.RestoreURLs(c.Request.Context(), urls, userIDStr)
+	if err != nil {
+		h.logger.Errorw("Failed to restore URLs", "userID", userIDStr, "error", err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, restored)
+}
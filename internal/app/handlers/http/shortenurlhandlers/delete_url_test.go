@@ -58,3 +58,37 @@ func TestDeleteUserURLs_BadJSON(t *testing.T) {
 		t.Fatalf("expected status %d for bad JSON, got %d", http.StatusBadRequest, w.Code)
 	}
 }
+
+func TestRestoreUserURLs(t *testing.T) {
+	handler := NewDeleteURLHandler(&config.ConfigType{}, &mockService{}, zap.NewNop().Sugar())
+
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/api/user/urls/restore", bytes.NewBufferString(`["a","b"]`))
+	c.Set("userID", "user123")
+
+	handler.RestoreUserURLs(c)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	if body := w.Body.String(); body != `["a"]` {
+		t.Errorf("expected only restored URLs in response, got %s", body)
+	}
+}
+
+func TestRestoreUserURLs_Unauthorized(t *testing.T) {
+	handler := NewDeleteURLHandler(&config.ConfigType{}, &mockService{}, zap.NewNop().Sugar())
+
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/api/user/urls/restore", bytes.NewBufferString(`["a"]`))
+
+	handler.RestoreUserURLs(c)
+
+	if w.Code != http.StatusUnauthorized {
+		t.Fatalf("expected status %d, got %d", http.StatusUnauthorized, w.Code)
+	}
+}
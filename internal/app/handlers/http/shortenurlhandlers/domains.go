@@ -0,0 +1,96 @@
+package shortenurlhandlers
+
+import (
+	"net/http"
+
+	"github.com/aseptimu/url-shortener/internal/app/config"
+	"github.com/aseptimu/url-shortener/internal/app/service"
+	"github.com/aseptimu/url-shortener/internal/app/utils"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// hostKey возвращает ключ ссылки с кодом code на коротком домене, к которому обращён запрос.
+func hostKey(c *gin.Context, cfg *config.ConfigType, code string) string {
+	return service.LinkKey(cfg.DomainByHost(c.Request.Host), code)
+}
+
+// ownedKey возвращает ключ ссылки для API управления: код берётся из пути,
+// домен — из параметра запроса domain (по умолчанию основной).
+// Ненастроенный домен даёт ключ, под которым ссылок нет.
+func ownedKey(c *gin.Context, cfg *config.ConfigType) string {
+	domain, ok := cfg.LookupDomain(c.Query("domain"))
+	if !ok {
+		domain = c.Query("domain")
+	}
+	return service.LinkKey(domain, c.Param("short"))
+}
+
+// linkURL возвращает полный адрес ссылки с ключом key на её коротком домене.
+func linkURL(cfg *config.ConfigType, key string) string {
+	return cfg.ShortURL(service.SplitLinkKey(key))
+}
+
+// shortenDomain возвращает домен создаваемой ссылки по имени name
+// или сам отвечает клиенту 400 Bad Request, если домен не настроен.
+func shortenDomain(c *gin.Context, cfg *config.ConfigType, name string) (string, bool) {
+	domain, ok := cfg.LookupDomain(name)
+	if !ok {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": service.ErrUnknownDomain.Error()})
+	}
+	return domain, ok
+}
+
+// DomainHandler обслуживает подтверждение владения дополнительными короткими доменами.
+type DomainHandler struct {
+	cfg    *config.ConfigType
+	logger *zap.SugaredLogger
+}
+
+// NewDomainHandler создаёт новый экземпляр DomainHandler.
+func NewDomainHandler(cfg *config.ConfigType, logger *zap.SugaredLogger) *DomainHandler {
+	return &DomainHandler{cfg: cfg, logger: logger}
+}
+
+// VerificationFile обрабатывает GET /.well-known/url-shortener-verification и отдаёт токен
+// подтверждения владения доменом, к которому обращён запрос. Владелец домена убеждается,
+// что домен направлен на сервис, сравнив ответ с токеном из GET /api/internal/domains.
+// Для основного домена и неизвестных хостов возвращает 404 Not Found.
+func (h *DomainHandler) VerificationFile(c *gin.Context) {
+	utils.LogRequest(c, h.logger)
+
+	domain := h.cfg.DomainByHost(c.Request.Host)
+	if domain == "" {
+		c.AbortWithStatus(http.StatusNotFound)
+		return
+	}
+	c.String(http.StatusOK, service.DomainVerificationToken(h.cfg.SecretKey, domain))
+}
+
+type domainResponse struct {
+	Domain            string `json:"domain"`
+	BaseURL           string `json:"base_url"`
+	VerificationToken string `json:"verification_token"`
+}
+
+// ListDomains обрабатывает GET /api/internal/domains и возвращает дополнительные короткие домены
+// с их токенами подтверждения. Доступен только из доверенной подсети.
+func (h *DomainHandler) ListDomains(c *gin.Context) {
+	utils.LogRequest(c, h.logger)
+
+	if !fromTrustedSubnet(c, h.cfg, h.logger) {
+		c.AbortWithStatus(http.StatusForbidden)
+		return
+	}
+
+	hosts := h.cfg.DomainHosts()
+	resp := make([]domainResponse, len(hosts))
+	for i, host := range hosts {
+		resp[i] = domainResponse{
+			Domain:            host,
+			BaseURL:           h.cfg.BaseURL(host),
+			VerificationToken: service.DomainVerificationToken(h.cfg.SecretKey, host),
+		}
+	}
+	c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,107 @@
+package shortenurlhandlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aseptimu/url-shortener/internal/app/config"
+	"github.com/aseptimu/url-shortener/internal/app/service"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func newTestDomainConfig() *config.ConfigType {
+	return &config.ConfigType{
+		BaseAddress:   "http://localhost:8080",
+		Domains:       "https://go.brand.com",
+		SecretKey:     "secret",
+		TrustedSubnet: "10.0.0.0/8",
+	}
+}
+
+func TestURLCreatorJSON_Domain(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+		wantBody   string
+	}{
+		{name: "branded domain", body: `{"url": "http://example.com", "alias": "sale", "domain": "GO.brand.com"}`,
+			wantStatus: http.StatusCreated, wantBody: `{"result":"https://go.brand.com/sale"}`},
+		{name: "default domain by host", body: `{"url": "http://example.com", "alias": "sale", "domain": "localhost:8080"}`,
+			wantStatus: http.StatusCreated, wantBody: `{"result":"http://localhost:8080/sale"}`},
+		{name: "unknown domain", body: `{"url": "http://example.com", "alias": "sale", "domain": "evil.com"}`,
+			wantStatus: http.StatusBadRequest, wantBody: `{"error":"unknown short domain"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.POST("/api/shorten", NewShortenHandler(newTestDomainConfig(), &mockService{}, zap.NewNop().Sugar()).URLCreatorJSON)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/shorten", strings.NewReader(tt.body)))
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			assert.JSONEq(t, tt.wantBody, w.Body.String())
+		})
+	}
+}
+
+func TestGetURL_ByHost(t *testing.T) {
+	router := gin.New()
+	router.GET("/:url", NewGetURLHandler(newTestDomainConfig(), &mockService{}, nil, zap.NewNop().Sugar()).GetURL)
+
+	for host, want := range map[string]string{"go.brand.com": "http://example.com/brand", "localhost:8080": "http://example.com"} {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/abcdef", nil)
+		r.Host = host
+		router.ServeHTTP(w, r)
+		assert.Equal(t, http.StatusTemporaryRedirect, w.Code, host)
+		assert.Equal(t, want, w.Header().Get("Location"), host)
+	}
+}
+
+func TestDomainHandler(t *testing.T) {
+	cfg := newTestDomainConfig()
+	handler := NewDomainHandler(cfg, zap.NewNop().Sugar())
+	router := gin.New()
+	router.GET(service.DomainVerificationPath, handler.VerificationFile)
+	router.GET("/api/internal/domains", handler.ListDomains)
+	token := service.DomainVerificationToken("secret", "go.brand.com")
+
+	tests := []struct {
+		name       string
+		path       string
+		host       string
+		realIP     string
+		wantStatus int
+		wantBody   string
+	}{
+		{name: "verification file", path: service.DomainVerificationPath, host: "go.brand.com", wantStatus: http.StatusOK, wantBody: token},
+		{name: "verification on main domain", path: service.DomainVerificationPath, host: "localhost:8080", wantStatus: http.StatusNotFound},
+		{name: "list from trusted subnet", path: "/api/internal/domains", realIP: "10.1.2.3", wantStatus: http.StatusOK,
+			wantBody: `[{"domain":"go.brand.com","base_url":"https://go.brand.com","verification_token":"` + token + `"}]`},
+		{name: "list from outside", path: "/api/internal/domains", realIP: "192.168.1.1", wantStatus: http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.host != "" {
+				r.Host = tt.host
+			}
+			if tt.realIP != "" {
+				r.Header.Set("X-Real-IP", tt.realIP)
+			}
+			router.ServeHTTP(w, r)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantBody != "" {
+				assert.Equal(t, tt.wantBody, w.Body.String())
+			}
+		})
+	}
+}
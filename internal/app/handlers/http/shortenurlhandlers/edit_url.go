@@ -0,0 +1,206 @@
+package shortenurlhandlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aseptimu/url-shortener/internal/app/config"
+	"github.com/aseptimu/url-shortener/internal/app/service"
+	"github.com/aseptimu/url-shortener/internal/app/utils"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// EditURLHandler позволяет владельцу менять назначение ссылки и просматривать историю изменений.
+// Текущая версия ссылки передаётся в заголовке ETag; заголовок If-Match в запросах на изменение
+// защищает от перезаписи чужих правок.
+type EditURLHandler struct {
+	cfg     *config.ConfigType
+	service service.URLEditor
+	logger  *zap.SugaredLogger
+}
+
+// NewEditURLHandler создаёт новый экземпляр EditURLHandler.
+func NewEditURLHandler(cfg *config.ConfigType, service service.URLEditor, logger *zap.SugaredLogger) *EditURLHandler {
+	return &EditURLHandler{cfg: cfg, service: service, logger: logger}
+}
+
+type urlVersionResponse struct {
+	ShortURL    string `json:"short_url"`
+	OriginalURL string `json:"original_url"`
+	Version     int    `json:"version"`
+}
+
+type historyEntry struct {
+	Version     int        `json:"version"`
+	OriginalURL string     `json:"original_url"`
+	ReplacedAt  *time.Time `json:"replaced_at,omitempty"`
+}
+
+type urlHistoryResponse struct {
+	urlVersionResponse
+	History []historyEntry `json:"history"`
+}
+
+// UpdateURL обрабатывает PATCH /api/user/urls/{short}.
+// Принимает JSON {"url": "..."} и возвращает новую версию ссылки с заголовком ETag.
+// Если If-Match не совпадает с текущей версией, возвращает 412 Precondition Failed,
+// если у пользователя уже есть ссылка на этот URL — 409 Conflict.
+func (h *EditURLHandler) UpdateURL(c *gin.Context) {
+	utils.LogRequest(c, h.logger)
+
+	userID, ok := h.userID(c)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		URL string `json:"url"`
+	}
+	if err := json.NewDecoder(c.Request.Body).Decode(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON format"})
+		return
+	}
+	ifVersion, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	key := ownedKey(c, h.cfg)
+	version, err := h.service.UpdateURL(c.Request.Context(), key, userID, req.URL, ifVersion)
+	if err != nil {
+		h.abortWithError(c, key, err)
+		return
+	}
+	h.writeVersion(c, key, req.URL, version)
+}
+
+// RollbackURL обрабатывает POST /api/user/urls/{short}/rollback.
+// Принимает JSON {"version": N} и возвращает ссылке назначение из версии N, создавая новую версию.
+// Заголовок If-Match обрабатывается так же, как в UpdateURL.
+func (h *EditURLHandler) RollbackURL(c *gin.Context) {
+	utils.LogRequest(c, h.logger)
+
+	userID, ok := h.userID(c)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Version int `json:"version"`
+	}
+	if err := json.NewDecoder(c.Request.Body).Decode(&req); err != nil || req.Version <= 0 {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "version must be a positive integer"})
+		return
+	}
+	ifVersion, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	key := ownedKey(c, h.cfg)
+	current, err := h.service.RollbackURL(c.Request.Context(), key, userID, req.Version, ifVersion)
+	if err != nil {
+		h.abortWithError(c, key, err)
+		return
+	}
+	h.writeVersion(c, key, current.OriginalURL, current.Version)
+}
+
+// GetURLHistory обрабатывает GET /api/user/urls/{short}/history.
+// Возвращает текущее назначение с заголовком ETag и прежние назначения от новых к старым.
+func (h *EditURLHandler) GetURLHistory(c *gin.Context) {
+	utils.LogRequest(c, h.logger)
+
+	userID, ok := h.userID(c)
+	if !ok {
+		return
+	}
+
+	key := ownedKey(c, h.cfg)
+	history, err := h.service.GetURLHistory(c.Request.Context(), key, userID)
+	if err != nil {
+		h.abortWithError(c, key, err)
+		return
+	}
+
+	resp := urlHistoryResponse{
+		urlVersionResponse: urlVersionResponse{
+			ShortURL:    linkURL(h.cfg, key),
+			OriginalURL: history.Current.OriginalURL,
+			Version:     history.Current.Version,
+		},
+		History: make([]historyEntry, len(history.Previous)),
+	}
+	for i, v := range history.Previous {
+		resp.History[i] = historyEntry{Version: v.Version, OriginalURL: v.OriginalURL, ReplacedAt: v.ReplacedAt}
+	}
+
+	c.Header("ETag", versionETag(history.Current.Version))
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *EditURLHandler) writeVersion(c *gin.Context, key, originalURL string, version int) {
+	c.Header("ETag", versionETag(version))
+	c.JSON(http.StatusOK, urlVersionResponse{
+		ShortURL:    linkURL(h.cfg, key),
+		OriginalURL: originalURL,
+		Version:     version,
+	})
+}
+
+// versionETag возвращает сильный ETag для номера версии ссылки.
+func versionETag(version int) string {
+	return `"` + strconv.Itoa(version) + `"`
+}
+
+// ifMatchVersion извлекает номер версии из заголовка If-Match.
+// Отсутствующий заголовок и "*" означают отсутствие проверки и дают 0.
+// ETag, который не может принадлежать ни одной версии, сразу приводит к 412 Precondition Failed.
+func ifMatchVersion(c *gin.Context) (int, bool) {
+	header := strings.TrimSpace(c.GetHeader("If-Match"))
+	if header == "" || header == "*" {
+		return 0, true
+	}
+	tag := strings.Trim(strings.TrimPrefix(header, "W/"), `"`)
+	version, err := strconv.Atoi(tag)
+	if err != nil || version <= 0 {
+		c.AbortWithStatusJSON(http.StatusPreconditionFailed, gin.H{"error": service.ErrVersionMismatch.Error()})
+		return 0, false
+	}
+	return version, true
+}
+
+func (h *EditURLHandler) userID(c *gin.Context) (string, bool) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return "", false
+	}
+	userIDStr, ok := userID.(string)
+	if !ok || userIDStr == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return "", false
+	}
+	return userIDStr, true
+}
+
+func (h *EditURLHandler) abortWithError(c *gin.Context, key string, err error) {
+	switch {
+	case errors.Is(err, service.ErrURLNotFound), errors.Is(err, service.ErrVersionNotFound):
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrVersionMismatch):
+		c.AbortWithStatusJSON(http.StatusPreconditionFailed, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrConflict):
+		c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrInvalidURL), errors.Is(err, service.ErrDestinationRejected):
+		c.AbortWithStatusJSON(http.StatusBadRequest, shortenErrorBody(err))
+	default:
+		h.logger.Errorw("Failed to edit URL", "shortURL", key, "error", err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
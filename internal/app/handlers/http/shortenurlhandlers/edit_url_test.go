@@ -0,0 +1,131 @@
+package shortenurlhandlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aseptimu/url-shortener/internal/app/config"
+	"github.com/aseptimu/url-shortener/internal/app/service"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+// mockEditor хранит ссылку "abcdef" пользователя "owner" в версии 2.
+type mockEditor struct{}
+
+func (m *mockEditor) UpdateURL(_ context.Context, shortURL, userID, originalURL string, ifVersion int) (int, error) {
+	switch {
+	case shortURL != "abcdef" || userID != "owner":
+		return 0, service.ErrURLNotFound
+	case ifVersion > 0 && ifVersion != 2:
+		return 0, service.ErrVersionMismatch
+	case originalURL == "http://localhost:8080/abcdef":
+		return 0, &service.PolicyError{Reason: service.ReasonSelfReference, URL: originalURL}
+	}
+	return 3, nil
+}
+
+func (m *mockEditor) GetURLHistory(_ context.Context, shortURL, userID string) (service.URLHistory, error) {
+	if shortURL != "abcdef" || userID != "owner" {
+		return service.URLHistory{}, service.ErrURLNotFound
+	}
+	replacedAt := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	return service.URLHistory{
+		Current:  service.URLVersion{Version: 2, OriginalURL: "https://example.com/v2"},
+		Previous: []service.URLVersion{{Version: 1, OriginalURL: "https://example.com/v1", ReplacedAt: &replacedAt}},
+	}, nil
+}
+
+func (m *mockEditor) RollbackURL(_ context.Context, shortURL, userID string, version, _ int) (service.URLVersion, error) {
+	if shortURL != "abcdef" || userID != "owner" {
+		return service.URLVersion{}, service.ErrURLNotFound
+	}
+	if version != 1 {
+		return service.URLVersion{}, service.ErrVersionNotFound
+	}
+	return service.URLVersion{Version: 3, OriginalURL: "https://example.com/v1"}, nil
+}
+
+func newTestEditRouter(userID string) *gin.Engine {
+	cfg := &config.ConfigType{BaseAddress: "http://localhost:8080"}
+	handler := NewEditURLHandler(cfg, &mockEditor{}, zap.NewNop().Sugar())
+	return newTestRouter(userID, func(r *gin.Engine) {
+		r.PATCH("/api/user/urls/:short", handler.UpdateURL)
+		r.GET("/api/user/urls/:short/history", handler.GetURLHistory)
+		r.POST("/api/user/urls/:short/rollback", handler.RollbackURL)
+	})
+}
+
+func TestUpdateURL(t *testing.T) {
+	tests := []struct {
+		name       string
+		userID     string
+		short      string
+		body       string
+		ifMatch    string
+		wantStatus int
+		wantBody   string
+	}{
+		{name: "success", userID: "owner", short: "abcdef", body: `{"url": "https://example.com/new"}`, ifMatch: `"2"`, wantStatus: http.StatusOK, wantBody: `"version":3`},
+		{name: "without If-Match", userID: "owner", short: "abcdef", body: `{"url": "https://example.com/new"}`, wantStatus: http.StatusOK},
+		{name: "stale If-Match", userID: "owner", short: "abcdef", body: `{"url": "https://example.com/new"}`, ifMatch: `"1"`, wantStatus: http.StatusPreconditionFailed},
+		{name: "malformed If-Match", userID: "owner", short: "abcdef", body: `{"url": "https://example.com/new"}`, ifMatch: `"abc"`, wantStatus: http.StatusPreconditionFailed},
+		{name: "foreign link", userID: "intruder", short: "abcdef", body: `{"url": "https://example.com/new"}`, wantStatus: http.StatusNotFound},
+		{name: "rejected destination", userID: "owner", short: "abcdef", body: `{"url": "http://localhost:8080/abcdef"}`, wantStatus: http.StatusBadRequest, wantBody: `"reason":"self_reference"`},
+		{name: "invalid JSON", userID: "owner", short: "abcdef", body: `{`, wantStatus: http.StatusBadRequest},
+		{name: "unauthorized", short: "abcdef", body: `{"url": "https://example.com/new"}`, wantStatus: http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPatch, "/api/user/urls/"+tt.short, strings.NewReader(tt.body))
+			if tt.ifMatch != "" {
+				r.Header.Set("If-Match", tt.ifMatch)
+			}
+			newTestEditRouter(tt.userID).ServeHTTP(w, r)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantBody != "" {
+				assert.Contains(t, w.Body.String(), tt.wantBody)
+			}
+			if w.Code == http.StatusOK {
+				assert.Equal(t, `"3"`, w.Header().Get("ETag"))
+			}
+		})
+	}
+}
+
+func TestGetURLHistory(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/api/user/urls/abcdef/history", nil)
+	newTestEditRouter("owner").ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `"2"`, w.Header().Get("ETag"))
+	assert.JSONEq(t, `{
+		"short_url": "http://localhost:8080/abcdef",
+		"original_url": "https://example.com/v2",
+		"version": 2,
+		"history": [{"version": 1, "original_url": "https://example.com/v1", "replaced_at": "2025-01-01T12:00:00Z"}]
+	}`, w.Body.String())
+}
+
+func TestRollbackURL(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/api/user/urls/abcdef/rollback", strings.NewReader(`{"version": 1}`))
+	newTestEditRouter("owner").ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `"3"`, w.Header().Get("ETag"))
+	assert.Contains(t, w.Body.String(), `"original_url":"https://example.com/v1"`)
+
+	w = httptest.NewRecorder()
+	r = httptest.NewRequest(http.MethodPost, "/api/user/urls/abcdef/rollback", strings.NewReader(`{"version": 5}`))
+	newTestEditRouter("owner").ServeHTTP(w, r)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
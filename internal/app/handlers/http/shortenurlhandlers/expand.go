@@ -0,0 +1,151 @@
+package shortenurlhandlers
+
+import (
+	"errors"
+	"html/template"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/aseptimu/url-shortener/internal/app/config"
+	"github.com/aseptimu/url-shortener/internal/app/service"
+	"github.com/aseptimu/url-shortener/internal/app/utils"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// previewSuffix — суффикс короткой ссылки, открывающий страницу предпросмотра вместо перехода.
+const previewSuffix = "+"
+
+// previewTemplate — страница предпросмотра ссылки.
+var previewTemplate = template.Must(template.New("preview").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<meta name="robots" content="noindex">
+<title>{{if .Title}}{{.Title}}{{else}}Link preview{{end}}</title>
+</head>
+<body>
+{{if .Title}}<h1>{{.Title}}</h1>{{end}}
+<p>{{.ShortURL}}</p>
+{{if eq .Status "deleted"}}<p>This link has been deleted.</p>
+{{else if eq .Status "expired"}}<p>This link has expired.</p>
+{{else if .Protected}}<p>This link is password protected.</p>
+<p><a href="{{.ShortURL}}">Continue</a></p>
+{{else}}<p>This link leads to:</p>
+<p><a href="{{.OriginalURL}}" rel="noopener noreferrer nofollow">{{.OriginalURL}}</a></p>
+{{end}}
+</body>
+</html>
+`))
+
+// ExpandHandler показывает, куда ведёт короткая ссылка, не выполняя перехода.
+type ExpandHandler struct {
+	cfg     *config.ConfigType
+	service service.URLExpander
+	logger  *zap.SugaredLogger
+}
+
+// NewExpandHandler создаёт новый экземпляр ExpandHandler.
+func NewExpandHandler(cfg *config.ConfigType, service service.URLExpander, logger *zap.SugaredLogger) *ExpandHandler {
+	return &ExpandHandler{cfg: cfg, service: service, logger: logger}
+}
+
+// qrPath — остаток пути короткой ссылки, по которому отдаётся её QR-код.
+const qrPath = "/qr"
+
+// ShortLink направляет запросы GET /{short} и GET /{short}/{path}:
+// /{short}+ — в preview, /{short}/qr — в qr, остальные — в redirect.
+// gin не позволяет описать такие маршруты шаблонами рядом с /{short}/*path, поэтому они разбираются здесь.
+func ShortLink(redirect, preview, qr gin.HandlerFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		switch path := c.Param("path"); {
+		case path == qrPath:
+			qr(c)
+		case path == "" && strings.HasSuffix(c.Param("url"), previewSuffix):
+			preview(c)
+		default:
+			redirect(c)
+		}
+	}
+}
+
+type expandResponse struct {
+	ShortURL          string     `json:"short_url"`
+	OriginalURL       string     `json:"original_url,omitempty"`
+	Title             string     `json:"title,omitempty"`
+	CreatedAt         *time.Time `json:"created_at,omitempty"`
+	Status            string     `json:"status"`
+	PasswordProtected bool       `json:"password_protected"`
+	Clicks            int        `json:"clicks"`
+}
+
+// ExpandURL обрабатывает GET /api/expand/{short} и возвращает назначение, заголовок, время создания,
+// состояние и число переходов ссылки. Переход при этом не засчитывается.
+func (h *ExpandHandler) ExpandURL(c *gin.Context) {
+	utils.LogRequest(c, h.logger)
+
+	info, ok := h.expand(c, ownedKey(c, h.cfg))
+	if !ok {
+		return
+	}
+
+	resp := expandResponse{
+		ShortURL:          linkURL(h.cfg, info.ShortURL),
+		OriginalURL:       info.OriginalURL,
+		Title:             info.Title,
+		Status:            info.Status,
+		PasswordProtected: info.Protected,
+		Clicks:            info.Clicks,
+	}
+	if !info.CreatedAt.IsZero() {
+		resp.CreatedAt = &info.CreatedAt
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// PreviewURL обрабатывает GET /{short}+ и отдаёт HTML-страницу с назначением и заголовком ссылки.
+// Для удалённых и просроченных ссылок страница отдаётся с кодом 410 Gone.
+func (h *ExpandHandler) PreviewURL(c *gin.Context) {
+	utils.LogRequest(c, h.logger)
+
+	info, ok := h.expand(c, hostKey(c, h.cfg, strings.TrimSuffix(c.Param("url"), previewSuffix)))
+	if !ok {
+		return
+	}
+
+	status := http.StatusOK
+	if info.Status != service.URLStatusActive {
+		status = http.StatusGone
+	}
+	c.Status(status)
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	_ = previewTemplate.Execute(c.Writer, struct {
+		ShortURL    string
+		OriginalURL string
+		Title       string
+		Status      string
+		Protected   bool
+	}{
+		ShortURL:    linkURL(h.cfg, info.ShortURL),
+		OriginalURL: info.OriginalURL,
+		Title:       info.Title,
+		Status:      info.Status,
+		Protected:   info.Protected,
+	})
+}
+
+// expand получает сведения о ссылке key и сам отвечает клиенту при ошибке.
+func (h *ExpandHandler) expand(c *gin.Context, key string) (service.URLInfo, bool) {
+	info, err := h.service.ExpandURL(c.Request.Context(), key)
+	switch {
+	case errors.Is(err, service.ErrURLNotFound):
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return info, false
+	case err != nil:
+		h.logger.Errorw("Failed to expand URL", "shortURL", key, "error", err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return info, false
+	}
+	return info, true
+}
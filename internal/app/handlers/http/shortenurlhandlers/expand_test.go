@@ -0,0 +1,114 @@
+package shortenurlhandlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/aseptimu/url-shortener/internal/app/config"
+	"github.com/aseptimu/url-shortener/internal/app/service"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+// mockExpander знает активную ссылку "abcdef" и удалённую "gone".
+type mockExpander struct{}
+
+func (m *mockExpander) ExpandURL(_ context.Context, shortURL string) (service.URLInfo, error) {
+	switch shortURL {
+	case "abcdef":
+		return service.URLInfo{
+			ShortURL:    shortURL,
+			OriginalURL: "https://example.com/?a=<b>",
+			Title:       "Example <page>",
+			CreatedAt:   time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC),
+			Status:      service.URLStatusActive,
+			Clicks:      5,
+		}, nil
+	case "gone":
+		return service.URLInfo{ShortURL: shortURL, Status: service.URLStatusDeleted}, nil
+	}
+	return service.URLInfo{}, service.ErrURLNotFound
+}
+
+func newTestExpandRouter() *gin.Engine {
+	cfg := &config.ConfigType{BaseAddress: "http://localhost:8080"}
+	handler := NewExpandHandler(cfg, &mockExpander{}, zap.NewNop().Sugar())
+	redirect := func(c *gin.Context) { c.String(http.StatusTemporaryRedirect, "redirect") }
+	qr := func(c *gin.Context) { c.String(http.StatusOK, "qr") }
+	shortLink := ShortLink(redirect, handler.PreviewURL, qr)
+	router := gin.New()
+	router.GET("/:url", shortLink)
+	router.GET("/:url/*path", shortLink)
+	router.GET("/api/expand/:short", handler.ExpandURL)
+	return router
+}
+
+func TestExpandURL(t *testing.T) {
+	w := httptest.NewRecorder()
+	newTestExpandRouter().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/expand/abcdef", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{
+		"short_url": "http://localhost:8080/abcdef",
+		"original_url": "https://example.com/?a=<b>",
+		"title": "Example <page>",
+		"created_at": "2025-01-01T12:00:00Z",
+		"status": "active",
+		"password_protected": false,
+		"clicks": 5
+	}`, w.Body.String())
+
+	w = httptest.NewRecorder()
+	newTestExpandRouter().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/expand/gone", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"short_url": "http://localhost:8080/gone", "status": "deleted", "password_protected": false, "clicks": 0}`, w.Body.String())
+
+	w = httptest.NewRecorder()
+	newTestExpandRouter().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/expand/missing", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestPreviewURL(t *testing.T) {
+	w := httptest.NewRecorder()
+	newTestExpandRouter().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/abcdef+", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), "text/html")
+	body := w.Body.String()
+	assert.Contains(t, body, "Example &lt;page&gt;")
+	assert.Contains(t, body, `href="https://example.com/?a=%3cb%3e"`)
+	assert.NotContains(t, body, "<page>")
+
+	w = httptest.NewRecorder()
+	newTestExpandRouter().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/gone+", nil))
+	assert.Equal(t, http.StatusGone, w.Code)
+
+	w = httptest.NewRecorder()
+	newTestExpandRouter().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/abcdef", nil))
+	assert.Equal(t, http.StatusTemporaryRedirect, w.Code)
+}
+
+func TestShortLink_Routing(t *testing.T) {
+	tests := []struct {
+		path     string
+		wantCode int
+		wantBody string
+	}{
+		{"/abcdef/qr", http.StatusOK, "qr"},
+		{"/abcdef/docs/page", http.StatusTemporaryRedirect, "redirect"},
+		{"/abcdef+/docs", http.StatusTemporaryRedirect, "redirect"},
+		{"/abcdef+", http.StatusOK, "Example &lt;page&gt;"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			newTestExpandRouter().ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			assert.Equal(t, tt.wantCode, w.Code)
+			assert.Contains(t, w.Body.String(), tt.wantBody)
+		})
+	}
+}
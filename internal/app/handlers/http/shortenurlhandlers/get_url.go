@@ -11,19 +11,23 @@ import (
 	"go.uber.org/zap"
 	"net"
 	"net/http"
+	"strconv"
+	"time"
 )
 
 // URLGetter предоставляет методы получения URL для клиентского кода.
 type URLGetter interface {
-	GetOriginalURL(ctx context.Context, input string) (string, error)
-	GetUserURLs(ctx context.Context, userID string) ([]service.URLDTO, error)
+	GetOriginalURL(ctx context.Context, input string, visit service.Visit) (service.Redirect, error)
+	UnlockURL(ctx context.Context, input, password string, visit service.Visit) (service.Redirect, error)
+	GetUserURLs(ctx context.Context, userID string, filter service.URLFilter) (service.URLPage, error)
 	GetStats(ctx context.Context) (service.StatsDTO, error)
 }
 
-// Stats хранит данные о количестве пользователей и сохраненных url
+// Stats хранит данные о количестве пользователей, сохраненных url и переходов
 type Stats struct {
-	Urls  int `json:"urls"`
-	Users int `json:"users"`
+	Urls   int `json:"urls"`
+	Users  int `json:"users"`
+	Clicks int `json:"clicks"`
 }
 
 // URLRecord хранит данные одной записи сокращённого URL.
@@ -39,50 +43,137 @@ type URLRecord struct {
 type GetURLHandler struct {
 	cfg     *config.ConfigType
 	service URLGetter
+	clicks  service.ClickTracker
 	logger  *zap.SugaredLogger
 }
 
 // NewGetURLHandler создаёт новый экземпляр GetURLHandler.
-func NewGetURLHandler(cfg *config.ConfigType, service URLGetter, logger *zap.SugaredLogger) *GetURLHandler {
-	return &GetURLHandler{cfg: cfg, service: service, logger: logger}
+// Если clicks равен nil, переходы по ссылкам не записываются.
+func NewGetURLHandler(cfg *config.ConfigType, service URLGetter, clicks service.ClickTracker, logger *zap.SugaredLogger) *GetURLHandler {
+	return &GetURLHandler{cfg: cfg, service: service, clicks: clicks, logger: logger}
 }
 
-// GetURL перенаправляет клиента на оригинальный URL, если он существует и не удалён.
+// recordClick записывает переход по ссылке key с выданным вариантом A/B-сплита variant.
+// Ошибка записи не мешает перенаправлению.
+func (h *GetURLHandler) recordClick(c *gin.Context, key, variant string) {
+	if h.clicks == nil {
+		return
+	}
+	err := h.clicks.RecordClick(c.Request.Context(), key, c.Request.Referer(), c.Request.UserAgent(), c.ClientIP(), variant)
+	if err != nil {
+		h.logger.Errorw("Failed to record click", "shortURL", key, "error", err)
+	}
+}
+
+// GetURL перенаправляет клиента на оригинальный URL, если он существует, не удалён и не просрочен.
+// Адрес назначения выбирается правилами ссылки по устройству, языку и стране посетителя,
+// а затем A/B-сплитом: выданный вариант запоминается в cookie и записывается в статистику переходов.
+// Код ответа задаётся ссылкой (по умолчанию 307); строка запроса и остаток пути после короткого кода
+// переносятся в адрес назначения, если это включено для ссылки.
+// Для удалённых, просроченных и исчерпавших лимит переходов ссылок возвращает 410 Gone.
+// Для защищённых паролем ссылок отдаёт HTML-форму ввода пароля с кодом 401.
 func (h *GetURLHandler) GetURL(c *gin.Context) {
 	utils.LogRequest(c, h.logger)
 
-	key := c.Param("url")
-	originalURL, err := h.service.GetOriginalURL(c.Request.Context(), key)
+	key := hostKey(c, h.cfg, c.Param("url"))
+	redirect, err := h.service.GetOriginalURL(c.Request.Context(), key, visit(c))
 	switch {
+	case errors.Is(err, service.ErrPasswordRequired):
+		renderPasswordForm(c, http.StatusUnauthorized, "")
+		return
 	case errors.Is(err, service.ErrURLNotFound):
 		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": err.Error()})
-	case errors.Is(err, service.ErrURLDeleted):
+		return
+	case errors.Is(err, service.ErrURLDeleted), errors.Is(err, service.ErrURLExpired),
+		errors.Is(err, service.ErrURLExhausted):
 		c.AbortWithStatusJSON(http.StatusGone, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		h.logger.Errorw("Failed to get original URL", "shortURL", key, "error", err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
 	}
 
-	c.Header("Location", originalURL)
+	h.recordClick(c, key, redirect.Variant)
+	rememberVariant(c, c.Param("url"), redirect.Variant)
+	c.Header("Location", redirect.URL)
 	c.Header("Content-Type", "text/plain")
-	c.String(http.StatusTemporaryRedirect, originalURL)
+	c.String(redirect.Code, redirect.URL)
 }
 
-// GetStats возвращает кол-во url и пользователей
-func (h *GetURLHandler) GetStats(c *gin.Context) {
+// UnlockURL обрабатывает POST /{short} и POST /{short}/{path} из формы ввода пароля.
+// При верном пароле перенаправляет на оригинальный URL с кодом 303 See Other,
+// при неверном снова отдаёт форму с кодом 401, а после превышения лимита попыток — 429.
+func (h *GetURLHandler) UnlockURL(c *gin.Context) {
 	utils.LogRequest(c, h.logger)
 
-	if h.cfg.TrustedSubnet == "" {
-		c.AbortWithStatus(http.StatusForbidden)
+	key := hostKey(c, h.cfg, c.Param("url"))
+	redirect, err := h.service.UnlockURL(c.Request.Context(), key, c.PostForm("password"), visit(c))
+	switch {
+	case errors.Is(err, service.ErrPasswordRequired):
+		renderPasswordForm(c, http.StatusUnauthorized, "")
+		return
+	case errors.Is(err, service.ErrInvalidPassword):
+		renderPasswordForm(c, http.StatusUnauthorized, err.Error())
+		return
+	case errors.Is(err, service.ErrTooManyAttempts):
+		renderPasswordForm(c, http.StatusTooManyRequests, err.Error())
+		return
+	case errors.Is(err, service.ErrURLNotFound):
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, service.ErrURLDeleted), errors.Is(err, service.ErrURLExpired),
+		errors.Is(err, service.ErrURLExhausted):
+		c.AbortWithStatusJSON(http.StatusGone, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		h.logger.Errorw("Failed to unlock URL", "shortURL", key, "error", err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	clientIP := net.ParseIP(c.GetHeader("X-Real-IP"))
-	_, trustedNet, err := net.ParseCIDR(h.cfg.TrustedSubnet)
-	if err != nil {
-		h.logger.Errorw("Invalid CIDR in config.TrustedSubnet", "value", h.cfg.TrustedSubnet, "error", err)
-		c.AbortWithStatus(http.StatusForbidden)
+	h.recordClick(c, key, redirect.Variant)
+	rememberVariant(c, c.Param("url"), redirect.Variant)
+	c.Redirect(http.StatusSeeOther, redirect.URL)
+}
+
+// visit собирает данные перехода: остаток пути после короткого кода, строку запроса
+// и признаки посетителя для правил перенаправления и A/B-сплита.
+func visit(c *gin.Context) service.Visit {
+	variant, _ := c.Cookie(variantCookiePrefix + c.Param("url"))
+	return service.Visit{
+		Path:           c.Param("path"),
+		Query:          c.Request.URL.RawQuery,
+		UserAgent:      c.Request.UserAgent(),
+		AcceptLanguage: c.GetHeader("Accept-Language"),
+		IP:             c.ClientIP(),
+		Variant:        variant,
+	}
+}
+
+const (
+	// variantCookiePrefix — префикс имени cookie, в которой запоминается вариант A/B-сплита ссылки.
+	variantCookiePrefix = "split_"
+	// variantCookieMaxAge — срок, в течение которого посетитель получает тот же вариант, в секундах.
+	variantCookieMaxAge = 30 * 24 * 60 * 60
+)
+
+// rememberVariant сохраняет выданный вариант A/B-сплита ссылки с кодом code в cookie,
+// чтобы при повторных переходах посетитель получал тот же вариант. Cookie привязана к хосту запроса,
+// поэтому одинаковые коды на разных доменах не делят вариант.
+func rememberVariant(c *gin.Context, code, variant string) {
+	if variant == "" {
 		return
 	}
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(variantCookiePrefix+code, variant, variantCookieMaxAge, "/"+code, "", false, true)
+}
 
-	if clientIP == nil || !trustedNet.Contains(clientIP) {
+// GetStats возвращает кол-во url и пользователей
+func (h *GetURLHandler) GetStats(c *gin.Context) {
+	utils.LogRequest(c, h.logger)
+
+	if !fromTrustedSubnet(c, h.cfg, h.logger) {
 		c.AbortWithStatus(http.StatusForbidden)
 		return
 	}
@@ -96,7 +187,32 @@ func (h *GetURLHandler) GetStats(c *gin.Context) {
 	c.JSON(http.StatusOK, stats)
 }
 
-// GetUserURLs возвращает все короткие URL, созданные текущим пользователем.
+// fromTrustedSubnet сообщает, входит ли адрес клиента из заголовка X-Real-IP в доверенную подсеть.
+// Если подсеть не задана, доверенных клиентов нет.
+func fromTrustedSubnet(c *gin.Context, cfg *config.ConfigType, logger *zap.SugaredLogger) bool {
+	if cfg.TrustedSubnet == "" {
+		return false
+	}
+	_, trustedNet, err := net.ParseCIDR(cfg.TrustedSubnet)
+	if err != nil {
+		logger.Errorw("Invalid CIDR in config.TrustedSubnet", "value", cfg.TrustedSubnet, "error", err)
+		return false
+	}
+	clientIP := net.ParseIP(c.GetHeader("X-Real-IP"))
+	return clientIP != nil && trustedNet.Contains(clientIP)
+}
+
+// nextPageTokenHeader — заголовок ответа с токеном следующей страницы списка ссылок.
+const nextPageTokenHeader = "X-Next-Page-Token"
+
+// GetUserURLs возвращает страницу коротких URL, созданных текущим пользователем.
+// Параметры запроса:
+//   - tags=a,b — только ссылки, у которых есть все перечисленные теги;
+//   - q — подстрока оригинального URL;
+//   - url — обратный поиск: ссылка пользователя на этот URL;
+//   - sort=created|destination и order=asc|desc — порядок списка;
+//   - limit и page_token — размер страницы и токен из заголовка X-Next-Page-Token предыдущего ответа;
+//     без них возвращается весь список.
 func (h *GetURLHandler) GetUserURLs(c *gin.Context) {
 	utils.LogRequest(c, h.logger)
 
@@ -111,31 +227,76 @@ func (h *GetURLHandler) GetUserURLs(c *gin.Context) {
 		return
 	}
 
-	records, err := h.service.GetUserURLs(c.Request.Context(), userIDStr)
-	if err != nil {
+	filter := service.URLFilter{
+		Tags:        queryTags(c),
+		Search:      c.Query("q"),
+		Destination: c.Query("url"),
+		Sort:        c.Query("sort"),
+		Order:       c.Query("order"),
+		PageToken:   c.Query("page_token"),
+	}
+	if limit := c.Query("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": service.ErrInvalidPageSize.Error()})
+			return
+		}
+		filter.Limit = n
+	}
+
+	page, err := h.service.GetUserURLs(c.Request.Context(), userIDStr, filter)
+	switch {
+	case errors.Is(err, service.ErrInvalidTag), errors.Is(err, service.ErrInvalidURL),
+		errors.Is(err, service.ErrInvalidSort), errors.Is(err, service.ErrInvalidPageSize),
+		errors.Is(err, service.ErrInvalidPageToken):
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	case err != nil:
 		h.logger.Errorw("Failed to get user URLs", "error", err)
 		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
+	records := page.URLs
+	if page.NextPageToken != "" {
+		c.Header(nextPageTokenHeader, page.NextPageToken)
+	}
 	if len(records) == 0 {
 		c.Status(http.StatusNoContent)
 		return
 	}
 
-	var resp []struct {
-		ShortURL    string `json:"short_url"`
-		OriginalURL string `json:"original_url"`
+	type userURL struct {
+		ShortURL      string     `json:"short_url"`
+		OriginalURL   string     `json:"original_url"`
+		ExpiresAt     *time.Time `json:"expires_at,omitempty"`
+		RemainingUses *int       `json:"remaining_uses,omitempty"`
+		Clicks        int        `json:"clicks"`
+		Tags          []string   `json:"tags,omitempty"`
+		CreatedAt     *time.Time `json:"created_at,omitempty"`
+		Title         string     `json:"title,omitempty"`
 	}
+	var resp []userURL
 	for _, rec := range records {
-		resp = append(resp, struct {
-			ShortURL    string `json:"short_url"`
-			OriginalURL string `json:"original_url"`
-		}{
-			ShortURL:    h.cfg.BaseAddress + "/" + rec.ShortURL,
-			OriginalURL: rec.OriginalURL,
+		resp = append(resp, userURL{
+			ShortURL:      linkURL(h.cfg, rec.ShortURL),
+			OriginalURL:   rec.OriginalURL,
+			ExpiresAt:     rec.ExpiresAt,
+			RemainingUses: rec.RemainingUses,
+			Clicks:        rec.Clicks,
+			Tags:          rec.Tags,
+			CreatedAt:     createdAt(rec),
+			Title:         rec.Title,
 		})
 	}
 
 	c.JSON(http.StatusOK, resp)
 }
+
+// createdAt возвращает время создания ссылки или nil, если хранилище его не знает.
+func createdAt(url service.URLDTO) *time.Time {
+	if url.CreatedAt.IsZero() {
+		return nil
+	}
+	return &url.CreatedAt
+}
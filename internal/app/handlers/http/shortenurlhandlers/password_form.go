@@ -0,0 +1,38 @@
+package shortenurlhandlers
+
+import (
+	"html/template"
+
+	"github.com/gin-gonic/gin"
+)
+
+// passwordFormTemplate — страница ввода пароля для защищённой ссылки.
+// Форма отправляется POST-запросом на тот же адрес вместе с путём и строкой запроса,
+// чтобы после ввода пароля они перенеслись в адрес назначения.
+var passwordFormTemplate = template.Must(template.New("password").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Protected link</title>
+</head>
+<body>
+<form method="POST" action="{{.Action}}">
+<p>This link is password protected.</p>
+{{if .Error}}<p style="color: red">{{.Error}}</p>{{end}}
+<input type="password" name="password" autofocus required>
+<button type="submit">Continue</button>
+</form>
+</body>
+</html>
+`))
+
+// renderPasswordForm отдаёт HTML-форму ввода пароля с кодом status.
+func renderPasswordForm(c *gin.Context, status int, errMsg string) {
+	c.Status(status)
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	_ = passwordFormTemplate.Execute(c.Writer, struct {
+		Action string
+		Error  string
+	}{Action: c.Request.URL.RequestURI(), Error: errMsg})
+	c.Abort()
+}
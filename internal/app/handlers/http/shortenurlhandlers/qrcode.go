@@ -0,0 +1,74 @@
+package shortenurlhandlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/aseptimu/url-shortener/internal/app/config"
+	"github.com/aseptimu/url-shortener/internal/app/service"
+	"github.com/aseptimu/url-shortener/internal/app/utils"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// qrCacheControl разрешает долго кэшировать QR-код: он зависит только от адреса ссылки и параметров запроса.
+const qrCacheControl = "public, max-age=31536000, immutable"
+
+// QRCodeHandler отдаёт QR-коды коротких ссылок.
+type QRCodeHandler struct {
+	cfg     *config.ConfigType
+	service service.QRCoder
+	logger  *zap.SugaredLogger
+}
+
+// NewQRCodeHandler создаёт новый экземпляр QRCodeHandler.
+func NewQRCodeHandler(cfg *config.ConfigType, service service.QRCoder, logger *zap.SugaredLogger) *QRCodeHandler {
+	return &QRCodeHandler{cfg: cfg, service: service, logger: logger}
+}
+
+// GetQRCode обрабатывает GET /{short}/qr и возвращает QR-код полного адреса ссылки.
+// Параметры запроса: format=png|svg, size — размер в пикселях, margin — рамка в модулях,
+// level=L|M|Q|H — уровень коррекции ошибок.
+func (h *QRCodeHandler) GetQRCode(c *gin.Context) {
+	utils.LogRequest(c, h.logger)
+
+	opts := service.QROptions{Format: c.Query("format"), Level: c.Query("level")}
+	if size := c.Query("size"); size != "" {
+		n, err := strconv.Atoi(size)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": service.ErrInvalidQROptions.Error()})
+			return
+		}
+		opts.Size = n
+	}
+	if margin := c.Query("margin"); margin != "" {
+		n, err := strconv.Atoi(margin)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": service.ErrInvalidQROptions.Error()})
+			return
+		}
+		opts.Margin = &n
+	}
+
+	key := hostKey(c, h.cfg, c.Param("url"))
+	image, contentType, err := h.service.QRCode(c.Request.Context(), key, opts)
+	switch {
+	case errors.Is(err, service.ErrInvalidQROptions):
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, service.ErrURLNotFound):
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, service.ErrURLDeleted):
+		c.AbortWithStatusJSON(http.StatusGone, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		h.logger.Errorw("Failed to render QR code", "shortURL", key, "error", err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Cache-Control", qrCacheControl)
+	c.Data(http.StatusOK, contentType, image)
+}
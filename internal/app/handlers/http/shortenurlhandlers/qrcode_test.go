@@ -0,0 +1,76 @@
+package shortenurlhandlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aseptimu/url-shortener/internal/app/config"
+	"github.com/aseptimu/url-shortener/internal/app/service"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+// mockQRCoder знает только ссылку "abcdef" и запоминает параметры последнего запроса.
+type mockQRCoder struct {
+	gotOpts service.QROptions
+}
+
+func (m *mockQRCoder) QRCode(_ context.Context, shortURL string, opts service.QROptions) ([]byte, string, error) {
+	m.gotOpts = opts
+	if shortURL != "abcdef" {
+		return nil, "", service.ErrURLNotFound
+	}
+	if opts.Level == "X" {
+		return nil, "", service.ErrInvalidQROptions
+	}
+	return []byte("<svg/>"), "image/svg+xml", nil
+}
+
+func TestGetQRCode(t *testing.T) {
+	coder := &mockQRCoder{}
+	handler := NewQRCodeHandler(&config.ConfigType{}, coder, zap.NewNop().Sugar())
+	router := gin.New()
+	router.GET("/:url/qr", handler.GetQRCode)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/abcdef/qr?format=svg&size=512&margin=0&level=Q", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "image/svg+xml", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Header().Get("Cache-Control"), "max-age=")
+	assert.Equal(t, "<svg/>", w.Body.String())
+	if assert.NotNil(t, coder.gotOpts.Margin) {
+		assert.Equal(t, 0, *coder.gotOpts.Margin)
+	}
+	assert.Equal(t, 512, coder.gotOpts.Size)
+	assert.Equal(t, "svg", coder.gotOpts.Format)
+	assert.Equal(t, "Q", coder.gotOpts.Level)
+}
+
+func TestGetQRCode_Errors(t *testing.T) {
+	handler := NewQRCodeHandler(&config.ConfigType{}, &mockQRCoder{}, zap.NewNop().Sugar())
+	router := gin.New()
+	router.GET("/:url/qr", handler.GetQRCode)
+
+	tests := []struct {
+		name       string
+		path       string
+		wantStatus int
+	}{
+		{name: "unknown link", path: "/nope/qr", wantStatus: http.StatusNotFound},
+		{name: "non-numeric size", path: "/abcdef/qr?size=big", wantStatus: http.StatusBadRequest},
+		{name: "non-numeric margin", path: "/abcdef/qr?margin=wide", wantStatus: http.StatusBadRequest},
+		{name: "invalid options", path: "/abcdef/qr?level=X", wantStatus: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			assert.Equal(t, tt.wantStatus, w.Code)
+			assert.Empty(t, w.Header().Get("Cache-Control"))
+		})
+	}
+}
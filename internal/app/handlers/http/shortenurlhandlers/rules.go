@@ -0,0 +1,133 @@
+package shortenurlhandlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/aseptimu/url-shortener/internal/app/config"
+	"github.com/aseptimu/url-shortener/internal/app/service"
+	"github.com/aseptimu/url-shortener/internal/app/utils"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// RuleHandler позволяет владельцу управлять правилами перенаправления своих ссылок.
+type RuleHandler struct {
+	cfg     *config.ConfigType
+	service service.RuleManager
+	logger  *zap.SugaredLogger
+}
+
+// NewRuleHandler создаёт новый экземпляр RuleHandler.
+func NewRuleHandler(cfg *config.ConfigType, service service.RuleManager, logger *zap.SugaredLogger) *RuleHandler {
+	return &RuleHandler{cfg: cfg, service: service, logger: logger}
+}
+
+type ruleMatch struct {
+	Device   string `json:"device,omitempty"`
+	Language string `json:"language,omitempty"`
+	Country  string `json:"country,omitempty"`
+}
+
+type redirectRule struct {
+	Match ruleMatch `json:"match"`
+	URL   string    `json:"url"`
+}
+
+type urlRulesResponse struct {
+	ShortURL string         `json:"short_url"`
+	Rules    []redirectRule `json:"rules"`
+}
+
+// GetRules обрабатывает GET /api/user/urls/{short}/rules
+// и возвращает правила перенаправления ссылки в порядке проверки.
+func (h *RuleHandler) GetRules(c *gin.Context) {
+	utils.LogRequest(c, h.logger)
+
+	userID, ok := h.userID(c)
+	if !ok {
+		return
+	}
+
+	key := ownedKey(c, h.cfg)
+	rules, err := h.service.GetRules(c.Request.Context(), key, userID)
+	if err != nil {
+		h.abortWithError(c, key, err)
+		return
+	}
+	c.JSON(http.StatusOK, h.response(key, rules))
+}
+
+// SetRules обрабатывает PUT /api/user/urls/{short}/rules.
+// Принимает JSON-массив правил вида {"match": {"device": "ios", "language": "de", "country": "DE"}, "url": "..."}
+// и заменяет ими все правила ссылки. При переходе срабатывает первое правило,
+// под все условия которого подходит посетитель; если таких нет, используется основное назначение.
+// Пустой массив удаляет все правила.
+func (h *RuleHandler) SetRules(c *gin.Context) {
+	utils.LogRequest(c, h.logger)
+
+	userID, ok := h.userID(c)
+	if !ok {
+		return
+	}
+
+	var req []redirectRule
+	if err := json.NewDecoder(c.Request.Body).Decode(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON format"})
+		return
+	}
+	rules := make([]service.RedirectRule, len(req))
+	for i, rule := range req {
+		rules[i] = service.RedirectRule{
+			Match: service.RuleMatch{Device: rule.Match.Device, Language: rule.Match.Language, Country: rule.Match.Country},
+			URL:   rule.URL,
+		}
+	}
+
+	key := ownedKey(c, h.cfg)
+	saved, err := h.service.SetRules(c.Request.Context(), key, userID, rules)
+	if err != nil {
+		h.abortWithError(c, key, err)
+		return
+	}
+	c.JSON(http.StatusOK, h.response(key, saved))
+}
+
+func (h *RuleHandler) response(key string, rules []service.RedirectRule) urlRulesResponse {
+	resp := urlRulesResponse{ShortURL: linkURL(h.cfg, key), Rules: make([]redirectRule, len(rules))}
+	for i, rule := range rules {
+		resp.Rules[i] = redirectRule{
+			Match: ruleMatch{Device: rule.Match.Device, Language: rule.Match.Language, Country: rule.Match.Country},
+			URL:   rule.URL,
+		}
+	}
+	return resp
+}
+
+func (h *RuleHandler) userID(c *gin.Context) (string, bool) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return "", false
+	}
+	userIDStr, ok := userID.(string)
+	if !ok || userIDStr == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return "", false
+	}
+	return userIDStr, true
+}
+
+func (h *RuleHandler) abortWithError(c *gin.Context, key string, err error) {
+	switch {
+	case errors.Is(err, service.ErrURLNotFound):
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrInvalidRule), errors.Is(err, service.ErrTooManyRules),
+		errors.Is(err, service.ErrInvalidURL), errors.Is(err, service.ErrDestinationRejected):
+		c.AbortWithStatusJSON(http.StatusBadRequest, shortenErrorBody(err))
+	default:
+		h.logger.Errorw("Failed to update redirect rules", "shortURL", key, "error", err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
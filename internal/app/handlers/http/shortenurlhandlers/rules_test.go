@@ -0,0 +1,80 @@
+package shortenurlhandlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aseptimu/url-shortener/internal/app/config"
+	"github.com/aseptimu/url-shortener/internal/app/service"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+// mockRuleManager хранит правило ссылки "abcdef" пользователя "owner".
+type mockRuleManager struct{}
+
+func (m *mockRuleManager) GetRules(_ context.Context, shortURL, userID string) ([]service.RedirectRule, error) {
+	if shortURL != "abcdef" || userID != "owner" {
+		return nil, service.ErrURLNotFound
+	}
+	return []service.RedirectRule{{Match: service.RuleMatch{Device: service.DeviceIOS}, URL: "https://apps.apple.com/app/id1"}}, nil
+}
+
+func (m *mockRuleManager) SetRules(_ context.Context, shortURL, userID string, rules []service.RedirectRule) ([]service.RedirectRule, error) {
+	if shortURL != "abcdef" || userID != "owner" {
+		return nil, service.ErrURLNotFound
+	}
+	for _, rule := range rules {
+		if rule.Match == (service.RuleMatch{}) {
+			return nil, service.ErrInvalidRule
+		}
+	}
+	return rules, nil
+}
+
+func newTestRuleRouter(userID string) *gin.Engine {
+	cfg := &config.ConfigType{BaseAddress: "http://localhost:8080"}
+	handler := NewRuleHandler(cfg, &mockRuleManager{}, zap.NewNop().Sugar())
+	return newTestRouter(userID, func(r *gin.Engine) {
+		r.GET("/api/user/urls/:short/rules", handler.GetRules)
+		r.PUT("/api/user/urls/:short/rules", handler.SetRules)
+	})
+}
+
+func TestRuleHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		userID     string
+		method     string
+		body       string
+		wantStatus int
+		wantBody   string
+	}{
+		{name: "get", userID: "owner", method: http.MethodGet, wantStatus: http.StatusOK,
+			wantBody: `{"short_url":"http://localhost:8080/abcdef","rules":[{"match":{"device":"ios"},"url":"https://apps.apple.com/app/id1"}]}`},
+		{name: "set", userID: "owner", method: http.MethodPut, body: `[{"match":{"language":"de","country":"DE"},"url":"https://example.de"}]`, wantStatus: http.StatusOK,
+			wantBody: `{"short_url":"http://localhost:8080/abcdef","rules":[{"match":{"language":"de","country":"DE"},"url":"https://example.de"}]}`},
+		{name: "clear", userID: "owner", method: http.MethodPut, body: `[]`, wantStatus: http.StatusOK,
+			wantBody: `{"short_url":"http://localhost:8080/abcdef","rules":[]}`},
+		{name: "invalid rule", userID: "owner", method: http.MethodPut, body: `[{"url":"https://example.com"}]`, wantStatus: http.StatusBadRequest},
+		{name: "invalid JSON", userID: "owner", method: http.MethodPut, body: `{`, wantStatus: http.StatusBadRequest},
+		{name: "foreign link", userID: "intruder", method: http.MethodGet, wantStatus: http.StatusNotFound},
+		{name: "unauthorized", method: http.MethodGet, wantStatus: http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(tt.method, "/api/user/urls/abcdef/rules", strings.NewReader(tt.body))
+			newTestRuleRouter(tt.userID).ServeHTTP(w, r)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantBody != "" {
+				assert.JSONEq(t, tt.wantBody, w.Body.String())
+			}
+		})
+	}
+}
@@ -4,6 +4,7 @@ package shortenurlhandlers
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"github.com/aseptimu/url-shortener/internal/app/config"
 	"github.com/aseptimu/url-shortener/internal/app/service"
 	"github.com/aseptimu/url-shortener/internal/app/utils"
@@ -12,6 +13,9 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"strconv"
+	"strings"
+	"time"
 )
 
 // ShortenHandler обрабатывает создание коротких ссылок
@@ -31,6 +35,14 @@ func NewShortenHandler(cfg *config.ConfigType, service service.URLShortener, log
 // URLCreator обрабатывает POST /
 // Читает из тела запроса plain-text URL, сокращает его
 // и возвращает новый короткий URL в виде text/plain.
+// Срок жизни ссылки можно задать query-параметрами expires_at (RFC 3339) или ttl (секунды),
+// лимит переходов — параметром max_uses, теги — параметром tags через запятую.
+// Код перенаправления задаётся параметром redirect_code, перенос строки запроса и пути —
+// параметрами pass_query и pass_path со значениями true/false.
+// UTM-шаблон пользователя задаётся параметром utm_template, отдельные UTM-параметры —
+// параметрами utm_source, utm_medium, utm_campaign, utm_term и utm_content.
+// Короткий домен ссылки задаётся параметром domain; по умолчанию используется основной.
+// Пароль передаётся заголовком X-Link-Password, чтобы не попадать в URL.
 // В случае конфликта возвращает 409 Conflict с уже существующим ключом.
 func (h *ShortenHandler) URLCreator(c *gin.Context) {
 	utils.LogRequest(c, h.logger)
@@ -54,7 +66,52 @@ func (h *ShortenHandler) URLCreator(c *gin.Context) {
 		return
 	}
 
-	shortURL, err := h.Service.ShortenURL(c.Request.Context(), text.String(), userIDStr)
+	var ttl int64
+	if rawTTL := c.Query("ttl"); rawTTL != "" {
+		if ttl, err = strconv.ParseInt(rawTTL, 10, 64); err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": service.ErrInvalidExpiry.Error()})
+			return
+		}
+	}
+	expiresAt, err := service.ParseExpiry(c.Query("expires_at"), ttl, time.Now())
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var maxUses int
+	if rawMaxUses := c.Query("max_uses"); rawMaxUses != "" {
+		if maxUses, err = strconv.Atoi(rawMaxUses); err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": service.ErrInvalidMaxUses.Error()})
+			return
+		}
+	}
+	var redirectCode int
+	if rawCode := c.Query("redirect_code"); rawCode != "" {
+		if redirectCode, err = strconv.Atoi(rawCode); err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": service.ErrInvalidRedirectCode.Error()})
+			return
+		}
+	}
+	passQuery, err := queryFlag(c, "pass_query")
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	passPath, err := queryFlag(c, "pass_path")
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	domain, ok := shortenDomain(c, h.cfg, c.Query("domain"))
+	if !ok {
+		return
+	}
+
+	shortURL, err := h.Service.ShortenURL(c.Request.Context(), service.ShortenRequest{OriginalURL: text.String(), Domain: domain, ExpiresAt: expiresAt, MaxUses: maxUses, Password: c.GetHeader("X-Link-Password"), Tags: queryTags(c), Title: c.Query("title"), RedirectCode: redirectCode, PassQuery: passQuery, PassPath: passPath, UTMTemplate: c.Query("utm_template"), UTM: queryUTM(c)}, userIDStr)
+	if status, ok := shortenErrorStatus(err); ok {
+		c.AbortWithStatusJSON(status, shortenErrorBody(err))
+		return
+	}
 	if err != nil && !errors.Is(err, service.ErrConflict) {
 		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -62,15 +119,30 @@ func (h *ShortenHandler) URLCreator(c *gin.Context) {
 
 	c.Header("Content-Type", "text/plain")
 	if errors.Is(err, service.ErrConflict) {
-		c.String(http.StatusConflict, h.cfg.BaseAddress+"/"+shortURL)
+		c.String(http.StatusConflict, linkURL(h.cfg, shortURL))
 	} else {
-		c.String(http.StatusCreated, h.cfg.BaseAddress+"/"+shortURL)
+		c.String(http.StatusCreated, linkURL(h.cfg, shortURL))
 	}
 }
 
 // URLCreatorJSON обрабатывает POST /api/shorten
-// Принимает JSON {"url": "..."} и возвращает JSON {"result": "..."}.
-// В случае конфликта возвращает 409 Conflict.
+// Принимает JSON {"url": "...", "alias": "...", "expires_at": "...", "ttl": 0, "max_uses": 0, "password": "...", "tags": ["..."]}
+// и возвращает JSON {"result": "..."}.
+// Поле alias необязательно: при невалидном алиасе возвращает 400 Bad Request,
+// если алиас уже занят — 409 Conflict с описанием ошибки.
+// Срок жизни задаётся либо expires_at (RFC 3339), либо ttl в секундах.
+// Если max_uses больше нуля, ссылка перестаёт работать после указанного числа переходов.
+// Если задан password, перед переходом по ссылке потребуется ввести пароль.
+// redirect_code задаёт код перенаправления (301, 302, 307 или 308, по умолчанию 307),
+// pass_query и pass_path включают перенос строки запроса и остатка пути в адрес назначения.
+// utm_template задаёт имя UTM-шаблона пользователя, а utm — объект
+// {"source": "...", "medium": "...", "campaign": "...", "term": "...", "content": "..."},
+// значения которого переопределяют значения шаблона. Итоговые параметры дописываются к url,
+// если в нём ещё нет параметров с такими именами; дубликаты ищутся по итоговому URL.
+// domain задаёт короткий домен ссылки: коды и дубликаты проверяются отдельно для каждого домена,
+// а адрес в ответе строится от базового адреса домена. Ненастроенный домен отклоняется с 400 Bad Request.
+// URL, запрещённый политикой назначения, отклоняется с 400 Bad Request и причиной в поле reason.
+// В случае конфликта по оригинальному URL возвращает 409 Conflict.
 func (h *ShortenHandler) URLCreatorJSON(c *gin.Context) {
 	utils.LogRequest(c, h.logger)
 
@@ -88,14 +160,45 @@ func (h *ShortenHandler) URLCreatorJSON(c *gin.Context) {
 	}
 
 	var req struct {
-		URL string `json:"url"`
+		URL       string   `json:"url"`
+		Alias     string   `json:"alias"`
+		ExpiresAt string   `json:"expires_at"`
+		TTL       int64    `json:"ttl"`
+		MaxUses   int      `json:"max_uses"`
+		Password  string   `json:"password"`
+		Tags      []string `json:"tags"`
+		Title     string   `json:"title"`
+
+		RedirectCode int  `json:"redirect_code"`
+		PassQuery    bool `json:"pass_query"`
+		PassPath     bool `json:"pass_path"`
+
+		UTMTemplate string    `json:"utm_template"`
+		UTM         utmParams `json:"utm"`
+
+		Domain string `json:"domain"`
 	}
 	if err = json.Unmarshal(body, &req); err != nil {
 		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON format"})
 		return
 	}
 
-	shortURL, err := h.Service.ShortenURL(c.Request.Context(), req.URL, userIDStr)
+	expiresAt, err := service.ParseExpiry(req.ExpiresAt, req.TTL, time.Now())
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	domain, ok := shortenDomain(c, h.cfg, req.Domain)
+	if !ok {
+		return
+	}
+
+	shortURL, err := h.Service.ShortenURL(c.Request.Context(), service.ShortenRequest{OriginalURL: req.URL, Domain: domain, Alias: req.Alias, ExpiresAt: expiresAt, MaxUses: req.MaxUses, Password: req.Password, Tags: req.Tags, Title: req.Title, RedirectCode: req.RedirectCode, PassQuery: req.PassQuery, PassPath: req.PassPath, UTMTemplate: req.UTMTemplate, UTM: req.UTM.toService()}, userIDStr)
+	if status, ok := shortenErrorStatus(err); ok {
+		c.AbortWithStatusJSON(status, shortenErrorBody(err))
+		return
+	}
 	if err != nil && !errors.Is(err, service.ErrConflict) {
 		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -104,7 +207,7 @@ func (h *ShortenHandler) URLCreatorJSON(c *gin.Context) {
 	resp := struct {
 		Result string `json:"result"`
 	}{
-		Result: h.cfg.BaseAddress + "/" + shortURL,
+		Result: linkURL(h.cfg, shortURL),
 	}
 
 	if errors.Is(err, service.ErrConflict) {
@@ -116,8 +219,21 @@ func (h *ShortenHandler) URLCreatorJSON(c *gin.Context) {
 
 // URLRequest описывает элемент входного массива для batch-сокращения.
 type URLRequest struct {
-	CorrelationID string `json:"correlation_id"`
-	OriginalURL   string `json:"original_url"`
+	CorrelationID string     `json:"correlation_id"`
+	OriginalURL   string     `json:"original_url"`
+	Alias         string     `json:"alias,omitempty"`
+	ExpiresAt     string     `json:"expires_at,omitempty"`
+	TTL           int64      `json:"ttl,omitempty"`
+	MaxUses       int        `json:"max_uses,omitempty"`
+	Password      string     `json:"password,omitempty"`
+	Tags          []string   `json:"tags,omitempty"`
+	Title         string     `json:"title,omitempty"`
+	RedirectCode  int        `json:"redirect_code,omitempty"`
+	PassQuery     bool       `json:"pass_query,omitempty"`
+	PassPath      bool       `json:"pass_path,omitempty"`
+	UTMTemplate   string     `json:"utm_template,omitempty"`
+	UTM           *utmParams `json:"utm,omitempty"`
+	Domain        string     `json:"domain,omitempty"`
 }
 
 // URLResponse описывает результат batch-сокращения для одного URL.
@@ -129,6 +245,7 @@ type URLResponse struct {
 // URLCreatorBatch обрабатывает POST /api/shorten/batch
 // Принимает JSON-массив URLRequest и возвращает JSON-массив URLResponse.
 // Генерирует короткие URL в batch-режиме, возвращая 201 Created.
+// Ошибки алиасов и срока жизни обрабатываются так же, как в URLCreatorJSON, и отменяют весь batch.
 func (h *ShortenHandler) URLCreatorBatch(c *gin.Context) {
 	utils.LogRequest(c, h.logger)
 
@@ -139,47 +256,112 @@ func (h *ShortenHandler) URLCreatorBatch(c *gin.Context) {
 		}
 	}
 
-	var requestURLs []struct {
-		CorrelationID string `json:"correlation_id"`
-		OriginalURL   string `json:"original_url"`
-	}
+	var requestURLs []URLRequest
 
 	if err := json.NewDecoder(c.Request.Body).Decode(&requestURLs); err != nil {
 		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON format"})
 		return
 	}
 
-	inputURLs := make([]string, len(requestURLs))
+	now := time.Now()
+	inputURLs := make([]service.ShortenRequest, len(requestURLs))
 	for i, req := range requestURLs {
-		inputURLs[i] = req.OriginalURL
+		expiresAt, err := service.ParseExpiry(req.ExpiresAt, req.TTL, now)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		domain, ok := shortenDomain(c, h.cfg, req.Domain)
+		if !ok {
+			return
+		}
+		inputURLs[i] = service.ShortenRequest{OriginalURL: req.OriginalURL, Domain: domain, Alias: req.Alias, ExpiresAt: expiresAt, MaxUses: req.MaxUses, Password: req.Password, Tags: req.Tags, Title: req.Title, RedirectCode: req.RedirectCode, PassQuery: req.PassQuery, PassPath: req.PassPath, UTMTemplate: req.UTMTemplate}
+		if req.UTM != nil {
+			inputURLs[i].UTM = req.UTM.toService()
+		}
 	}
 
-	// Функция ShortenURLs возвращает map[shortURL]originalURL
+	// Функция ShortenURLs возвращает короткие коды в порядке запросов
 	shortenedURLs, err := h.Service.ShortenURLs(c.Request.Context(), inputURLs, userIDStr)
+	if status, ok := shortenErrorStatus(err); ok {
+		c.AbortWithStatusJSON(status, shortenErrorBody(err))
+		return
+	}
 	if err != nil {
 		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Failed to shorten URLs"})
 		return
 	}
 
-	// Преобразуем в map[originalURL]shortURL
-	shortenedURLsMap := make(map[string]string, len(shortenedURLs))
-	for short, orig := range shortenedURLs {
-		shortenedURLsMap[orig] = short
+	if len(shortenedURLs) != len(requestURLs) {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Mismatch in shortened URLs"})
+		return
 	}
 
 	responseURLs := make([]URLResponse, len(requestURLs))
 	for i, req := range requestURLs {
-		shortURL, ok := shortenedURLsMap[req.OriginalURL]
-		if !ok {
-			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Mismatch in shortened URLs"})
-			return
-		}
 		responseURLs[i] = URLResponse{
 			CorrelationID: req.CorrelationID,
-			ShortURL:      h.cfg.BaseAddress + "/" + shortURL,
+			ShortURL:      linkURL(h.cfg, shortenedURLs[i]),
 		}
 	}
 
 	c.Header("Content-Type", "application/json")
 	c.JSON(http.StatusCreated, responseURLs)
 }
+
+// shortenErrorStatus возвращает HTTP-статус для ошибок проверки параметров создаваемой ссылки.
+// Второе значение равно false, если err не относится к таким ошибкам.
+func shortenErrorStatus(err error) (int, bool) {
+	switch {
+	case errors.Is(err, service.ErrInvalidAlias), errors.Is(err, service.ErrReservedAlias),
+		errors.Is(err, service.ErrInvalidExpiry), errors.Is(err, service.ErrInvalidMaxUses),
+		errors.Is(err, service.ErrPasswordTooLong), errors.Is(err, service.ErrDestinationRejected),
+		errors.Is(err, service.ErrInvalidTag), errors.Is(err, service.ErrTooManyTags),
+		errors.Is(err, service.ErrTitleTooLong), errors.Is(err, service.ErrInvalidRedirectCode),
+		errors.Is(err, service.ErrInvalidUTM), errors.Is(err, service.ErrUTMTemplateNotFound),
+		errors.Is(err, service.ErrUnknownDomain):
+		return http.StatusBadRequest, true
+	case errors.Is(err, service.ErrShortURLTaken):
+		return http.StatusConflict, true
+	}
+	return 0, false
+}
+
+// shortenErrorBody возвращает тело ответа с ошибкой. Для URL, отклонённых политикой назначения,
+// добавляет машиночитаемую причину в поле reason.
+func shortenErrorBody(err error) gin.H {
+	body := gin.H{"error": err.Error()}
+	var policyErr *service.PolicyError
+	if errors.As(err, &policyErr) {
+		body["reason"] = policyErr.Reason
+	}
+	return body
+}
+
+// queryTags возвращает теги из параметра запроса tags, перечисленные через запятую.
+func queryTags(c *gin.Context) []string {
+	raw := c.Query("tags")
+	if raw == "" {
+		return nil
+	}
+	var tags []string
+	for _, tag := range strings.Split(raw, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// queryFlag разбирает логический query-параметр name; отсутствующий параметр означает false.
+func queryFlag(c *gin.Context, name string) (bool, error) {
+	raw := c.Query(name)
+	if raw == "" {
+		return false, nil
+	}
+	flag, err := strconv.ParseBool(raw)
+	if err != nil {
+		return false, fmt.Errorf("%s must be true or false", name)
+	}
+	return flag, nil
+}
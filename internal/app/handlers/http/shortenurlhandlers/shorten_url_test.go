@@ -20,31 +20,85 @@ import (
 // === mockService for Shorten, GetURL, etc. ===
 type mockService struct{}
 
-func (m *mockService) ShortenURL(_ context.Context, url string, _ string) (string, error) {
-	if url == "http://example.com" {
+func (m *mockService) ShortenURL(_ context.Context, req service.ShortenRequest, _ string) (string, error) {
+	if req.MaxUses < 0 {
+		return "", service.ErrInvalidMaxUses
+	}
+	if req.UTMTemplate == "missing" {
+		return "", service.ErrUTMTemplateNotFound
+	}
+	if req.UTM.Source == "too-long" {
+		return "", service.ErrInvalidUTM
+	}
+	switch req.Alias {
+	case "":
+	case "taken":
+		return "", service.ErrShortURLTaken
+	case "api":
+		return "", service.ErrReservedAlias
+	default:
+		return service.LinkKey(req.Domain, req.Alias), nil
+	}
+	if req.OriginalURL == "http://localhost:8080/abc" {
+		return "", &service.PolicyError{Reason: service.ReasonSelfReference, URL: req.OriginalURL}
+	}
+	if req.OriginalURL == "http://example.com" {
 		return "abcdef", nil
 	}
 	return "", errors.New("invalid URL format")
 }
-func (m *mockService) ShortenURLs(_ context.Context, inputs []string, _ string) (map[string]string, error) {
-	shortened := make(map[string]string, len(inputs))
+func (m *mockService) ShortenURLs(_ context.Context, inputs []service.ShortenRequest, _ string) ([]string, error) {
+	shortened := make([]string, 0, len(inputs))
 	for _, input := range inputs {
-		if input == "http://example.com" {
-			shortened["abcdef"] = input
+		if input.OriginalURL == "http://example.com" {
+			shortened = append(shortened, "abcdef")
 		} else {
 			return nil, errors.New("invalid URL format")
 		}
 	}
 	return shortened, nil
 }
-func (m *mockService) GetOriginalURL(_ context.Context, input string) (string, error) {
-	if input == "abcdef" {
-		return "http://example.com", nil
+func (m *mockService) GetOriginalURL(_ context.Context, input string, visit service.Visit) (service.Redirect, error) {
+	switch input {
+	case "abcdef":
+		return service.Redirect{URL: "http://example.com", Code: http.StatusTemporaryRedirect}, nil
+	case "go.brand.com/abcdef":
+		return service.Redirect{URL: "http://example.com/brand", Code: http.StatusTemporaryRedirect}, nil
+	case "moved":
+		// Ссылка с кодом 301, переносящая путь и строку запроса.
+		return service.Redirect{URL: "http://example.com" + visit.Path + "?" + visit.Query, Code: http.StatusMovedPermanently}, nil
+	case "expired":
+		return service.Redirect{}, service.ErrURLExpired
+	case "exhausted":
+		return service.Redirect{}, service.ErrURLExhausted
+	case "protected":
+		return service.Redirect{}, service.ErrPasswordRequired
+	case "split":
+		// Ссылка с A/B-сплитом: новому посетителю выдаётся вариант b.
+		variant := visit.Variant
+		if variant == "" {
+			variant = "b"
+		}
+		return service.Redirect{URL: "http://example.com/" + variant, Code: http.StatusTemporaryRedirect, Variant: variant}, nil
+	}
+	return service.Redirect{}, service.ErrURLNotFound
+}
+
+func (m *mockService) UnlockURL(_ context.Context, input, password string, visit service.Visit) (service.Redirect, error) {
+	switch {
+	case input != "protected":
+		return service.Redirect{}, service.ErrURLNotFound
+	case password == "":
+		return service.Redirect{}, service.ErrPasswordRequired
+	case password == "locked":
+		return service.Redirect{}, service.ErrTooManyAttempts
+	case password != "secret":
+		return service.Redirect{}, service.ErrInvalidPassword
 	}
-	return "", nil
+	return service.Redirect{URL: "http://example.com" + visit.Path, Code: http.StatusTemporaryRedirect}, nil
 }
-func (m *mockService) GetUserURLs(_ context.Context, _ string) ([]service.URLDTO, error) {
-	return nil, nil
+func (m *mockService) GetUserURLs(_ context.Context, _ string, _ service.URLFilter) (service.URLPage, error) {
+	return service.URLPage{}, nil
 }
 func (m *mockService) GetStats(_ context.Context) (service.StatsDTO, error) {
 	return service.StatsDTO{}, nil
@@ -52,6 +106,12 @@ func (m *mockService) GetStats(_ context.Context) (service.StatsDTO, error) {
 func (m *mockService) DeleteURLs(_ context.Context, _ []string, _ string) error {
 	return nil
 }
+func (m *mockService) RestoreURLs(_ context.Context, shortURLs []string, userID string) ([]string, error) {
+	if userID != "user123" {
+		return []string{}, nil
+	}
+	return shortURLs[:1], nil
+}
 
 func newTestHandlerShorten() *ShortenHandler {
 	cfg := &config.ConfigType{BaseAddress: "http://localhost:8080"}
@@ -64,7 +124,20 @@ func newTestHandlerGetter() *GetURLHandler {
 	cfg := &config.ConfigType{BaseAddress: "http://localhost:8080"}
 	logger, _ := zap.NewDevelopment()
 	sugar := logger.Sugar()
-	return NewGetURLHandler(cfg, &mockService{}, sugar)
+	return NewGetURLHandler(cfg, &mockService{}, nil, sugar)
+}
+
+// newTestRouter создаёт роутер, в котором запросы выполняются от имени userID
+// (пустой userID — без пользователя), и регистрирует в нём маршруты через register.
+func newTestRouter(userID string, register func(r *gin.Engine)) *gin.Engine {
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		if userID != "" {
+			c.Set("userID", userID)
+		}
+	})
+	register(router)
+	return router
 }
 
 // === Tests for ShortenHandler and GetURLHandler ===
@@ -98,6 +171,143 @@ func TestGetURL(t *testing.T) {
 	assert.Equal(t, "http://example.com", res.Header.Get("Location"))
 }
 
+func TestGetURL_RedirectCodeAndVisit(t *testing.T) {
+	handler := newTestHandlerGetter()
+	router := gin.New()
+	router.GET("/:url", handler.GetURL)
+	router.GET("/:url/*path", handler.GetURL)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/moved/docs/intro?utm_source=qr", nil))
+	assert.Equal(t, http.StatusMovedPermanently, w.Code)
+	assert.Equal(t, "http://example.com/docs/intro?utm_source=qr", w.Header().Get("Location"))
+}
+
+func TestGetURL_StickyVariant(t *testing.T) {
+	handler := newTestHandlerGetter()
+	router := gin.New()
+	router.GET("/:url", handler.GetURL)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/split", nil))
+	assert.Equal(t, "http://example.com/b", w.Header().Get("Location"))
+	cookies := w.Result().Cookies()
+	require.Len(t, cookies, 1)
+	assert.Equal(t, "split_split", cookies[0].Name)
+	assert.Equal(t, "b", cookies[0].Value)
+	assert.Equal(t, "/split", cookies[0].Path)
+
+	w = httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/split", nil)
+	r.AddCookie(&http.Cookie{Name: "split_split", Value: "a"})
+	router.ServeHTTP(w, r)
+	assert.Equal(t, "http://example.com/a", w.Header().Get("Location"))
+}
+
+func TestGetURL_Expired(t *testing.T) {
+	handler := newTestHandlerGetter()
+	router := gin.New()
+	router.GET("/:url", handler.GetURL)
+
+	for _, key := range []string{"expired", "exhausted"} {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/"+key, nil)
+		router.ServeHTTP(w, r)
+
+		assert.Equal(t, http.StatusGone, w.Code, key)
+		assert.Empty(t, w.Header().Get("Location"), key)
+	}
+}
+
+func TestGetURL_PasswordProtected(t *testing.T) {
+	handler := newTestHandlerGetter()
+	router := gin.New()
+	router.GET("/:url", handler.GetURL)
+	router.POST("/:url", handler.UnlockURL)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/protected", nil))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), "text/html")
+	assert.Contains(t, w.Body.String(), `action="/protected"`)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/protected?ref=mail", nil))
+	assert.Contains(t, w.Body.String(), `action="/protected?ref=mail"`)
+
+	tests := []struct {
+		password   string
+		wantStatus int
+	}{
+		{password: "wrong", wantStatus: http.StatusUnauthorized},
+		{password: "locked", wantStatus: http.StatusTooManyRequests},
+		{password: "secret", wantStatus: http.StatusSeeOther},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodPost, "/protected", strings.NewReader("password="+tt.password))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		router.ServeHTTP(w, r)
+
+		assert.Equal(t, tt.wantStatus, w.Code, tt.password)
+		if tt.wantStatus == http.StatusSeeOther {
+			assert.Equal(t, "http://example.com", w.Header().Get("Location"))
+		}
+	}
+}
+
+func TestURLCreatorJSON_InvalidExpiry(t *testing.T) {
+	handler := newTestHandlerShorten()
+	router := gin.New()
+	router.POST("/api/shorten", handler.URLCreatorJSON)
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/api/shorten", strings.NewReader(`{"url": "http://example.com", "ttl": -5}`))
+	router.ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestURLCreatorJSON_UTM(t *testing.T) {
+	handler := newTestHandlerShorten()
+	router := gin.New()
+	router.POST("/api/shorten", handler.URLCreatorJSON)
+
+	for _, body := range []string{
+		`{"url": "http://example.com", "utm_template": "missing"}`,
+		`{"url": "http://example.com", "utm": {"source": "too-long"}}`,
+	} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/shorten", strings.NewReader(body)))
+		assert.Equal(t, http.StatusBadRequest, w.Code, body)
+	}
+}
+
+func TestURLCreatorJSON_InvalidMaxUses(t *testing.T) {
+	handler := newTestHandlerShorten()
+	router := gin.New()
+	router.POST("/api/shorten", handler.URLCreatorJSON)
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/api/shorten", strings.NewReader(`{"url": "http://example.com", "max_uses": -1}`))
+	router.ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestURLCreatorJSON_DestinationRejected(t *testing.T) {
+	handler := newTestHandlerShorten()
+	router := gin.New()
+	router.POST("/api/shorten", handler.URLCreatorJSON)
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/api/shorten", strings.NewReader(`{"url": "http://localhost:8080/abc"}`))
+	router.ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `"reason":"self_reference"`)
+}
+
 func TestURLCreatorJSON(t *testing.T) {
 	handler := newTestHandlerShorten()
 	router := gin.New()
@@ -117,6 +327,50 @@ func TestURLCreatorJSON(t *testing.T) {
 	assert.JSONEq(t, `{"result":"http://localhost:8080/abcdef"}`, string(bodyBytes))
 }
 
+func TestURLCreatorJSON_Alias(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "custom alias",
+			body:       `{"url": "http://example.com", "alias": "spring-sale"}`,
+			wantStatus: http.StatusCreated,
+			wantBody:   `{"result":"http://localhost:8080/spring-sale"}`,
+		},
+		{
+			name:       "alias taken",
+			body:       `{"url": "http://example.com", "alias": "taken"}`,
+			wantStatus: http.StatusConflict,
+			wantBody:   `{"error":"short URL is already taken"}`,
+		},
+		{
+			name:       "reserved alias",
+			body:       `{"url": "http://example.com", "alias": "api"}`,
+			wantStatus: http.StatusBadRequest,
+			wantBody:   `{"error":"alias is reserved"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := newTestHandlerShorten()
+			router := gin.New()
+			router.POST("/api/shorten", handler.URLCreatorJSON)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, "/api/shorten", strings.NewReader(tt.body))
+			r.Header.Set("Content-Type", "application/json")
+			router.ServeHTTP(w, r)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			assert.JSONEq(t, tt.wantBody, w.Body.String())
+		})
+	}
+}
+
 func TestURLCreatorBatch(t *testing.T) {
 	handler := newTestHandlerShorten()
 	router := gin.New()
@@ -139,15 +393,21 @@ func TestURLCreatorBatch(t *testing.T) {
 
 // === Tests for GetUserURLs ===
 type stubGetter struct {
-	records []service.URLDTO
-	err     error
+	records   []service.URLDTO
+	next      string
+	err       error
+	gotFilter service.URLFilter
 }
 
-func (s *stubGetter) GetOriginalURL(_ context.Context, _ string) (string, error) {
-	return "", nil
+func (s *stubGetter) GetOriginalURL(_ context.Context, _ string, _ service.Visit) (service.Redirect, error) {
+	return service.Redirect{}, nil
 }
-func (s *stubGetter) GetUserURLs(_ context.Context, _ string) ([]service.URLDTO, error) {
-	return s.records, s.err
+func (s *stubGetter) UnlockURL(_ context.Context, _, _ string, _ service.Visit) (service.Redirect, error) {
+	return service.Redirect{}, nil
+}
+func (s *stubGetter) GetUserURLs(_ context.Context, _ string, filter service.URLFilter) (service.URLPage, error) {
+	s.gotFilter = filter
+	return service.URLPage{URLs: s.records, NextPageToken: s.next}, s.err
 }
 func (s *stubGetter) GetStats(_ context.Context) (service.StatsDTO, error) {
 	return service.StatsDTO{}, s.err
@@ -156,7 +416,7 @@ func (s *stubGetter) GetStats(_ context.Context) (service.StatsDTO, error) {
 func newTestHandlerGetUserURLs(records []service.URLDTO, err error) *GetURLHandler {
 	cfg := &config.ConfigType{BaseAddress: "http://localhost:8080"}
 	logger := zap.NewNop().Sugar()
-	return NewGetURLHandler(cfg, &stubGetter{records: records, err: err}, logger)
+	return NewGetURLHandler(cfg, &stubGetter{records: records, err: err}, nil, logger)
 }
 
 func TestGetUserURLs_Unauthorized_NoUserID(t *testing.T) {
@@ -208,7 +468,7 @@ func TestGetUserURLs_Success(t *testing.T) {
 	c.Set("userID", "alice")
 
 	records := []service.URLDTO{
-		{ShortURL: "abc", OriginalURL: "https://go.dev"},
+		{ShortURL: "abc", OriginalURL: "https://go.dev", Clicks: 3},
 		{ShortURL: "xyz", OriginalURL: "https://gin-gonic.com"},
 	}
 	handler := newTestHandlerGetUserURLs(records, nil)
@@ -216,8 +476,57 @@ func TestGetUserURLs_Success(t *testing.T) {
 
 	assert.Equal(t, http.StatusOK, w.Code)
 	expected := `[
-		{"short_url":"http://localhost:8080/abc","original_url":"https://go.dev"},
-		{"short_url":"http://localhost:8080/xyz","original_url":"https://gin-gonic.com"}
+		{"short_url":"http://localhost:8080/abc","original_url":"https://go.dev","clicks":3},
+		{"short_url":"http://localhost:8080/xyz","original_url":"https://gin-gonic.com","clicks":0}
 	]`
 	assert.JSONEq(t, expected, w.Body.String())
 }
+
+func TestGetUserURLs_Pagination(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet,
+		"/urls?limit=1&page_token=tok&sort=destination&order=desc&q=go&url=https://go.dev&tags=a,b", nil)
+	c.Set("userID", "alice")
+
+	getter := &stubGetter{records: []service.URLDTO{{ShortURL: "abc", OriginalURL: "https://go.dev"}}, next: "next-tok"}
+	handler := NewGetURLHandler(&config.ConfigType{BaseAddress: "http://localhost:8080"}, getter, nil, zap.NewNop().Sugar())
+	handler.GetUserURLs(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "next-tok", w.Header().Get("X-Next-Page-Token"))
+	assert.Equal(t, service.URLFilter{
+		Tags:        []string{"a", "b"},
+		Search:      "go",
+		Destination: "https://go.dev",
+		Sort:        "destination",
+		Order:       "desc",
+		Limit:       1,
+		PageToken:   "tok",
+	}, getter.gotFilter)
+}
+
+func TestGetUserURLs_InvalidQuery(t *testing.T) {
+	for _, tt := range []struct {
+		name  string
+		query string
+		err   error
+	}{
+		{name: "non-numeric limit", query: "?limit=ten"},
+		{name: "bad sort", query: "?sort=clicks", err: service.ErrInvalidSort},
+		{name: "bad token", query: "?page_token=x", err: service.ErrInvalidPageToken},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/urls"+tt.query, nil)
+			c.Set("userID", "alice")
+
+			newTestHandlerGetUserURLs(nil, tt.err).GetUserURLs(c)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package shortenurlhandlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/aseptimu/url-shortener/internal/app/config"
+	"github.com/aseptimu/url-shortener/internal/app/service"
+	"github.com/aseptimu/url-shortener/internal/app/utils"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// SplitHandler позволяет владельцу настраивать A/B-сплит своих ссылок.
+type SplitHandler struct {
+	cfg     *config.ConfigType
+	service service.URLSplitter
+	logger  *zap.SugaredLogger
+}
+
+// NewSplitHandler создаёт новый экземпляр SplitHandler.
+func NewSplitHandler(cfg *config.ConfigType, service service.URLSplitter, logger *zap.SugaredLogger) *SplitHandler {
+	return &SplitHandler{cfg: cfg, service: service, logger: logger}
+}
+
+type splitVariant struct {
+	Name   string `json:"name"`
+	URL    string `json:"url"`
+	Weight int    `json:"weight"`
+}
+
+type urlVariantsResponse struct {
+	ShortURL string         `json:"short_url"`
+	Variants []splitVariant `json:"variants"`
+}
+
+// GetVariants обрабатывает GET /api/user/urls/{short}/variants
+// и возвращает варианты A/B-сплита ссылки с их весами.
+func (h *SplitHandler) GetVariants(c *gin.Context) {
+	utils.LogRequest(c, h.logger)
+
+	userID, ok := h.userID(c)
+	if !ok {
+		return
+	}
+
+	key := ownedKey(c, h.cfg)
+	variants, err := h.service.GetVariants(c.Request.Context(), key, userID)
+	if err != nil {
+		h.abortWithError(c, key, err)
+		return
+	}
+	c.JSON(http.StatusOK, h.response(key, variants))
+}
+
+// SetVariants обрабатывает PUT /api/user/urls/{short}/variants.
+// Принимает JSON-массив вариантов вида {"name": "a", "url": "...", "weight": 70}
+// и заменяет ими все варианты ссылки; короткий код при этом не меняется.
+// Пустой массив отключает сплит.
+func (h *SplitHandler) SetVariants(c *gin.Context) {
+	utils.LogRequest(c, h.logger)
+
+	userID, ok := h.userID(c)
+	if !ok {
+		return
+	}
+
+	var req []splitVariant
+	if err := json.NewDecoder(c.Request.Body).Decode(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON format"})
+		return
+	}
+	variants := make([]service.SplitVariant, len(req))
+	for i, v := range req {
+		variants[i] = service.SplitVariant{Name: v.Name, URL: v.URL, Weight: v.Weight}
+	}
+
+	key := ownedKey(c, h.cfg)
+	saved, err := h.service.SetVariants(c.Request.Context(), key, userID, variants)
+	if err != nil {
+		h.abortWithError(c, key, err)
+		return
+	}
+	c.JSON(http.StatusOK, h.response(key, saved))
+}
+
+func (h *SplitHandler) response(key string, variants []service.SplitVariant) urlVariantsResponse {
+	resp := urlVariantsResponse{ShortURL: linkURL(h.cfg, key), Variants: make([]splitVariant, len(variants))}
+	for i, v := range variants {
+		resp.Variants[i] = splitVariant{Name: v.Name, URL: v.URL, Weight: v.Weight}
+	}
+	return resp
+}
+
+func (h *SplitHandler) userID(c *gin.Context) (string, bool) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return "", false
+	}
+	userIDStr, ok := userID.(string)
+	if !ok || userIDStr == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return "", false
+	}
+	return userIDStr, true
+}
+
+func (h *SplitHandler) abortWithError(c *gin.Context, key string, err error) {
+	switch {
+	case errors.Is(err, service.ErrURLNotFound):
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrInvalidSplit), errors.Is(err, service.ErrInvalidURL),
+		errors.Is(err, service.ErrDestinationRejected):
+		c.AbortWithStatusJSON(http.StatusBadRequest, shortenErrorBody(err))
+	default:
+		h.logger.Errorw("Failed to update split variants", "shortURL", key, "error", err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
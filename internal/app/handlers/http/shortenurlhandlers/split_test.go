@@ -0,0 +1,80 @@
+package shortenurlhandlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aseptimu/url-shortener/internal/app/config"
+	"github.com/aseptimu/url-shortener/internal/app/service"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+// mockSplitter хранит варианты ссылки "abcdef" пользователя "owner".
+type mockSplitter struct{}
+
+func (m *mockSplitter) GetVariants(_ context.Context, shortURL, userID string) ([]service.SplitVariant, error) {
+	if shortURL != "abcdef" || userID != "owner" {
+		return nil, service.ErrURLNotFound
+	}
+	return []service.SplitVariant{{Name: "a", URL: "https://example.com/a", Weight: 70}, {Name: "b", URL: "https://example.com/b", Weight: 30}}, nil
+}
+
+func (m *mockSplitter) SetVariants(_ context.Context, shortURL, userID string, variants []service.SplitVariant) ([]service.SplitVariant, error) {
+	if shortURL != "abcdef" || userID != "owner" {
+		return nil, service.ErrURLNotFound
+	}
+	for _, v := range variants {
+		if v.Weight < 0 {
+			return nil, service.ErrInvalidSplit
+		}
+	}
+	return variants, nil
+}
+
+func newTestSplitRouter(userID string) *gin.Engine {
+	cfg := &config.ConfigType{BaseAddress: "http://localhost:8080"}
+	handler := NewSplitHandler(cfg, &mockSplitter{}, zap.NewNop().Sugar())
+	return newTestRouter(userID, func(r *gin.Engine) {
+		r.GET("/api/user/urls/:short/variants", handler.GetVariants)
+		r.PUT("/api/user/urls/:short/variants", handler.SetVariants)
+	})
+}
+
+func TestSplitHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		userID     string
+		method     string
+		body       string
+		wantStatus int
+		wantBody   string
+	}{
+		{name: "get", userID: "owner", method: http.MethodGet, wantStatus: http.StatusOK,
+			wantBody: `{"short_url":"http://localhost:8080/abcdef","variants":[{"name":"a","url":"https://example.com/a","weight":70},{"name":"b","url":"https://example.com/b","weight":30}]}`},
+		{name: "set", userID: "owner", method: http.MethodPut, body: `[{"name":"new","url":"https://example.com/new","weight":1}]`, wantStatus: http.StatusOK,
+			wantBody: `{"short_url":"http://localhost:8080/abcdef","variants":[{"name":"new","url":"https://example.com/new","weight":1}]}`},
+		{name: "clear", userID: "owner", method: http.MethodPut, body: `[]`, wantStatus: http.StatusOK,
+			wantBody: `{"short_url":"http://localhost:8080/abcdef","variants":[]}`},
+		{name: "invalid split", userID: "owner", method: http.MethodPut, body: `[{"name":"a","url":"https://example.com","weight":-1}]`, wantStatus: http.StatusBadRequest},
+		{name: "invalid JSON", userID: "owner", method: http.MethodPut, body: `{`, wantStatus: http.StatusBadRequest},
+		{name: "foreign link", userID: "intruder", method: http.MethodGet, wantStatus: http.StatusNotFound},
+		{name: "unauthorized", method: http.MethodGet, wantStatus: http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(tt.method, "/api/user/urls/abcdef/variants", strings.NewReader(tt.body))
+			newTestSplitRouter(tt.userID).ServeHTTP(w, r)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantBody != "" {
+				assert.JSONEq(t, tt.wantBody, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package shortenurlhandlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/aseptimu/url-shortener/internal/app/config"
+	"github.com/aseptimu/url-shortener/internal/app/service"
+	"github.com/aseptimu/url-shortener/internal/app/utils"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// TagHandler позволяет владельцу управлять тегами своих ссылок.
+type TagHandler struct {
+	cfg     *config.ConfigType
+	service service.URLTagger
+	logger  *zap.SugaredLogger
+}
+
+// NewTagHandler создаёт новый экземпляр TagHandler.
+func NewTagHandler(cfg *config.ConfigType, service service.URLTagger, logger *zap.SugaredLogger) *TagHandler {
+	return &TagHandler{cfg: cfg, service: service, logger: logger}
+}
+
+type tagCount struct {
+	Tag   string `json:"tag"`
+	Count int    `json:"count"`
+}
+
+type urlTagsResponse struct {
+	ShortURL string   `json:"short_url"`
+	Tags     []string `json:"tags"`
+}
+
+// ListTags обрабатывает GET /api/user/tags
+// и возвращает все теги пользователя с числом ссылок для каждого.
+func (h *TagHandler) ListTags(c *gin.Context) {
+	utils.LogRequest(c, h.logger)
+
+	userID, ok := h.userID(c)
+	if !ok {
+		return
+	}
+
+	tags, err := h.service.ListTags(c.Request.Context(), userID)
+	if err != nil {
+		h.logger.Errorw("Failed to list tags", "userID", userID, "error", err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp := make([]tagCount, len(tags))
+	for i, tag := range tags {
+		resp[i] = tagCount{Tag: tag.Tag, Count: tag.Count}
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// SetTags обрабатывает PUT /api/user/urls/{short}/tags.
+// Принимает JSON-массив тегов и заменяет ими все теги ссылки.
+func (h *TagHandler) SetTags(c *gin.Context) {
+	h.updateTags(c, h.service.SetTags)
+}
+
+// AddTags обрабатывает POST /api/user/urls/{short}/tags.
+// Принимает JSON-массив тегов и добавляет их к уже назначенным.
+func (h *TagHandler) AddTags(c *gin.Context) {
+	h.updateTags(c, h.service.AddTags)
+}
+
+// RemoveTag обрабатывает DELETE /api/user/urls/{short}/tags/{tag} и снимает тег со ссылки.
+func (h *TagHandler) RemoveTag(c *gin.Context) {
+	utils.LogRequest(c, h.logger)
+
+	userID, ok := h.userID(c)
+	if !ok {
+		return
+	}
+
+	key := ownedKey(c, h.cfg)
+	tags, err := h.service.RemoveTags(c.Request.Context(), key, userID, []string{c.Param("tag")})
+	if err != nil {
+		h.abortWithError(c, key, err)
+		return
+	}
+	c.JSON(http.StatusOK, urlTagsResponse{ShortURL: linkURL(h.cfg, key), Tags: tags})
+}
+
+type tagUpdater func(ctx context.Context, shortURL, userID string, tags []string) ([]string, error)
+
+func (h *TagHandler) updateTags(c *gin.Context, update tagUpdater) {
+	utils.LogRequest(c, h.logger)
+
+	userID, ok := h.userID(c)
+	if !ok {
+		return
+	}
+
+	var req []string
+	if err := json.NewDecoder(c.Request.Body).Decode(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON format"})
+		return
+	}
+
+	key := ownedKey(c, h.cfg)
+	tags, err := update(c.Request.Context(), key, userID, req)
+	if err != nil {
+		h.abortWithError(c, key, err)
+		return
+	}
+	c.JSON(http.StatusOK, urlTagsResponse{ShortURL: linkURL(h.cfg, key), Tags: tags})
+}
+
+func (h *TagHandler) userID(c *gin.Context) (string, bool) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return "", false
+	}
+	userIDStr, ok := userID.(string)
+	if !ok || userIDStr == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return "", false
+	}
+	return userIDStr, true
+}
+
+func (h *TagHandler) abortWithError(c *gin.Context, key string, err error) {
+	switch {
+	case errors.Is(err, service.ErrURLNotFound):
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrInvalidTag), errors.Is(err, service.ErrTooManyTags):
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		h.logger.Errorw("Failed to update tags", "shortURL", key, "error", err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,90 @@
+package shortenurlhandlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aseptimu/url-shortener/internal/app/config"
+	"github.com/aseptimu/url-shortener/internal/app/service"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+// mockTagger хранит ссылку "abcdef" пользователя "owner" с тегом "news".
+type mockTagger struct{}
+
+func (m *mockTagger) update(shortURL, userID string, tags []string) ([]string, error) {
+	if shortURL != "abcdef" || userID != "owner" {
+		return nil, service.ErrURLNotFound
+	}
+	for _, tag := range tags {
+		if strings.Contains(tag, " ") {
+			return nil, service.ErrInvalidTag
+		}
+	}
+	return tags, nil
+}
+
+func (m *mockTagger) SetTags(_ context.Context, shortURL, userID string, tags []string) ([]string, error) {
+	return m.update(shortURL, userID, tags)
+}
+
+func (m *mockTagger) AddTags(_ context.Context, shortURL, userID string, tags []string) ([]string, error) {
+	return m.update(shortURL, userID, append([]string{"news"}, tags...))
+}
+
+func (m *mockTagger) RemoveTags(_ context.Context, shortURL, userID string, _ []string) ([]string, error) {
+	return m.update(shortURL, userID, []string{})
+}
+
+func (m *mockTagger) ListTags(_ context.Context, _ string) ([]service.TagCount, error) {
+	return []service.TagCount{{Tag: "news", Count: 2}}, nil
+}
+
+func newTestTagRouter(userID string) *gin.Engine {
+	cfg := &config.ConfigType{BaseAddress: "http://localhost:8080"}
+	handler := NewTagHandler(cfg, &mockTagger{}, zap.NewNop().Sugar())
+	return newTestRouter(userID, func(r *gin.Engine) {
+		r.GET("/api/user/tags", handler.ListTags)
+		r.PUT("/api/user/urls/:short/tags", handler.SetTags)
+		r.POST("/api/user/urls/:short/tags", handler.AddTags)
+		r.DELETE("/api/user/urls/:short/tags/:tag", handler.RemoveTag)
+	})
+}
+
+func TestTagHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		userID     string
+		method     string
+		path       string
+		body       string
+		wantStatus int
+		wantBody   string
+	}{
+		{name: "list", userID: "owner", method: http.MethodGet, path: "/api/user/tags", wantStatus: http.StatusOK, wantBody: `[{"tag":"news","count":2}]`},
+		{name: "set", userID: "owner", method: http.MethodPut, path: "/api/user/urls/abcdef/tags", body: `["work"]`, wantStatus: http.StatusOK, wantBody: `{"short_url":"http://localhost:8080/abcdef","tags":["work"]}`},
+		{name: "add", userID: "owner", method: http.MethodPost, path: "/api/user/urls/abcdef/tags", body: `["work"]`, wantStatus: http.StatusOK, wantBody: `{"short_url":"http://localhost:8080/abcdef","tags":["news","work"]}`},
+		{name: "remove", userID: "owner", method: http.MethodDelete, path: "/api/user/urls/abcdef/tags/news", wantStatus: http.StatusOK, wantBody: `{"short_url":"http://localhost:8080/abcdef","tags":[]}`},
+		{name: "invalid tag", userID: "owner", method: http.MethodPut, path: "/api/user/urls/abcdef/tags", body: `["two words"]`, wantStatus: http.StatusBadRequest},
+		{name: "invalid JSON", userID: "owner", method: http.MethodPut, path: "/api/user/urls/abcdef/tags", body: `{`, wantStatus: http.StatusBadRequest},
+		{name: "foreign link", userID: "intruder", method: http.MethodPost, path: "/api/user/urls/abcdef/tags", body: `["work"]`, wantStatus: http.StatusNotFound},
+		{name: "unauthorized", method: http.MethodGet, path: "/api/user/tags", wantStatus: http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			newTestTagRouter(tt.userID).ServeHTTP(w, r)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantBody != "" {
+				assert.JSONEq(t, tt.wantBody, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package shortenurlhandlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/aseptimu/url-shortener/internal/app/config"
+	"github.com/aseptimu/url-shortener/internal/app/service"
+	"github.com/aseptimu/url-shortener/internal/app/utils"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// utmParams — UTM-параметры в JSON-запросах и ответах.
+type utmParams struct {
+	Source   string `json:"source,omitempty"`
+	Medium   string `json:"medium,omitempty"`
+	Campaign string `json:"campaign,omitempty"`
+	Term     string `json:"term,omitempty"`
+	Content  string `json:"content,omitempty"`
+}
+
+func (p utmParams) toService() service.UTM {
+	return service.UTM{Source: p.Source, Medium: p.Medium, Campaign: p.Campaign, Term: p.Term, Content: p.Content}
+}
+
+func newUTMParams(u service.UTM) utmParams {
+	return utmParams{Source: u.Source, Medium: u.Medium, Campaign: u.Campaign, Term: u.Term, Content: u.Content}
+}
+
+// queryUTM возвращает UTM-параметры из query-параметров utm_source, utm_medium,
+// utm_campaign, utm_term и utm_content.
+func queryUTM(c *gin.Context) service.UTM {
+	return service.UTM{
+		Source:   c.Query("utm_source"),
+		Medium:   c.Query("utm_medium"),
+		Campaign: c.Query("utm_campaign"),
+		Term:     c.Query("utm_term"),
+		Content:  c.Query("utm_content"),
+	}
+}
+
+// UTMTemplateHandler позволяет пользователю управлять своими UTM-шаблонами.
+type UTMTemplateHandler struct {
+	cfg     *config.ConfigType
+	service service.UTMTemplater
+	logger  *zap.SugaredLogger
+}
+
+// NewUTMTemplateHandler создаёт новый экземпляр UTMTemplateHandler.
+func NewUTMTemplateHandler(cfg *config.ConfigType, service service.UTMTemplater, logger *zap.SugaredLogger) *UTMTemplateHandler {
+	return &UTMTemplateHandler{cfg: cfg, service: service, logger: logger}
+}
+
+type utmTemplate struct {
+	Name string `json:"name"`
+	utmParams
+}
+
+// ListTemplates обрабатывает GET /api/user/utm-templates
+// и возвращает UTM-шаблоны пользователя, отсортированные по имени.
+func (h *UTMTemplateHandler) ListTemplates(c *gin.Context) {
+	utils.LogRequest(c, h.logger)
+
+	userID, ok := h.userID(c)
+	if !ok {
+		return
+	}
+
+	templates, err := h.service.ListTemplates(c.Request.Context(), userID)
+	if err != nil {
+		h.logger.Errorw("Failed to list UTM templates", "userID", userID, "error", err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp := make([]utmTemplate, len(templates))
+	for i, t := range templates {
+		resp[i] = utmTemplate{Name: t.Name, utmParams: newUTMParams(t.UTM)}
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// SaveTemplate обрабатывает PUT /api/user/utm-templates/{name}.
+// Принимает JSON {"source": "...", "medium": "...", "campaign": "...", "term": "...", "content": "..."}
+// и создаёт шаблон или заменяет шаблон с тем же именем. Ссылки, созданные раньше, не меняются.
+func (h *UTMTemplateHandler) SaveTemplate(c *gin.Context) {
+	utils.LogRequest(c, h.logger)
+
+	userID, ok := h.userID(c)
+	if !ok {
+		return
+	}
+
+	var req utmParams
+	if err := json.NewDecoder(c.Request.Body).Decode(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON format"})
+		return
+	}
+
+	saved, err := h.service.SaveTemplate(c.Request.Context(), userID, service.UTMTemplate{Name: c.Param("name"), UTM: req.toService()})
+	if errors.Is(err, service.ErrInvalidUTM) {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		h.logger.Errorw("Failed to save UTM template", "userID", userID, "error", err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, utmTemplate{Name: saved.Name, utmParams: newUTMParams(saved.UTM)})
+}
+
+// DeleteTemplate обрабатывает DELETE /api/user/utm-templates/{name} и возвращает 204 No Content.
+func (h *UTMTemplateHandler) DeleteTemplate(c *gin.Context) {
+	utils.LogRequest(c, h.logger)
+
+	userID, ok := h.userID(c)
+	if !ok {
+		return
+	}
+
+	err := h.service.DeleteTemplate(c.Request.Context(), userID, c.Param("name"))
+	if errors.Is(err, service.ErrUTMTemplateNotFound) {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		h.logger.Errorw("Failed to delete UTM template", "userID", userID, "error", err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (h *UTMTemplateHandler) userID(c *gin.Context) (string, bool) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return "", false
+	}
+	userIDStr, ok := userID.(string)
+	if !ok || userIDStr == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return "", false
+	}
+	return userIDStr, true
+}
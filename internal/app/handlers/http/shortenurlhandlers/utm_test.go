@@ -0,0 +1,82 @@
+package shortenurlhandlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aseptimu/url-shortener/internal/app/config"
+	"github.com/aseptimu/url-shortener/internal/app/service"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+// mockTemplater хранит шаблон "newsletter" пользователя "owner".
+type mockTemplater struct{}
+
+func (m *mockTemplater) ListTemplates(_ context.Context, userID string) ([]service.UTMTemplate, error) {
+	if userID != "owner" {
+		return []service.UTMTemplate{}, nil
+	}
+	return []service.UTMTemplate{{Name: "newsletter", UTM: service.UTM{Source: "newsletter", Medium: "email"}}}, nil
+}
+
+func (m *mockTemplater) SaveTemplate(_ context.Context, _ string, template service.UTMTemplate) (service.UTMTemplate, error) {
+	if template.UTM == (service.UTM{}) {
+		return service.UTMTemplate{}, service.ErrInvalidUTM
+	}
+	return template, nil
+}
+
+func (m *mockTemplater) DeleteTemplate(_ context.Context, userID, name string) error {
+	if userID != "owner" || name != "newsletter" {
+		return service.ErrUTMTemplateNotFound
+	}
+	return nil
+}
+
+func newTestUTMRouter(userID string) *gin.Engine {
+	cfg := &config.ConfigType{BaseAddress: "http://localhost:8080"}
+	handler := NewUTMTemplateHandler(cfg, &mockTemplater{}, zap.NewNop().Sugar())
+	return newTestRouter(userID, func(r *gin.Engine) {
+		r.GET("/api/user/utm-templates", handler.ListTemplates)
+		r.PUT("/api/user/utm-templates/:name", handler.SaveTemplate)
+		r.DELETE("/api/user/utm-templates/:name", handler.DeleteTemplate)
+	})
+}
+
+func TestUTMTemplateHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		userID     string
+		method     string
+		path       string
+		body       string
+		wantStatus int
+		wantBody   string
+	}{
+		{name: "list", userID: "owner", method: http.MethodGet, path: "/api/user/utm-templates", wantStatus: http.StatusOK,
+			wantBody: `[{"name":"newsletter","source":"newsletter","medium":"email"}]`},
+		{name: "save", userID: "owner", method: http.MethodPut, path: "/api/user/utm-templates/spring", body: `{"source":"ads","campaign":"spring"}`,
+			wantStatus: http.StatusOK, wantBody: `{"name":"spring","source":"ads","campaign":"spring"}`},
+		{name: "save empty", userID: "owner", method: http.MethodPut, path: "/api/user/utm-templates/spring", body: `{}`, wantStatus: http.StatusBadRequest},
+		{name: "invalid JSON", userID: "owner", method: http.MethodPut, path: "/api/user/utm-templates/spring", body: `{`, wantStatus: http.StatusBadRequest},
+		{name: "delete", userID: "owner", method: http.MethodDelete, path: "/api/user/utm-templates/newsletter", wantStatus: http.StatusNoContent},
+		{name: "delete missing", userID: "owner", method: http.MethodDelete, path: "/api/user/utm-templates/missing", wantStatus: http.StatusNotFound},
+		{name: "unauthorized", method: http.MethodGet, path: "/api/user/utm-templates", wantStatus: http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			newTestUTMRouter(tt.userID).ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body)))
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantBody != "" {
+				assert.JSONEq(t, tt.wantBody, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,190 @@
+package shortenurlhandlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/aseptimu/url-shortener/internal/app/config"
+	"github.com/aseptimu/url-shortener/internal/app/service"
+	"github.com/aseptimu/url-shortener/internal/app/utils"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// WorkspaceHandler обрабатывает создание рабочих пространств и управление их участниками.
+// Работа со ссылками пространства идёт через обычные маршруты /api/user/... с параметром workspace.
+type WorkspaceHandler struct {
+	cfg     *config.ConfigType
+	service service.WorkspaceManager
+	logger  *zap.SugaredLogger
+}
+
+// NewWorkspaceHandler создаёт новый экземпляр WorkspaceHandler.
+func NewWorkspaceHandler(cfg *config.ConfigType, service service.WorkspaceManager, logger *zap.SugaredLogger) *WorkspaceHandler {
+	return &WorkspaceHandler{cfg: cfg, service: service, logger: logger}
+}
+
+type workspaceResponse struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type workspaceMember struct {
+	UserID string `json:"user_id"`
+	Role   string `json:"role"`
+}
+
+// CreateWorkspace обрабатывает POST /api/user/workspaces.
+// Принимает JSON {"name": "..."}, создаёт рабочее пространство, делает пользователя его владельцем
+// и возвращает 201 Created с описанием пространства.
+func (h *WorkspaceHandler) CreateWorkspace(c *gin.Context) {
+	utils.LogRequest(c, h.logger)
+
+	userID, ok := h.userID(c)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Name string `json:"name"`
+	}
+	if err := json.NewDecoder(c.Request.Body).Decode(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON format"})
+		return
+	}
+
+	workspace, err := h.service.CreateWorkspace(c.Request.Context(), userID, req.Name)
+	if h.abortOnError(c, err, "Failed to create workspace") {
+		return
+	}
+	c.JSON(http.StatusCreated, newWorkspaceResponse(workspace))
+}
+
+// ListWorkspaces обрабатывает GET /api/user/workspaces и возвращает рабочие пространства
+// пользователя с его ролями, отсортированные по названию.
+func (h *WorkspaceHandler) ListWorkspaces(c *gin.Context) {
+	utils.LogRequest(c, h.logger)
+
+	userID, ok := h.userID(c)
+	if !ok {
+		return
+	}
+
+	workspaces, err := h.service.ListWorkspaces(c.Request.Context(), userID)
+	if h.abortOnError(c, err, "Failed to list workspaces") {
+		return
+	}
+	resp := make([]workspaceResponse, len(workspaces))
+	for i, w := range workspaces {
+		resp[i] = newWorkspaceResponse(w)
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// ListMembers обрабатывает GET /api/user/workspaces/{workspace}/members
+// и возвращает участников пространства; доступно любому участнику.
+func (h *WorkspaceHandler) ListMembers(c *gin.Context) {
+	utils.LogRequest(c, h.logger)
+
+	userID, ok := h.userID(c)
+	if !ok {
+		return
+	}
+
+	members, err := h.service.ListMembers(c.Request.Context(), userID, c.Param("workspace"))
+	if h.abortOnError(c, err, "Failed to list workspace members") {
+		return
+	}
+	resp := make([]workspaceMember, len(members))
+	for i, m := range members {
+		resp[i] = workspaceMember{UserID: m.UserID, Role: m.Role}
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// SetMember обрабатывает PUT /api/user/workspaces/{workspace}/members/{member}.
+// Принимает JSON {"role": "owner|editor|viewer"} и добавляет участника или меняет его роль;
+// доступно только владельцам. Понизить последнего владельца нельзя — 409 Conflict.
+func (h *WorkspaceHandler) SetMember(c *gin.Context) {
+	utils.LogRequest(c, h.logger)
+
+	userID, ok := h.userID(c)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Role string `json:"role"`
+	}
+	if err := json.NewDecoder(c.Request.Body).Decode(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON format"})
+		return
+	}
+
+	member, err := h.service.SetMember(c.Request.Context(), userID, c.Param("workspace"),
+		service.WorkspaceMember{UserID: c.Param("member"), Role: req.Role})
+	if h.abortOnError(c, err, "Failed to set workspace member") {
+		return
+	}
+	c.JSON(http.StatusOK, workspaceMember{UserID: member.UserID, Role: member.Role})
+}
+
+// RemoveMember обрабатывает DELETE /api/user/workspaces/{workspace}/members/{member}
+// и возвращает 204 No Content. Владельцы исключают любого участника, остальные могут выйти сами.
+// Ссылки пространства при этом остаются в нём.
+func (h *WorkspaceHandler) RemoveMember(c *gin.Context) {
+	utils.LogRequest(c, h.logger)
+
+	userID, ok := h.userID(c)
+	if !ok {
+		return
+	}
+
+	err := h.service.RemoveMember(c.Request.Context(), userID, c.Param("workspace"), c.Param("member"))
+	if h.abortOnError(c, err, "Failed to remove workspace member") {
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// abortOnError отвечает клиенту статусом, соответствующим ошибке err, и сообщает, была ли ошибка.
+func (h *WorkspaceHandler) abortOnError(c *gin.Context, err error, msg string) bool {
+	switch {
+	case err == nil:
+		return false
+	case errors.Is(err, service.ErrInvalidWorkspace):
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrWorkspaceNotFound):
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrWorkspaceForbidden):
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrLastWorkspaceOwner):
+		c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		h.logger.Errorw(msg, "error", err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+	return true
+}
+
+func (h *WorkspaceHandler) userID(c *gin.Context) (string, bool) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return "", false
+	}
+	userIDStr, ok := userID.(string)
+	if !ok || userIDStr == "" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return "", false
+	}
+	return userIDStr, true
+}
+
+func newWorkspaceResponse(w service.Workspace) workspaceResponse {
+	return workspaceResponse{ID: w.ID, Name: w.Name, Role: w.Role, CreatedAt: w.CreatedAt}
+}
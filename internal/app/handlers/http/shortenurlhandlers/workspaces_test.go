@@ -0,0 +1,119 @@
+package shortenurlhandlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aseptimu/url-shortener/internal/app/config"
+	"github.com/aseptimu/url-shortener/internal/app/service"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+// mockWorkspaceManager знает пространство "ws_team" с владельцем "owner" и наблюдателем "viewer".
+type mockWorkspaceManager struct{}
+
+var testWorkspaceCreatedAt = time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+func (m *mockWorkspaceManager) Authorize(_ context.Context, userID, workspaceID, _ string) (string, error) {
+	return userID, nil
+}
+
+func (m *mockWorkspaceManager) CreateWorkspace(_ context.Context, _, name string) (service.Workspace, error) {
+	if strings.TrimSpace(name) == "" {
+		return service.Workspace{}, service.ErrInvalidWorkspace
+	}
+	return service.Workspace{ID: "ws_new", Name: name, Role: service.RoleOwner, CreatedAt: testWorkspaceCreatedAt}, nil
+}
+
+func (m *mockWorkspaceManager) ListWorkspaces(_ context.Context, userID string) ([]service.Workspace, error) {
+	if userID != "owner" {
+		return []service.Workspace{}, nil
+	}
+	return []service.Workspace{{ID: "ws_team", Name: "Team", Role: service.RoleOwner, CreatedAt: testWorkspaceCreatedAt}}, nil
+}
+
+func (m *mockWorkspaceManager) ListMembers(_ context.Context, userID, workspaceID string) ([]service.WorkspaceMember, error) {
+	if workspaceID != "ws_team" || (userID != "owner" && userID != "viewer") {
+		return nil, service.ErrWorkspaceNotFound
+	}
+	return []service.WorkspaceMember{{UserID: "owner", Role: service.RoleOwner}, {UserID: "viewer", Role: service.RoleViewer}}, nil
+}
+
+func (m *mockWorkspaceManager) SetMember(_ context.Context, userID, workspaceID string, member service.WorkspaceMember) (service.WorkspaceMember, error) {
+	switch {
+	case workspaceID != "ws_team":
+		return service.WorkspaceMember{}, service.ErrWorkspaceNotFound
+	case userID != "owner":
+		return service.WorkspaceMember{}, service.ErrWorkspaceForbidden
+	case member.UserID == "owner" && member.Role != service.RoleOwner:
+		return service.WorkspaceMember{}, service.ErrLastWorkspaceOwner
+	}
+	return member, nil
+}
+
+func (m *mockWorkspaceManager) RemoveMember(_ context.Context, userID, workspaceID, memberID string) error {
+	if workspaceID != "ws_team" {
+		return service.ErrWorkspaceNotFound
+	}
+	if userID != "owner" && userID != memberID {
+		return service.ErrWorkspaceForbidden
+	}
+	return nil
+}
+
+func newTestWorkspaceRouter(userID string) *gin.Engine {
+	cfg := &config.ConfigType{BaseAddress: "http://localhost:8080"}
+	handler := NewWorkspaceHandler(cfg, &mockWorkspaceManager{}, zap.NewNop().Sugar())
+	return newTestRouter(userID, func(r *gin.Engine) {
+		r.POST("/api/user/workspaces", handler.CreateWorkspace)
+		r.GET("/api/user/workspaces", handler.ListWorkspaces)
+		r.GET("/api/user/workspaces/:workspace/members", handler.ListMembers)
+		r.PUT("/api/user/workspaces/:workspace/members/:member", handler.SetMember)
+		r.DELETE("/api/user/workspaces/:workspace/members/:member", handler.RemoveMember)
+	})
+}
+
+func TestWorkspaceHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		userID     string
+		method     string
+		path       string
+		body       string
+		wantStatus int
+		wantBody   string
+	}{
+		{name: "create", userID: "owner", method: http.MethodPost, path: "/api/user/workspaces", body: `{"name":"Growth"}`,
+			wantStatus: http.StatusCreated, wantBody: `{"id":"ws_new","name":"Growth","role":"owner","created_at":"2024-05-01T12:00:00Z"}`},
+		{name: "create without name", userID: "owner", method: http.MethodPost, path: "/api/user/workspaces", body: `{}`, wantStatus: http.StatusBadRequest},
+		{name: "list", userID: "owner", method: http.MethodGet, path: "/api/user/workspaces",
+			wantStatus: http.StatusOK, wantBody: `[{"id":"ws_team","name":"Team","role":"owner","created_at":"2024-05-01T12:00:00Z"}]`},
+		{name: "members", userID: "viewer", method: http.MethodGet, path: "/api/user/workspaces/ws_team/members",
+			wantStatus: http.StatusOK, wantBody: `[{"user_id":"owner","role":"owner"},{"user_id":"viewer","role":"viewer"}]`},
+		{name: "members of foreign workspace", userID: "stranger", method: http.MethodGet, path: "/api/user/workspaces/ws_team/members", wantStatus: http.StatusNotFound},
+		{name: "add member", userID: "owner", method: http.MethodPut, path: "/api/user/workspaces/ws_team/members/bob", body: `{"role":"editor"}`,
+			wantStatus: http.StatusOK, wantBody: `{"user_id":"bob","role":"editor"}`},
+		{name: "viewer cannot add", userID: "viewer", method: http.MethodPut, path: "/api/user/workspaces/ws_team/members/bob", body: `{"role":"editor"}`, wantStatus: http.StatusForbidden},
+		{name: "last owner", userID: "owner", method: http.MethodPut, path: "/api/user/workspaces/ws_team/members/owner", body: `{"role":"viewer"}`, wantStatus: http.StatusConflict},
+		{name: "leave", userID: "viewer", method: http.MethodDelete, path: "/api/user/workspaces/ws_team/members/viewer", wantStatus: http.StatusNoContent},
+		{name: "unauthorized", method: http.MethodGet, path: "/api/user/workspaces", wantStatus: http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			newTestWorkspaceRouter(tt.userID).ServeHTTP(w, r)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantBody != "" {
+				assert.JSONEq(t, tt.wantBody, w.Body.String())
+			}
+		})
+	}
+}
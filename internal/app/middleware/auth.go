@@ -1,16 +1,18 @@
-// Package middleware предоставляет Gin-middleware для аутентификации с помощью JWT.
+// Package middleware предоставляет Gin-middleware для аутентификации с помощью JWT и API-ключей.
 package middleware
 
 import (
 	"context"
-	"fmt"
+	"errors"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 	"net/http"
-	"time"
+	"slices"
+	"strings"
 
+	"github.com/aseptimu/url-shortener/internal/app/service"
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
@@ -31,40 +33,96 @@ type contextKey string
 
 const ctxKeyUserID = contextKey("userID")
 
+const (
+	// ScopesKey — ключ контекста Gin с областями действия API-ключа. Для сессий по cookie он не задан.
+	ScopesKey = "scopes"
+	// authorizationKey — заголовок HTTP и ключ метаданных gRPC, в которых передаётся API-ключ.
+	authorizationKey = "authorization"
+)
+
 // AuthMiddleware возвращает Gin-мiddleware, который:
-//  1. проверяет наличие и валидность JWT в cookie с именем cookieName;
-//  2. при отсутствии или невалидном токене создаёт новый, устанавливает его в cookie
+//  1. при заголовке Authorization: Bearer проверяет API-ключ и сохраняет в контексте
+//     его владельца и области действия; неизвестный ключ отклоняется с 401 без выдачи cookie;
+//  2. иначе проверяет JWT в cookie с именем cookieName: подпись одним из ключей tokens,
+//     срок жизни и отсутствие в списке отозванных;
+//  3. при отсутствии или недействительном токене создаёт новый, устанавливает его в cookie
 //     и сохраняет userID в контексте запроса;
-//  3. при валидном токене извлекает userID из него и сохраняет в контексте.
-func AuthMiddleware(secretKey string, logger *zap.SugaredLogger) gin.HandlerFunc {
+//  4. при действительном токене извлекает userID из него и сохраняет в контексте,
+//     перевыпуская токен, если прошла половина его срока жизни или сменился ключ подписи.
+func AuthMiddleware(tokens *Tokens, keys service.APIKeyAuthenticator, logger *zap.SugaredLogger) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		if header := c.GetHeader(authorizationKey); header != "" {
+			identity, err := authenticateKey(c.Request.Context(), keys, header)
+			if err != nil {
+				logger.Debugw("API key rejected", "error", err)
+				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid API key"})
+				return
+			}
+			c.Set(cookieName, identity.UserID)
+			c.Set(ScopesKey, identity.Scopes)
+			return
+		}
+
 		cookie, err := c.Cookie(cookieName)
 		if err != nil {
 			logger.Debug("JWT cookie not found, generating new one")
-			issueNewToken(c, secretKey, logger)
+			issueNewToken(c, tokens, logger)
 			return
 		}
 
-		claims := &Claims{}
+		claims, refresh, err := tokens.Verify(c.Request.Context(), cookie)
+		if errors.Is(err, errInvalidToken) {
+			logger.Debugw("Invalid JWT, issuing new one", "error", err)
+			issueNewToken(c, tokens, logger)
+			return
+		}
+		if err != nil {
+			logger.Errorw("Failed to verify JWT", "error", err)
+			c.AbortWithStatus(http.StatusInternalServerError)
+			return
+		}
 
-		token, err := jwt.ParseWithClaims(cookie, claims, func(token *jwt.Token) (interface{}, error) {
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		c.Set(cookieName, claims.UserID)
+		if refresh {
+			if err = StartSession(c, tokens, claims.UserID); err != nil {
+				logger.Warnw("Failed to refresh JWT", "error", err)
 			}
-			return []byte(secretKey), nil
-		})
+		}
+	}
+}
 
-		if err != nil || !token.Valid {
-			logger.Debugw("Invalid JWT, issuing new one", "error", err)
-			issueNewToken(c, secretKey, logger)
+// RequireScope возвращает Gin-middleware, который пропускает запрос по API-ключу,
+// только если у ключа есть область действия scope. Сессии по cookie не ограничиваются.
+func RequireScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		scopes, ok := c.Get(ScopesKey)
+		if !ok {
 			return
 		}
+		if granted, _ := scopes.([]string); !slices.Contains(granted, scope) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "API key lacks scope " + scope})
+		}
+	}
+}
 
-		c.Set(cookieName, claims.UserID)
+// RequireSession возвращает Gin-middleware, который отклоняет запросы по API-ключу с 403.
+// Им закрыты маршруты управления учётной записью, например выпуск самих ключей.
+func RequireSession() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := c.Get(ScopesKey); ok {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "API keys are not allowed here"})
+		}
 	}
 }
 
-func AuthInterceptor(secretKey string, logger *zap.SugaredLogger) grpc.UnaryServerInterceptor {
+// AuthInterceptor возвращает gRPC-перехватчик, который определяет пользователя вызова.
+// API-ключ передаётся в метаданных authorization как "Bearer <ключ>" и должен иметь область
+// действия, которую scopes сопоставляет методу; методы без области доступны любому ключу.
+// Иначе пользователь берётся из JWT в метаданных userID, а без действительного токена
+// выдаётся новый. Новый или перевыпущенный токен возвращается в заголовке userID.
+// Найденный или новый пользователь записывается в метаданные userID, которые читают обработчики,
+// поэтому присланный клиентом идентификатор без подписи не принимается.
+func AuthInterceptor(tokens *Tokens, keys service.APIKeyAuthenticator, scopes map[string]string, logger *zap.SugaredLogger) grpc.UnaryServerInterceptor {
 	return func(
 		ctx context.Context,
 		req interface{},
@@ -72,29 +130,37 @@ func AuthInterceptor(secretKey string, logger *zap.SugaredLogger) grpc.UnaryServ
 		handler grpc.UnaryHandler,
 	) (interface{}, error) {
 		var userID string
+		var issue bool
 
-		if md, ok := metadata.FromIncomingContext(ctx); ok {
-			if vals := md.Get(cookieName); len(vals) > 0 {
-				rawToken := vals[0]
-				claims := &Claims{}
-				t, err := jwt.ParseWithClaims(rawToken, claims, func(token *jwt.Token) (interface{}, error) {
-					if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-						return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-					}
-					return []byte(secretKey), nil
-				})
-				if err == nil && t.Valid {
-					userID = claims.UserID
-				} else {
-					logger.Debugw("invalid JWT in metadata, will issue new", "error", err)
-				}
-
+		md, _ := metadata.FromIncomingContext(ctx)
+		if vals := md.Get(authorizationKey); len(vals) > 0 {
+			identity, err := authenticateKey(ctx, keys, vals[0])
+			if err != nil {
+				logger.Debugw("API key rejected", "error", err)
+				return nil, status.Error(codes.Unauthenticated, "invalid API key")
+			}
+			if scope, ok := scopes[info.FullMethod]; ok && !identity.Allows(scope) {
+				return nil, status.Error(codes.PermissionDenied, "API key lacks scope "+scope)
+			}
+			userID = identity.UserID
+		} else if vals := md.Get(cookieName); len(vals) > 0 {
+			claims, refresh, err := tokens.Verify(ctx, vals[0])
+			switch {
+			case errors.Is(err, errInvalidToken):
+				logger.Debugw("invalid JWT in metadata, will issue new", "error", err)
+			case err != nil:
+				logger.Errorw("failed to verify JWT", "error", err)
+				return nil, status.Error(codes.Internal, "failed to verify JWT")
+			default:
+				userID, issue = claims.UserID, refresh
 			}
 		}
 
 		if userID == "" {
-			userID = uuid.New().String()
-			newToken, err := generateJWT(userID, secretKey)
+			userID, issue = uuid.New().String(), true
+		}
+		if issue {
+			newToken, err := tokens.Issue(userID)
 			if err != nil {
 				logger.Errorw("failed to generate JWT", "error", err)
 				return nil, status.Error(codes.Internal, "failed to generate JWT")
@@ -106,41 +172,66 @@ func AuthInterceptor(secretKey string, logger *zap.SugaredLogger) grpc.UnaryServ
 			}
 		}
 
+		md = md.Copy()
+		md.Set(cookieName, userID)
+		ctx = metadata.NewIncomingContext(ctx, md)
 		newCtx := context.WithValue(ctx, ctxKeyUserID, userID)
 		return handler(newCtx, req)
 	}
 
 }
 
+// authenticateKey проверяет API-ключ из значения заголовка Authorization вида "Bearer <ключ>".
+func authenticateKey(ctx context.Context, keys service.APIKeyAuthenticator, header string) (service.APIKeyIdentity, error) {
+	scheme, token, ok := strings.Cut(header, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return service.APIKeyIdentity{}, service.ErrAPIKeyNotFound
+	}
+	return keys.Authenticate(ctx, strings.TrimSpace(token))
+}
+
 // issueNewToken генерирует новый JWT для уникального userID,
 // устанавливает его в cookie и сохраняет userID в контексте.
-func issueNewToken(c *gin.Context, secretKey string, logger *zap.SugaredLogger) {
-	userID := uuid.New().String()
-
-	tokenString, err := generateJWT(userID, secretKey)
-	if err != nil {
+func issueNewToken(c *gin.Context, tokens *Tokens, logger *zap.SugaredLogger) {
+	if err := StartSession(c, tokens, uuid.New().String()); err != nil {
 		logger.Errorw("Failed to generate JWT", "error", err)
 		c.AbortWithStatus(http.StatusInternalServerError)
-		return
+	}
+}
+
+// StartSession выдаёт JWT пользователя userID в cookie и сохраняет userID в контексте.
+// Используется при входе в учётную запись, чтобы следующие запросы шли от её имени.
+func StartSession(c *gin.Context, tokens *Tokens, userID string) error {
+	c.Set(cookieName, userID)
+
+	tokenString, err := tokens.Issue(userID)
+	if err != nil {
+		return err
 	}
 
 	http.SetCookie(c.Writer, &http.Cookie{
 		Name:     cookieName,
 		Value:    tokenString,
+		MaxAge:   int(tokens.TTL().Seconds()),
 		HttpOnly: true,
 	})
-
-	c.Set(cookieName, userID)
+	return nil
 }
 
-// generateJWT создаёт и подписывает JWT для заданного userID и секрета.
-func generateJWT(userID, secret string) (string, error) {
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, Claims{
-		RegisteredClaims: jwt.RegisteredClaims{
-			IssuedAt: jwt.NewNumericDate(time.Now()),
-		},
-		UserID: userID,
-	})
+// EndSession отзывает JWT из cookie и удаляет cookie: следующий запрос получит
+// нового анонимного пользователя, а скопированный токен больше не примется.
+func EndSession(c *gin.Context, tokens *Tokens) error {
+	if cookie, err := c.Cookie(cookieName); err == nil {
+		if err = tokens.Revoke(c.Request.Context(), cookie); err != nil {
+			return err
+		}
+	}
 
-	return token.SignedString([]byte(secret))
+	http.SetCookie(c.Writer, &http.Cookie{
+		Name:     cookieName,
+		Value:    "",
+		MaxAge:   -1,
+		HttpOnly: true,
+	})
+	return nil
 }
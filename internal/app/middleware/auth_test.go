@@ -0,0 +1,202 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/aseptimu/url-shortener/internal/app/service"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const testSecret = "secret"
+
+// memRevoker хранит отозванные токены в памяти.
+type memRevoker map[string]time.Time
+
+func (m memRevoker) Revoke(_ context.Context, id string, expiresAt time.Time) error {
+	m[id] = expiresAt
+	return nil
+}
+
+func (m memRevoker) Revoked(_ context.Context, id string) (bool, error) {
+	_, ok := m[id]
+	return ok, nil
+}
+
+func newTestTokens() *Tokens {
+	return NewTokens(testSecret, nil, time.Hour, memRevoker{})
+}
+
+// stubKeys принимает ключ "usk_reader" пользователя "alice" с областью read.
+type stubKeys struct{}
+
+func (stubKeys) Authenticate(_ context.Context, token string) (service.APIKeyIdentity, error) {
+	if token != "usk_reader" {
+		return service.APIKeyIdentity{}, service.ErrAPIKeyNotFound
+	}
+	return service.APIKeyIdentity{UserID: "alice", Scopes: []string{service.ScopeRead}}, nil
+}
+
+func TestAuthMiddleware_APIKey(t *testing.T) {
+	tests := []struct {
+		name       string
+		header     string
+		scope      string
+		session    bool
+		wantStatus int
+		wantUserID string
+		wantCookie bool
+	}{
+		{name: "valid key", header: "Bearer usk_reader", scope: service.ScopeRead, wantStatus: http.StatusOK, wantUserID: "alice"},
+		{name: "missing scope", header: "Bearer usk_reader", scope: service.ScopeDelete, wantStatus: http.StatusForbidden},
+		{name: "session only", header: "Bearer usk_reader", session: true, wantStatus: http.StatusForbidden},
+		{name: "unknown key", header: "Bearer usk_other", wantStatus: http.StatusUnauthorized},
+		{name: "wrong scheme", header: "Basic usk_reader", wantStatus: http.StatusUnauthorized},
+		{name: "cookie session", scope: service.ScopeDelete, session: true, wantStatus: http.StatusOK, wantCookie: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			router.Use(AuthMiddleware(newTestTokens(), stubKeys{}, zap.NewNop().Sugar()))
+			if tt.scope != "" {
+				router.Use(RequireScope(tt.scope))
+			}
+			if tt.session {
+				router.Use(RequireSession())
+			}
+			var userID string
+			router.GET("/test", func(c *gin.Context) {
+				userID = c.GetString(cookieName)
+				c.Status(http.StatusOK)
+			})
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/test", nil)
+			if tt.header != "" {
+				r.Header.Set("Authorization", tt.header)
+			}
+			router.ServeHTTP(w, r)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d", tt.wantStatus, w.Code)
+			}
+			if tt.wantUserID != "" && userID != tt.wantUserID {
+				t.Errorf("expected userID %q, got %q", tt.wantUserID, userID)
+			}
+			if gotCookie := w.Header().Get("Set-Cookie") != ""; gotCookie != tt.wantCookie {
+				t.Errorf("expected cookie issued: %v, got %v", tt.wantCookie, gotCookie)
+			}
+		})
+	}
+}
+
+func TestAuthInterceptor(t *testing.T) {
+	tokens := newTestTokens()
+	token, err := tokens.Issue("bob")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	scopes := map[string]string{"/svc/Read": service.ScopeRead, "/svc/Delete": service.ScopeDelete}
+
+	tests := []struct {
+		name       string
+		method     string
+		md         metadata.MD
+		wantCode   codes.Code
+		wantUserID string
+	}{
+		{name: "api key", method: "/svc/Read", md: metadata.Pairs("authorization", "Bearer usk_reader", "userID", "mallory"), wantUserID: "alice"},
+		{name: "public method", method: "/svc/Ping", md: metadata.Pairs("authorization", "Bearer usk_reader"), wantUserID: "alice"},
+		{name: "missing scope", method: "/svc/Delete", md: metadata.Pairs("authorization", "Bearer usk_reader"), wantCode: codes.PermissionDenied},
+		{name: "unknown key", method: "/svc/Read", md: metadata.Pairs("authorization", "Bearer usk_other"), wantCode: codes.Unauthenticated},
+		{name: "jwt", method: "/svc/Delete", md: metadata.Pairs("userID", token), wantUserID: "bob"},
+		{name: "unsigned user id", method: "/svc/Delete", md: metadata.Pairs("userID", "bob")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			interceptor := AuthInterceptor(tokens, stubKeys{}, scopes, zap.NewNop().Sugar())
+			ctx := metadata.NewIncomingContext(context.Background(), tt.md)
+			var userID string
+			_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: tt.method}, func(ctx context.Context, _ interface{}) (interface{}, error) {
+				md, _ := metadata.FromIncomingContext(ctx)
+				userID = md.Get(cookieName)[0]
+				return nil, nil
+			})
+
+			if status.Code(err) != tt.wantCode {
+				t.Fatalf("expected code %v, got %v", tt.wantCode, err)
+			}
+			if tt.wantUserID == "" && tt.wantCode == codes.OK && (userID == "" || userID == "bob") {
+				t.Errorf("expected a new user, got %q", userID)
+			} else if tt.wantUserID != "" && userID != tt.wantUserID {
+				t.Errorf("expected userID %q, got %q", tt.wantUserID, userID)
+			}
+		})
+	}
+}
+
+func TestAuthMiddleware_Session(t *testing.T) {
+	revoked := memRevoker{}
+	tokens := NewTokens(testSecret, []string{"old-secret"}, time.Hour, revoked)
+	current, err := tokens.Issue("alice")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	previous, err := NewTokens("old-secret", nil, time.Hour, memRevoker{}).Issue("bob")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	logout, err := tokens.Issue("carol")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err = tokens.Revoke(context.Background(), logout); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		cookie     string
+		wantUserID string
+		wantCookie bool
+	}{
+		{name: "valid", cookie: current, wantUserID: "alice"},
+		{name: "rotated key is refreshed", cookie: previous, wantUserID: "bob", wantCookie: true},
+		{name: "revoked", cookie: logout, wantCookie: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			router.Use(AuthMiddleware(tokens, stubKeys{}, zap.NewNop().Sugar()))
+			var userID string
+			router.GET("/test", func(c *gin.Context) {
+				userID = c.GetString(cookieName)
+			})
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/test", nil)
+			r.AddCookie(&http.Cookie{Name: cookieName, Value: tt.cookie})
+			router.ServeHTTP(w, r)
+
+			if tt.wantUserID != "" && userID != tt.wantUserID {
+				t.Errorf("expected userID %q, got %q", tt.wantUserID, userID)
+			}
+			if tt.wantUserID == "" && (userID == "" || userID == "carol") {
+				t.Errorf("expected a new anonymous user, got %q", userID)
+			}
+			if gotCookie := w.Header().Get("Set-Cookie") != ""; gotCookie != tt.wantCookie {
+				t.Errorf("expected cookie issued: %v, got %v", tt.wantCookie, gotCookie)
+			}
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package middleware
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aseptimu/url-shortener/internal/app/service"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// errInvalidToken оборачивает причины, по которым JWT не принимается:
+// вместо такого токена пользователю выдаётся новый.
+var errInvalidToken = errors.New("invalid JWT")
+
+// signingKey — ключ подписи JWT и его идентификатор kid.
+type signingKey struct {
+	id     string
+	secret []byte
+}
+
+// Tokens выпускает и проверяет JWT пользователей по связке ключей.
+// Новые токены подписываются текущим ключом и несут его kid в заголовке;
+// токены, подписанные прежними ключами, принимаются и при использовании перевыпускаются,
+// поэтому ключ можно сменить, не разлогинивая пользователей.
+type Tokens struct {
+	keys        []signingKey
+	ttl         time.Duration
+	revocations service.TokenRevoker
+}
+
+// NewTokens создаёт Tokens с текущим ключом secret, прежними ключами previous
+// и сроком жизни токенов ttl. revocations хранит токены, отозванные при выходе.
+func NewTokens(secret string, previous []string, ttl time.Duration, revocations service.TokenRevoker) *Tokens {
+	keys := []signingKey{{id: keyID(secret), secret: []byte(secret)}}
+	for _, p := range previous {
+		keys = append(keys, signingKey{id: keyID(p), secret: []byte(p)})
+	}
+	return &Tokens{keys: keys, ttl: ttl, revocations: revocations}
+}
+
+// keyID возвращает kid ключа: начало его SHA-256, чтобы не хранить идентификаторы отдельно.
+func keyID(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:4])
+}
+
+// TTL возвращает срок жизни токенов.
+func (t *Tokens) TTL() time.Duration {
+	return t.ttl
+}
+
+// Issue выпускает JWT пользователя userID, подписанный текущим ключом.
+func (t *Tokens) Issue(userID string) (string, error) {
+	now := time.Now()
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.New().String(),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(t.ttl)),
+		},
+		UserID: userID,
+	})
+	token.Header["kid"] = t.keys[0].id
+	return token.SignedString(t.keys[0].secret)
+}
+
+// Verify проверяет JWT и возвращает его утверждения. refresh сообщает, что токен пора
+// перевыпустить: прошла половина срока жизни или он подписан прежним ключом.
+// Токены без срока жизни, выпущенные до его появления, истекают через ttl после выпуска.
+// Недействительные токены отклоняются с ошибкой, оборачивающей errInvalidToken.
+func (t *Tokens) Verify(ctx context.Context, raw string) (*Claims, bool, error) {
+	claims := &Claims{}
+	token, err := jwt.ParseWithClaims(raw, claims, t.keyFunc)
+	if err != nil || !token.Valid {
+		return nil, false, fmt.Errorf("%w: %v", errInvalidToken, err)
+	}
+	if claims.UserID == "" || claims.IssuedAt == nil {
+		return nil, false, fmt.Errorf("%w: missing claims", errInvalidToken)
+	}
+
+	expiresAt := claims.IssuedAt.Add(t.ttl)
+	if claims.ExpiresAt != nil {
+		expiresAt = claims.ExpiresAt.Time
+	}
+	now := time.Now()
+	if !expiresAt.After(now) {
+		return nil, false, fmt.Errorf("%w: token expired", errInvalidToken)
+	}
+
+	revoked, err := t.revocations.Revoked(ctx, claims.ID)
+	if err != nil {
+		return nil, false, err
+	}
+	if revoked {
+		return nil, false, fmt.Errorf("%w: token revoked", errInvalidToken)
+	}
+
+	kid, _ := token.Header["kid"].(string)
+	refresh := kid != t.keys[0].id || claims.ExpiresAt == nil || expiresAt.Sub(now) < t.ttl/2
+	return claims, refresh, nil
+}
+
+// Revoke отзывает действительный JWT до истечения его срока. Недействительные токены пропускаются.
+func (t *Tokens) Revoke(ctx context.Context, raw string) error {
+	claims, _, err := t.Verify(ctx, raw)
+	if errors.Is(err, errInvalidToken) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if claims.ExpiresAt == nil {
+		// Токены без идентификатора отозвать нельзя; они перевыпускаются при первом использовании.
+		return nil
+	}
+	return t.revocations.Revoke(ctx, claims.ID, claims.ExpiresAt.Time)
+}
+
+// keyFunc выбирает ключ проверки по kid. Для токенов без kid, выпущенных до появления
+// связки ключей, подходит любой ключ связки.
+func (t *Tokens) keyFunc(token *jwt.Token) (interface{}, error) {
+	if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+		return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+	}
+	kid, ok := token.Header["kid"].(string)
+	if !ok {
+		set := jwt.VerificationKeySet{}
+		for _, k := range t.keys {
+			set.Keys = append(set.Keys, k.secret)
+		}
+		return set, nil
+	}
+	for _, k := range t.keys {
+		if k.id == kid {
+			return k.secret, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown signing key %q", kid)
+}
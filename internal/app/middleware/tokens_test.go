@@ -0,0 +1,97 @@
+package middleware
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// signLegacy подписывает токен так, как это делалось до появления срока жизни и kid.
+func signLegacy(t *testing.T, userID, secret string, issuedAt time.Time) string {
+	t.Helper()
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, Claims{
+		RegisteredClaims: jwt.RegisteredClaims{IssuedAt: jwt.NewNumericDate(issuedAt)},
+		UserID:           userID,
+	}).SignedString([]byte(secret))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return token
+}
+
+func TestTokens_Verify(t *testing.T) {
+	ctx := context.Background()
+	old := NewTokens("old-secret", nil, time.Hour, memRevoker{})
+	oldToken, err := old.Issue("alice")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	rotated := NewTokens("new-secret", []string{"old-secret"}, time.Hour, memRevoker{})
+	newToken, err := rotated.Issue("bob")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expired, err := NewTokens("new-secret", nil, -time.Minute, memRevoker{}).Issue("carol")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		tokens      *Tokens
+		token       string
+		wantUserID  string
+		wantRefresh bool
+	}{
+		{name: "current key", tokens: rotated, token: newToken, wantUserID: "bob"},
+		{name: "previous key is refreshed", tokens: rotated, token: oldToken, wantUserID: "alice", wantRefresh: true},
+		{name: "retired key", tokens: NewTokens("new-secret", nil, time.Hour, memRevoker{}), token: oldToken},
+		{name: "expired", tokens: rotated, token: expired},
+		{name: "legacy token is refreshed", tokens: rotated, token: signLegacy(t, "dave", "old-secret", time.Now()), wantUserID: "dave", wantRefresh: true},
+		{name: "legacy token past ttl", tokens: rotated, token: signLegacy(t, "dave", "new-secret", time.Now().Add(-2*time.Hour))},
+		{name: "unsigned", tokens: rotated, token: signLegacy(t, "eve", "guess", time.Now())},
+		{name: "half-life passed", tokens: NewTokens("new-secret", nil, 3*time.Hour, memRevoker{}), token: newToken, wantUserID: "bob", wantRefresh: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, refresh, err := tt.tokens.Verify(ctx, tt.token)
+			if tt.wantUserID == "" {
+				if err == nil {
+					t.Fatalf("expected the token to be rejected, got %+v", claims)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if claims.UserID != tt.wantUserID || refresh != tt.wantRefresh {
+				t.Errorf("expected %q with refresh %v, got %q with refresh %v", tt.wantUserID, tt.wantRefresh, claims.UserID, refresh)
+			}
+		})
+	}
+}
+
+func TestTokens_Revoke(t *testing.T) {
+	ctx := context.Background()
+	tokens := NewTokens(testSecret, nil, time.Hour, memRevoker{})
+	token, err := tokens.Issue("alice")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	other, err := tokens.Issue("alice")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err = tokens.Revoke(ctx, token); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, _, err = tokens.Verify(ctx, token); err == nil {
+		t.Errorf("expected revoked token to be rejected")
+	}
+	if _, _, err = tokens.Verify(ctx, other); err != nil {
+		t.Errorf("expected other sessions to stay valid, got %v", err)
+	}
+}
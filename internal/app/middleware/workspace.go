@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/aseptimu/url-shortener/internal/app/service"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// WorkspaceParam — параметр запроса с ID рабочего пространства, от имени которого выполняется запрос.
+const WorkspaceParam = "workspace"
+
+// MemberIDKey — ключ контекста gin с userID пользователя, выполняющего запрос.
+// Его сохраняет WorkspaceMiddleware, так как userID в контексте заменяется владельцем ссылок.
+const MemberIDKey = "memberID"
+
+// WorkspaceMiddleware возвращает Gin-middleware, который выполняет запрос от имени рабочего пространства
+// из параметра WorkspaceParam:
+//  1. проверяет, что у пользователя из контекста есть в пространстве роль не ниже role;
+//  2. сохраняет userID пользователя под ключом MemberIDKey;
+//  3. заменяет userID в контексте на ID пространства, так что хендлеры работают со ссылками пространства.
+//
+// Без параметра запрос выполняется в личном пространстве пользователя. Не участнику пространства
+// возвращается 404 Not Found, участнику с недостаточной ролью — 403 Forbidden.
+func WorkspaceMiddleware(workspaces service.WorkspaceAuthorizer, role string, logger *zap.SugaredLogger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := c.GetString(cookieName)
+		c.Set(MemberIDKey, userID)
+
+		workspaceID := c.Query(WorkspaceParam)
+		if workspaceID == "" {
+			return
+		}
+		if userID == "" {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+			return
+		}
+
+		owner, err := workspaces.Authorize(c.Request.Context(), userID, workspaceID, role)
+		switch {
+		case errors.Is(err, service.ErrWorkspaceNotFound):
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		case errors.Is(err, service.ErrWorkspaceForbidden):
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		case err != nil:
+			logger.Errorw("Failed to authorize workspace access", "workspaceID", workspaceID, "error", err)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.Set(cookieName, owner)
+	}
+}
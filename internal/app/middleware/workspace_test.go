@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aseptimu/url-shortener/internal/app/service"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// stubAuthorizer пускает в пространство "ws_team" пользователя "editor" с ролью editor.
+type stubAuthorizer struct{}
+
+func (stubAuthorizer) Authorize(_ context.Context, userID, workspaceID, role string) (string, error) {
+	switch {
+	case workspaceID == "":
+		return userID, nil
+	case workspaceID != "ws_team" || userID != "editor":
+		return "", service.ErrWorkspaceNotFound
+	case role == service.RoleOwner:
+		return "", service.ErrWorkspaceForbidden
+	}
+	return workspaceID, nil
+}
+
+func TestWorkspaceMiddleware(t *testing.T) {
+	tests := []struct {
+		name       string
+		userID     string
+		query      string
+		role       string
+		wantStatus int
+		wantOwner  string
+	}{
+		{name: "personal", userID: "editor", role: service.RoleOwner, wantStatus: http.StatusOK, wantOwner: "editor"},
+		{name: "workspace", userID: "editor", query: "?workspace=ws_team", role: service.RoleEditor, wantStatus: http.StatusOK, wantOwner: "ws_team"},
+		{name: "insufficient role", userID: "editor", query: "?workspace=ws_team", role: service.RoleOwner, wantStatus: http.StatusForbidden},
+		{name: "not a member", userID: "stranger", query: "?workspace=ws_team", role: service.RoleViewer, wantStatus: http.StatusNotFound},
+		{name: "anonymous", query: "?workspace=ws_team", role: service.RoleViewer, wantStatus: http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			router.Use(func(c *gin.Context) {
+				if tt.userID != "" {
+					c.Set(cookieName, tt.userID)
+				}
+			})
+			router.Use(WorkspaceMiddleware(stubAuthorizer{}, tt.role, zap.NewNop().Sugar()))
+			var owner, member string
+			router.GET("/test", func(c *gin.Context) {
+				owner, member = c.GetString(cookieName), c.GetString(MemberIDKey)
+				c.Status(http.StatusOK)
+			})
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/test"+tt.query, nil))
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d", tt.wantStatus, w.Code)
+			}
+			if owner != tt.wantOwner || (tt.wantOwner != "" && member != tt.userID) {
+				t.Errorf("expected owner %q and member %q, got %q and %q", tt.wantOwner, tt.userID, owner, member)
+			}
+		})
+	}
+}
@@ -8,20 +8,52 @@ import (
 	"github.com/aseptimu/url-shortener/internal/app/handlers/grpc/proto"
 	"github.com/aseptimu/url-shortener/internal/app/handlers/http/dbhandlers"
 	"github.com/aseptimu/url-shortener/internal/app/handlers/http/shortenurlhandlers"
+	"github.com/aseptimu/url-shortener/internal/app/middleware"
 	"github.com/aseptimu/url-shortener/internal/app/service"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
 	"net"
+	"time"
 )
 
+// MethodScopes сопоставляет методам сервиса области действия, которые нужны API-ключу для их вызова.
+// Методы без записи не работают со ссылками пользователя и доступны любому ключу.
+// Вход и регистрация требуют scopeSession, которой нет ни у одного ключа.
+var MethodScopes = map[string]string{
+	proto.URLShortener_Register_FullMethodName:        scopeSession,
+	proto.URLShortener_Login_FullMethodName:           scopeSession,
+	proto.URLShortener_URLCreator_FullMethodName:      service.ScopeShorten,
+	proto.URLShortener_URLCreatorJSON_FullMethodName:  service.ScopeShorten,
+	proto.URLShortener_URLCreatorBatch_FullMethodName: service.ScopeShorten,
+	proto.URLShortener_UpdateURL_FullMethodName:       service.ScopeShorten,
+	proto.URLShortener_GetUserURLs_FullMethodName:     service.ScopeRead,
+	proto.URLShortener_ListUserURLs_FullMethodName:    service.ScopeRead,
+	proto.URLShortener_DeleteUserURLs_FullMethodName:  service.ScopeDelete,
+	proto.URLShortener_RestoreUserURLs_FullMethodName: service.ScopeDelete,
+	proto.URLShortener_GetURLStats_FullMethodName:     service.ScopeStats,
+}
+
+// scopeSession — область действия, которую нельзя выдать API-ключу.
+const scopeSession = "session"
+
 type Server struct {
 	proto.UnimplementedURLShortenerServer
-	cfg    *config.ConfigType
-	svc    service.URLShortener
-	getSvc shortenurlhandlers.URLGetter
-	delSvc service.URLDeleter
-	ping   dbhandlers.Pinger
+	cfg      *config.ConfigType
+	svc      service.URLShortener
+	getSvc   shortenurlhandlers.URLGetter
+	delSvc   service.URLDeleter
+	clickSvc service.ClickTracker
+	editSvc  service.URLEditor
+	qrSvc    service.QRCoder
+	expSvc   service.URLExpander
+	wsSvc    service.WorkspaceAuthorizer
+	accSvc   service.AccountManager
+	tokens   *middleware.Tokens
+	ping     dbhandlers.Pinger
 }
 
 func NewServer(
@@ -29,27 +61,115 @@ func NewServer(
 	svc service.URLShortener,
 	getSvc shortenurlhandlers.URLGetter,
 	delSvc service.URLDeleter,
+	clickSvc service.ClickTracker,
+	editSvc service.URLEditor,
+	qrSvc service.QRCoder,
+	expSvc service.URLExpander,
+	wsSvc service.WorkspaceAuthorizer,
+	accSvc service.AccountManager,
+	tokens *middleware.Tokens,
 	ping dbhandlers.Pinger,
 ) *Server {
-	return &Server{cfg: cfg, svc: svc, getSvc: getSvc, delSvc: delSvc, ping: ping}
+	return &Server{
+		cfg:      cfg,
+		svc:      svc,
+		getSvc:   getSvc,
+		delSvc:   delSvc,
+		clickSvc: clickSvc,
+		editSvc:  editSvc,
+		qrSvc:    qrSvc,
+		expSvc:   expSvc,
+		wsSvc:    wsSvc,
+		accSvc:   accSvc,
+		tokens:   tokens,
+		ping:     ping,
+	}
 }
 
+// GetURL возвращает адрес назначения и код перенаправления ссылки.
+// Поля path и query переносятся в адрес назначения, если это включено для ссылки.
+// Поле variant сохраняет за клиентом вариант A/B-сплита, выданный ему в ответе раньше.
+// Поле domain выбирает короткий домен ссылки; ненастроенный домен приводит к InvalidArgument.
+// Правила перенаправления видят посетителя по метаданным user-agent, accept-language и адресу клиента
+// (X-Real-IP учитывается только от прокси из доверенной подсети). Для защищённых ссылок пароль передаётся в поле password:
+// без него возвращается Unauthenticated, с неверным — PermissionDenied,
+// а после превышения лимита попыток — ResourceExhausted.
 func (s *Server) GetURL(ctx context.Context, req *proto.GetURLRequest) (*proto.GetURLResponse, error) {
-	original, err := s.getSvc.GetOriginalURL(ctx, req.GetUrl())
+	key, err := s.linkKey(req.GetDomain(), req.GetUrl())
+	if err != nil {
+		return nil, err
+	}
+	redirect, err := s.getSvc.UnlockURL(ctx, key, req.GetPassword(), s.visit(ctx, req))
 	switch {
+	case errors.Is(err, service.ErrPasswordRequired):
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	case errors.Is(err, service.ErrInvalidPassword):
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	case errors.Is(err, service.ErrTooManyAttempts):
+		return nil, status.Error(codes.ResourceExhausted, err.Error())
 	case errors.Is(err, service.ErrURLNotFound):
 		return nil, status.Error(codes.NotFound, err.Error())
-	case errors.Is(err, service.ErrURLDeleted):
+	case errors.Is(err, service.ErrURLDeleted), errors.Is(err, service.ErrURLExpired),
+		errors.Is(err, service.ErrURLExhausted):
 		return nil, status.Error(codes.NotFound, err.Error())
+	case err != nil:
+		return nil, status.Error(codes.Internal, err.Error())
 	}
 
 	resp := &proto.GetURLResponse{}
-	resp.SetOriginalUrl(original)
+	resp.SetOriginalUrl(redirect.URL)
+	resp.SetRedirectCode(int32(redirect.Code))
+	resp.SetVariant(redirect.Variant)
 
 	return resp, nil
 }
 
-func (s *Server) GetUserURLs(ctx context.Context, _ *proto.GetUserURLsRequest) (*proto.GetUserURLsResponse, error) {
+// visit собирает данные перехода из запроса и метаданных вызова.
+func (s *Server) visit(ctx context.Context, req *proto.GetURLRequest) service.Visit {
+	v := service.Visit{Path: req.GetPath(), Query: req.GetQuery(), Variant: req.GetVariant()}
+	md, _ := metadata.FromIncomingContext(ctx)
+	if vals := md.Get("user-agent"); len(vals) != 0 {
+		v.UserAgent = vals[0]
+	}
+	if vals := md.Get("accept-language"); len(vals) != 0 {
+		v.AcceptLanguage = vals[0]
+	}
+	v.IP = s.clientIP(ctx)
+	return v
+}
+
+// clientIP возвращает адрес клиента: из метаданных X-Real-IP, если вызов пришёл от прокси
+// из доверенной подсети, иначе — адрес соединения.
+func (s *Server) clientIP(ctx context.Context) string {
+	ip := peerIP(ctx)
+	if s.cfg.TrustedSubnet == "" {
+		return ip
+	}
+	_, trustedNet, err := net.ParseCIDR(s.cfg.TrustedSubnet)
+	if err != nil || !trustedNet.Contains(net.ParseIP(ip)) {
+		return ip
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	if vals := md.Get("X-Real-IP"); len(vals) != 0 {
+		return vals[0]
+	}
+	return ip
+}
+
+// peerIP возвращает адрес клиента из соединения вызова или пустую строку, если он неизвестен.
+func peerIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return ""
+	}
+	return host
+}
+
+func (s *Server) GetUserURLs(ctx context.Context, req *proto.GetUserURLsRequest) (*proto.GetUserURLsResponse, error) {
 	md, ok := metadata.FromIncomingContext(ctx)
 	if !ok {
 		return nil, status.Error(codes.Unauthenticated, "no metadata in context")
@@ -59,28 +179,103 @@ func (s *Server) GetUserURLs(ctx context.Context, _ *proto.GetUserURLsRequest) (
 		return nil, status.Error(codes.Unauthenticated, "no userID provided")
 	}
 
-	userID := users[0]
-
-	urls, err := s.getSvc.GetUserURLs(ctx, userID)
+	userID, err := s.owner(ctx, users[0], service.RoleViewer)
 	if err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, err
+	}
+
+	// Ответ сохраняет прежний контракт и содержит все ссылки, поэтому страницы собираются здесь.
+	filter := service.URLFilter{Tags: req.GetTags()}
+	var urls []service.URLDTO
+	for {
+		page, err := s.getSvc.GetUserURLs(ctx, userID, filter)
+		if err != nil {
+			return nil, listError(err)
+		}
+		urls = append(urls, page.URLs...)
+		if page.NextPageToken == "" {
+			break
+		}
+		filter.PageToken = page.NextPageToken
 	}
 
 	if len(urls) == 0 {
 		return nil, status.Error(codes.NotFound, fmt.Sprintf("No urls for user: %s", userID))
 	}
 
+	response := &proto.GetUserURLsResponse{}
+	response.SetUrls(s.userURLs(urls))
+	return response, nil
+}
+
+func (s *Server) ListUserURLs(ctx context.Context, req *proto.ListUserURLsRequest) (*proto.ListUserURLsResponse, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "no metadata in context")
+	}
+	users := md.Get("userID")
+	if len(users) == 0 {
+		return nil, status.Error(codes.Unauthenticated, "no userID provided")
+	}
+
+	owner, err := s.owner(ctx, users[0], service.RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	page, err := s.getSvc.GetUserURLs(ctx, owner, service.URLFilter{
+		Tags:        req.GetTags(),
+		Search:      req.GetSearch(),
+		Destination: req.GetUrl(),
+		Sort:        req.GetSort(),
+		Order:       req.GetOrder(),
+		Limit:       int(req.GetPageSize()),
+		PageToken:   req.GetPageToken(),
+	})
+	if err != nil {
+		return nil, listError(err)
+	}
+
+	response := &proto.ListUserURLsResponse{}
+	response.SetUrls(s.userURLs(page.URLs))
+	response.SetNextPageToken(page.NextPageToken)
+	return response, nil
+}
+
+// listError преобразует ошибку получения списка ссылок в статус gRPC.
+func listError(err error) error {
+	switch {
+	case errors.Is(err, service.ErrInvalidTag), errors.Is(err, service.ErrInvalidURL),
+		errors.Is(err, service.ErrInvalidSort), errors.Is(err, service.ErrInvalidPageSize),
+		errors.Is(err, service.ErrInvalidPageToken):
+		return status.Error(codes.InvalidArgument, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
+
+// userURLs преобразует ссылки пользователя в сообщения ответа.
+func (s *Server) userURLs(urls []service.URLDTO) []*proto.UserURL {
 	var responseUrls []*proto.UserURL
 	for _, url := range urls {
 		userURL := &proto.UserURL{}
-		userURL.SetShortUrl(s.cfg.BaseAddress + "/" + url.ShortURL)
+		userURL.SetShortUrl(s.cfg.ShortURL(service.SplitLinkKey(url.ShortURL)))
 		userURL.SetOriginalUrl(url.OriginalURL)
+		if url.ExpiresAt != nil {
+			userURL.SetExpiresAt(url.ExpiresAt.Format(time.RFC3339))
+		}
+		if url.RemainingUses != nil {
+			userURL.SetRemainingUses(int32(*url.RemainingUses))
+		}
+		userURL.SetClicks(int64(url.Clicks))
+		userURL.SetTags(url.Tags)
+		if !url.CreatedAt.IsZero() {
+			userURL.SetCreatedAt(url.CreatedAt.Format(time.RFC3339))
+		}
+		userURL.SetTitle(url.Title)
 		responseUrls = append(responseUrls, userURL)
 	}
-
-	response := &proto.GetUserURLsResponse{}
-	response.SetUrls(responseUrls)
-	return response, nil
+	return responseUrls
 }
 
 func (s *Server) GetStats(ctx context.Context, _ *proto.GetStatsRequest) (*proto.GetStatsResponse, error) {
@@ -106,15 +301,92 @@ func (s *Server) GetStats(ctx context.Context, _ *proto.GetStatsRequest) (*proto
 			resp := &proto.GetStatsResponse{}
 			resp.SetTotalUsers(int32(stats.Users))
 			resp.SetTotalUrls(int32(stats.Urls))
+			resp.SetTotalClicks(int64(stats.Clicks))
 			return resp, nil
 		}
 	}
 	return nil, status.Error(codes.FailedPrecondition, "No metadata in context")
 }
 
+// shortenError преобразует ошибки проверки параметров создаваемой ссылки в gRPC-статус.
+// Причина отклонения URL политикой назначения передаётся в деталях статуса как ErrorInfo.
+// Для остальных ошибок возвращает nil.
+func shortenError(err error) error {
+	var policyErr *service.PolicyError
+	switch {
+	case errors.As(err, &policyErr):
+		st, detailsErr := status.New(codes.InvalidArgument, err.Error()).WithDetails(&errdetails.ErrorInfo{
+			Reason: policyErr.Reason,
+			Domain: "url-shortener",
+		})
+		if detailsErr != nil {
+			return status.Error(codes.InvalidArgument, err.Error())
+		}
+		return st.Err()
+	case errors.Is(err, service.ErrInvalidAlias), errors.Is(err, service.ErrReservedAlias),
+		errors.Is(err, service.ErrInvalidExpiry), errors.Is(err, service.ErrInvalidMaxUses),
+		errors.Is(err, service.ErrPasswordTooLong), errors.Is(err, service.ErrInvalidTag),
+		errors.Is(err, service.ErrTooManyTags), errors.Is(err, service.ErrTitleTooLong),
+		errors.Is(err, service.ErrInvalidRedirectCode), errors.Is(err, service.ErrInvalidUTM),
+		errors.Is(err, service.ErrUTMTemplateNotFound):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, service.ErrShortURLTaken):
+		return status.Error(codes.AlreadyExists, err.Error())
+	}
+	return nil
+}
+
+// owner возвращает владельца ссылок, от имени которого выполняется вызов пользователя userID:
+// рабочее пространство из метаданных workspace, если у пользователя в нём есть роль role,
+// иначе самого пользователя. Не участнику пространства возвращается NotFound,
+// участнику с недостаточной ролью — PermissionDenied.
+func (s *Server) owner(ctx context.Context, userID, role string) (string, error) {
+	var workspaceID string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if vals := md.Get("workspace"); len(vals) != 0 {
+			workspaceID = vals[0]
+		}
+	}
+	owner, err := s.wsSvc.Authorize(ctx, userID, workspaceID, role)
+	switch {
+	case errors.Is(err, service.ErrWorkspaceNotFound):
+		return "", status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, service.ErrWorkspaceForbidden):
+		return "", status.Error(codes.PermissionDenied, err.Error())
+	case err != nil:
+		return "", status.Error(codes.Internal, err.Error())
+	}
+	return owner, nil
+}
+
+// domain возвращает короткий домен по имени из запроса или InvalidArgument, если домен не настроен.
+func (s *Server) domain(name string) (string, error) {
+	domain, ok := s.cfg.LookupDomain(name)
+	if !ok {
+		return "", status.Error(codes.InvalidArgument, service.ErrUnknownDomain.Error())
+	}
+	return domain, nil
+}
+
+// linkKey возвращает ключ ссылки с кодом code на коротком домене name, как ownedKey в HTTP API,
+// или InvalidArgument, если домен не настроен.
+func (s *Server) linkKey(name, code string) (string, error) {
+	domain, err := s.domain(name)
+	if err != nil {
+		return "", err
+	}
+	return service.LinkKey(domain, code), nil
+}
+
+// utmOf преобразует UTM-параметры из запроса; nil означает, что параметры не заданы.
+func utmOf(u *proto.UTM) service.UTM {
+	return service.UTM{Source: u.GetSource(), Medium: u.GetMedium(), Campaign: u.GetCampaign(), Term: u.GetTerm(), Content: u.GetContent()}
+}
+
 // URLCreator обрабатывает создание URL
 // Возвращает новый короткий URL в виде text/plain.
 // В случае конфликта возвращает 6 AlreadyExists с уже существующим ключом.
+// Невалидный алиас приводит к InvalidArgument, занятый — к AlreadyExists без деталей.
 func (s *Server) URLCreator(ctx context.Context, req *proto.URLCreatorRequest) (*proto.URLCreatorResponse, error) {
 	var userIDStr string
 	if md, ok := metadata.FromIncomingContext(ctx); ok {
@@ -123,8 +395,24 @@ func (s *Server) URLCreator(ctx context.Context, req *proto.URLCreatorRequest) (
 		} else {
 			return nil, status.Error(codes.PermissionDenied, "Unauthorized")
 		}
+		owner, err := s.owner(ctx, userIDStr, service.RoleEditor)
+		if err != nil {
+			return nil, err
+		}
 
-		shortURL, err := s.svc.ShortenURL(ctx, req.GetOriginalUrl(), userIDStr)
+		expiresAt, err := service.ParseExpiry(req.GetExpiresAt(), req.GetTtl(), time.Now())
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		domain, err := s.domain(req.GetDomain())
+		if err != nil {
+			return nil, err
+		}
+
+		shortURL, err := s.svc.ShortenURL(ctx, service.ShortenRequest{OriginalURL: req.GetOriginalUrl(), Domain: domain, Alias: req.GetAlias(), ExpiresAt: expiresAt, MaxUses: int(req.GetMaxUses()), Password: req.GetPassword(), Tags: req.GetTags(), Title: req.GetTitle(), RedirectCode: int(req.GetRedirectCode()), PassQuery: req.GetPassQuery(), PassPath: req.GetPassPath(), UTMTemplate: req.GetUtmTemplate(), UTM: utmOf(req.GetUtm())}, owner)
+		if shortenErr := shortenError(err); shortenErr != nil {
+			return nil, shortenErr
+		}
 		if err != nil && !errors.Is(err, service.ErrConflict) {
 			return nil, status.Error(codes.Internal, err.Error())
 		}
@@ -169,8 +457,24 @@ func (s *Server) URLCreatorJSON(ctx context.Context, req *proto.URLCreatorJSONRe
 		} else {
 			return nil, status.Error(codes.PermissionDenied, "Unauthorized")
 		}
+		owner, err := s.owner(ctx, userIDStr, service.RoleEditor)
+		if err != nil {
+			return nil, err
+		}
 
-		shortURL, err := s.svc.ShortenURL(ctx, req.GetJsonOriginalUrl(), userIDStr)
+		expiresAt, err := service.ParseExpiry(req.GetExpiresAt(), req.GetTtl(), time.Now())
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		domain, err := s.domain(req.GetDomain())
+		if err != nil {
+			return nil, err
+		}
+
+		shortURL, err := s.svc.ShortenURL(ctx, service.ShortenRequest{OriginalURL: req.GetJsonOriginalUrl(), Domain: domain, Alias: req.GetAlias(), ExpiresAt: expiresAt, MaxUses: int(req.GetMaxUses()), Password: req.GetPassword(), Tags: req.GetTags(), Title: req.GetTitle(), RedirectCode: int(req.GetRedirectCode()), PassQuery: req.GetPassQuery(), PassPath: req.GetPassPath(), UTMTemplate: req.GetUtmTemplate(), UTM: utmOf(req.GetUtm())}, owner)
+		if shortenErr := shortenError(err); shortenErr != nil {
+			return nil, shortenErr
+		}
 		if err != nil && !errors.Is(err, service.ErrConflict) {
 			return nil, status.Error(codes.Internal, err.Error())
 		}
@@ -200,33 +504,44 @@ func (s *Server) URLCreatorBatch(ctx context.Context, req *proto.URLCreatorBatch
 		} else {
 			return nil, status.Error(codes.PermissionDenied, "Unauthorized")
 		}
+		owner, err := s.owner(ctx, userIDStr, service.RoleEditor)
+		if err != nil {
+			return nil, err
+		}
 		origs := make([]string, len(req.GetRequests()))
 		for i, item := range req.GetRequests() {
 			origs[i] = item.GetOriginalUrl()
 		}
-		inputURLs := make([]string, len(req.GetRequests()))
+		now := time.Now()
+		inputURLs := make([]service.ShortenRequest, len(req.GetRequests()))
 		for i, request := range req.GetRequests() {
-			inputURLs[i] = request.GetOriginalUrl()
+			expiresAt, err := service.ParseExpiry(request.GetExpiresAt(), request.GetTtl(), now)
+			if err != nil {
+				return nil, status.Error(codes.InvalidArgument, err.Error())
+			}
+			domain, err := s.domain(request.GetDomain())
+			if err != nil {
+				return nil, err
+			}
+			inputURLs[i] = service.ShortenRequest{OriginalURL: request.GetOriginalUrl(), Domain: domain, Alias: request.GetAlias(), ExpiresAt: expiresAt, MaxUses: int(request.GetMaxUses()), Password: request.GetPassword(), Tags: request.GetTags(), Title: request.GetTitle(), RedirectCode: int(request.GetRedirectCode()), PassQuery: request.GetPassQuery(), PassPath: request.GetPassPath(), UTMTemplate: request.GetUtmTemplate(), UTM: utmOf(request.GetUtm())}
 		}
 
-		shortenedURLs, err := s.svc.ShortenURLs(ctx, inputURLs, userIDStr)
+		shortenedURLs, err := s.svc.ShortenURLs(ctx, inputURLs, owner)
+		if shortenErr := shortenError(err); shortenErr != nil {
+			return nil, shortenErr
+		}
 		if err != nil {
 			return nil, status.Error(codes.Internal, err.Error())
 		}
-		shortenedURLsMap := make(map[string]string, len(shortenedURLs))
-		for short, orig := range shortenedURLs {
-			shortenedURLsMap[orig] = short
+		if len(shortenedURLs) != len(req.GetRequests()) {
+			return nil, status.Error(codes.Internal, "Mismatch in shortened URLs")
 		}
 
 		responseURLs := make([]*proto.URLResponse, len(req.GetRequests()))
 		for i, request := range req.GetRequests() {
-			shortURL, ok := shortenedURLsMap[request.GetOriginalUrl()]
-			if !ok {
-				return nil, status.Error(codes.Internal, "Mismatch in shortened URLs")
-			}
 			res := &proto.URLResponse{}
 			res.SetCorrelationId(request.GetCorrelationId())
-			res.SetShortUrl(s.cfg.BaseAddress + "/" + shortURL)
+			res.SetShortUrl(s.cfg.ShortURL(service.SplitLinkKey(shortenedURLs[i])))
 			responseURLs[i] = res
 		}
 		res := &proto.URLCreatorBatchResponse{}
@@ -244,9 +559,14 @@ func (s *Server) DeleteUserURLs(ctx context.Context, req *proto.DeleteUserURLsRe
 		} else {
 			return nil, status.Error(codes.Unauthenticated, "Unauthorized")
 		}
+		owner, err := s.owner(ctx, userIDStr, service.RoleEditor)
+		if err != nil {
+			return nil, err
+		}
 		task := shortenurlhandlers.DeleteTask{
-			URLs:   req.GetUrls(),
-			UserID: userIDStr,
+			URLs:     req.GetUrls(),
+			UserID:   owner,
+			MemberID: userIDStr,
 		}
 
 		shortenurlhandlers.DeleteTaskCh <- task
@@ -255,3 +575,256 @@ func (s *Server) DeleteUserURLs(ctx context.Context, req *proto.DeleteUserURLsRe
 	return nil, status.Error(codes.FailedPrecondition, "No metadata in context")
 
 }
+
+// RestoreUserURLs восстанавливает удалённые пользователем ссылки в пределах льготного периода
+// и возвращает список восстановленных; остальные ссылки пропускаются.
+func (s *Server) RestoreUserURLs(ctx context.Context, req *proto.RestoreUserURLsRequest) (*proto.RestoreUserURLsResponse, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "no metadata in context")
+	}
+	users := md.Get("userID")
+	if len(users) == 0 {
+		return nil, status.Error(codes.Unauthenticated, "no userID provided")
+	}
+
+	owner, err := s.owner(ctx, users[0], service.RoleEditor)
+	if err != nil {
+		return nil, err
+	}
+
+	restored, err := s.delSvc.RestoreURLs(ctx, req.GetUrls(), owner)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	resp := &proto.RestoreUserURLsResponse{}
+	resp.SetRestored(restored)
+	return resp, nil
+}
+
+// GetURLStats возвращает статистику переходов по ссылке текущего пользователя.
+// Для чужих и несуществующих ссылок возвращает NotFound.
+// Поле domain выбирает короткий домен ссылки; ненастроенный домен приводит к InvalidArgument.
+func (s *Server) GetURLStats(ctx context.Context, req *proto.GetURLStatsRequest) (*proto.GetURLStatsResponse, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "no metadata in context")
+	}
+	users := md.Get("userID")
+	if len(users) == 0 {
+		return nil, status.Error(codes.Unauthenticated, "no userID provided")
+	}
+
+	owner, err := s.owner(ctx, users[0], service.RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := s.linkKey(req.GetDomain(), req.GetShortUrl())
+	if err != nil {
+		return nil, err
+	}
+	stats, err := s.clickSvc.GetURLStats(ctx, key, owner)
+	switch {
+	case errors.Is(err, service.ErrURLNotFound):
+		return nil, status.Error(codes.NotFound, err.Error())
+	case err != nil:
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	resp := &proto.GetURLStatsResponse{}
+	resp.SetTotal(int64(stats.Total))
+	resp.SetByDay(toProtoClickCounts(stats.ByDay))
+	resp.SetByReferrer(toProtoClickCounts(stats.ByReferrer))
+	resp.SetByUserAgent(toProtoClickCounts(stats.ByUserAgent))
+	resp.SetByVariant(toProtoClickCounts(stats.ByVariant))
+	return resp, nil
+}
+
+func toProtoClickCounts(counts []service.ClickCount) []*proto.ClickCount {
+	result := make([]*proto.ClickCount, len(counts))
+	for i, c := range counts {
+		count := &proto.ClickCount{}
+		count.SetKey(c.Key)
+		count.SetCount(int64(c.Count))
+		result[i] = count
+	}
+	return result
+}
+
+// UpdateURL меняет назначение ссылки владельца. Если if_version задан и не совпадает
+// с текущей версией, возвращает FailedPrecondition; если у пользователя уже есть ссылка
+// на новый URL — AlreadyExists.
+// Поле domain выбирает короткий домен ссылки; ненастроенный домен приводит к InvalidArgument.
+func (s *Server) UpdateURL(ctx context.Context, req *proto.UpdateURLRequest) (*proto.UpdateURLResponse, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "no metadata in context")
+	}
+	users := md.Get("userID")
+	if len(users) == 0 {
+		return nil, status.Error(codes.Unauthenticated, "no userID provided")
+	}
+
+	owner, err := s.owner(ctx, users[0], service.RoleEditor)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := s.linkKey(req.GetDomain(), req.GetShortUrl())
+	if err != nil {
+		return nil, err
+	}
+	version, err := s.editSvc.UpdateURL(ctx, key, owner, req.GetOriginalUrl(), int(req.GetIfVersion()))
+	if shortenErr := shortenError(err); shortenErr != nil {
+		return nil, shortenErr
+	}
+	switch {
+	case errors.Is(err, service.ErrURLNotFound):
+		return nil, status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, service.ErrVersionMismatch):
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, service.ErrConflict):
+		return nil, status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, service.ErrInvalidURL):
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	case err != nil:
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	resp := &proto.UpdateURLResponse{}
+	resp.SetShortUrl(s.cfg.ShortURL(service.SplitLinkKey(key)))
+	resp.SetOriginalUrl(req.GetOriginalUrl())
+	resp.SetVersion(int32(version))
+	return resp, nil
+}
+
+// GetQRCode возвращает изображение QR-кода полного адреса короткой ссылки.
+// Поле domain выбирает короткий домен ссылки; ненастроенный домен приводит к InvalidArgument.
+func (s *Server) GetQRCode(ctx context.Context, req *proto.GetQRCodeRequest) (*proto.GetQRCodeResponse, error) {
+	opts := service.QROptions{Format: req.GetFormat(), Size: int(req.GetSize()), Level: req.GetLevel()}
+	if req.HasMargin() {
+		margin := int(req.GetMargin())
+		opts.Margin = &margin
+	}
+
+	key, err := s.linkKey(req.GetDomain(), req.GetShortUrl())
+	if err != nil {
+		return nil, err
+	}
+	image, contentType, err := s.qrSvc.QRCode(ctx, key, opts)
+	switch {
+	case errors.Is(err, service.ErrInvalidQROptions):
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, service.ErrURLNotFound), errors.Is(err, service.ErrURLDeleted):
+		return nil, status.Error(codes.NotFound, err.Error())
+	case err != nil:
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	resp := &proto.GetQRCodeResponse{}
+	resp.SetImage(image)
+	resp.SetContentType(contentType)
+	return resp, nil
+}
+
+// ExpandURL возвращает сведения о короткой ссылке, не засчитывая переход по ней.
+// Поле domain выбирает короткий домен ссылки; ненастроенный домен приводит к InvalidArgument.
+func (s *Server) ExpandURL(ctx context.Context, req *proto.ExpandURLRequest) (*proto.ExpandURLResponse, error) {
+	key, err := s.linkKey(req.GetDomain(), req.GetShortUrl())
+	if err != nil {
+		return nil, err
+	}
+	info, err := s.expSvc.ExpandURL(ctx, key)
+	switch {
+	case errors.Is(err, service.ErrURLNotFound):
+		return nil, status.Error(codes.NotFound, err.Error())
+	case err != nil:
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	resp := &proto.ExpandURLResponse{}
+	resp.SetShortUrl(s.cfg.ShortURL(service.SplitLinkKey(info.ShortURL)))
+	resp.SetOriginalUrl(info.OriginalURL)
+	resp.SetTitle(info.Title)
+	if !info.CreatedAt.IsZero() {
+		resp.SetCreatedAt(info.CreatedAt.Format(time.RFC3339))
+	}
+	resp.SetStatus(info.Status)
+	resp.SetPasswordProtected(info.Protected)
+	resp.SetClicks(int64(info.Clicks))
+	return resp, nil
+}
+
+// Register создаёт учётную запись и возвращает её JWT.
+// Некорректный логин или пароль приводят к InvalidArgument, занятый логин — к AlreadyExists.
+func (s *Server) Register(ctx context.Context, req *proto.AuthRequest) (*proto.AuthResponse, error) {
+	account, err := s.accSvc.Register(ctx, callerID(ctx), req.GetLogin(), req.GetPassword())
+	switch {
+	case errors.Is(err, service.ErrInvalidAccount):
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, service.ErrLoginTaken):
+		return nil, status.Error(codes.AlreadyExists, err.Error())
+	case err != nil:
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return s.authResponse(ctx, account)
+}
+
+// Login выполняет вход и возвращает JWT учётной записи.
+// Неверный логин или пароль приводят к Unauthenticated, превышение лимита попыток — к ResourceExhausted.
+func (s *Server) Login(ctx context.Context, req *proto.AuthRequest) (*proto.AuthResponse, error) {
+	account, err := s.accSvc.Login(ctx, callerID(ctx), req.GetLogin(), req.GetPassword(), s.clientIP(ctx))
+	switch {
+	case errors.Is(err, service.ErrInvalidCredentials):
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	case errors.Is(err, service.ErrTooManyAttempts):
+		return nil, status.Error(codes.ResourceExhausted, err.Error())
+	case err != nil:
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return s.authResponse(ctx, account)
+}
+
+// WhoAmI возвращает пользователя вызова и логин его учётной записи.
+func (s *Server) WhoAmI(ctx context.Context, _ *proto.WhoAmIRequest) (*proto.WhoAmIResponse, error) {
+	userID := callerID(ctx)
+	resp := &proto.WhoAmIResponse{}
+	resp.SetUserId(userID)
+
+	account, err := s.accSvc.Account(ctx, userID)
+	switch {
+	case errors.Is(err, service.ErrAccountNotFound):
+		resp.SetAnonymous(true)
+	case err != nil:
+		return nil, status.Error(codes.Internal, err.Error())
+	default:
+		resp.SetLogin(account.Login)
+	}
+	return resp, nil
+}
+
+// authResponse выпускает JWT учётной записи и передаёт его в ответе и в заголовке userID.
+func (s *Server) authResponse(ctx context.Context, account service.Account) (*proto.AuthResponse, error) {
+	token, err := s.tokens.Issue(account.UserID)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "failed to generate JWT")
+	}
+	_ = grpc.SetHeader(ctx, metadata.Pairs("userID", token))
+
+	resp := &proto.AuthResponse{}
+	resp.SetUserId(account.UserID)
+	resp.SetLogin(account.Login)
+	resp.SetToken(token)
+	return resp, nil
+}
+
+// callerID возвращает пользователя вызова, определённого AuthInterceptor.
+func callerID(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if vals := md.Get("userID"); len(vals) != 0 {
+		return vals[0]
+	}
+	return ""
+}
@@ -7,6 +7,7 @@ import (
 	"errors"
 	http2 "github.com/aseptimu/url-shortener/internal/app/handlers/http"
 	"github.com/aseptimu/url-shortener/internal/app/middleware"
+	"github.com/aseptimu/url-shortener/internal/app/service"
 	"github.com/aseptimu/url-shortener/internal/app/utils"
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
@@ -20,11 +21,21 @@ type Server struct {
 	logger *zap.SugaredLogger
 }
 
-func NewServer(addr string, secretKey string, logger *zap.SugaredLogger, h http2.Handlers) *Server {
+// NewServer создаёт сервер на адресе addr. Заголовкам X-Forwarded-For и X-Real-IP верят
+// только от прокси из подсети trustedSubnet; без неё адрес клиента берётся из соединения.
+func NewServer(addr, trustedSubnet string, tokens *middleware.Tokens, keys service.APIKeyAuthenticator, logger *zap.SugaredLogger, h http2.Handlers) *Server {
 	gin.SetMode(gin.ReleaseMode)
 	r := gin.New()
+	var proxies []string
+	if trustedSubnet != "" {
+		proxies = []string{trustedSubnet}
+	}
+	if err := r.SetTrustedProxies(proxies); err != nil {
+		logger.Errorw("Invalid trusted subnet, client addresses are taken from connections", "value", trustedSubnet, "error", err)
+		_ = r.SetTrustedProxies(nil)
+	}
 	logger.Debug("Setting up middleware")
-	r.Use(middleware.MiddlewareLogger(logger), middleware.GzipMiddleware(), middleware.AuthMiddleware(secretKey, logger))
+	r.Use(middleware.MiddlewareLogger(logger), middleware.GzipMiddleware(), middleware.AuthMiddleware(tokens, keys, logger))
 	h.RegisterRoutes(r)
 
 	return &Server{
@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// minAccountPasswordLength — минимальная длина пароля учётной записи в байтах.
+const minAccountPasswordLength = 8
+
+// loginPattern — допустимый логин: 3-64 строчные латинские буквы, цифры и символы '.', '_', '-', '@'.
+var loginPattern = regexp.MustCompile(`^[a-z0-9._@-]{3,64}$`)
+
+// Account описывает учётную запись. UserID совпадает с идентификатором пользователя в JWT,
+// поэтому ссылки учётной записи хранятся так же, как ссылки анонимного пользователя.
+type Account struct {
+	UserID    string
+	Login     string
+	CreatedAt time.Time
+}
+
+// StoreAccounts описывает хранение учётных записей.
+// CreateAccount возвращает ErrLoginTaken, если логин занят; GetAccountByLogin и GetAccount
+// возвращают ErrAccountNotFound для неизвестной учётной записи.
+// MergeUserURLs передаёт ссылки пользователя fromUserID пользователю toUserID;
+// ссылки на адреса, уже сокращённые toUserID на том же домене, остаются у fromUserID.
+type StoreAccounts interface {
+	CreateAccount(ctx context.Context, account Account, passwordHash string) error
+	GetAccountByLogin(ctx context.Context, login string) (Account, string, error)
+	GetAccount(ctx context.Context, userID string) (Account, error)
+	MergeUserURLs(ctx context.Context, fromUserID, toUserID string) error
+}
+
+// AccountManager регистрирует учётные записи, выполняет вход и сообщает, кто текущий пользователь.
+type AccountManager interface {
+	Register(ctx context.Context, userID, login, password string) (Account, error)
+	Login(ctx context.Context, userID, login, password, clientIP string) (Account, error)
+	Account(ctx context.Context, userID string) (Account, error)
+}
+
+// AccountService реализует AccountManager через StoreAccounts.
+type AccountService struct {
+	store   StoreAccounts
+	limiter *passwordLimiter
+}
+
+// NewAccountService создаёт новый AccountService.
+func NewAccountService(store StoreAccounts) *AccountService {
+	return &AccountService{store: store, limiter: newPasswordLimiter(maxPasswordAttempts, passwordAttemptWindow)}
+}
+
+// Register создаёт учётную запись с логином login и паролем password.
+// Если текущий пользователь userID анонимный, учётная запись получает его идентификатор
+// и вместе с ним все его ссылки; иначе для неё создаётся новый идентификатор.
+// Некорректный логин или пароль отклоняются с ErrInvalidAccount, занятый логин — с ErrLoginTaken.
+func (s *AccountService) Register(ctx context.Context, userID, login, password string) (Account, error) {
+	login = strings.ToLower(strings.TrimSpace(login))
+	if !loginPattern.MatchString(login) || len(password) < minAccountPasswordLength || len(password) > maxPasswordLength {
+		return Account{}, ErrInvalidAccount
+	}
+	anonymous, err := s.anonymous(ctx, userID)
+	if err != nil {
+		return Account{}, err
+	}
+	if !anonymous {
+		userID = uuid.New().String()
+	}
+	hash, err := hashPassword(password)
+	if err != nil {
+		return Account{}, err
+	}
+	account := Account{UserID: userID, Login: login, CreatedAt: time.Now().UTC()}
+	if err = s.store.CreateAccount(ctx, account, hash); err != nil {
+		return Account{}, err
+	}
+	return account, nil
+}
+
+// Login проверяет логин и пароль и возвращает учётную запись.
+// Ссылки анонимного пользователя userID переходят в учётную запись; ссылки другой
+// учётной записи, из которой выполнен вход, остаются в ней.
+// Неверный логин или пароль отклоняются с ErrInvalidCredentials, а после превышения
+// лимита неудачных попыток для логина с адреса clientIP — с ErrTooManyAttempts:
+// попытки с чужого адреса не блокируют вход владельцу учётной записи.
+func (s *AccountService) Login(ctx context.Context, userID, login, password, clientIP string) (Account, error) {
+	login = strings.ToLower(strings.TrimSpace(login))
+	key := login + " " + clientIP
+	now := time.Now()
+	if !s.limiter.allow(key, now) {
+		return Account{}, ErrTooManyAttempts
+	}
+	account, hash, err := s.store.GetAccountByLogin(ctx, login)
+	if err != nil && !errors.Is(err, ErrAccountNotFound) {
+		return Account{}, err
+	}
+	if err != nil || !checkPassword(hash, password) {
+		s.limiter.fail(key, now)
+		return Account{}, ErrInvalidCredentials
+	}
+	s.limiter.reset(key)
+
+	if userID != "" && userID != account.UserID {
+		anonymous, err := s.anonymous(ctx, userID)
+		if err != nil {
+			return Account{}, err
+		}
+		if anonymous {
+			if err = s.store.MergeUserURLs(ctx, userID, account.UserID); err != nil {
+				return Account{}, err
+			}
+		}
+	}
+	return account, nil
+}
+
+// Account возвращает учётную запись пользователя userID или ErrAccountNotFound для анонимного пользователя.
+func (s *AccountService) Account(ctx context.Context, userID string) (Account, error) {
+	return s.store.GetAccount(ctx, userID)
+}
+
+// anonymous сообщает, что у пользователя userID нет учётной записи.
+func (s *AccountService) anonymous(ctx context.Context, userID string) (bool, error) {
+	if userID == "" {
+		return false, nil
+	}
+	_, err := s.store.GetAccount(ctx, userID)
+	if errors.Is(err, ErrAccountNotFound) {
+		return true, nil
+	}
+	return false, err
+}
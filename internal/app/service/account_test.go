@@ -0,0 +1,142 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// memAccountStore хранит учётные записи и владельцев ссылок в памяти.
+type memAccountStore struct {
+	accounts map[string]Account
+	hashes   map[string]string
+	// owners — владельцы ссылок по короткому коду.
+	owners map[string]string
+}
+
+func newMemAccountStore() *memAccountStore {
+	return &memAccountStore{accounts: make(map[string]Account), hashes: make(map[string]string), owners: make(map[string]string)}
+}
+
+func (m *memAccountStore) CreateAccount(_ context.Context, account Account, passwordHash string) error {
+	if _, ok := m.accounts[account.Login]; ok {
+		return ErrLoginTaken
+	}
+	m.accounts[account.Login], m.hashes[account.Login] = account, passwordHash
+	return nil
+}
+
+func (m *memAccountStore) GetAccountByLogin(_ context.Context, login string) (Account, string, error) {
+	account, ok := m.accounts[login]
+	if !ok {
+		return Account{}, "", ErrAccountNotFound
+	}
+	return account, m.hashes[login], nil
+}
+
+func (m *memAccountStore) GetAccount(_ context.Context, userID string) (Account, error) {
+	for _, account := range m.accounts {
+		if account.UserID == userID {
+			return account, nil
+		}
+	}
+	return Account{}, ErrAccountNotFound
+}
+
+func (m *memAccountStore) MergeUserURLs(_ context.Context, fromUserID, toUserID string) error {
+	for code, owner := range m.owners {
+		if owner == fromUserID {
+			m.owners[code] = toUserID
+		}
+	}
+	return nil
+}
+
+func TestAccountService(t *testing.T) {
+	store := newMemAccountStore()
+	store.owners["first"] = "anon-1"
+	svc := NewAccountService(store)
+	ctx := context.Background()
+
+	account, err := svc.Register(ctx, "anon-1", " Alice@Example.com ", "correct horse")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if account.UserID != "anon-1" || account.Login != "alice@example.com" {
+		t.Errorf("expected the anonymous identity to become the account, got %+v", account)
+	}
+	if store.hashes[account.Login] == "correct horse" {
+		t.Errorf("expected the password to be hashed")
+	}
+	if _, err = svc.Register(ctx, "anon-2", "alice@example.com", "another password"); !errors.Is(err, ErrLoginTaken) {
+		t.Errorf("expected ErrLoginTaken, got %v", err)
+	}
+
+	// Со второго устройства ссылки нового анонимного пользователя переходят в учётную запись при входе.
+	store.owners["second"] = "anon-2"
+	if _, err = svc.Login(ctx, "anon-2", "alice@example.com", "wrong password", "192.0.2.1"); !errors.Is(err, ErrInvalidCredentials) {
+		t.Errorf("expected ErrInvalidCredentials, got %v", err)
+	}
+	if _, err = svc.Login(ctx, "anon-2", "bob", "correct horse", "192.0.2.1"); !errors.Is(err, ErrInvalidCredentials) {
+		t.Errorf("expected ErrInvalidCredentials for unknown login, got %v", err)
+	}
+	if store.owners["second"] != "anon-2" {
+		t.Errorf("expected failed logins to keep links in place")
+	}
+	if account, err = svc.Login(ctx, "anon-2", "ALICE@example.com", "correct horse", "192.0.2.1"); err != nil || account.UserID != "anon-1" {
+		t.Fatalf("unexpected login result %+v, %v", account, err)
+	}
+	if store.owners["second"] != "anon-1" {
+		t.Errorf("expected anonymous links to be merged into the account, got owner %q", store.owners["second"])
+	}
+
+	// Ссылки другой учётной записи при входе не переносятся.
+	bob, err := svc.Register(ctx, "anon-1", "bob", "bob's password")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if bob.UserID == "anon-1" {
+		t.Errorf("expected a new identity when registering from an account")
+	}
+	if _, err = svc.Login(ctx, "anon-1", "bob", "bob's password", "192.0.2.1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if store.owners["first"] != "anon-1" {
+		t.Errorf("expected account links to stay with the account")
+	}
+
+	if _, err = svc.Account(ctx, "anon-3"); !errors.Is(err, ErrAccountNotFound) {
+		t.Errorf("expected ErrAccountNotFound for an anonymous user, got %v", err)
+	}
+}
+
+func TestAccountService_Register_Invalid(t *testing.T) {
+	svc := NewAccountService(newMemAccountStore())
+	for _, tt := range []struct{ login, password string }{
+		{"al", "long enough"},
+		{"alice smith", "long enough"},
+		{"alice", "short"},
+		{"alice", string(make([]byte, maxPasswordLength+1))},
+	} {
+		if _, err := svc.Register(context.Background(), "anon", tt.login, tt.password); !errors.Is(err, ErrInvalidAccount) {
+			t.Errorf("%q: expected ErrInvalidAccount, got %v", tt.login, err)
+		}
+	}
+}
+
+func TestAccountService_Login_TooManyAttempts(t *testing.T) {
+	svc := NewAccountService(newMemAccountStore())
+	ctx := context.Background()
+	if _, err := svc.Register(ctx, "anon", "alice", "correct horse"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i := 0; i < maxPasswordAttempts; i++ {
+		svc.Login(ctx, "anon", "alice", "wrong password", "192.0.2.1")
+	}
+	if _, err := svc.Login(ctx, "anon", "alice", "correct horse", "192.0.2.1"); !errors.Is(err, ErrTooManyAttempts) {
+		t.Errorf("expected ErrTooManyAttempts, got %v", err)
+	}
+	if _, err := svc.Login(ctx, "anon", "alice", "correct horse", "198.51.100.7"); err != nil {
+		t.Errorf("expected login from another address to succeed, got %v", err)
+	}
+}
@@ -0,0 +1,36 @@
+// Package service содержит бизнес-логику работы с URL.
+package service
+
+import (
+	"regexp"
+	"strings"
+)
+
+var aliasPattern = regexp.MustCompile(`^[a-zA-Z0-9_-]{3,64}$`)
+
+// reservedAliases содержит слова, которые нельзя использовать в качестве алиаса,
+// чтобы короткая ссылка не перекрывала маршруты сервиса.
+var reservedAliases = map[string]struct{}{
+	"api":     {},
+	"ping":    {},
+	"admin":   {},
+	"health":  {},
+	"static":  {},
+	"assets":  {},
+	"login":   {},
+	"logout":  {},
+	"debug":   {},
+	"metrics": {},
+}
+
+// ValidateAlias проверяет, что алиас подходит по набору символов и длине
+// и не совпадает с зарезервированным словом.
+func ValidateAlias(alias string) error {
+	if !aliasPattern.MatchString(alias) {
+		return ErrInvalidAlias
+	}
+	if _, reserved := reservedAliases[strings.ToLower(alias)]; reserved {
+		return ErrReservedAlias
+	}
+	return nil
+}
@@ -0,0 +1,183 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Области действия API-ключей.
+const (
+	// ScopeShorten разрешает создавать и менять ссылки.
+	ScopeShorten = "shorten"
+	// ScopeRead разрешает читать ссылки и их настройки.
+	ScopeRead = "read"
+	// ScopeDelete разрешает удалять и восстанавливать ссылки.
+	ScopeDelete = "delete"
+	// ScopeStats разрешает читать статистику переходов.
+	ScopeStats = "stats"
+)
+
+var knownScopes = []string{ScopeShorten, ScopeRead, ScopeDelete, ScopeStats}
+
+const (
+	// apiKeyPrefix отличает API-ключи от других токенов.
+	apiKeyPrefix = "usk_"
+	// apiKeyDisplayLength — длина начала ключа, которое хранится открыто, чтобы ключ можно было узнать в списке.
+	apiKeyDisplayLength = 12
+	// maxAPIKeyNameLength ограничивает длину названия ключа в символах.
+	maxAPIKeyNameLength = 100
+)
+
+// APIKey описывает API-ключ пользователя. Сам ключ не хранится: хранилище получает
+// только его хеш и начало Prefix для отображения.
+type APIKey struct {
+	ID        string
+	Name      string
+	Prefix    string
+	Scopes    []string
+	CreatedAt time.Time
+}
+
+// APIKeyIdentity — пользователь и области действия, которые даёт предъявленный API-ключ.
+type APIKeyIdentity struct {
+	UserID string
+	KeyID  string
+	Scopes []string
+}
+
+// Allows сообщает, входит ли scope в области действия ключа.
+func (i APIKeyIdentity) Allows(scope string) bool {
+	return slices.Contains(i.Scopes, scope)
+}
+
+// StoreAPIKeys описывает хранение API-ключей.
+// GetAPIKeyByHash возвращает ErrAPIKeyNotFound для неизвестного хеша,
+// DeleteAPIKey — если у пользователя нет ключа с таким ID.
+type StoreAPIKeys interface {
+	CreateAPIKey(ctx context.Context, userID string, key APIKey, hash string) error
+	GetAPIKeys(ctx context.Context, userID string) ([]APIKey, error)
+	GetAPIKeyByHash(ctx context.Context, hash string) (APIKey, string, error)
+	DeleteAPIKey(ctx context.Context, userID, id string) error
+}
+
+// APIKeyAuthenticator проверяет предъявленные API-ключи.
+type APIKeyAuthenticator interface {
+	Authenticate(ctx context.Context, token string) (APIKeyIdentity, error)
+}
+
+// APIKeyManager позволяет пользователю выпускать, просматривать и отзывать свои API-ключи.
+type APIKeyManager interface {
+	CreateKey(ctx context.Context, userID, name string, scopes []string) (APIKey, string, error)
+	ListKeys(ctx context.Context, userID string) ([]APIKey, error)
+	RevokeKey(ctx context.Context, userID, id string) error
+}
+
+// APIKeyService реализует APIKeyAuthenticator и APIKeyManager через StoreAPIKeys.
+type APIKeyService struct {
+	store StoreAPIKeys
+}
+
+// NewAPIKeyService создаёт новый APIKeyService.
+func NewAPIKeyService(store StoreAPIKeys) *APIKeyService {
+	return &APIKeyService{store: store}
+}
+
+// IsAPIKey сообщает, похож ли token на API-ключ, а не на JWT.
+func IsAPIKey(token string) bool {
+	return strings.HasPrefix(token, apiKeyPrefix)
+}
+
+// hashAPIKey возвращает хеш, под которым хранится ключ. Ключ содержит 256 случайных бит,
+// поэтому медленный хеш не нужен, а быстрый позволяет искать ключ по хешу.
+func hashAPIKey(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// CreateKey выпускает ключ с названием name и областями действия scopes.
+// Возвращает описание ключа и сам ключ: он показывается только один раз.
+// Пустой список областей, неизвестная область и некорректное название отклоняются с ErrInvalidAPIKey.
+func (s *APIKeyService) CreateKey(ctx context.Context, userID, name string, scopes []string) (APIKey, string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || utf8.RuneCountInString(name) > maxAPIKeyNameLength || len(scopes) == 0 {
+		return APIKey{}, "", ErrInvalidAPIKey
+	}
+	normalized := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		scope = strings.ToLower(strings.TrimSpace(scope))
+		if !slices.Contains(knownScopes, scope) {
+			return APIKey{}, "", ErrInvalidAPIKey
+		}
+		if !slices.Contains(normalized, scope) {
+			normalized = append(normalized, scope)
+		}
+	}
+	slices.Sort(normalized)
+
+	id, err := randomToken(9)
+	if err != nil {
+		return APIKey{}, "", err
+	}
+	secret, err := randomToken(32)
+	if err != nil {
+		return APIKey{}, "", err
+	}
+	token := apiKeyPrefix + secret
+	key := APIKey{
+		ID:        id,
+		Name:      name,
+		Prefix:    token[:apiKeyDisplayLength],
+		Scopes:    normalized,
+		CreatedAt: time.Now().UTC(),
+	}
+	if err = s.store.CreateAPIKey(ctx, userID, key, hashAPIKey(token)); err != nil {
+		return APIKey{}, "", err
+	}
+	return key, token, nil
+}
+
+// ListKeys возвращает ключи пользователя без самих ключей.
+func (s *APIKeyService) ListKeys(ctx context.Context, userID string) ([]APIKey, error) {
+	keys, err := s.store.GetAPIKeys(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if keys == nil {
+		keys = []APIKey{}
+	}
+	return keys, nil
+}
+
+// RevokeKey отзывает ключ пользователя: после этого он больше не принимается.
+func (s *APIKeyService) RevokeKey(ctx context.Context, userID, id string) error {
+	return s.store.DeleteAPIKey(ctx, userID, id)
+}
+
+// Authenticate находит пользователя и области действия по предъявленному ключу.
+// Для неизвестного или отозванного ключа возвращает ErrAPIKeyNotFound.
+func (s *APIKeyService) Authenticate(ctx context.Context, token string) (APIKeyIdentity, error) {
+	if !IsAPIKey(token) {
+		return APIKeyIdentity{}, ErrAPIKeyNotFound
+	}
+	key, userID, err := s.store.GetAPIKeyByHash(ctx, hashAPIKey(token))
+	if err != nil {
+		return APIKeyIdentity{}, err
+	}
+	return APIKeyIdentity{UserID: userID, KeyID: key.ID, Scopes: key.Scopes}, nil
+}
+
+// randomToken возвращает n случайных байт в base64url без выравнивания.
+func randomToken(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// memAPIKeyStore хранит API-ключи по хешу в памяти.
+type memAPIKeyStore struct {
+	keys   map[string]APIKey
+	owners map[string]string
+}
+
+func (m *memAPIKeyStore) CreateAPIKey(_ context.Context, userID string, key APIKey, hash string) error {
+	m.keys[hash], m.owners[hash] = key, userID
+	return nil
+}
+
+func (m *memAPIKeyStore) GetAPIKeys(_ context.Context, userID string) ([]APIKey, error) {
+	var result []APIKey
+	for hash, key := range m.keys {
+		if m.owners[hash] == userID {
+			result = append(result, key)
+		}
+	}
+	return result, nil
+}
+
+func (m *memAPIKeyStore) GetAPIKeyByHash(_ context.Context, hash string) (APIKey, string, error) {
+	key, ok := m.keys[hash]
+	if !ok {
+		return APIKey{}, "", ErrAPIKeyNotFound
+	}
+	return key, m.owners[hash], nil
+}
+
+func (m *memAPIKeyStore) DeleteAPIKey(_ context.Context, userID, id string) error {
+	for hash, key := range m.keys {
+		if key.ID == id && m.owners[hash] == userID {
+			delete(m.keys, hash)
+			return nil
+		}
+	}
+	return ErrAPIKeyNotFound
+}
+
+func TestAPIKeyService_Lifecycle(t *testing.T) {
+	store := &memAPIKeyStore{keys: make(map[string]APIKey), owners: make(map[string]string)}
+	svc := NewAPIKeyService(store)
+	ctx := context.Background()
+
+	key, token, err := svc.CreateKey(ctx, "alice", " CI ", []string{"stats", "Shorten", "stats"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if key.Name != "CI" || strings.Join(key.Scopes, ",") != "shorten,stats" {
+		t.Errorf("unexpected key %+v", key)
+	}
+	if !IsAPIKey(token) || !strings.HasPrefix(token, key.Prefix) {
+		t.Errorf("unexpected token %q for prefix %q", token, key.Prefix)
+	}
+	for hash := range store.keys {
+		if strings.Contains(hash, token) {
+			t.Errorf("expected only the hash of the key to be stored")
+		}
+	}
+
+	identity, err := svc.Authenticate(ctx, token)
+	if err != nil || identity.UserID != "alice" || !identity.Allows(ScopeStats) || identity.Allows(ScopeDelete) {
+		t.Errorf("unexpected identity %+v, %v", identity, err)
+	}
+	if _, err = svc.Authenticate(ctx, token+"x"); !errors.Is(err, ErrAPIKeyNotFound) {
+		t.Errorf("expected ErrAPIKeyNotFound for a wrong key, got %v", err)
+	}
+
+	if err = svc.RevokeKey(ctx, "bob", key.ID); !errors.Is(err, ErrAPIKeyNotFound) {
+		t.Errorf("expected another user to be unable to revoke the key, got %v", err)
+	}
+	if err = svc.RevokeKey(ctx, "alice", key.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err = svc.Authenticate(ctx, token); !errors.Is(err, ErrAPIKeyNotFound) {
+		t.Errorf("expected revoked key to be rejected, got %v", err)
+	}
+	if keys, err := svc.ListKeys(ctx, "alice"); err != nil || len(keys) != 0 {
+		t.Errorf("expected no keys, got %v, %v", keys, err)
+	}
+}
+
+func TestAPIKeyService_CreateKey_Invalid(t *testing.T) {
+	svc := NewAPIKeyService(&memAPIKeyStore{keys: make(map[string]APIKey), owners: make(map[string]string)})
+	tests := []struct {
+		name   string
+		key    string
+		scopes []string
+	}{
+		{name: "empty name", key: "  ", scopes: []string{ScopeRead}},
+		{name: "long name", key: strings.Repeat("x", maxAPIKeyNameLength+1), scopes: []string{ScopeRead}},
+		{name: "no scopes", key: "CI"},
+		{name: "unknown scope", key: "CI", scopes: []string{ScopeRead, "admin"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := svc.CreateKey(context.Background(), "alice", tt.key, tt.scopes); !errors.Is(err, ErrInvalidAPIKey) {
+				t.Errorf("expected ErrInvalidAPIKey, got %v", err)
+			}
+		})
+	}
+}
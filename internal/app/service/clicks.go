@@ -0,0 +1,247 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ClickEvent описывает один переход по короткой ссылке.
+// IPHash содержит солёный SHA-256 от IP-адреса клиента: сам адрес не сохраняется.
+// Variant — имя выданного варианта A/B-сплита; пусто для ссылок без сплита.
+type ClickEvent struct {
+	ShortURL  string
+	ClickedAt time.Time
+	Referrer  string
+	UserAgent string
+	IPHash    string
+	Variant   string
+}
+
+// Периоды агрегации переходов в ClickRollup.
+const (
+	ClickPeriodHour = "hour"
+	ClickPeriodDay  = "day"
+)
+
+// ClickRollup — приращение счётчика переходов по ссылке за час или сутки,
+// начинающиеся в BucketStart (UTC).
+type ClickRollup struct {
+	ShortURL    string
+	Period      string
+	BucketStart time.Time
+	Count       int
+}
+
+// RollupClicks группирует переходы в почасовые и суточные приращения счётчиков
+// и упорядочивает их по ссылке, периоду и началу интервала. Единый порядок нужен, чтобы
+// параллельные сбросы агрегатора блокировали строки агрегатов в одной последовательности
+// и не попадали во взаимную блокировку.
+func RollupClicks(clicks []ClickEvent) []ClickRollup {
+	index := make(map[ClickRollup]int)
+	var rollups []ClickRollup
+	add := func(key ClickRollup) {
+		if i, ok := index[key]; ok {
+			rollups[i].Count++
+			return
+		}
+		index[key] = len(rollups)
+		key.Count = 1
+		rollups = append(rollups, key)
+	}
+	for _, click := range clicks {
+		at := click.ClickedAt.UTC()
+		add(ClickRollup{ShortURL: click.ShortURL, Period: ClickPeriodHour, BucketStart: at.Truncate(time.Hour)})
+		add(ClickRollup{ShortURL: click.ShortURL, Period: ClickPeriodDay, BucketStart: time.Date(at.Year(), at.Month(), at.Day(), 0, 0, 0, 0, time.UTC)})
+	}
+	sort.Slice(rollups, func(i, j int) bool {
+		a, b := rollups[i], rollups[j]
+		if a.ShortURL != b.ShortURL {
+			return a.ShortURL < b.ShortURL
+		}
+		if a.Period != b.Period {
+			return a.Period < b.Period
+		}
+		return a.BucketStart.Before(b.BucketStart)
+	})
+	return rollups
+}
+
+// StoreClickTracker описывает методы хранилища для записи и чтения переходов.
+type StoreClickTracker interface {
+	// SaveClicks сохраняет пачку переходов вместе с приращениями агрегатов по ним.
+	SaveClicks(ctx context.Context, clicks []ClickEvent, rollups []ClickRollup) error
+	GetClicks(ctx context.Context, shortURL string) ([]ClickEvent, error)
+	// GetOwner возвращает userID владельца ссылки или ErrURLNotFound.
+	GetOwner(ctx context.Context, shortURL string) (string, error)
+}
+
+// ClickCount — число переходов для одного значения разреза статистики.
+type ClickCount struct {
+	Key   string
+	Count int
+}
+
+// ClickStats содержит итоговую статистику переходов по ссылке.
+// ByVariant учитывает только переходы, при которых был выдан вариант A/B-сплита.
+type ClickStats struct {
+	Total       int
+	ByDay       []ClickCount
+	ByReferrer  []ClickCount
+	ByUserAgent []ClickCount
+	ByVariant   []ClickCount
+}
+
+// ClickTracker предоставляет методы записи переходов и получения статистики по ним.
+type ClickTracker interface {
+	RecordClick(ctx context.Context, shortURL, referrer, userAgent, clientIP, variant string) error
+	GetURLStats(ctx context.Context, shortURL, userID string) (ClickStats, error)
+	GetClicks(ctx context.Context, shortURL, userID string) ([]ClickEvent, error)
+}
+
+// ClickService реализует ClickTracker поверх StoreClickTracker.
+// Переходы не пишутся в хранилище напрямую, а ставятся в очередь,
+// которую разбирает workers.StartClickAggregator.
+type ClickService struct {
+	store  StoreClickTracker
+	queue  chan<- ClickEvent
+	ipSalt string
+}
+
+// NewClickService создаёт ClickService, отправляющий переходы в queue.
+// ipSalt добавляется к IP-адресу перед хэшированием.
+func NewClickService(store StoreClickTracker, queue chan<- ClickEvent, ipSalt string) *ClickService {
+	return &ClickService{store: store, queue: queue, ipSalt: ipSalt}
+}
+
+// RecordClick ставит переход по shortURL в очередь, не блокируя вызывающего.
+// variant — имя выданного варианта A/B-сплита или пустая строка.
+// Если очередь заполнена, событие отбрасывается и возвращается ErrClickQueueFull.
+func (s *ClickService) RecordClick(_ context.Context, shortURL, referrer, userAgent, clientIP, variant string) error {
+	click := ClickEvent{
+		ShortURL:  shortURL,
+		ClickedAt: time.Now().UTC(),
+		Referrer:  referrer,
+		UserAgent: userAgent,
+		IPHash:    s.hashIP(clientIP),
+		Variant:   variant,
+	}
+	select {
+	case s.queue <- click:
+		return nil
+	default:
+		return ErrClickQueueFull
+	}
+}
+
+// GetClicks возвращает все переходы по ссылке, если она принадлежит userID.
+// Для чужих ссылок возвращает ErrURLNotFound, чтобы не раскрывать их существование.
+func (s *ClickService) GetClicks(ctx context.Context, shortURL, userID string) ([]ClickEvent, error) {
+	owner, err := s.store.GetOwner(ctx, shortURL)
+	if err != nil {
+		return nil, err
+	}
+	if owner != userID {
+		return nil, ErrURLNotFound
+	}
+	return s.store.GetClicks(ctx, shortURL)
+}
+
+// GetURLStats возвращает статистику переходов по ссылке пользователя userID
+// с разбивкой по дням, источникам перехода, семействам браузеров и вариантам A/B-сплита.
+func (s *ClickService) GetURLStats(ctx context.Context, shortURL, userID string) (ClickStats, error) {
+	clicks, err := s.GetClicks(ctx, shortURL, userID)
+	if err != nil {
+		return ClickStats{}, err
+	}
+
+	byDay := make(map[string]int)
+	byReferrer := make(map[string]int)
+	byUserAgent := make(map[string]int)
+	byVariant := make(map[string]int)
+	for _, click := range clicks {
+		byDay[click.ClickedAt.UTC().Format(time.DateOnly)]++
+		byReferrer[referrerHost(click.Referrer)]++
+		byUserAgent[userAgentFamily(click.UserAgent)]++
+		if click.Variant != "" {
+			byVariant[click.Variant]++
+		}
+	}
+
+	return ClickStats{
+		Total:       len(clicks),
+		ByDay:       sortedCounts(byDay, true),
+		ByReferrer:  sortedCounts(byReferrer, false),
+		ByUserAgent: sortedCounts(byUserAgent, false),
+		ByVariant:   sortedCounts(byVariant, true),
+	}, nil
+}
+
+func (s *ClickService) hashIP(ip string) string {
+	if ip == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(s.ipSalt + ip))
+	return hex.EncodeToString(sum[:])
+}
+
+// referrerHost сводит referrer к имени хоста; пустой referrer считается прямым переходом.
+func referrerHost(referrer string) string {
+	if referrer == "" {
+		return "direct"
+	}
+	u, err := url.Parse(referrer)
+	if err != nil || u.Host == "" {
+		return "unknown"
+	}
+	return strings.ToLower(u.Hostname())
+}
+
+// userAgentFamilies перечисляет маркеры User-Agent в порядке проверки:
+// Edge и Opera содержат "Chrome", а Chrome — "Safari", поэтому они идут раньше.
+var userAgentFamilies = []struct {
+	marker string
+	family string
+}{
+	{"bot", "Bot"},
+	{"spider", "Bot"},
+	{"curl/", "curl"},
+	{"edg/", "Edge"},
+	{"opr/", "Opera"},
+	{"firefox/", "Firefox"},
+	{"chrome/", "Chrome"},
+	{"safari/", "Safari"},
+}
+
+// userAgentFamily возвращает семейство браузера по строке User-Agent.
+func userAgentFamily(userAgent string) string {
+	if userAgent == "" {
+		return "unknown"
+	}
+	ua := strings.ToLower(userAgent)
+	for _, f := range userAgentFamilies {
+		if strings.Contains(ua, f.marker) {
+			return f.family
+		}
+	}
+	return "other"
+}
+
+// sortedCounts преобразует карту счётчиков в срез: по ключу, если byKey, иначе по убыванию числа.
+func sortedCounts(counts map[string]int, byKey bool) []ClickCount {
+	result := make([]ClickCount, 0, len(counts))
+	for k, v := range counts {
+		result = append(result, ClickCount{Key: k, Count: v})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if !byKey && result[i].Count != result[j].Count {
+			return result[i].Count > result[j].Count
+		}
+		return result[i].Key < result[j].Key
+	})
+	return result
+}
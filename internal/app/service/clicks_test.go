@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type memClickStore struct {
+	owners map[string]string
+	clicks []ClickEvent
+}
+
+func (s *memClickStore) SaveClicks(_ context.Context, clicks []ClickEvent, _ []ClickRollup) error {
+	s.clicks = append(s.clicks, clicks...)
+	return nil
+}
+
+func (s *memClickStore) GetClicks(_ context.Context, shortURL string) ([]ClickEvent, error) {
+	var result []ClickEvent
+	for _, c := range s.clicks {
+		if c.ShortURL == shortURL {
+			result = append(result, c)
+		}
+	}
+	return result, nil
+}
+
+func (s *memClickStore) GetOwner(_ context.Context, shortURL string) (string, error) {
+	owner, ok := s.owners[shortURL]
+	if !ok {
+		return "", ErrURLNotFound
+	}
+	return owner, nil
+}
+
+func TestClickService_RecordClick(t *testing.T) {
+	queue := make(chan ClickEvent, 1)
+	svc := NewClickService(&memClickStore{}, queue, "salt")
+	if err := svc.RecordClick(context.Background(), "abc", "", "", "10.0.0.1", ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := svc.RecordClick(context.Background(), "abc", "", "", "10.0.0.1", ""); !errors.Is(err, ErrClickQueueFull) {
+		t.Fatalf("expected ErrClickQueueFull, got %v", err)
+	}
+
+	click := <-queue
+	if click.ShortURL != "abc" {
+		t.Errorf("expected click for abc, got %q", click.ShortURL)
+	}
+	if hash := click.IPHash; hash == "10.0.0.1" || len(hash) != 64 {
+		t.Errorf("expected sha256 hex hash, got %q", hash)
+	}
+}
+
+func TestRollupClicks(t *testing.T) {
+	base := time.Date(2025, 1, 1, 10, 15, 0, 0, time.UTC)
+	clicks := []ClickEvent{
+		{ShortURL: "xyz", ClickedAt: base},
+		{ShortURL: "abc", ClickedAt: base.Add(time.Hour)},
+		{ShortURL: "abc", ClickedAt: base},
+		{ShortURL: "abc", ClickedAt: base.Add(30 * time.Minute)},
+	}
+	hour := base.Truncate(time.Hour)
+	day := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	want := []ClickRollup{
+		{ShortURL: "abc", Period: ClickPeriodDay, BucketStart: day, Count: 3},
+		{ShortURL: "abc", Period: ClickPeriodHour, BucketStart: hour, Count: 2},
+		{ShortURL: "abc", Period: ClickPeriodHour, BucketStart: hour.Add(time.Hour), Count: 1},
+		{ShortURL: "xyz", Period: ClickPeriodDay, BucketStart: day, Count: 1},
+		{ShortURL: "xyz", Period: ClickPeriodHour, BucketStart: hour, Count: 1},
+	}
+	if got := RollupClicks(clicks); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+}
+
+func TestClickService_GetURLStats(t *testing.T) {
+	day1 := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	day2 := day1.Add(24 * time.Hour)
+	store := &memClickStore{
+		owners: map[string]string{"abc": "user1"},
+		clicks: []ClickEvent{
+			{ShortURL: "abc", ClickedAt: day1, Referrer: "https://News.example.com/x", UserAgent: "Mozilla/5.0 Firefox/120.0", Variant: "a"},
+			{ShortURL: "abc", ClickedAt: day2, UserAgent: "Mozilla/5.0 Chrome/120.0 Safari/537.36 Edg/120.0", Variant: "b"},
+			{ShortURL: "abc", ClickedAt: day2, Referrer: "https://news.example.com/y", UserAgent: "Mozilla/5.0 Chrome/120.0 Safari/537.36"},
+			{ShortURL: "other", ClickedAt: day1},
+		},
+	}
+	svc := NewClickService(store, nil, "")
+
+	stats, err := svc.GetURLStats(context.Background(), "abc", "user1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := ClickStats{
+		Total:       3,
+		ByDay:       []ClickCount{{"2025-01-01", 1}, {"2025-01-02", 2}},
+		ByReferrer:  []ClickCount{{"news.example.com", 2}, {"direct", 1}},
+		ByUserAgent: []ClickCount{{"Chrome", 1}, {"Edge", 1}, {"Firefox", 1}},
+		ByVariant:   []ClickCount{{"a", 1}, {"b", 1}},
+	}
+	if !reflect.DeepEqual(stats, want) {
+		t.Errorf("expected %+v, got %+v", want, stats)
+	}
+
+	if _, err := svc.GetURLStats(context.Background(), "abc", "user2"); !errors.Is(err, ErrURLNotFound) {
+		t.Errorf("expected ErrURLNotFound for another user, got %v", err)
+	}
+}
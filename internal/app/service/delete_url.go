@@ -3,29 +3,51 @@ package service
 
 import (
 	"context"
+	"time"
 )
 
-// StoreURLDeleter описывает метод пакетного удаления URL из хранилища.
+// StoreURLDeleter описывает пакетное мягкое удаление URL, их восстановление и окончательную очистку.
 type StoreURLDeleter interface {
 	BatchDelete(ctx context.Context, shortURLs []string, userID string) error
+	// RestoreURLs снимает пометку удаления со ссылок userID, удалённых позже deletedAfter,
+	// и возвращает восстановленные shortURLs.
+	RestoreURLs(ctx context.Context, shortURLs []string, userID string, deletedAfter time.Time) ([]string, error)
+	// PurgeDeletedURLs безвозвратно удаляет ссылки, помеченные удалёнными не позже deletedBefore,
+	// и возвращает их количество.
+	PurgeDeletedURLs(ctx context.Context, deletedBefore time.Time) (int, error)
 }
 
-// URLDeleter предоставляет возможность удаления URL.
+// URLDeleter предоставляет возможность удаления URL и их восстановления в течение льготного периода.
 type URLDeleter interface {
 	DeleteURLs(ctx context.Context, shortURLs []string, userID string) error
+	RestoreURLs(ctx context.Context, shortURLs []string, userID string) ([]string, error)
 }
 
 // DeleteURLService реализует URLDeleter через StoreURLDeleter.
 type DeleteURLService struct {
-	store StoreURLDeleter
+	store       StoreURLDeleter
+	gracePeriod time.Duration
 }
 
 // NewURLDeleter создаёт новый сервис для удаления URL на основе переданного хранилища.
-func NewURLDeleter(store StoreURLDeleter) *DeleteURLService {
-	return &DeleteURLService{store: store}
+// Удалённые ссылки можно восстановить в течение gracePeriod, после чего PurgeDeletedURLs
+// удаляет их окончательно.
+func NewURLDeleter(store StoreURLDeleter, gracePeriod time.Duration) *DeleteURLService {
+	return &DeleteURLService{store: store, gracePeriod: gracePeriod}
 }
 
 // DeleteURLs вызывает BatchDelete у внутреннего хранилища для удаления списка shortURLs.
 func (s *DeleteURLService) DeleteURLs(ctx context.Context, shortURLs []string, userID string) error {
 	return s.store.BatchDelete(ctx, shortURLs, userID)
 }
+
+// RestoreURLs восстанавливает ссылки пользователя, удалённые не раньше чем gracePeriod назад.
+// Чужие, не удалённые и удалённые раньше ссылки пропускаются и не попадают в результат.
+func (s *DeleteURLService) RestoreURLs(ctx context.Context, shortURLs []string, userID string) ([]string, error) {
+	return s.store.RestoreURLs(ctx, shortURLs, userID, time.Now().Add(-s.gracePeriod))
+}
+
+// PurgeDeletedURLs безвозвратно удаляет ссылки, льготный период которых истёк.
+func (s *DeleteURLService) PurgeDeletedURLs(ctx context.Context) (int, error) {
+	return s.store.PurgeDeletedURLs(ctx, time.Now().Add(-s.gracePeriod))
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"reflect"
 	"testing"
+	"time"
 )
 
 type stubStoreDelete struct {
@@ -12,6 +13,7 @@ type stubStoreDelete struct {
 	gotURLs   []string
 	gotUserID string
 	errToRet  error
+	gotCutoff time.Time
 }
 
 func (s *stubStoreDelete) BatchDelete(ctx context.Context, shortURLs []string, userID string) error {
@@ -21,9 +23,21 @@ func (s *stubStoreDelete) BatchDelete(ctx context.Context, shortURLs []string, u
 	return s.errToRet
 }
 
+func (s *stubStoreDelete) RestoreURLs(_ context.Context, shortURLs []string, userID string, deletedAfter time.Time) ([]string, error) {
+	s.gotURLs = append([]string(nil), shortURLs...)
+	s.gotUserID = userID
+	s.gotCutoff = deletedAfter
+	return shortURLs, s.errToRet
+}
+
+func (s *stubStoreDelete) PurgeDeletedURLs(_ context.Context, deletedBefore time.Time) (int, error) {
+	s.gotCutoff = deletedBefore
+	return 1, s.errToRet
+}
+
 func TestDeleteURLs_Success(t *testing.T) {
 	stub := &stubStoreDelete{errToRet: nil}
-	svc := NewURLDeleter(stub)
+	svc := NewURLDeleter(stub, time.Hour)
 
 	urls := []string{"u1", "u2"}
 	user := "user42"
@@ -46,7 +60,7 @@ func TestDeleteURLs_Success(t *testing.T) {
 func TestDeleteURLs_Error(t *testing.T) {
 	expectedErr := errors.New("delete failed")
 	stub := &stubStoreDelete{errToRet: expectedErr}
-	svc := NewURLDeleter(stub)
+	svc := NewURLDeleter(stub, time.Hour)
 
 	urls := []string{"only-one"}
 	user := "uXYZ"
@@ -59,3 +73,27 @@ func TestDeleteURLs_Error(t *testing.T) {
 		t.Error("expected BatchDelete to be called even on error")
 	}
 }
+
+func TestRestoreURLs_GracePeriod(t *testing.T) {
+	stub := &stubStoreDelete{}
+	svc := NewURLDeleter(stub, time.Hour)
+
+	before := time.Now()
+	restored, err := svc.RestoreURLs(context.Background(), []string{"u1"}, "user42")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(restored, []string{"u1"}) || stub.gotUserID != "user42" {
+		t.Errorf("unexpected restore call: %v for %q", restored, stub.gotUserID)
+	}
+	if cutoff := before.Add(-time.Hour); stub.gotCutoff.Before(cutoff) || stub.gotCutoff.After(time.Now().Add(-time.Hour)) {
+		t.Errorf("expected cutoff about an hour ago, got %v", stub.gotCutoff)
+	}
+
+	if _, err = svc.PurgeDeletedURLs(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stub.gotCutoff.After(time.Now().Add(-time.Hour)) {
+		t.Errorf("purge must only remove links deleted before the grace period, got cutoff %v", stub.gotCutoff)
+	}
+}
@@ -0,0 +1,42 @@
+package service
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// DomainVerificationPath — путь файла с токеном подтверждения владения коротким доменом.
+const DomainVerificationPath = "/.well-known/url-shortener-verification"
+
+// DomainResolver возвращает базовый адрес коротких ссылок домена; пустой домен — основной.
+type DomainResolver interface {
+	BaseURL(domain string) string
+}
+
+// LinkKey возвращает ключ, под которым хранится ссылка с кодом code на домене domain.
+// Ссылки основного домена хранятся под своим кодом, ссылки дополнительных доменов —
+// под "домен/код", поэтому один и тот же код на разных доменах относится к разным ссылкам.
+func LinkKey(domain, code string) string {
+	if domain == "" {
+		return code
+	}
+	return domain + "/" + code
+}
+
+// SplitLinkKey разбирает ключ ссылки на домен и код; для основного домена домен пуст.
+func SplitLinkKey(key string) (domain, code string) {
+	if domain, code, ok := strings.Cut(key, "/"); ok {
+		return domain, code
+	}
+	return "", key
+}
+
+// DomainVerificationToken возвращает токен подтверждения владения доменом domain.
+// Токен не меняется, пока не меняется secret, и не позволяет восстановить secret.
+func DomainVerificationToken(secret, domain string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("domain-verification:" + domain))
+	return hex.EncodeToString(mac.Sum(nil))
+}
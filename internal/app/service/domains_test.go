@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestLinkKey(t *testing.T) {
+	tests := []struct {
+		domain, code, key string
+	}{
+		{"", "abc", "abc"},
+		{"go.brand.com", "abc", "go.brand.com/abc"},
+	}
+	for _, tt := range tests {
+		if got := LinkKey(tt.domain, tt.code); got != tt.key {
+			t.Errorf("LinkKey(%q, %q): expected %q, got %q", tt.domain, tt.code, tt.key, got)
+		}
+		if domain, code := SplitLinkKey(tt.key); domain != tt.domain || code != tt.code {
+			t.Errorf("SplitLinkKey(%q): expected %q, %q, got %q, %q", tt.key, tt.domain, tt.code, domain, code)
+		}
+	}
+}
+
+func TestShortenURL_DomainNamespaces(t *testing.T) {
+	stored := make(map[string]string)
+	store := &stubStore{
+		setFn: func(ctx context.Context, shortURL, originalURL string) (string, error) {
+			if _, taken := stored[shortURL]; taken {
+				return "", ErrShortURLTaken
+			}
+			stored[shortURL] = originalURL
+			return shortURL, nil
+		},
+	}
+	svc := NewURLService(store, NewRandomCodeGenerator(DefaultShortCodeLength), nil, nil, nil)
+	ctx := context.Background()
+
+	for domain, want := range map[string]string{"": "sale", "go.brand.com": "go.brand.com/sale"} {
+		got, err := svc.ShortenURL(ctx, ShortenRequest{OriginalURL: "https://example.com", Alias: "sale", Domain: domain}, "")
+		if err != nil || got != want {
+			t.Errorf("domain %q: expected %q, got %q, %v", domain, want, got, err)
+		}
+	}
+	_, err := svc.ShortenURL(ctx, ShortenRequest{OriginalURL: "https://example.org", Alias: "sale", Domain: "go.brand.com"}, "")
+	if !errors.Is(err, ErrShortURLTaken) {
+		t.Errorf("expected ErrShortURLTaken, got %v", err)
+	}
+}
+
+func TestShortenURLs_DedupPerDomain(t *testing.T) {
+	var passed map[string]string
+	store := &stubStore{
+		batchFn: func(ctx context.Context, urls map[string]string) (map[string]string, error) {
+			passed = urls
+			return urls, nil
+		},
+	}
+	svc := NewURLService(store, NewRandomCodeGenerator(DefaultShortCodeLength), nil, nil, nil)
+	got, err := svc.ShortenURLs(context.Background(), []ShortenRequest{
+		{OriginalURL: "https://example.com"},
+		{OriginalURL: "https://example.com", Domain: "go.brand.com"},
+		{OriginalURL: "https://example.com", Domain: "go.brand.com"},
+	}, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(passed) != 2 {
+		t.Fatalf("expected one link per domain, store got %v", passed)
+	}
+	if got[0] == got[1] || got[1] != got[2] {
+		t.Errorf("expected distinct links per domain and shared link within a domain, got %v", got)
+	}
+	if domain, _ := SplitLinkKey(got[1]); domain != "go.brand.com" {
+		t.Errorf("expected link on go.brand.com, got %q", got[1])
+	}
+}
+
+func TestDestinationPolicy_ShortDomains(t *testing.T) {
+	policy, err := NewDestinationPolicy(DestinationPolicyOptions{
+		BaseAddress:  "http://localhost:8080",
+		ShortDomains: []string{"go.brand.com"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var policyErr *PolicyError
+	if err = policy.Check("https://go.brand.com/abc"); !errors.As(err, &policyErr) || policyErr.Reason != ReasonSelfReference {
+		t.Errorf("expected self_reference rejection, got %v", err)
+	}
+	if err = policy.Check("https://brand.com/abc"); err != nil {
+		t.Errorf("expected brand site to be allowed, got %v", err)
+	}
+}
+
+func TestDomainVerificationToken(t *testing.T) {
+	token := DomainVerificationToken("secret", "go.brand.com")
+	if token != DomainVerificationToken("secret", "go.brand.com") {
+		t.Error("expected stable token")
+	}
+	if token == DomainVerificationToken("secret", "go.other.com") || token == DomainVerificationToken("other", "go.brand.com") {
+		t.Error("expected token to depend on domain and secret")
+	}
+}
@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// URLVersion описывает одну версию назначения ссылки.
+// ReplacedAt — момент, когда версию сменила следующая; nil для текущей версии.
+type URLVersion struct {
+	Version     int
+	OriginalURL string
+	ReplacedAt  *time.Time
+}
+
+// URLHistory содержит текущую версию ссылки и предыдущие версии от новых к старым.
+type URLHistory struct {
+	Current  URLVersion
+	Previous []URLVersion
+}
+
+// StoreURLEditor описывает изменение назначения ссылки с сохранением истории.
+type StoreURLEditor interface {
+	// UpdateURL заменяет OriginalURL и NormalizedURL ссылки shortURL пользователя userID,
+	// сохраняя прежнее назначение в истории, и возвращает номер новой версии.
+	// Если ifVersion больше нуля и не совпадает с текущей версией, возвращает ErrVersionMismatch.
+	UpdateURL(ctx context.Context, shortURL, userID string, url URLDTO, ifVersion int) (int, error)
+	GetURLHistory(ctx context.Context, shortURL, userID string) (URLHistory, error)
+}
+
+// URLEditor позволяет владельцу менять назначение ссылки и откатывать его к прежним версиям.
+// Параметр ifVersion реализует оптимистичную блокировку: 0 отключает проверку версии.
+type URLEditor interface {
+	UpdateURL(ctx context.Context, shortURL, userID, originalURL string, ifVersion int) (int, error)
+	GetURLHistory(ctx context.Context, shortURL, userID string) (URLHistory, error)
+	RollbackURL(ctx context.Context, shortURL, userID string, version, ifVersion int) (URLVersion, error)
+}
+
+// EditService реализует URLEditor через StoreURLEditor.
+type EditService struct {
+	store      StoreURLEditor
+	normalizer *URLNormalizer
+	policy     *DestinationPolicy
+}
+
+// NewEditService создаёт EditService. Новое назначение проверяется и нормализуется
+// так же, как при создании ссылки через URLService.
+func NewEditService(store StoreURLEditor, normalizer *URLNormalizer, policy *DestinationPolicy) *EditService {
+	return &EditService{store: store, normalizer: normalizer, policy: policy}
+}
+
+// UpdateURL меняет назначение ссылки и возвращает номер новой версии.
+// Для чужих, удалённых и несуществующих ссылок возвращает ErrURLNotFound,
+// если у пользователя уже есть другая ссылка на этот URL — ErrConflict.
+func (s *EditService) UpdateURL(ctx context.Context, shortURL, userID, originalURL string, ifVersion int) (int, error) {
+	if !isValidURL(originalURL) {
+		return 0, ErrInvalidURL
+	}
+	if err := s.policy.Check(originalURL); err != nil {
+		return 0, err
+	}
+	normalized, err := s.normalizer.Normalize(originalURL)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrInvalidURL, err)
+	}
+
+	url := URLDTO{ShortURL: shortURL, OriginalURL: originalURL, NormalizedURL: normalized}
+	return s.store.UpdateURL(ctx, shortURL, userID, url, ifVersion)
+}
+
+// GetURLHistory возвращает текущее и все прежние назначения ссылки пользователя.
+func (s *EditService) GetURLHistory(ctx context.Context, shortURL, userID string) (URLHistory, error) {
+	return s.store.GetURLHistory(ctx, shortURL, userID)
+}
+
+// RollbackURL возвращает ссылке назначение из версии version и возвращает получившуюся текущую версию.
+// Откат не стирает историю, а создаёт новую версию; откат к текущей версии ничего не меняет.
+// Если версии нет в истории, возвращает ErrVersionNotFound.
+func (s *EditService) RollbackURL(ctx context.Context, shortURL, userID string, version, ifVersion int) (URLVersion, error) {
+	history, err := s.store.GetURLHistory(ctx, shortURL, userID)
+	if err != nil {
+		return URLVersion{}, err
+	}
+	if ifVersion > 0 && ifVersion != history.Current.Version {
+		return URLVersion{}, ErrVersionMismatch
+	}
+	if version == history.Current.Version {
+		return history.Current, nil
+	}
+
+	for _, previous := range history.Previous {
+		if previous.Version != version {
+			continue
+		}
+		// Передаём прочитанную версию, чтобы не откатить поверх параллельного изменения.
+		newVersion, err := s.UpdateURL(ctx, shortURL, userID, previous.OriginalURL, history.Current.Version)
+		if err != nil {
+			return URLVersion{}, err
+		}
+		return URLVersion{Version: newVersion, OriginalURL: previous.OriginalURL}, nil
+	}
+	return URLVersion{}, ErrVersionNotFound
+}
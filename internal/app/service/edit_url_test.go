@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// memEditStore хранит одну ссылку пользователя "owner" и её историю в памяти.
+type memEditStore struct {
+	current  URLVersion
+	previous []URLVersion
+}
+
+func (m *memEditStore) UpdateURL(_ context.Context, shortURL, userID string, url URLDTO, ifVersion int) (int, error) {
+	if shortURL != "abc" || userID != "owner" {
+		return 0, ErrURLNotFound
+	}
+	if ifVersion > 0 && ifVersion != m.current.Version {
+		return 0, ErrVersionMismatch
+	}
+	m.previous = append([]URLVersion{m.current}, m.previous...)
+	m.current = URLVersion{Version: m.current.Version + 1, OriginalURL: url.OriginalURL}
+	return m.current.Version, nil
+}
+
+func (m *memEditStore) GetURLHistory(_ context.Context, shortURL, userID string) (URLHistory, error) {
+	if shortURL != "abc" || userID != "owner" {
+		return URLHistory{}, ErrURLNotFound
+	}
+	return URLHistory{Current: m.current, Previous: m.previous}, nil
+}
+
+func TestEditService_UpdateURL(t *testing.T) {
+	store := &memEditStore{current: URLVersion{Version: 1, OriginalURL: "https://example.com/typo"}}
+	svc := NewEditService(store, nil, nil)
+	ctx := context.Background()
+
+	version, err := svc.UpdateURL(ctx, "abc", "owner", "https://example.com/fixed", 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if version != 2 || store.current.OriginalURL != "https://example.com/fixed" {
+		t.Errorf("expected version 2 with new URL, got %d %q", version, store.current.OriginalURL)
+	}
+
+	if _, err = svc.UpdateURL(ctx, "abc", "owner", "https://example.com/other", 1); !errors.Is(err, ErrVersionMismatch) {
+		t.Errorf("expected ErrVersionMismatch for stale version, got %v", err)
+	}
+	if _, err = svc.UpdateURL(ctx, "abc", "intruder", "https://example.com/other", 0); !errors.Is(err, ErrURLNotFound) {
+		t.Errorf("expected ErrURLNotFound for foreign link, got %v", err)
+	}
+	if _, err = svc.UpdateURL(ctx, "abc", "owner", "not a url", 0); !errors.Is(err, ErrInvalidURL) {
+		t.Errorf("expected ErrInvalidURL, got %v", err)
+	}
+}
+
+func TestEditService_UpdateURL_Policy(t *testing.T) {
+	policy, err := NewDestinationPolicy(DestinationPolicyOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	store := &memEditStore{current: URLVersion{Version: 1, OriginalURL: "https://example.com"}}
+	svc := NewEditService(store, nil, policy)
+
+	_, err = svc.UpdateURL(context.Background(), "abc", "owner", "http://127.0.0.1/admin", 0)
+	if !errors.Is(err, ErrDestinationRejected) {
+		t.Fatalf("expected ErrDestinationRejected, got %v", err)
+	}
+	if store.current.Version != 1 {
+		t.Errorf("rejected update must not change the link")
+	}
+}
+
+func TestEditService_RollbackURL(t *testing.T) {
+	store := &memEditStore{current: URLVersion{Version: 1, OriginalURL: "https://example.com/v1"}}
+	svc := NewEditService(store, nil, nil)
+	ctx := context.Background()
+
+	if _, err := svc.UpdateURL(ctx, "abc", "owner", "https://example.com/v2", 0); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	current, err := svc.RollbackURL(ctx, "abc", "owner", 1, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if current.Version != 3 || current.OriginalURL != "https://example.com/v1" {
+		t.Errorf("expected version 3 pointing to v1, got %+v", current)
+	}
+	if len(store.previous) != 2 {
+		t.Errorf("rollback must keep history, got %d previous versions", len(store.previous))
+	}
+
+	if _, err = svc.RollbackURL(ctx, "abc", "owner", 7, 0); !errors.Is(err, ErrVersionNotFound) {
+		t.Errorf("expected ErrVersionNotFound, got %v", err)
+	}
+	if _, err = svc.RollbackURL(ctx, "abc", "owner", 1, 2); !errors.Is(err, ErrVersionMismatch) {
+		t.Errorf("expected ErrVersionMismatch for stale If-Match, got %v", err)
+	}
+}
@@ -5,4 +5,90 @@ import "errors"
 var (
 	ErrURLNotFound = errors.New("URL not found")
 	ErrURLDeleted  = errors.New("URL is deleted")
+	ErrURLExpired  = errors.New("URL is expired")
+	// ErrURLExhausted возвращается, если у ссылки закончились разрешённые переходы.
+	ErrURLExhausted = errors.New("URL has reached its usage limit")
+	// ErrInvalidURL возвращается, если URL не абсолютный или не разбирается.
+	ErrInvalidURL = errors.New("invalid URL format")
+
+	// ErrShortURLTaken возвращается хранилищем, если короткий код уже занят другой ссылкой.
+	ErrShortURLTaken = errors.New("short URL is already taken")
+	// ErrInvalidAlias возвращается, если пользовательский алиас не проходит проверку формата.
+	ErrInvalidAlias = errors.New("alias must be 3-64 characters long and contain only latin letters, digits, '-' or '_'")
+	// ErrUnknownDomain возвращается, если для ссылки указан домен, не настроенный в сервисе.
+	ErrUnknownDomain = errors.New("unknown short domain")
+	// ErrReservedAlias возвращается, если алиас совпадает с зарезервированным словом.
+	ErrReservedAlias = errors.New("alias is reserved")
+	// ErrInvalidExpiry возвращается, если срок жизни ссылки задан некорректно.
+	ErrInvalidExpiry = errors.New("expires_at must be a future RFC 3339 time, ttl must be positive, and only one of them may be set")
+	// ErrInvalidMaxUses возвращается, если лимит переходов отрицательный.
+	ErrInvalidMaxUses = errors.New("max_uses must not be negative")
+	// ErrPasswordTooLong возвращается, если пароль ссылки длиннее, чем допускает bcrypt.
+	ErrPasswordTooLong = errors.New("password must be at most 72 bytes long")
+	// ErrInvalidRedirectCode возвращается для кода перенаправления, отличного от 301, 302, 307 и 308.
+	ErrInvalidRedirectCode = errors.New("redirect code must be one of 301, 302, 307, 308")
+	// ErrInvalidRule возвращается для правила перенаправления без условий или с некорректным условием.
+	ErrInvalidRule = errors.New("rule must have at least one valid condition: device (ios, android, other), language or country")
+	// ErrTooManyRules возвращается, если у ссылки оказалось бы больше 20 правил перенаправления.
+	ErrTooManyRules = errors.New("a link can have at most 20 redirect rules")
+	// ErrInvalidSplit возвращается для некорректного набора вариантов A/B-сплита.
+	ErrInvalidSplit = errors.New("split variants must have unique names of 1-32 letters, digits, '-' or '_', weights from 0 to 10000 with a positive sum, and at most 10 variants")
+	// ErrInvalidUTM возвращается для UTM-шаблона с некорректным именем или без параметров
+	// и для слишком длинного значения UTM-параметра.
+	ErrInvalidUTM = errors.New("UTM template name must be 1-64 latin letters, digits, '-' or '_', a template must set at least one parameter, and values must be at most 200 characters long")
+	// ErrUTMTemplateNotFound возвращается, если у пользователя нет UTM-шаблона с указанным именем.
+	ErrUTMTemplateNotFound = errors.New("UTM template not found")
+	// ErrWorkspaceNotFound возвращается, если рабочего пространства нет или пользователь в нём не состоит.
+	ErrWorkspaceNotFound = errors.New("workspace not found")
+	// ErrWorkspaceForbidden возвращается, если роли пользователя в рабочем пространстве недостаточно для действия.
+	ErrWorkspaceForbidden = errors.New("insufficient workspace role")
+	// ErrInvalidWorkspace возвращается для пустого или слишком длинного названия рабочего пространства
+	// и для участника с неизвестной ролью.
+	ErrInvalidWorkspace = errors.New("workspace name must be 1-100 characters long and member role must be owner, editor or viewer")
+	// ErrLastWorkspaceOwner возвращается при попытке исключить или понизить последнего владельца рабочего пространства.
+	ErrLastWorkspaceOwner = errors.New("workspace must keep at least one owner")
+	// ErrInvalidAPIKey возвращается для API-ключа без названия, без областей действия или с неизвестной областью.
+	ErrInvalidAPIKey = errors.New("API key name must be 1-100 characters long and scopes must be a non-empty subset of shorten, read, delete, stats")
+	// ErrAPIKeyNotFound возвращается для неизвестного или отозванного API-ключа.
+	ErrAPIKeyNotFound = errors.New("API key not found")
+	// ErrInvalidAccount возвращается при регистрации с некорректным логином или паролем.
+	ErrInvalidAccount = errors.New("login must be 3-64 characters: latin letters, digits, '.', '_', '-' or '@'; password must be 8-72 bytes long")
+	// ErrLoginTaken возвращается хранилищем, если логин уже занят другой учётной записью.
+	ErrLoginTaken = errors.New("login is already taken")
+	// ErrInvalidCredentials возвращается при входе с неизвестным логином или неверным паролем.
+	ErrInvalidCredentials = errors.New("invalid login or password")
+	// ErrAccountNotFound возвращается, если у пользователя нет учётной записи.
+	ErrAccountNotFound = errors.New("account not found")
+	// ErrTitleTooLong возвращается, если заголовок ссылки длиннее 200 символов.
+	ErrTitleTooLong = errors.New("title must be at most 200 characters long")
+	// ErrInvalidTag возвращается, если тег не проходит проверку формата.
+	ErrInvalidTag = errors.New("tags must be 1-32 characters long and contain only letters, digits, '-' or '_'")
+	// ErrTooManyTags возвращается, если у ссылки оказалось бы больше 20 тегов.
+	ErrTooManyTags = errors.New("a link can have at most 20 tags")
+	// ErrInvalidSort возвращается для неизвестного поля или направления сортировки списка ссылок.
+	ErrInvalidSort = errors.New("sort must be 'created' or 'destination' and order must be 'asc' or 'desc'")
+	// ErrInvalidPageSize возвращается для отрицательного размера страницы.
+	ErrInvalidPageSize = errors.New("limit must not be negative")
+	// ErrInvalidPageToken возвращается для повреждённого токена страницы
+	// или токена, выданного для списка с другой сортировкой.
+	ErrInvalidPageToken = errors.New("invalid page token")
+	// ErrInvalidQROptions возвращается для неизвестного формата, размера, рамки или уровня коррекции QR-кода.
+	ErrInvalidQROptions = errors.New("QR code format must be png or svg, size 64-2048, margin 0-16 and level one of L, M, Q, H")
+	// ErrDestinationRejected оборачивается в PolicyError, если URL запрещён политикой назначения.
+	ErrDestinationRejected = errors.New("destination URL is not allowed")
+
+	// ErrPasswordRequired возвращается при переходе по защищённой ссылке без пароля.
+	ErrPasswordRequired = errors.New("URL is password protected")
+	// ErrInvalidPassword возвращается, если введён неверный пароль ссылки.
+	ErrInvalidPassword = errors.New("invalid password")
+	// ErrTooManyAttempts возвращается, если для ссылки превышен лимит неудачных попыток ввода пароля.
+	ErrTooManyAttempts = errors.New("too many failed password attempts, try again later")
+
+	// ErrVersionMismatch возвращается, если ссылка изменилась после того, как клиент прочитал её версию.
+	ErrVersionMismatch = errors.New("URL has been modified, version does not match")
+	// ErrVersionNotFound возвращается, если в истории ссылки нет запрошенной версии.
+	ErrVersionNotFound = errors.New("URL version not found")
+
+	// ErrClickQueueFull возвращается, если очередь переходов заполнена и событие отброшено.
+	ErrClickQueueFull = errors.New("click queue is full")
 )
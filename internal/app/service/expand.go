@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Состояния ссылки в ответе ExpandURL.
+const (
+	URLStatusActive  = "active"
+	URLStatusDeleted = "deleted"
+	URLStatusExpired = "expired"
+)
+
+// URLInfo описывает ссылку для предпросмотра без перехода по ней.
+// OriginalURL и Title пусты для удалённых ссылок, а OriginalURL — ещё и для защищённых паролем,
+// чтобы предпросмотр не раскрывал то, что скрыто паролем.
+type URLInfo struct {
+	ShortURL    string
+	OriginalURL string
+	Title       string
+	CreatedAt   time.Time
+	Status      string
+	Protected   bool
+	Clicks      int
+}
+
+// StoreURLExpander описывает чтение ссылки и её счётчика переходов для предпросмотра.
+type StoreURLExpander interface {
+	Get(ctx context.Context, shortURL string) (URLDTO, error)
+	// GetClickCount возвращает число переходов по ссылке по сохранённым агрегатам.
+	GetClickCount(ctx context.Context, shortURL string) (int, error)
+}
+
+// URLExpander показывает, куда ведёт короткая ссылка, не засчитывая переход.
+type URLExpander interface {
+	ExpandURL(ctx context.Context, shortURL string) (URLInfo, error)
+}
+
+// ExpandService реализует URLExpander через StoreURLExpander.
+type ExpandService struct {
+	store StoreURLExpander
+}
+
+// NewExpandService создаёт новый ExpandService.
+func NewExpandService(store StoreURLExpander) *ExpandService {
+	return &ExpandService{store: store}
+}
+
+// ExpandURL возвращает сведения о ссылке shortURL. Ссылки с исчерпанным лимитом
+// переходов считаются просроченными. Для несуществующих ссылок возвращает ErrURLNotFound.
+func (s *ExpandService) ExpandURL(ctx context.Context, shortURL string) (URLInfo, error) {
+	url, err := s.store.Get(ctx, shortURL)
+	if err != nil && !errors.Is(err, ErrURLDeleted) {
+		return URLInfo{}, err
+	}
+	clicks, clicksErr := s.store.GetClickCount(ctx, shortURL)
+	if clicksErr != nil {
+		return URLInfo{}, clicksErr
+	}
+
+	info := URLInfo{ShortURL: shortURL, Clicks: clicks}
+	if err != nil {
+		info.Status = URLStatusDeleted
+		return info, nil
+	}
+
+	info.Title = url.Title
+	info.CreatedAt = url.CreatedAt
+	info.Protected = url.PasswordHash != ""
+	if !info.Protected {
+		info.OriginalURL = url.OriginalURL
+	}
+	info.Status = URLStatusActive
+	if url.checkActive(time.Now()) != nil {
+		info.Status = URLStatusExpired
+	}
+	return info, nil
+}
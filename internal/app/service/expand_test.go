@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// expandStore хранит ссылки в памяти; удалённые ссылки перечислены в deleted.
+type expandStore struct {
+	urls    map[string]URLDTO
+	deleted map[string]bool
+	clicks  map[string]int
+}
+
+func (s *expandStore) Get(_ context.Context, shortURL string) (URLDTO, error) {
+	if s.deleted[shortURL] {
+		return URLDTO{}, ErrURLDeleted
+	}
+	url, ok := s.urls[shortURL]
+	if !ok {
+		return URLDTO{}, ErrURLNotFound
+	}
+	return url, nil
+}
+
+func (s *expandStore) GetClickCount(_ context.Context, shortURL string) (int, error) {
+	return s.clicks[shortURL], nil
+}
+
+func TestExpandURL(t *testing.T) {
+	created := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	past := time.Now().Add(-time.Hour)
+	zero := 0
+	store := &expandStore{
+		urls: map[string]URLDTO{
+			"active":    {ShortURL: "active", OriginalURL: "https://example.com", Title: "Example", CreatedAt: created},
+			"expired":   {ShortURL: "expired", OriginalURL: "https://example.com", ExpiresAt: &past},
+			"exhausted": {ShortURL: "exhausted", OriginalURL: "https://example.com", RemainingUses: &zero},
+			"protected": {ShortURL: "protected", OriginalURL: "https://secret.example.com", PasswordHash: "hash"},
+		},
+		deleted: map[string]bool{"deleted": true},
+		clicks:  map[string]int{"active": 7, "deleted": 2},
+	}
+	svc := NewExpandService(store)
+
+	tests := []struct {
+		short string
+		want  URLInfo
+	}{
+		{short: "active", want: URLInfo{ShortURL: "active", OriginalURL: "https://example.com", Title: "Example", CreatedAt: created, Status: URLStatusActive, Clicks: 7}},
+		{short: "expired", want: URLInfo{ShortURL: "expired", OriginalURL: "https://example.com", Status: URLStatusExpired}},
+		{short: "exhausted", want: URLInfo{ShortURL: "exhausted", OriginalURL: "https://example.com", Status: URLStatusExpired}},
+		{short: "protected", want: URLInfo{ShortURL: "protected", Status: URLStatusActive, Protected: true}},
+		{short: "deleted", want: URLInfo{ShortURL: "deleted", Status: URLStatusDeleted, Clicks: 2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.short, func(t *testing.T) {
+			got, err := svc.ExpandURL(context.Background(), tt.short)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("expected %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+
+	if _, err := svc.ExpandURL(context.Background(), "missing"); !errors.Is(err, ErrURLNotFound) {
+		t.Errorf("expected ErrURLNotFound, got %v", err)
+	}
+}
+
+func TestShortenURL_TitleTooLong(t *testing.T) {
+	svc := NewURLService(&stubStore{}, NewRandomCodeGenerator(DefaultShortCodeLength), nil, nil, nil)
+	req := ShortenRequest{OriginalURL: "https://example.com", Title: strings.Repeat("я", maxTitleLength+1)}
+
+	if _, err := svc.ShortenURL(context.Background(), req, "u1"); !errors.Is(err, ErrTitleTooLong) {
+		t.Errorf("expected ErrTitleTooLong, got %v", err)
+	}
+}
@@ -0,0 +1,32 @@
+// Package service содержит бизнес-логику работы с URL.
+package service
+
+import "time"
+
+// ParseExpiry вычисляет момент истечения ссылки из абсолютного времени expiresAt
+// в формате RFC 3339 или из ttl в секундах относительно now.
+// Если оба параметра пустые, возвращает nil — ссылка бессрочная.
+func ParseExpiry(expiresAt string, ttl int64, now time.Time) (*time.Time, error) {
+	switch {
+	case expiresAt != "" && ttl != 0:
+		return nil, ErrInvalidExpiry
+	case expiresAt != "":
+		t, err := time.Parse(time.RFC3339, expiresAt)
+		if err != nil || !t.After(now) {
+			return nil, ErrInvalidExpiry
+		}
+		t = t.UTC()
+		return &t, nil
+	case ttl < 0:
+		return nil, ErrInvalidExpiry
+	case ttl > 0:
+		t := now.Add(time.Duration(ttl) * time.Second).UTC()
+		return &t, nil
+	}
+	return nil, nil
+}
+
+// isExpired сообщает, истёк ли срок жизни ссылки к моменту now.
+func (u URLDTO) isExpired(now time.Time) bool {
+	return u.ExpiresAt != nil && !now.Before(*u.ExpiresAt)
+}
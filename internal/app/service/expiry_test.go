@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseExpiry(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name      string
+		expiresAt string
+		ttl       int64
+		want      *time.Time
+		wantErr   bool
+	}{
+		{name: "no expiry"},
+		{name: "ttl", ttl: 60, want: ptrTime(now.Add(time.Minute))},
+		{name: "expires_at", expiresAt: "2025-01-02T00:00:00+03:00", want: ptrTime(time.Date(2025, 1, 1, 21, 0, 0, 0, time.UTC))},
+		{name: "both", expiresAt: "2025-01-02T00:00:00Z", ttl: 60, wantErr: true},
+		{name: "past", expiresAt: "2024-01-01T00:00:00Z", wantErr: true},
+		{name: "malformed", expiresAt: "tomorrow", wantErr: true},
+		{name: "negative ttl", ttl: -1, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseExpiry(tt.expiresAt, tt.ttl, now)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidExpiry) {
+					t.Fatalf("expected ErrInvalidExpiry, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if (got == nil) != (tt.want == nil) || (got != nil && !got.Equal(*tt.want)) {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+type expiryStore struct {
+	stubStore
+	url URLDTO
+}
+
+func (s *expiryStore) Get(_ context.Context, _ string) (URLDTO, error) {
+	return s.url, nil
+}
+
+func TestGetOriginalURL_Expired(t *testing.T) {
+	past := time.Now().Add(-time.Second)
+	svc := NewGetURLService(&expiryStore{url: URLDTO{OriginalURL: "https://example.com", ExpiresAt: &past}}, nil, nil)
+	if _, err := svc.GetOriginalURL(context.Background(), "key", Visit{}); !errors.Is(err, ErrURLExpired) {
+		t.Fatalf("expected ErrURLExpired, got %v", err)
+	}
+
+	future := time.Now().Add(time.Hour)
+	svc = NewGetURLService(&expiryStore{url: URLDTO{OriginalURL: "https://example.com", ExpiresAt: &future}}, nil, nil)
+	got, err := svc.GetOriginalURL(context.Background(), "key", Visit{})
+	if err != nil || got.URL != "https://example.com" {
+		t.Fatalf("expected active link, got %q, %v", got, err)
+	}
+}
+
+func ptrTime(t time.Time) *time.Time {
+	return &t
+}
@@ -0,0 +1,106 @@
+package service
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/netip"
+	"os"
+	"sort"
+	"strings"
+)
+
+// CountryResolver определяет страну по IP-адресу.
+type CountryResolver interface {
+	// Country возвращает код страны ISO 3166-1 alpha-2 или пустую строку, если страна неизвестна.
+	Country(ip netip.Addr) string
+}
+
+// countryRange — непрерывный диапазон адресов одной страны.
+type countryRange struct {
+	first, last netip.Addr
+	country     string
+}
+
+// CountryDB сопоставляет IP-адреса странам по локальному файлу диапазонов.
+type CountryDB struct {
+	ranges []countryRange
+}
+
+// NewCountryDB загружает базу стран из файла path. Если путь пуст, возвращает пустую базу,
+// в которой страна любого адреса неизвестна.
+// Формат файла — по одной записи "CIDR,код страны" на строку, например "81.2.69.0/24,GB";
+// пустые строки и строки, начинающиеся с '#', пропускаются. Диапазоны не должны пересекаться.
+func NewCountryDB(path string) (*CountryDB, error) {
+	if path == "" {
+		return &CountryDB{}, nil
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	db, err := parseCountryDB(file)
+	if err != nil {
+		return nil, fmt.Errorf("country database %s: %w", path, err)
+	}
+	return db, nil
+}
+
+// parseCountryDB читает записи базы стран и сортирует диапазоны по первому адресу.
+func parseCountryDB(r io.Reader) (*CountryDB, error) {
+	db := &CountryDB{}
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		entry := strings.TrimSpace(scanner.Text())
+		if entry == "" || strings.HasPrefix(entry, "#") {
+			continue
+		}
+		cidr, country, ok := strings.Cut(entry, ",")
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected CIDR,country", line)
+		}
+		prefix, err := netip.ParsePrefix(strings.TrimSpace(cidr))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		country = strings.ToUpper(strings.TrimSpace(country))
+		if !countryPattern.MatchString(country) {
+			return nil, fmt.Errorf("line %d: invalid country code %q", line, country)
+		}
+		prefix = prefix.Masked()
+		db.ranges = append(db.ranges, countryRange{first: prefix.Addr(), last: lastAddr(prefix), country: country})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	sort.Slice(db.ranges, func(i, j int) bool { return db.ranges[i].first.Less(db.ranges[j].first) })
+	return db, nil
+}
+
+// lastAddr возвращает последний адрес префикса.
+func lastAddr(prefix netip.Prefix) netip.Addr {
+	addr := prefix.Addr()
+	bytes := addr.AsSlice()
+	for bit := prefix.Bits(); bit < addr.BitLen(); bit++ {
+		bytes[bit/8] |= 1 << (7 - bit%8)
+	}
+	last, _ := netip.AddrFromSlice(bytes)
+	return last
+}
+
+// Country возвращает код страны для ip или пустую строку, если адрес не входит ни в один диапазон.
+// IPv4-адреса, отображённые в IPv6, ищутся как IPv4.
+func (db *CountryDB) Country(ip netip.Addr) string {
+	if db == nil {
+		return ""
+	}
+	ip = ip.Unmap()
+	// Ищем последний диапазон, начинающийся не позже ip.
+	i := sort.Search(len(db.ranges), func(i int) bool { return ip.Less(db.ranges[i].first) }) - 1
+	if i < 0 || db.ranges[i].last.Less(ip) {
+		return ""
+	}
+	return db.ranges[i].country
+}
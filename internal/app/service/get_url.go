@@ -1,52 +1,220 @@
 // Package service содержит бизнес-логику работы с URL.
 package service
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 // StatsDTO хранит данные о количестве пользователей и сохраненных url
 type StatsDTO struct {
-	Urls  int
-	Users int
+	Urls   int
+	Users  int
+	Clicks int
 }
 
+// URLDTO описывает сокращённую ссылку при передаче между хранилищем, сервисами и хендлерами.
+// ExpiresAt равен nil для бессрочных ссылок, RemainingUses — для ссылок без лимита переходов.
+// PasswordHash содержит bcrypt-хэш пароля и пуст для незащищённых ссылок.
+// NormalizedURL — канонический вид OriginalURL, по которому ищутся дубликаты;
+// OriginalURL хранится в том виде, в каком его прислал пользователь.
+// Clicks заполняется только в списке ссылок пользователя и учитывает уже сброшенные агрегаты.
+// Tags отсортированы по имени. CreatedAt заполняется хранилищем при сохранении ссылки.
+// Title — необязательный заголовок, который владелец показывает на странице предпросмотра.
+// RedirectCode — код ответа при переходе, 0 означает DefaultRedirectCode.
+// PassQuery и PassPath включают перенос строки запроса и остатка пути в адрес назначения.
+// Rules — упорядоченные правила перенаправления; первое подходящее заменяет OriginalURL.
+// Variants — варианты A/B-сплита; используются, если ни одно правило не подошло.
+// Standalone — ссылка создана с собственными параметрами и не участвует в поиске дубликатов.
 type URLDTO struct {
-	ShortURL    string
-	OriginalURL string
+	ShortURL      string
+	OriginalURL   string
+	NormalizedURL string
+	ExpiresAt     *time.Time
+	RemainingUses *int
+	PasswordHash  string
+	Clicks        int
+	Tags          []string
+	CreatedAt     time.Time
+	Title         string
+	RedirectCode  int
+	PassQuery     bool
+	PassPath      bool
+	Rules         []RedirectRule
+	Variants      []SplitVariant
+	Standalone    bool
 }
 
 // StoreURLGetter описывает методы получения URL из хранилища.
 type StoreURLGetter interface {
-	Get(ctx context.Context, shortURL string) (originalURL string, err error)
-	GetUserURLs(ctx context.Context, userID string) ([]URLDTO, error)
+	Get(ctx context.Context, shortURL string) (URLDTO, error)
+	// ConsumeUse атомарно уменьшает счётчик оставшихся переходов
+	// и возвращает ErrURLExhausted, если он уже равен нулю.
+	ConsumeUse(ctx context.Context, shortURL string) error
+	// GetUserURLs возвращает не удалённые ссылки пользователя, подходящие под query, в порядке query.
+	GetUserURLs(ctx context.Context, userID string, query URLQuery) ([]URLDTO, error)
 	GetStats(ctx context.Context) (int, int, error)
+	// GetTotalClicks возвращает число переходов по всем ссылкам по сохранённым агрегатам.
+	GetTotalClicks(ctx context.Context) (int, error)
+	GetExpiredURLs(ctx context.Context, now time.Time) (map[string][]string, error)
 }
 
 // GetURLService реализует URLGetter через StoreURLGetter.
 type GetURLService struct {
-	store StoreURLGetter
+	store      StoreURLGetter
+	normalizer *URLNormalizer
+	countries  CountryResolver
+	limiter    *passwordLimiter
 }
 
 // NewGetURLService создаёт новый GetURLService на основе переданного хранилища.
-func NewGetURLService(store StoreURLGetter) *GetURLService {
-	return &GetURLService{store: store}
+// normalizer приводит URL из обратного поиска к тому же виду, что и при сокращении; может быть nil.
+// countries определяет страну посетителя для правил перенаправления; если nil, правила по стране не срабатывают.
+func NewGetURLService(store StoreURLGetter, normalizer *URLNormalizer, countries CountryResolver) *GetURLService {
+	return &GetURLService{
+		store:      store,
+		normalizer: normalizer,
+		countries:  countries,
+		limiter:    newPasswordLimiter(maxPasswordAttempts, passwordAttemptWindow),
+	}
 }
 
-// GetOriginalURL возвращает оригинальный URL, exists и deleted.
-func (s *GetURLService) GetOriginalURL(ctx context.Context, input string) (string, error) {
-	return s.store.Get(ctx, input)
+// GetOriginalURL возвращает перенаправление по ссылке и засчитывает переход для ссылок с лимитом.
+// Адрес назначения выбирается правилами ссылки по признакам посетителя из visit,
+// а если ни одно не подошло — A/B-сплитом с учётом ранее выданного варианта visit.Variant;
+// путь и строка запроса переносятся в него, если это разрешено ссылкой.
+// Для удалённых ссылок возвращает ErrURLDeleted, для просроченных — ErrURLExpired,
+// для исчерпавших лимит переходов — ErrURLExhausted,
+// для защищённых паролем — ErrPasswordRequired.
+func (s *GetURLService) GetOriginalURL(ctx context.Context, input string, visit Visit) (Redirect, error) {
+	url, err := s.store.Get(ctx, input)
+	if err != nil {
+		return Redirect{URL: url.OriginalURL}, err
+	}
+	if err = url.checkActive(time.Now()); err != nil {
+		return Redirect{}, err
+	}
+	if url.PasswordHash != "" {
+		return Redirect{}, ErrPasswordRequired
+	}
+	return s.follow(ctx, url, visit)
+}
+
+// UnlockURL возвращает перенаправление по защищённой ссылке после проверки пароля.
+// Для незащищённых ссылок пароль игнорируется. Неудачные попытки ограничены для каждой ссылки:
+// после превышения лимита возвращается ErrTooManyAttempts.
+func (s *GetURLService) UnlockURL(ctx context.Context, input, password string, visit Visit) (Redirect, error) {
+	url, err := s.store.Get(ctx, input)
+	if err != nil {
+		return Redirect{}, err
+	}
+	now := time.Now()
+	if err = url.checkActive(now); err != nil {
+		return Redirect{}, err
+	}
+	if url.PasswordHash != "" {
+		if password == "" {
+			return Redirect{}, ErrPasswordRequired
+		}
+		if !s.limiter.allow(input, now) {
+			return Redirect{}, ErrTooManyAttempts
+		}
+		if !checkPassword(url.PasswordHash, password) {
+			s.limiter.fail(input, now)
+			return Redirect{}, ErrInvalidPassword
+		}
+		s.limiter.reset(input)
+	}
+	return s.follow(ctx, url, visit)
 }
 
-// GetUserURLs возвращает все URLRecord для данного пользователя.
-func (s *GetURLService) GetUserURLs(ctx context.Context, userID string) ([]URLDTO, error) {
-	return s.store.GetUserURLs(ctx, userID)
+// checkActive проверяет, что по ссылке ещё можно перейти.
+func (url URLDTO) checkActive(now time.Time) error {
+	if url.isExpired(now) {
+		return ErrURLExpired
+	}
+	if url.RemainingUses != nil && *url.RemainingUses <= 0 {
+		return ErrURLExhausted
+	}
+	return nil
 }
 
-// GetStats возвращает количество пользователей и url
+// follow выбирает адрес назначения по правилам и A/B-сплиту ссылки, строит перенаправление
+// и засчитывает переход.
+func (s *GetURLService) follow(ctx context.Context, url URLDTO, visit Visit) (Redirect, error) {
+	destination, variant := s.route(url, visit)
+	url.OriginalURL = destination
+	redirect, err := url.redirect(visit)
+	if err != nil {
+		return Redirect{}, err
+	}
+	redirect.Variant = variant
+	if url.RemainingUses != nil {
+		if err := s.store.ConsumeUse(ctx, url.ShortURL); err != nil {
+			return Redirect{}, err
+		}
+	}
+	return redirect, nil
+}
+
+// GetUserURLs возвращает страницу ссылок пользователя, подходящих под filter.
+// Для некорректного фильтра возвращает ErrInvalidTag, ErrInvalidURL, ErrInvalidSort,
+// ErrInvalidPageSize или ErrInvalidPageToken.
+func (s *GetURLService) GetUserURLs(ctx context.Context, userID string, filter URLFilter) (URLPage, error) {
+	limit := filter.Limit
+	switch {
+	case limit < 0:
+		return URLPage{}, ErrInvalidPageSize
+	case limit == 0 && filter.PageToken != "":
+		limit = defaultPageSize
+	case limit > maxPageSize:
+		limit = maxPageSize
+	}
+
+	query, err := s.buildQuery(filter)
+	if err != nil {
+		return URLPage{}, err
+	}
+	if limit == 0 {
+		// Без limit и page_token список отдаётся целиком, как до появления постраничного вывода.
+		urls, err := s.store.GetUserURLs(ctx, userID, query)
+		if err != nil {
+			return URLPage{}, err
+		}
+		return URLPage{URLs: urls}, nil
+	}
+	// Лишняя ссылка показывает, есть ли следующая страница.
+	query.Limit = limit + 1
+
+	urls, err := s.store.GetUserURLs(ctx, userID, query)
+	if err != nil {
+		return URLPage{}, err
+	}
+	page := URLPage{URLs: urls}
+	if len(urls) > limit {
+		page.URLs = urls[:limit]
+		page.NextPageToken = encodePageToken(query, cursorOf(urls[limit-1]))
+	}
+	return page, nil
+}
+
+// GetStats возвращает количество пользователей, url и переходов
 func (s *GetURLService) GetStats(ctx context.Context) (StatsDTO, error) {
 	urlsCount, usersCount, err := s.store.GetStats(ctx)
+	if err != nil {
+		return StatsDTO{}, err
+	}
+	clicks, err := s.store.GetTotalClicks(ctx)
 	stat := StatsDTO{
 		urlsCount,
 		usersCount,
+		clicks,
 	}
 	return stat, err
 }
+
+// GetExpiredURLs возвращает ещё не удалённые просроченные ссылки,
+// сгруппированные по userID владельца.
+func (s *GetURLService) GetExpiredURLs(ctx context.Context) (map[string][]string, error) {
+	return s.store.GetExpiredURLs(ctx, time.Now())
+}
@@ -0,0 +1,212 @@
+package service
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Поля, по которым сортируется список ссылок пользователя.
+const (
+	URLSortCreated     = "created"
+	URLSortDestination = "destination"
+)
+
+// Направления сортировки списка ссылок.
+const (
+	SortOrderAsc  = "asc"
+	SortOrderDesc = "desc"
+)
+
+// defaultPageSize — размер страницы, если клиент передал токен страницы без её размера.
+const defaultPageSize = 100
+
+// maxPageSize ограничивает размер одной страницы.
+const maxPageSize = 1000
+
+// URLFilter задаёт условия выборки и постраничного вывода ссылок пользователя.
+// Tags оставляет только ссылки, у которых есть все перечисленные теги.
+// Search — подстрока оригинального URL без учёта регистра.
+// Destination ищет ссылку пользователя на конкретный URL с учётом канонического вида.
+// Sort — URLSortCreated (по умолчанию) или URLSortDestination; Order — SortOrderAsc или SortOrderDesc,
+// по умолчанию новые ссылки идут первыми, а адреса — по алфавиту.
+// Limit — размер страницы: 0 без PageToken возвращает все ссылки одной страницей,
+// 0 с PageToken означает defaultPageSize. PageToken — токен из предыдущей страницы.
+type URLFilter struct {
+	Tags        []string
+	Search      string
+	Destination string
+	Sort        string
+	Order       string
+	Limit       int
+	PageToken   string
+}
+
+// URLPage — одна страница списка ссылок. NextPageToken пуст на последней странице.
+type URLPage struct {
+	URLs          []URLDTO
+	NextPageToken string
+}
+
+// URLCursor — позиция ссылки в отсортированном списке, после которой начинается следующая страница.
+type URLCursor struct {
+	CreatedAt   time.Time
+	OriginalURL string
+	ShortURL    string
+}
+
+// cursorOf возвращает позицию ссылки url.
+func cursorOf(url URLDTO) URLCursor {
+	return URLCursor{CreatedAt: url.CreatedAt, OriginalURL: url.OriginalURL, ShortURL: url.ShortURL}
+}
+
+// URLQuery описывает выборку ссылок пользователя для хранилища.
+// Ссылки упорядочены по Sort, а при равенстве — по короткому коду; Desc обращает порядок.
+// After, если задан, оставляет только ссылки строго после этой позиции.
+// Limit ограничивает число ссылок; 0 снимает ограничение.
+type URLQuery struct {
+	Tags          []string
+	Search        string
+	NormalizedURL string
+	Sort          string
+	Desc          bool
+	After         *URLCursor
+	Limit         int
+}
+
+// compare сравнивает позиции a и b в порядке выборки.
+func (q URLQuery) compare(a, b URLCursor) int {
+	var c int
+	if q.Sort == URLSortDestination {
+		c = strings.Compare(a.OriginalURL, b.OriginalURL)
+	} else {
+		c = a.CreatedAt.Compare(b.CreatedAt)
+	}
+	if c == 0 {
+		c = strings.Compare(a.ShortURL, b.ShortURL)
+	}
+	if q.Desc {
+		c = -c
+	}
+	return c
+}
+
+// Match сообщает, подходит ли ссылка url под условия выборки, включая позицию After.
+func (q URLQuery) Match(url URLDTO) bool {
+	if !hasAllTags(url.Tags, q.Tags) {
+		return false
+	}
+	if q.NormalizedURL != "" && url.NormalizedURL != q.NormalizedURL {
+		return false
+	}
+	if q.Search != "" && !strings.Contains(strings.ToLower(url.OriginalURL), strings.ToLower(q.Search)) {
+		return false
+	}
+	return q.After == nil || q.compare(cursorOf(url), *q.After) > 0
+}
+
+// Apply отбирает из urls подходящие ссылки, сортирует их и обрезает до Limit.
+// Используется хранилищами, которые не умеют делать выборку на своей стороне.
+func (q URLQuery) Apply(urls []URLDTO) []URLDTO {
+	var result []URLDTO
+	for _, url := range urls {
+		if q.Match(url) {
+			result = append(result, url)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return q.compare(cursorOf(result[i]), cursorOf(result[j])) < 0
+	})
+	if q.Limit > 0 && len(result) > q.Limit {
+		result = result[:q.Limit]
+	}
+	return result
+}
+
+// pageToken — содержимое токена страницы. Порядок сортировки сохраняется в токене,
+// чтобы токен нельзя было применить к списку в другом порядке.
+type pageToken struct {
+	Sort        string    `json:"s"`
+	Desc        bool      `json:"d"`
+	CreatedAt   time.Time `json:"t"`
+	OriginalURL string    `json:"o"`
+	ShortURL    string    `json:"k"`
+}
+
+// encodePageToken возвращает токен страницы, начинающейся после cursor.
+func encodePageToken(q URLQuery, cursor URLCursor) string {
+	data, _ := json.Marshal(pageToken{
+		Sort:        q.Sort,
+		Desc:        q.Desc,
+		CreatedAt:   cursor.CreatedAt,
+		OriginalURL: cursor.OriginalURL,
+		ShortURL:    cursor.ShortURL,
+	})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodePageToken разбирает токен страницы для выборки q.
+// Для повреждённого токена или токена от списка с другим порядком возвращает ErrInvalidPageToken.
+func decodePageToken(q URLQuery, token string) (*URLCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, ErrInvalidPageToken
+	}
+	var t pageToken
+	if err = json.Unmarshal(data, &t); err != nil || t.ShortURL == "" {
+		return nil, ErrInvalidPageToken
+	}
+	if t.Sort != q.Sort || t.Desc != q.Desc {
+		return nil, ErrInvalidPageToken
+	}
+	return &URLCursor{CreatedAt: t.CreatedAt, OriginalURL: t.OriginalURL, ShortURL: t.ShortURL}, nil
+}
+
+// buildQuery проверяет filter и превращает его в выборку для хранилища
+// без учёта размера страницы.
+func (s *GetURLService) buildQuery(filter URLFilter) (URLQuery, error) {
+	q := URLQuery{Search: strings.TrimSpace(filter.Search)}
+
+	tags, err := normalizeTags(filter.Tags)
+	if err != nil {
+		return q, err
+	}
+	q.Tags = tags
+
+	if filter.Destination != "" {
+		if !isValidURL(filter.Destination) {
+			return q, ErrInvalidURL
+		}
+		if q.NormalizedURL, err = s.normalizer.Normalize(filter.Destination); err != nil {
+			return q, ErrInvalidURL
+		}
+	}
+
+	switch filter.Sort {
+	case "", URLSortCreated:
+		q.Sort = URLSortCreated
+		q.Desc = true
+	case URLSortDestination:
+		q.Sort = URLSortDestination
+	default:
+		return q, ErrInvalidSort
+	}
+	switch filter.Order {
+	case "":
+	case SortOrderAsc:
+		q.Desc = false
+	case SortOrderDesc:
+		q.Desc = true
+	default:
+		return q, ErrInvalidSort
+	}
+
+	if filter.PageToken != "" {
+		if q.After, err = decodePageToken(q, filter.PageToken); err != nil {
+			return q, err
+		}
+	}
+	return q, nil
+}
@@ -0,0 +1,130 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// listStore отдаёт выборку из фиксированного набора ссылок через URLQuery.Apply.
+type listStore struct {
+	stubStore
+	urls []URLDTO
+}
+
+func (s *listStore) GetUserURLs(_ context.Context, _ string, query URLQuery) ([]URLDTO, error) {
+	return query.Apply(s.urls), nil
+}
+
+func newListStore() *listStore {
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	return &listStore{urls: []URLDTO{
+		{ShortURL: "a", OriginalURL: "https://go.dev/doc", NormalizedURL: "https://go.dev/doc", CreatedAt: base},
+		{ShortURL: "b", OriginalURL: "https://example.com/", NormalizedURL: "https://example.com/", CreatedAt: base.Add(time.Hour)},
+		{ShortURL: "c", OriginalURL: "https://Go.dev/blog", NormalizedURL: "https://go.dev/blog", CreatedAt: base.Add(time.Hour)},
+		{ShortURL: "d", OriginalURL: "https://ya.ru/", NormalizedURL: "https://ya.ru/", CreatedAt: base.Add(2 * time.Hour)},
+		{ShortURL: "e", OriginalURL: "https://abc.xyz/", NormalizedURL: "https://abc.xyz/", CreatedAt: base.Add(3 * time.Hour)},
+	}}
+}
+
+// collectPages проходит по всем страницам и возвращает короткие коды в порядке выдачи.
+func collectPages(t *testing.T, svc *GetURLService, filter URLFilter) []string {
+	t.Helper()
+	var keys []string
+	for i := 0; ; i++ {
+		if i > 10 {
+			t.Fatal("too many pages")
+		}
+		page, err := svc.GetUserURLs(context.Background(), "u1", filter)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if filter.Limit > 0 && len(page.URLs) > filter.Limit {
+			t.Fatalf("page has %d urls, limit is %d", len(page.URLs), filter.Limit)
+		}
+		for _, url := range page.URLs {
+			keys = append(keys, url.ShortURL)
+		}
+		if page.NextPageToken == "" {
+			return keys
+		}
+		filter.PageToken = page.NextPageToken
+	}
+}
+
+func TestGetUserURLs_Pagination(t *testing.T) {
+	svc := NewGetURLService(newListStore(), nil, nil)
+
+	tests := []struct {
+		name   string
+		filter URLFilter
+		want   []string
+	}{
+		{name: "newest first by default", filter: URLFilter{Limit: 2}, want: []string{"e", "d", "c", "b", "a"}},
+		{name: "oldest first", filter: URLFilter{Limit: 2, Order: SortOrderAsc}, want: []string{"a", "b", "c", "d", "e"}},
+		{name: "by destination", filter: URLFilter{Limit: 3, Sort: URLSortDestination}, want: []string{"c", "e", "b", "a", "d"}},
+		{name: "search is case-insensitive", filter: URLFilter{Limit: 1, Search: "GO.DEV"}, want: []string{"c", "a"}},
+		{name: "reverse lookup", filter: URLFilter{Destination: "HTTPS://go.dev:443/blog"}, want: []string{"c"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := collectPages(t, svc, tt.filter); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestGetUserURLs_InvalidFilter(t *testing.T) {
+	svc := NewGetURLService(newListStore(), nil, nil)
+	ctx := context.Background()
+
+	page, err := svc.GetUserURLs(ctx, "u1", URLFilter{Limit: 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		filter URLFilter
+		want   error
+	}{
+		{name: "negative limit", filter: URLFilter{Limit: -1}, want: ErrInvalidPageSize},
+		{name: "unknown sort", filter: URLFilter{Sort: "clicks"}, want: ErrInvalidSort},
+		{name: "unknown order", filter: URLFilter{Order: "up"}, want: ErrInvalidSort},
+		{name: "garbage token", filter: URLFilter{PageToken: "not a token"}, want: ErrInvalidPageToken},
+		{name: "token from another order", filter: URLFilter{Sort: URLSortDestination, PageToken: page.NextPageToken}, want: ErrInvalidPageToken},
+		{name: "invalid destination", filter: URLFilter{Destination: "not a url"}, want: ErrInvalidURL},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := svc.GetUserURLs(ctx, "u1", tt.filter); !errors.Is(err, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestGetUserURLs_NoLimitReturnsAll(t *testing.T) {
+	store := &listStore{}
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < defaultPageSize+5; i++ {
+		store.urls = append(store.urls, URLDTO{
+			ShortURL:    fmt.Sprintf("k%d", i),
+			OriginalURL: fmt.Sprintf("https://example.com/%d", i),
+			CreatedAt:   base.Add(time.Duration(i) * time.Minute),
+		})
+	}
+	svc := NewGetURLService(store, nil, nil)
+
+	page, err := svc.GetUserURLs(context.Background(), "u1", URLFilter{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(page.URLs) != len(store.urls) || page.NextPageToken != "" {
+		t.Errorf("expected all %d urls without a token, got %d and %q", len(store.urls), len(page.URLs), page.NextPageToken)
+	}
+}
@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	"net"
+	"net/url"
+	"slices"
+	"strings"
+
+	"golang.org/x/net/idna"
+)
+
+// defaultPorts содержит порты, которые не влияют на адрес для своей схемы.
+var defaultPorts = map[string]string{
+	"http":  "80",
+	"https": "443",
+}
+
+// trackingParams перечисляет параметры запроса, которые используются только для аналитики.
+// Параметры с префиксом utm_ удаляются отдельно.
+var trackingParams = map[string]struct{}{
+	"fbclid":  {},
+	"gclid":   {},
+	"yclid":   {},
+	"msclkid": {},
+}
+
+// legacyNormalizeBatch — число ссылок, канонический вид которых пересчитывается за один запрос к хранилищу.
+const legacyNormalizeBatch = 500
+
+// URLNormalizer приводит URL к каноническому виду, по которому ищутся дубликаты.
+type URLNormalizer struct {
+	stripTracking bool
+}
+
+// NewURLNormalizer создаёт URLNormalizer. Если stripTracking равен true,
+// из запроса удаляются параметры utm_* и идентификаторы кликов рекламных систем.
+func NewURLNormalizer(stripTracking bool) *URLNormalizer {
+	return &URLNormalizer{stripTracking: stripTracking}
+}
+
+// Normalize возвращает канонический вид rawURL:
+// схема и хост в нижнем регистре, IDN-хост в punycode, без порта по умолчанию,
+// пустой путь заменён на "/", параметры запроса отсортированы по имени.
+// Nil-получатель нормализует без удаления трекинговых параметров.
+func (n *URLNormalizer) Normalize(rawURL string) (string, error) {
+	return n.normalize(rawURL, nil)
+}
+
+// normalize работает как Normalize, но не удаляет трекинговые параметры с именами из keep.
+func (n *URLNormalizer) normalize(rawURL string, keep []string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+
+	u.Scheme = strings.ToLower(u.Scheme)
+
+	host := strings.ToLower(u.Hostname())
+	if net.ParseIP(host) == nil {
+		if host, err = idna.Lookup.ToASCII(host); err != nil {
+			return "", err
+		}
+	}
+	port := u.Port()
+	if defaultPorts[u.Scheme] == port {
+		port = ""
+	}
+	switch {
+	case port != "":
+		u.Host = net.JoinHostPort(host, port)
+	case strings.Contains(host, ":"):
+		u.Host = "[" + host + "]"
+	default:
+		u.Host = host
+	}
+
+	if u.Path == "" && u.Opaque == "" {
+		u.Path = "/"
+	}
+
+	if u.RawQuery != "" {
+		query := u.Query()
+		if n != nil && n.stripTracking {
+			for key := range query {
+				if isTrackingParam(key) && !slices.Contains(keep, key) {
+					query.Del(key)
+				}
+			}
+		}
+		// Encode сортирует параметры по имени.
+		u.RawQuery = query.Encode()
+	}
+	u.ForceQuery = false
+
+	return u.String(), nil
+}
+
+func isTrackingParam(key string) bool {
+	key = strings.ToLower(key)
+	if strings.HasPrefix(key, "utm_") {
+		return true
+	}
+	_, ok := trackingParams[key]
+	return ok
+}
+
+// StoreLegacyURLs описывает ссылки, сохранённые до появления нормализации: их канонический вид
+// совпадает с оригинальным URL. UnnormalizedURLs возвращает до limit таких ссылок.
+// SetNormalizedURL сохраняет канонический вид ссылки и снимает с неё пометку; если у владельца
+// уже есть ссылка на тот же канонический URL, ссылка становится отдельной.
+type StoreLegacyURLs interface {
+	UnnormalizedURLs(ctx context.Context, limit int) ([]URLDTO, error)
+	SetNormalizedURL(ctx context.Context, shortURL, normalizedURL string) error
+}
+
+// NormalizeLegacyURLs пересчитывает канонический вид ссылок, сохранённых до появления нормализации,
+// чтобы повторное сокращение того же адреса находило их как дубликаты. URL, который не удаётся
+// разобрать, остаётся каноническим как есть. Возвращает число обработанных ссылок.
+func NormalizeLegacyURLs(ctx context.Context, store StoreLegacyURLs, normalizer *URLNormalizer) (int, error) {
+	total := 0
+	for {
+		urls, err := store.UnnormalizedURLs(ctx, legacyNormalizeBatch)
+		if err != nil {
+			return total, err
+		}
+		if len(urls) == 0 {
+			return total, nil
+		}
+		for _, url := range urls {
+			normalized, err := normalizer.Normalize(url.OriginalURL)
+			if err != nil {
+				normalized = url.OriginalURL
+			}
+			if err = store.SetNormalizedURL(ctx, url.ShortURL, normalized); err != nil {
+				return total, err
+			}
+			total++
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package service
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestURLNormalizer_Normalize(t *testing.T) {
+	tests := []struct {
+		name          string
+		input         string
+		stripTracking bool
+		want          string
+	}{
+		{name: "scheme, host and default port", input: "HTTPS://Example.COM:443/a?b=1&a=2", want: "https://example.com/a?a=2&b=1"},
+		{name: "already canonical", input: "https://example.com/a?a=2&b=1", want: "https://example.com/a?a=2&b=1"},
+		{name: "http default port", input: "http://example.com:80", want: "http://example.com/"},
+		{name: "non-default port kept", input: "http://example.com:8080/x", want: "http://example.com:8080/x"},
+		{name: "path case kept", input: "https://example.com/Path", want: "https://example.com/Path"},
+		{name: "idn host", input: "https://Пример.рф/путь", want: "https://xn--e1afmkfd.xn--p1ai/%D0%BF%D1%83%D1%82%D1%8C"},
+		{name: "ipv6 host", input: "http://[::1]:80/", want: "http://[::1]/"},
+		{name: "tracking kept by default", input: "https://example.com/?utm_source=x&id=1", want: "https://example.com/?id=1&utm_source=x"},
+		{name: "tracking stripped", input: "https://example.com/?utm_source=x&UTM_Medium=y&fbclid=z&id=1", stripTracking: true, want: "https://example.com/?id=1"},
+		{name: "only tracking params", input: "https://example.com/a?utm_source=x", stripTracking: true, want: "https://example.com/a"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewURLNormalizer(tt.stripTracking).Normalize(tt.input)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestShortenURLs_DedupByNormalizedURL(t *testing.T) {
+	var stored []URLDTO
+	store := &batchDTOStore{batchFn: func(urls []URLDTO) map[string]string {
+		stored = urls
+		result := make(map[string]string, len(urls))
+		for _, url := range urls {
+			result[url.ShortURL] = url.OriginalURL
+		}
+		return result
+	}}
+	svc := NewURLService(store, NewRandomCodeGenerator(DefaultShortCodeLength), nil, nil, nil)
+	got, err := svc.ShortenURLs(context.Background(), []ShortenRequest{
+		{OriginalURL: "HTTPS://Example.com:443/a?b=1&a=2"},
+		{OriginalURL: "https://example.com/a?a=2&b=1"},
+	}, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(stored) != 1 || stored[0].OriginalURL != "HTTPS://Example.com:443/a?b=1&a=2" {
+		t.Fatalf("expected one stored record with the user's input, got %+v", stored)
+	}
+	if len(got) != 2 || got[0] != got[1] {
+		t.Errorf("expected both requests to share a short URL, got %v", got)
+	}
+}
+
+type batchDTOStore struct {
+	stubStore
+	batchFn func(urls []URLDTO) map[string]string
+}
+
+func (s *batchDTOStore) BatchSet(_ context.Context, urls []URLDTO, _ string) (map[string]string, error) {
+	return s.batchFn(urls), nil
+}
+
+// legacyStore хранит канонический вид ссылок; пустое значение означает, что он ещё не пересчитан.
+type legacyStore struct {
+	original   map[string]string
+	normalized map[string]string
+}
+
+func (s *legacyStore) UnnormalizedURLs(_ context.Context, limit int) ([]URLDTO, error) {
+	var urls []URLDTO
+	for key, original := range s.original {
+		if s.normalized[key] == "" && len(urls) < limit {
+			urls = append(urls, URLDTO{ShortURL: key, OriginalURL: original})
+		}
+	}
+	return urls, nil
+}
+
+func (s *legacyStore) SetNormalizedURL(_ context.Context, shortURL, normalizedURL string) error {
+	s.normalized[shortURL] = normalizedURL
+	return nil
+}
+
+func TestNormalizeLegacyURLs(t *testing.T) {
+	store := &legacyStore{
+		original: map[string]string{
+			"a": "HTTPS://Example.com:443/a?b=1&a=2",
+			"b": "https://example.com",
+			"c": "http://[::1",
+		},
+		normalized: map[string]string{},
+	}
+
+	n, err := NormalizeLegacyURLs(context.Background(), store, NewURLNormalizer(false))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := map[string]string{
+		"a": "https://example.com/a?a=2&b=1",
+		"b": "https://example.com/",
+		"c": "http://[::1",
+	}
+	if n != len(want) || !reflect.DeepEqual(store.normalized, want) {
+		t.Errorf("expected %d urls normalized to %v, got %d and %v", len(want), want, n, store.normalized)
+	}
+}
@@ -0,0 +1,118 @@
+package service
+
+import (
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	// maxPasswordLength — ограничение bcrypt на длину пароля в байтах.
+	maxPasswordLength = 72
+	// maxPasswordAttempts — число неудачных попыток ввода пароля, после которого ссылка блокируется.
+	maxPasswordAttempts = 5
+	// passwordAttemptWindow — окно, в течение которого считаются неудачные попытки.
+	passwordAttemptWindow = 15 * time.Minute
+	// maxLimiterEntries ограничивает число ключей, для которых passwordLimiter хранит попытки.
+	maxLimiterEntries = 10000
+)
+
+// hashPassword возвращает bcrypt-хэш пароля ссылки.
+func hashPassword(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+// checkPassword сравнивает пароль с bcrypt-хэшем.
+func checkPassword(hash, password string) bool {
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
+}
+
+// passwordAttempts хранит неудачные попытки ввода пароля для одного ключа.
+type passwordAttempts struct {
+	failures int
+	since    time.Time
+}
+
+// passwordLimiter ограничивает число неудачных попыток ввода пароля для каждого ключа:
+// короткой ссылки или пары логина и адреса клиента. Истёкшие записи удаляются раз в окно,
+// а число записей не превышает maxEntries: при переполнении вытесняется самая старая.
+type passwordLimiter struct {
+	mu         sync.Mutex
+	max        int
+	window     time.Duration
+	maxEntries int
+	sweptAt    time.Time
+	attempts   map[string]*passwordAttempts
+}
+
+// newPasswordLimiter создаёт passwordLimiter, допускающий max неудачных попыток за window.
+func newPasswordLimiter(max int, window time.Duration) *passwordLimiter {
+	return &passwordLimiter{max: max, window: window, maxEntries: maxLimiterEntries, attempts: make(map[string]*passwordAttempts)}
+}
+
+// allow сообщает, можно ли сейчас проверять пароль для key.
+func (l *passwordLimiter) allow(key string, now time.Time) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	a, ok := l.attempts[key]
+	if !ok {
+		return true
+	}
+	if now.Sub(a.since) >= l.window {
+		delete(l.attempts, key)
+		return true
+	}
+	return a.failures < l.max
+}
+
+// fail засчитывает неудачную попытку для key.
+func (l *passwordLimiter) fail(key string, now time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	a, ok := l.attempts[key]
+	if !ok || now.Sub(a.since) >= l.window {
+		if !ok {
+			l.makeRoom(now)
+		}
+		a = &passwordAttempts{since: now}
+		l.attempts[key] = a
+	}
+	a.failures++
+}
+
+// reset сбрасывает счётчик после успешного ввода пароля.
+func (l *passwordLimiter) reset(key string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.attempts, key)
+}
+
+// makeRoom удаляет истёкшие записи не чаще раза в окно и вытесняет самую старую запись,
+// если места для новой всё равно нет. Вызывается под l.mu.
+func (l *passwordLimiter) makeRoom(now time.Time) {
+	if now.Sub(l.sweptAt) >= l.window {
+		for key, a := range l.attempts {
+			if now.Sub(a.since) >= l.window {
+				delete(l.attempts, key)
+			}
+		}
+		l.sweptAt = now
+	}
+	if len(l.attempts) < l.maxEntries {
+		return
+	}
+	var oldest string
+	for key, a := range l.attempts {
+		if oldest == "" || a.since.Before(l.attempts[oldest].since) {
+			oldest = key
+		}
+	}
+	delete(l.attempts, oldest)
+}
@@ -0,0 +1,28 @@
+package service
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestPasswordLimiter_Bounded(t *testing.T) {
+	l := newPasswordLimiter(1, time.Minute)
+	l.maxEntries = 3
+	now := time.Now()
+
+	for i := 0; i < 5; i++ {
+		l.fail(fmt.Sprintf("k%d", i), now.Add(time.Duration(i)*time.Second))
+	}
+	if len(l.attempts) != 3 {
+		t.Fatalf("expected 3 entries, got %d", len(l.attempts))
+	}
+	if !l.allow("k0", now) || l.allow("k4", now) {
+		t.Error("expected the oldest entries to be evicted first")
+	}
+
+	l.fail("late", now.Add(2*time.Minute))
+	if len(l.attempts) != 1 {
+		t.Errorf("expected expired entries to be swept, got %d", len(l.attempts))
+	}
+}
@@ -0,0 +1,255 @@
+package service
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/idna"
+)
+
+// Причины отклонения ссылки политикой назначения. Возвращаются клиенту как есть,
+// поэтому не должны меняться.
+const (
+	ReasonURLTooLong       = "url_too_long"
+	ReasonSchemeNotAllowed = "scheme_not_allowed"
+	ReasonDomainDenied     = "domain_denied"
+	ReasonPatternDenied    = "pattern_denied"
+	ReasonPrivateAddress   = "private_address"
+	ReasonSelfReference    = "self_reference"
+)
+
+// PolicyError описывает отклонение URL политикой назначения.
+// errors.Is(err, ErrDestinationRejected) истинно для любой PolicyError.
+type PolicyError struct {
+	Reason string
+	URL    string
+}
+
+func (e *PolicyError) Error() string {
+	return fmt.Sprintf("%s (%s): %s", ErrDestinationRejected, e.Reason, e.URL)
+}
+
+func (e *PolicyError) Unwrap() error {
+	return ErrDestinationRejected
+}
+
+// DestinationPolicyOptions задаёт параметры политики назначения.
+// AllowedSchemes — разрешённые схемы; если список пуст, разрешены http и https.
+// DenylistPath — необязательный файл со списком запрещённых доменов и регулярных выражений.
+// MaxLength — максимальная длина URL в байтах; 0 снимает ограничение.
+// AllowPrivateIPs разрешает ссылки на loopback, частные и link-local адреса.
+// BaseAddress — адрес самого сервиса: ссылки на его хост отклоняются, чтобы не было циклов.
+// ShortDomains — хосты дополнительных коротких доменов, ссылки на которые отклоняются так же.
+type DestinationPolicyOptions struct {
+	AllowedSchemes  []string
+	DenylistPath    string
+	MaxLength       int
+	AllowPrivateIPs bool
+	BaseAddress     string
+	ShortDomains    []string
+}
+
+// DestinationPolicy проверяет, можно ли сокращать URL.
+type DestinationPolicy struct {
+	schemes         map[string]struct{}
+	deniedDomains   []string
+	deniedPatterns  []*regexp.Regexp
+	maxLength       int
+	allowPrivateIPs bool
+	ownHosts        map[string]struct{}
+}
+
+// NewDestinationPolicy создаёт DestinationPolicy и загружает denylist, если указан путь к нему.
+func NewDestinationPolicy(opts DestinationPolicyOptions) (*DestinationPolicy, error) {
+	p := &DestinationPolicy{
+		schemes:         make(map[string]struct{}),
+		maxLength:       opts.MaxLength,
+		allowPrivateIPs: opts.AllowPrivateIPs,
+		ownHosts:        make(map[string]struct{}),
+	}
+
+	for _, scheme := range opts.AllowedSchemes {
+		if scheme = strings.ToLower(strings.TrimSpace(scheme)); scheme != "" {
+			p.schemes[scheme] = struct{}{}
+		}
+	}
+	if len(p.schemes) == 0 {
+		p.schemes["http"] = struct{}{}
+		p.schemes["https"] = struct{}{}
+	}
+
+	if opts.DenylistPath != "" {
+		file, err := os.Open(opts.DenylistPath)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		if p.deniedDomains, p.deniedPatterns, err = parseDenylist(file); err != nil {
+			return nil, fmt.Errorf("denylist %s: %w", opts.DenylistPath, err)
+		}
+	}
+
+	if opts.BaseAddress != "" {
+		base, err := url.Parse(opts.BaseAddress)
+		if err != nil {
+			return nil, fmt.Errorf("invalid base address: %w", err)
+		}
+		p.ownHosts[canonicalHost(base.Hostname())] = struct{}{}
+	}
+	for _, domain := range opts.ShortDomains {
+		host := domain
+		if h, _, err := net.SplitHostPort(domain); err == nil {
+			host = h
+		}
+		p.ownHosts[canonicalHost(host)] = struct{}{}
+	}
+
+	return p, nil
+}
+
+// parseDenylist читает denylist: по одной записи на строку, пустые строки и строки,
+// начинающиеся с '#', пропускаются. Запись вида /выражение/ — регулярное выражение
+// для всего URL, остальные записи — домены, запрещённые вместе с поддоменами.
+func parseDenylist(r io.Reader) ([]string, []*regexp.Regexp, error) {
+	var domains []string
+	var patterns []*regexp.Regexp
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		entry := strings.TrimSpace(scanner.Text())
+		if entry == "" || strings.HasPrefix(entry, "#") {
+			continue
+		}
+		if len(entry) > 1 && strings.HasPrefix(entry, "/") && strings.HasSuffix(entry, "/") {
+			re, err := regexp.Compile(entry[1 : len(entry)-1])
+			if err != nil {
+				return nil, nil, fmt.Errorf("line %d: %w", line, err)
+			}
+			patterns = append(patterns, re)
+			continue
+		}
+		domains = append(domains, canonicalHost(entry))
+	}
+	return domains, patterns, scanner.Err()
+}
+
+// Check возвращает *PolicyError, если rawURL нарушает политику.
+// Nil-получатель пропускает любой URL.
+// Хост не резолвится через DNS: проверяются только IP-адреса, указанные в URL явно, и localhost.
+func (p *DestinationPolicy) Check(rawURL string) error {
+	if p == nil {
+		return nil
+	}
+	reject := func(reason string) error {
+		return &PolicyError{Reason: reason, URL: rawURL}
+	}
+
+	if p.maxLength > 0 && len(rawURL) > p.maxLength {
+		return reject(ReasonURLTooLong)
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return err
+	}
+	if _, ok := p.schemes[strings.ToLower(u.Scheme)]; !ok {
+		return reject(ReasonSchemeNotAllowed)
+	}
+
+	host := canonicalHost(u.Hostname())
+	if _, own := p.ownHosts[host]; own && host != "" {
+		return reject(ReasonSelfReference)
+	}
+	if !p.allowPrivateIPs && isPrivateHost(host) {
+		return reject(ReasonPrivateAddress)
+	}
+	for _, domain := range p.deniedDomains {
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return reject(ReasonDomainDenied)
+		}
+	}
+	for _, re := range p.deniedPatterns {
+		if re.MatchString(rawURL) {
+			return reject(ReasonPatternDenied)
+		}
+	}
+	return nil
+}
+
+// canonicalHost приводит имя хоста к нижнему регистру и punycode без завершающей точки.
+func canonicalHost(host string) string {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	if net.ParseIP(host) != nil {
+		return host
+	}
+	if ascii, err := idna.Lookup.ToASCII(host); err == nil {
+		return ascii
+	}
+	return host
+}
+
+// isPrivateHost сообщает, указывает ли хост на локальную машину или внутреннюю сеть.
+func isPrivateHost(host string) bool {
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return true
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		ip = parseLegacyIPv4(host)
+	}
+	if ip == nil {
+		return false
+	}
+	return ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast()
+}
+
+// parseLegacyIPv4 разбирает IPv4-адрес в устаревших формах inet_aton, которые браузеры
+// принимают в хосте URL: одно число (2130706433), сокращённая запись (127.1),
+// шестнадцатеричные (0x7f000001) и восьмеричные (0177.0.0.1) части.
+// Для хоста, не являющегося такой записью, возвращает nil.
+func parseLegacyIPv4(host string) net.IP {
+	parts := strings.Split(host, ".")
+	if len(parts) > 4 {
+		return nil
+	}
+	values := make([]uint64, len(parts))
+	for i, part := range parts {
+		base := 10
+		switch {
+		case strings.HasPrefix(part, "0x"):
+			base, part = 16, part[2:]
+		case len(part) > 1 && part[0] == '0':
+			base, part = 8, part[1:]
+		}
+		if part == "" && base == 16 {
+			continue // "0x" означает 0
+		}
+		v, err := strconv.ParseUint(part, base, 32)
+		if err != nil {
+			return nil
+		}
+		values[i] = v
+	}
+
+	// Последняя часть заполняет все оставшиеся байты адреса.
+	last := values[len(values)-1]
+	if last >= 1<<(8*(5-len(values))) {
+		return nil
+	}
+	addr := last
+	for i, v := range values[:len(values)-1] {
+		if v > 0xff {
+			return nil
+		}
+		addr |= v << (8 * (3 - i))
+	}
+	return net.IPv4(byte(addr>>24), byte(addr>>16), byte(addr>>8), byte(addr))
+}
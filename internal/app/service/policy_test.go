@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDestinationPolicy_Check(t *testing.T) {
+	denylist := filepath.Join(t.TempDir(), "denylist.txt")
+	content := "# comment\n\nEvil.example\n/\\.exe$/\n"
+	if err := os.WriteFile(denylist, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	policy, err := NewDestinationPolicy(DestinationPolicyOptions{
+		AllowedSchemes: []string{"http", " HTTPS "},
+		DenylistPath:   denylist,
+		MaxLength:      64,
+		BaseAddress:    "http://short.example:8080",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		input  string
+		reason string
+	}{
+		{name: "allowed", input: "https://example.com/path"},
+		{name: "scheme not allowed", input: "ftp://example.com/file", reason: ReasonSchemeNotAllowed},
+		{name: "too long", input: "https://example.com/" + string(make([]byte, 64)), reason: ReasonURLTooLong},
+		{name: "denied domain", input: "https://EVIL.example/", reason: ReasonDomainDenied},
+		{name: "denied subdomain", input: "https://a.evil.example/", reason: ReasonDomainDenied},
+		{name: "similar domain allowed", input: "https://notevil.example/"},
+		{name: "denied pattern", input: "https://example.com/setup.exe", reason: ReasonPatternDenied},
+		{name: "loopback", input: "http://127.0.0.1/admin", reason: ReasonPrivateAddress},
+		{name: "localhost", input: "http://localhost:9000/", reason: ReasonPrivateAddress},
+		{name: "private network", input: "http://10.1.2.3/", reason: ReasonPrivateAddress},
+		{name: "ipv6 loopback", input: "http://[::1]/", reason: ReasonPrivateAddress},
+		{name: "decimal loopback", input: "http://2130706433/", reason: ReasonPrivateAddress},
+		{name: "short dotted loopback", input: "http://127.1/", reason: ReasonPrivateAddress},
+		{name: "hex loopback", input: "http://0x7f000001/", reason: ReasonPrivateAddress},
+		{name: "octal private network", input: "http://012.0.0.1/", reason: ReasonPrivateAddress},
+		{name: "public ip", input: "http://8.8.8.8/"},
+		{name: "decimal public ip", input: "http://134744072/"},
+		{name: "own host", input: "http://Short.Example/abc", reason: ReasonSelfReference},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := policy.Check(tt.input)
+			if tt.reason == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			var policyErr *PolicyError
+			if !errors.As(err, &policyErr) {
+				t.Fatalf("expected PolicyError, got %v", err)
+			}
+			if policyErr.Reason != tt.reason {
+				t.Errorf("expected reason %q, got %q", tt.reason, policyErr.Reason)
+			}
+			if !errors.Is(err, ErrDestinationRejected) {
+				t.Errorf("expected error to wrap ErrDestinationRejected")
+			}
+		})
+	}
+}
+
+func TestDestinationPolicy_AllowPrivateIPs(t *testing.T) {
+	policy, err := NewDestinationPolicy(DestinationPolicyOptions{AllowPrivateIPs: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := policy.Check("http://192.168.0.1/"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := policy.Check("file://host/etc/passwd"); !errors.Is(err, ErrDestinationRejected) {
+		t.Errorf("expected default schemes to reject file://, got %v", err)
+	}
+}
+
+func TestNewDestinationPolicy_InvalidPattern(t *testing.T) {
+	denylist := filepath.Join(t.TempDir(), "denylist.txt")
+	if err := os.WriteFile(denylist, []byte("/[/\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewDestinationPolicy(DestinationPolicyOptions{DenylistPath: denylist}); err == nil {
+		t.Fatal("expected error for invalid pattern")
+	}
+}
+
+func TestShortenURL_PolicyRejected(t *testing.T) {
+	policy, err := NewDestinationPolicy(DestinationPolicyOptions{BaseAddress: "http://localhost:8080"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	svc := NewURLService(&stubStore{}, NewRandomCodeGenerator(DefaultShortCodeLength), nil, policy, nil)
+
+	_, err = svc.ShortenURL(context.Background(), ShortenRequest{OriginalURL: "http://localhost:8080/abc"}, "user")
+	var policyErr *PolicyError
+	if !errors.As(err, &policyErr) || policyErr.Reason != ReasonSelfReference {
+		t.Fatalf("expected self_reference rejection, got %v", err)
+	}
+
+	_, err = svc.ShortenURLs(context.Background(), []ShortenRequest{{OriginalURL: "https://example.com"}, {OriginalURL: "http://127.0.0.1"}}, "user")
+	if !errors.As(err, &policyErr) || policyErr.Reason != ReasonPrivateAddress {
+		t.Fatalf("expected private_address rejection, got %v", err)
+	}
+}
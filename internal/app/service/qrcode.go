@@ -0,0 +1,186 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"strings"
+
+	"github.com/skip2/go-qrcode"
+)
+
+// Форматы изображения QR-кода.
+const (
+	QRFormatPNG = "png"
+	QRFormatSVG = "svg"
+)
+
+// Ограничения и значения по умолчанию для параметров QR-кода.
+const (
+	defaultQRSize   = 256
+	minQRSize       = 64
+	maxQRSize       = 2048
+	defaultQRMargin = 4
+	maxQRMargin     = 16
+)
+
+// qrLevels сопоставляет уровень коррекции ошибок из запроса с уровнем кодировщика.
+var qrLevels = map[string]qrcode.RecoveryLevel{
+	"L": qrcode.Low,
+	"M": qrcode.Medium,
+	"Q": qrcode.High,
+	"H": qrcode.Highest,
+}
+
+// QROptions задаёт вид QR-кода.
+// Format — QRFormatPNG (по умолчанию) или QRFormatSVG.
+// Size — ширина и высота изображения в пикселях; 0 означает 256.
+// Margin — ширина белой рамки в модулях; nil означает 4, как требует стандарт.
+// Level — уровень коррекции ошибок L, M, Q или H; пустая строка означает M.
+type QROptions struct {
+	Format string
+	Size   int
+	Margin *int
+	Level  string
+}
+
+// withDefaults проверяет параметры и подставляет значения по умолчанию.
+func (o QROptions) withDefaults() (QROptions, error) {
+	o.Format = strings.ToLower(o.Format)
+	if o.Format == "" {
+		o.Format = QRFormatPNG
+	}
+	if o.Format != QRFormatPNG && o.Format != QRFormatSVG {
+		return o, ErrInvalidQROptions
+	}
+	if o.Size == 0 {
+		o.Size = defaultQRSize
+	}
+	if o.Size < minQRSize || o.Size > maxQRSize {
+		return o, ErrInvalidQROptions
+	}
+	if o.Margin == nil {
+		margin := defaultQRMargin
+		o.Margin = &margin
+	}
+	if *o.Margin < 0 || *o.Margin > maxQRMargin {
+		return o, ErrInvalidQROptions
+	}
+	o.Level = strings.ToUpper(o.Level)
+	if o.Level == "" {
+		o.Level = "M"
+	}
+	if _, ok := qrLevels[o.Level]; !ok {
+		return o, ErrInvalidQROptions
+	}
+	return o, nil
+}
+
+// QRCoder выдаёт QR-код короткой ссылки.
+type QRCoder interface {
+	// QRCode возвращает изображение QR-кода полного адреса ссылки shortURL и его MIME-тип.
+	QRCode(ctx context.Context, shortURL string, opts QROptions) ([]byte, string, error)
+}
+
+// QRCodeService реализует QRCoder. Код строится локально, без внешних сервисов.
+type QRCodeService struct {
+	store   StoreURLGetter
+	domains DomainResolver
+}
+
+// NewQRCodeService создаёт QRCodeService; полный адрес ссылки строится от базового адреса её домена.
+func NewQRCodeService(store StoreURLGetter, domains DomainResolver) *QRCodeService {
+	return &QRCodeService{store: store, domains: domains}
+}
+
+// QRCode возвращает изображение QR-кода ссылки shortURL и его MIME-тип.
+// Для несуществующих ссылок возвращает ErrURLNotFound, для удалённых — ErrURLDeleted,
+// для некорректных параметров — ErrInvalidQROptions.
+func (s *QRCodeService) QRCode(ctx context.Context, shortURL string, opts QROptions) ([]byte, string, error) {
+	opts, err := opts.withDefaults()
+	if err != nil {
+		return nil, "", err
+	}
+	if _, err = s.store.Get(ctx, shortURL); err != nil {
+		return nil, "", err
+	}
+
+	domain, code := SplitLinkKey(shortURL)
+	qr, err := qrcode.New(s.domains.BaseURL(domain)+"/"+code, qrLevels[opts.Level])
+	if err != nil {
+		return nil, "", fmt.Errorf("encode QR code: %w", err)
+	}
+	qr.DisableBorder = true
+	modules := qr.Bitmap()
+
+	if opts.Format == QRFormatSVG {
+		return renderQRSVG(modules, opts.Size, *opts.Margin), "image/svg+xml", nil
+	}
+	data, err := renderQRPNG(modules, opts.Size, *opts.Margin)
+	if err != nil {
+		return nil, "", err
+	}
+	return data, "image/png", nil
+}
+
+// qrScale возвращает размер модуля в пикселях и отступ, центрирующий код в изображении size.
+// Если size меньше числа модулей, изображение увеличивается до одного пикселя на модуль.
+func qrScale(total, size int) (scale, offset, imageSize int) {
+	scale = size / total
+	if scale < 1 {
+		return 1, 0, total
+	}
+	return scale, (size - scale*total) / 2, size
+}
+
+// renderQRPNG рисует модули QR-кода в чёрно-белый PNG.
+// Модули имеют целый размер в пикселях, чтобы их края оставались чёткими.
+func renderQRPNG(modules [][]bool, size, margin int) ([]byte, error) {
+	total := len(modules) + 2*margin
+	scale, offset, size := qrScale(total, size)
+
+	img := image.NewPaletted(image.Rect(0, 0, size, size), color.Palette{color.White, color.Black})
+	for y, row := range modules {
+		for x, dark := range row {
+			if !dark {
+				continue
+			}
+			x0 := offset + (x+margin)*scale
+			y0 := offset + (y+margin)*scale
+			for py := y0; py < y0+scale; py++ {
+				for px := x0; px < x0+scale; px++ {
+					img.SetColorIndex(px, py, 1)
+				}
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	encoder := png.Encoder{CompressionLevel: png.BestCompression}
+	if err := encoder.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// renderQRSVG рисует модули QR-кода одним контуром SVG в координатах модулей.
+func renderQRSVG(modules [][]bool, size, margin int) []byte {
+	total := len(modules) + 2*margin
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" shape-rendering="crispEdges">`,
+		size, size, total, total)
+	fmt.Fprintf(&buf, `<rect width="%d" height="%d" fill="#fff"/><path fill="#000" d="`, total, total)
+	for y, row := range modules {
+		for x, dark := range row {
+			if dark {
+				fmt.Fprintf(&buf, "M%d %dh1v1h-1z", x+margin, y+margin)
+			}
+		}
+	}
+	buf.WriteString(`"/></svg>`)
+	return buf.Bytes()
+}
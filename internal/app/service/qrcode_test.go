@@ -0,0 +1,105 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+// qrStore знает только ссылку "abc" и удалённую ссылку "gone".
+type qrStore struct {
+	stubStore
+}
+
+func (s *qrStore) Get(_ context.Context, shortURL string) (URLDTO, error) {
+	switch shortURL {
+	case "abc":
+		return URLDTO{ShortURL: shortURL, OriginalURL: "https://example.com"}, nil
+	case "gone":
+		return URLDTO{}, ErrURLDeleted
+	}
+	return URLDTO{}, ErrURLNotFound
+}
+
+// baseURL — DomainResolver с одним базовым адресом для всех доменов.
+type baseURL string
+
+func (b baseURL) BaseURL(string) string {
+	return string(b)
+}
+
+func TestQRCode_PNG(t *testing.T) {
+	svc := NewQRCodeService(&qrStore{}, baseURL("http://localhost:8080"))
+
+	data, contentType, err := svc.QRCode(context.Background(), "abc", QROptions{Size: 300})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if contentType != "image/png" {
+		t.Errorf("expected image/png, got %q", contentType)
+	}
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("response is not a PNG: %v", err)
+	}
+	if b := img.Bounds(); b.Dx() != 300 || b.Dy() != 300 {
+		t.Errorf("expected 300x300 image, got %dx%d", b.Dx(), b.Dy())
+	}
+	if r, _, _, _ := img.At(0, 0).RGBA(); r == 0 {
+		t.Error("expected white quiet zone in the corner")
+	}
+	if r, _, _, _ := img.At(150, 150).RGBA(); r != 0 && r != 0xffff {
+		t.Errorf("expected a black-and-white image, got red channel %d", r)
+	}
+}
+
+func TestQRCode_SVG(t *testing.T) {
+	svc := NewQRCodeService(&qrStore{}, baseURL("http://localhost:8080"))
+	margin := 0
+
+	data, contentType, err := svc.QRCode(context.Background(), "abc", QROptions{Format: "SVG", Margin: &margin, Level: "h"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if contentType != "image/svg+xml" {
+		t.Errorf("expected image/svg+xml, got %q", contentType)
+	}
+	svg := string(data)
+	if !strings.HasPrefix(svg, "<svg") || !strings.Contains(svg, `width="256"`) {
+		t.Errorf("unexpected SVG: %.80s", svg)
+	}
+	// Без рамки верхний левый модуль принадлежит поисковому узору и закрашен.
+	if !strings.Contains(svg, "M0 0h1v1h-1z") {
+		t.Error("expected the finder pattern to start at the origin without margin")
+	}
+}
+
+func TestQRCode_Errors(t *testing.T) {
+	svc := NewQRCodeService(&qrStore{}, baseURL("http://localhost:8080"))
+	negative := -1
+
+	tests := []struct {
+		name  string
+		short string
+		opts  QROptions
+		want  error
+	}{
+		{name: "unknown link", short: "nope", want: ErrURLNotFound},
+		{name: "deleted link", short: "gone", want: ErrURLDeleted},
+		{name: "unknown format", short: "abc", opts: QROptions{Format: "gif"}, want: ErrInvalidQROptions},
+		{name: "too small", short: "abc", opts: QROptions{Size: 10}, want: ErrInvalidQROptions},
+		{name: "too large", short: "abc", opts: QROptions{Size: 10000}, want: ErrInvalidQROptions},
+		{name: "negative margin", short: "abc", opts: QROptions{Margin: &negative}, want: ErrInvalidQROptions},
+		{name: "unknown level", short: "abc", opts: QROptions{Level: "X"}, want: ErrInvalidQROptions},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := svc.QRCode(context.Background(), tt.short, tt.opts); !errors.Is(err, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+}
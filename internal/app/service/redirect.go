@@ -0,0 +1,88 @@
+package service
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// DefaultRedirectCode — код ответа для ссылок, у которых он не задан.
+const DefaultRedirectCode = http.StatusTemporaryRedirect
+
+// redirectCodes перечисляет коды ответа, которые можно назначить ссылке.
+var redirectCodes = map[int]struct{}{
+	http.StatusMovedPermanently:  {},
+	http.StatusFound:             {},
+	http.StatusTemporaryRedirect: {},
+	http.StatusPermanentRedirect: {},
+}
+
+// Redirect описывает перенаправление по короткой ссылке: адрес назначения и код ответа.
+// Variant — имя выданного варианта A/B-сплита; пусто, если сплит не применялся.
+type Redirect struct {
+	URL     string
+	Code    int
+	Variant string
+}
+
+// Visit описывает входящий переход по ссылке.
+// Path — остаток пути после короткого кода, начинающийся с "/"; Query — строка запроса без "?".
+// Их ссылка может перенести в адрес назначения.
+// UserAgent, AcceptLanguage и IP посетителя нужны для выбора правила перенаправления,
+// Variant — вариант A/B-сплита, выданный посетителю при прошлом переходе.
+type Visit struct {
+	Path  string
+	Query string
+
+	UserAgent      string
+	AcceptLanguage string
+	IP             string
+	Variant        string
+}
+
+// redirect строит перенаправление по ссылке с учётом её режимов переноса.
+// Если в запросе есть остаток пути, а ссылка не переносит путь или путь содержит сегмент "..",
+// который вывел бы перенаправление за пределы пути ссылки, возвращает ErrURLNotFound:
+// такого адреса у сервиса нет.
+func (u URLDTO) redirect(visit Visit) (Redirect, error) {
+	code := u.RedirectCode
+	if code == 0 {
+		code = DefaultRedirectCode
+	}
+	result := Redirect{URL: u.OriginalURL, Code: code}
+
+	hasPath := visit.Path != "" && visit.Path != "/"
+	if hasPath && (!u.PassPath || escapesPath(visit.Path)) {
+		return Redirect{}, ErrURLNotFound
+	}
+	if !(hasPath || u.PassQuery && visit.Query != "") {
+		return result, nil
+	}
+
+	dest, err := url.Parse(u.OriginalURL)
+	if err != nil {
+		return Redirect{}, err
+	}
+	if hasPath {
+		dest = dest.JoinPath(visit.Path)
+	}
+	if u.PassQuery && visit.Query != "" {
+		if dest.RawQuery != "" {
+			dest.RawQuery += "&" + visit.Query
+		} else {
+			dest.RawQuery = visit.Query
+		}
+	}
+	result.URL = dest.String()
+	return result, nil
+}
+
+// escapesPath сообщает, что в пути есть сегмент "..", в том числе закодированный.
+func escapesPath(path string) bool {
+	for _, segment := range strings.Split(path, "/") {
+		if decoded, err := url.PathUnescape(segment); err == nil && decoded == ".." {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestURLDTO_Redirect(t *testing.T) {
+	tests := []struct {
+		name     string
+		url      URLDTO
+		visit    Visit
+		wantURL  string
+		wantCode int
+		wantErr  error
+	}{
+		{
+			name:     "default code",
+			url:      URLDTO{OriginalURL: "https://example.com/a"},
+			visit:    Visit{Query: "x=1"},
+			wantURL:  "https://example.com/a",
+			wantCode: http.StatusTemporaryRedirect,
+		},
+		{
+			name:     "permanent",
+			url:      URLDTO{OriginalURL: "https://example.com/a", RedirectCode: http.StatusMovedPermanently},
+			wantURL:  "https://example.com/a",
+			wantCode: http.StatusMovedPermanently,
+		},
+		{
+			name:     "query appended",
+			url:      URLDTO{OriginalURL: "https://example.com/a?src=link", PassQuery: true},
+			visit:    Visit{Path: "/", Query: "utm_source=qr"},
+			wantURL:  "https://example.com/a?src=link&utm_source=qr",
+			wantCode: http.StatusTemporaryRedirect,
+		},
+		{
+			name:     "path joined",
+			url:      URLDTO{OriginalURL: "https://docs.example.com/v1/", PassPath: true, PassQuery: true},
+			visit:    Visit{Path: "/guide/intro", Query: "lang=en"},
+			wantURL:  "https://docs.example.com/v1/guide/intro?lang=en",
+			wantCode: http.StatusTemporaryRedirect,
+		},
+		{
+			name:    "path not allowed",
+			url:     URLDTO{OriginalURL: "https://example.com"},
+			visit:   Visit{Path: "/extra"},
+			wantErr: ErrURLNotFound,
+		},
+		{
+			name:    "path escapes prefix",
+			url:     URLDTO{OriginalURL: "https://docs.example.com/v1/", PassPath: true},
+			visit:   Visit{Path: "/../admin"},
+			wantErr: ErrURLNotFound,
+		},
+		{
+			name:    "encoded path escapes prefix",
+			url:     URLDTO{OriginalURL: "https://docs.example.com/v1/", PassPath: true},
+			visit:   Visit{Path: "/guide/%2e%2E/%2E%2e/admin"},
+			wantErr: ErrURLNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.url.redirect(tt.visit)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+			if got.URL != tt.wantURL || got.Code != tt.wantCode {
+				t.Errorf("expected %s %d, got %s %d", tt.wantURL, tt.wantCode, got.URL, got.Code)
+			}
+		})
+	}
+}
+
+func TestShortenURL_InvalidRedirectCode(t *testing.T) {
+	svc := NewURLService(&stubStore{}, NewRandomCodeGenerator(DefaultShortCodeLength), nil, nil, nil)
+	req := ShortenRequest{OriginalURL: "https://example.com", RedirectCode: http.StatusOK}
+
+	if _, err := svc.ShortenURL(context.Background(), req, "u1"); !errors.Is(err, ErrInvalidRedirectCode) {
+		t.Errorf("expected ErrInvalidRedirectCode, got %v", err)
+	}
+}
@@ -0,0 +1,46 @@
+package service
+
+import (
+	"context"
+	"time"
+)
+
+// StoreRevokedTokens описывает хранение отозванных JWT.
+// Записи с истёкшим expiresAt хранилище может удалять: такие токены не принимаются и без них.
+type StoreRevokedTokens interface {
+	RevokeToken(ctx context.Context, id string, expiresAt time.Time) error
+	IsTokenRevoked(ctx context.Context, id string) (bool, error)
+}
+
+// TokenRevoker ведёт список JWT, отозванных при выходе пользователя.
+type TokenRevoker interface {
+	Revoke(ctx context.Context, id string, expiresAt time.Time) error
+	Revoked(ctx context.Context, id string) (bool, error)
+}
+
+// RevocationService реализует TokenRevoker через StoreRevokedTokens.
+type RevocationService struct {
+	store StoreRevokedTokens
+}
+
+// NewRevocationService создаёт новый RevocationService.
+func NewRevocationService(store StoreRevokedTokens) *RevocationService {
+	return &RevocationService{store: store}
+}
+
+// Revoke отзывает токен id до момента expiresAt, после которого он истекает сам.
+// Уже истёкшие токены и токены без идентификатора не сохраняются.
+func (s *RevocationService) Revoke(ctx context.Context, id string, expiresAt time.Time) error {
+	if id == "" || !expiresAt.After(time.Now()) {
+		return nil
+	}
+	return s.store.RevokeToken(ctx, id, expiresAt)
+}
+
+// Revoked сообщает, отозван ли токен id.
+func (s *RevocationService) Revoked(ctx context.Context, id string) (bool, error) {
+	if id == "" {
+		return false, nil
+	}
+	return s.store.IsTokenRevoked(ctx, id)
+}
@@ -0,0 +1,217 @@
+package service
+
+import (
+	"context"
+	"net/netip"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Устройства посетителя, по которым различаются правила перенаправления.
+const (
+	DeviceIOS     = "ios"
+	DeviceAndroid = "android"
+	DeviceOther   = "other"
+)
+
+// maxRulesPerURL ограничивает число правил перенаправления у одной ссылки.
+const maxRulesPerURL = 20
+
+var (
+	languagePattern = regexp.MustCompile(`^[a-z]{1,8}(-[a-z0-9]{1,8})*$`)
+	countryPattern  = regexp.MustCompile(`^[A-Z]{2}$`)
+)
+
+// RuleMatch задаёт условия правила перенаправления; пустое условие подходит любому посетителю.
+// Device — ios, android или other. Language — языковой тег вроде en или pt-br:
+// он сравнивается с самым предпочтительным языком из Accept-Language, тег en подходит и для en-us.
+// Country — код страны ISO 3166-1 alpha-2, определённый по IP-адресу посетителя.
+type RuleMatch struct {
+	Device   string `json:"device,omitempty"`
+	Language string `json:"language,omitempty"`
+	Country  string `json:"country,omitempty"`
+}
+
+// RedirectRule направляет посетителей, подходящих под Match, на URL вместо основного назначения ссылки.
+type RedirectRule struct {
+	Match RuleMatch `json:"match"`
+	URL   string    `json:"url"`
+}
+
+// visitor содержит признаки посетителя, по которым выбирается правило.
+type visitor struct {
+	device   string
+	language string
+	country  string
+}
+
+// matches сообщает, подходит ли посетитель v под все условия m.
+func (m RuleMatch) matches(v visitor) bool {
+	if m.Device != "" && m.Device != v.device {
+		return false
+	}
+	if m.Language != "" && v.language != m.Language && !strings.HasPrefix(v.language, m.Language+"-") {
+		return false
+	}
+	return m.Country == "" || m.Country == v.country
+}
+
+// route выбирает адрес назначения для перехода visit: URL первого подходящего правила,
+// иначе вариант A/B-сплита, иначе OriginalURL. Второе значение — имя выданного варианта.
+func (s *GetURLService) route(url URLDTO, visit Visit) (string, string) {
+	if len(url.Rules) > 0 {
+		v := s.visitor(visit)
+		for _, rule := range url.Rules {
+			if rule.Match.matches(v) {
+				return rule.URL, ""
+			}
+		}
+	}
+	if len(url.Variants) > 0 {
+		if variant := url.pickVariant(visit.Variant); variant.Name != "" {
+			return variant.URL, variant.Name
+		}
+	}
+	return url.OriginalURL, ""
+}
+
+// visitor определяет устройство, язык и страну посетителя по данным перехода.
+func (s *GetURLService) visitor(visit Visit) visitor {
+	v := visitor{
+		device:   deviceOf(visit.UserAgent),
+		language: preferredLanguage(visit.AcceptLanguage),
+	}
+	if s.countries != nil {
+		if ip, err := netip.ParseAddr(visit.IP); err == nil {
+			v.country = s.countries.Country(ip)
+		}
+	}
+	return v
+}
+
+// deviceOf определяет устройство по заголовку User-Agent.
+func deviceOf(userAgent string) string {
+	ua := strings.ToLower(userAgent)
+	switch {
+	case strings.Contains(ua, "iphone"), strings.Contains(ua, "ipad"), strings.Contains(ua, "ipod"):
+		return DeviceIOS
+	case strings.Contains(ua, "android"):
+		return DeviceAndroid
+	}
+	return DeviceOther
+}
+
+// preferredLanguage возвращает язык с наибольшим весом q из заголовка Accept-Language
+// в нижнем регистре; при равных весах побеждает указанный раньше. "*" не учитывается.
+func preferredLanguage(header string) string {
+	best, bestQ := "", 0.0
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(part, ";")
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || tag == "*" {
+			continue
+		}
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if q > bestQ {
+			best, bestQ = tag, q
+		}
+	}
+	return best
+}
+
+// normalizeRule приводит условия правила к каноническому виду и проверяет их.
+// Правило без условий и правило с некорректным условием отклоняются с ErrInvalidRule.
+func normalizeRule(rule RedirectRule) (RedirectRule, error) {
+	m := RuleMatch{
+		Device:   strings.ToLower(strings.TrimSpace(rule.Match.Device)),
+		Language: strings.ToLower(strings.TrimSpace(rule.Match.Language)),
+		Country:  strings.ToUpper(strings.TrimSpace(rule.Match.Country)),
+	}
+	if m == (RuleMatch{}) {
+		return RedirectRule{}, ErrInvalidRule
+	}
+	switch m.Device {
+	case "", DeviceIOS, DeviceAndroid, DeviceOther:
+	default:
+		return RedirectRule{}, ErrInvalidRule
+	}
+	if m.Language != "" && !languagePattern.MatchString(m.Language) {
+		return RedirectRule{}, ErrInvalidRule
+	}
+	if m.Country != "" && !countryPattern.MatchString(m.Country) {
+		return RedirectRule{}, ErrInvalidRule
+	}
+	return RedirectRule{Match: m, URL: strings.TrimSpace(rule.URL)}, nil
+}
+
+// StoreURLRules описывает чтение и замену правил перенаправления ссылки её владельцем.
+// Для чужих, удалённых и несуществующих ссылок методы возвращают ErrURLNotFound.
+type StoreURLRules interface {
+	GetRedirectRules(ctx context.Context, shortURL, userID string) ([]RedirectRule, error)
+	SetRedirectRules(ctx context.Context, shortURL, userID string, rules []RedirectRule) error
+}
+
+// RuleManager позволяет владельцу управлять правилами перенаправления своих ссылок.
+type RuleManager interface {
+	GetRules(ctx context.Context, shortURL, userID string) ([]RedirectRule, error)
+	SetRules(ctx context.Context, shortURL, userID string, rules []RedirectRule) ([]RedirectRule, error)
+}
+
+// RuleService реализует RuleManager через StoreURLRules.
+type RuleService struct {
+	store  StoreURLRules
+	policy *DestinationPolicy
+}
+
+// NewRuleService создаёт RuleService. Адреса правил проверяются так же,
+// как основное назначение при создании ссылки.
+func NewRuleService(store StoreURLRules, policy *DestinationPolicy) *RuleService {
+	return &RuleService{store: store, policy: policy}
+}
+
+// GetRules возвращает правила ссылки в порядке проверки.
+func (s *RuleService) GetRules(ctx context.Context, shortURL, userID string) ([]RedirectRule, error) {
+	rules, err := s.store.GetRedirectRules(ctx, shortURL, userID)
+	if err != nil {
+		return nil, err
+	}
+	if rules == nil {
+		rules = []RedirectRule{}
+	}
+	return rules, nil
+}
+
+// SetRules заменяет правила ссылки на rules и возвращает сохранённые правила.
+// Пустой список удаляет все правила. Порядок правил сохраняется: при переходе
+// срабатывает первое подходящее.
+func (s *RuleService) SetRules(ctx context.Context, shortURL, userID string, rules []RedirectRule) ([]RedirectRule, error) {
+	if len(rules) > maxRulesPerURL {
+		return nil, ErrTooManyRules
+	}
+	normalized := make([]RedirectRule, 0, len(rules))
+	for _, rule := range rules {
+		rule, err := normalizeRule(rule)
+		if err != nil {
+			return nil, err
+		}
+		if !isValidURL(rule.URL) {
+			return nil, ErrInvalidURL
+		}
+		if err = s.policy.Check(rule.URL); err != nil {
+			return nil, err
+		}
+		normalized = append(normalized, rule)
+	}
+	if err := s.store.SetRedirectRules(ctx, shortURL, userID, normalized); err != nil {
+		return nil, err
+	}
+	return normalized, nil
+}
@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/netip"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// memRuleStore хранит правила ссылки "abc" пользователя "owner" в памяти.
+type memRuleStore struct {
+	rules []RedirectRule
+}
+
+func (m *memRuleStore) GetRedirectRules(_ context.Context, shortURL, userID string) ([]RedirectRule, error) {
+	if shortURL != "abc" || userID != "owner" {
+		return nil, ErrURLNotFound
+	}
+	return m.rules, nil
+}
+
+func (m *memRuleStore) SetRedirectRules(_ context.Context, shortURL, userID string, rules []RedirectRule) error {
+	if shortURL != "abc" || userID != "owner" {
+		return ErrURLNotFound
+	}
+	m.rules = rules
+	return nil
+}
+
+const testCountryDB = `# CIDR,country
+81.2.69.0/24,GB
+2a02:2e0::/32,de
+`
+
+func TestCountryDB(t *testing.T) {
+	db, err := parseCountryDB(strings.NewReader(testCountryDB))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	tests := map[string]string{
+		"81.2.69.160":         "GB",
+		"81.2.70.1":           "",
+		"::ffff:81.2.69.1":    "GB",
+		"2a02:2e0:3fe:1001::": "DE",
+		"2a02:2e1::1":         "",
+	}
+	for ip, want := range tests {
+		if got := db.Country(netip.MustParseAddr(ip)); got != want {
+			t.Errorf("%s: expected %q, got %q", ip, want, got)
+		}
+	}
+
+	if _, err := parseCountryDB(strings.NewReader("81.2.69.0/24,GBR\n")); err == nil {
+		t.Error("expected error for invalid country code")
+	}
+}
+
+func TestPreferredLanguage(t *testing.T) {
+	tests := map[string]string{
+		"":                            "",
+		"de-DE,de;q=0.9,en;q=0.8":     "de-de",
+		"en;q=0.5, fr;q=0.9, *;q=1.0": "fr",
+		"*":                           "",
+	}
+	for header, want := range tests {
+		if got := preferredLanguage(header); got != want {
+			t.Errorf("%q: expected %q, got %q", header, want, got)
+		}
+	}
+}
+
+func TestGetOriginalURL_Rules(t *testing.T) {
+	countries, err := parseCountryDB(strings.NewReader(testCountryDB))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	store := &expiryStore{url: URLDTO{
+		OriginalURL: "https://example.com",
+		Rules: []RedirectRule{
+			{Match: RuleMatch{Device: DeviceIOS}, URL: "https://apps.apple.com/app/id1"},
+			{Match: RuleMatch{Device: DeviceAndroid}, URL: "https://play.google.com/store/apps/details?id=app"},
+			{Match: RuleMatch{Language: "de", Country: "GB"}, URL: "https://example.com/de-uk"},
+			{Match: RuleMatch{Country: "GB"}, URL: "https://example.co.uk"},
+		},
+	}}
+	svc := NewGetURLService(store, nil, countries)
+
+	tests := []struct {
+		name  string
+		visit Visit
+		want  string
+	}{
+		{"ios", Visit{UserAgent: "Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X)", IP: "81.2.69.1"}, "https://apps.apple.com/app/id1"},
+		{"android", Visit{UserAgent: "Mozilla/5.0 (Linux; Android 14; Pixel 8)"}, "https://play.google.com/store/apps/details?id=app"},
+		{"language and country", Visit{AcceptLanguage: "de-AT,en;q=0.5", IP: "81.2.69.1"}, "https://example.com/de-uk"},
+		{"country", Visit{AcceptLanguage: "en-GB", IP: "81.2.69.1"}, "https://example.co.uk"},
+		{"fallback", Visit{UserAgent: "Mozilla/5.0 (Windows NT 10.0)", IP: "10.0.0.1"}, "https://example.com"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := svc.GetOriginalURL(context.Background(), "key", tt.visit)
+			if err != nil || got.URL != tt.want {
+				t.Errorf("expected %q, got %q, %v", tt.want, got.URL, err)
+			}
+		})
+	}
+}
+
+func TestRuleService_SetRules(t *testing.T) {
+	store := &memRuleStore{}
+	svc := NewRuleService(store, nil)
+	ctx := context.Background()
+
+	got, err := svc.SetRules(ctx, "abc", "owner", []RedirectRule{
+		{Match: RuleMatch{Device: " iOS ", Language: "PT-br", Country: "br"}, URL: "https://example.com/br"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []RedirectRule{{Match: RuleMatch{Device: DeviceIOS, Language: "pt-br", Country: "BR"}, URL: "https://example.com/br"}}
+	if !reflect.DeepEqual(got, want) || !reflect.DeepEqual(store.rules, want) {
+		t.Errorf("expected %v, got %v (stored %v)", want, got, store.rules)
+	}
+
+	tests := []struct {
+		name  string
+		rules []RedirectRule
+		want  error
+	}{
+		{"no conditions", []RedirectRule{{URL: "https://example.com"}}, ErrInvalidRule},
+		{"unknown device", []RedirectRule{{Match: RuleMatch{Device: "tablet"}, URL: "https://example.com"}}, ErrInvalidRule},
+		{"bad country", []RedirectRule{{Match: RuleMatch{Country: "GBR"}, URL: "https://example.com"}}, ErrInvalidRule},
+		{"bad url", []RedirectRule{{Match: RuleMatch{Country: "GB"}, URL: "not a url"}}, ErrInvalidURL},
+		{"too many", make([]RedirectRule, maxRulesPerURL+1), ErrTooManyRules},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := svc.SetRules(ctx, "abc", "owner", tt.rules); !errors.Is(err, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+
+	if _, err := svc.SetRules(ctx, "abc", "stranger", nil); !errors.Is(err, ErrURLNotFound) {
+		t.Errorf("expected ErrURLNotFound, got %v", err)
+	}
+	if rules, err := svc.GetRules(ctx, "abc", "owner"); err != nil || len(rules) != 1 {
+		t.Errorf("expected stored rule, got %v, %v", rules, err)
+	}
+}
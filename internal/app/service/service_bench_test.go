@@ -4,6 +4,7 @@ import (
 	"context"
 	"sync"
 	"testing"
+	"time"
 )
 
 type memStore struct {
@@ -18,28 +19,30 @@ func (m *memStore) Get(_ context.Context, shortURL string) (string, bool) {
 	return v, ok
 }
 
-func (m *memStore) Set(_ context.Context, shortURL, originalURL string, _ string) (string, error) {
+func (m *memStore) Set(_ context.Context, url URLDTO, _ string) (string, error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
-	if existing, ok := m.data[shortURL]; ok {
+	if existing, ok := m.data[url.ShortURL]; ok {
 		return existing, nil
 	}
-	m.data[shortURL] = originalURL
-	return originalURL, nil
+	m.data[url.ShortURL] = url.OriginalURL
+	return url.OriginalURL, nil
 }
 
-func (m *memStore) BatchSet(_ context.Context, urls map[string]string, _ string) (map[string]string, error) {
+func (m *memStore) BatchSet(_ context.Context, urls []URLDTO, _ string) (map[string]string, error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
-	for short, orig := range urls {
-		if _, exists := m.data[short]; !exists {
-			m.data[short] = orig
+	result := make(map[string]string, len(urls))
+	for _, url := range urls {
+		if _, exists := m.data[url.ShortURL]; !exists {
+			m.data[url.ShortURL] = url.OriginalURL
 		}
+		result[url.ShortURL] = url.OriginalURL
 	}
-	return urls, nil
+	return result, nil
 }
 
-func (m *memStore) GetUserURLs(_ context.Context, _ string) ([]URLDTO, error) {
+func (m *memStore) GetUserURLs(_ context.Context, _ string, _ URLQuery) ([]URLDTO, error) {
 	return nil, nil
 }
 
@@ -47,15 +50,23 @@ func (m *memStore) BatchDelete(_ context.Context, _ []string, _ string) error {
 	return nil
 }
 
+func (m *memStore) RestoreURLs(_ context.Context, _ []string, _ string, _ time.Time) ([]string, error) {
+	return nil, nil
+}
+
+func (m *memStore) PurgeDeletedURLs(_ context.Context, _ time.Time) (int, error) {
+	return 0, nil
+}
+
 func newMemStore() *memStore {
 	return &memStore{data: make(map[string]string)}
 }
 
 func BenchmarkShortenURL(b *testing.B) {
-	svc := NewURLService(newMemStore())
-	inputs := make([]string, 100)
+	svc := NewURLService(newMemStore(), NewRandomCodeGenerator(DefaultShortCodeLength), nil, nil, nil)
+	inputs := make([]ShortenRequest, 100)
 	for i := range inputs {
-		inputs[i] = "https://test.com/" + string(rune(i))
+		inputs[i] = ShortenRequest{OriginalURL: "https://test.com/" + string(rune(i))}
 	}
 	b.ReportAllocs()
 	for i := 0; i < b.N; i++ {
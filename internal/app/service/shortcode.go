@@ -0,0 +1,203 @@
+// Package service содержит бизнес-логику работы с URL.
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Стратегии генерации коротких кодов.
+const (
+	ShortCodeRandom   = "random"
+	ShortCodeSequence = "sequence"
+	ShortCodeHash     = "hash"
+	ShortCodeWords    = "words"
+)
+
+// DefaultShortCodeLength — длина короткого кода по умолчанию.
+const DefaultShortCodeLength = 6
+
+// DefaultShortCodeWords — число слов в коде стратегии words по умолчанию.
+const DefaultShortCodeWords = 2
+
+const base62Alphabet = "0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
+
+// ShortCodeGenerator генерирует короткие коды для новых ссылок.
+// attempt — номер попытки, начиная с 0: при коллизии сервис вызывает Generate повторно
+// с увеличенным attempt, и детерминированные стратегии используют его, чтобы получить другой код.
+type ShortCodeGenerator interface {
+	Generate(ctx context.Context, originalURL string, attempt int) (string, error)
+}
+
+// StoreCodeSequence описывает общий для всех экземпляров сервиса счётчик стратегии sequence,
+// который сохраняется между перезапусками.
+type StoreCodeSequence interface {
+	NextCodeSequence(ctx context.Context) (uint64, error)
+}
+
+// NewShortCodeGenerator создаёт генератор по имени стратегии.
+// length задаёт длину кода, words — количество слов для стратегии words.
+// Стратегия sequence берёт значения счётчика из sequence.
+func NewShortCodeGenerator(strategy string, length, words int, sequence StoreCodeSequence) (ShortCodeGenerator, error) {
+	if length <= 0 {
+		return nil, fmt.Errorf("short code length must be positive, got %d", length)
+	}
+	switch strategy {
+	case ShortCodeRandom, "":
+		return NewRandomCodeGenerator(length), nil
+	case ShortCodeSequence:
+		if sequence == nil {
+			return nil, fmt.Errorf("short code strategy %q requires a sequence store", strategy)
+		}
+		return NewSequenceCodeGenerator(length, sequence), nil
+	case ShortCodeHash:
+		return NewHashCodeGenerator(length), nil
+	case ShortCodeWords:
+		if words <= 0 {
+			return nil, fmt.Errorf("short code word count must be positive, got %d", words)
+		}
+		return NewWordCodeGenerator(words), nil
+	}
+	return nil, fmt.Errorf("unknown short code strategy %q", strategy)
+}
+
+// RandomCodeGenerator генерирует случайные коды из base62-алфавита через crypto/rand.
+type RandomCodeGenerator struct {
+	length int
+}
+
+// NewRandomCodeGenerator создаёт RandomCodeGenerator для кодов длины length.
+func NewRandomCodeGenerator(length int) *RandomCodeGenerator {
+	return &RandomCodeGenerator{length: length}
+}
+
+// Generate возвращает новый случайный код; аргументы не используются.
+func (g *RandomCodeGenerator) Generate(_ context.Context, _ string, _ int) (string, error) {
+	b := make([]byte, g.length)
+	limit := big.NewInt(int64(len(base62Alphabet)))
+	for i := range b {
+		n, err := rand.Int(rand.Reader, limit)
+		if err != nil {
+			return "", err
+		}
+		b[i] = base62Alphabet[n.Int64()]
+	}
+	return string(b), nil
+}
+
+// SequenceCodeGenerator кодирует в base62 значения монотонного счётчика.
+// Коды дополняются слева до length символов и с ростом счётчика становятся длиннее.
+type SequenceCodeGenerator struct {
+	length   int
+	sequence StoreCodeSequence
+}
+
+// NewSequenceCodeGenerator создаёт SequenceCodeGenerator, берущий значения из sequence.
+// Счётчик хранится в хранилище, поэтому после перезапуска и в нескольких экземплярах
+// сервиса значения не повторяются.
+func NewSequenceCodeGenerator(length int, sequence StoreCodeSequence) *SequenceCodeGenerator {
+	return &SequenceCodeGenerator{length: length, sequence: sequence}
+}
+
+// Generate возвращает base62-представление следующего значения счётчика.
+func (g *SequenceCodeGenerator) Generate(ctx context.Context, _ string, _ int) (string, error) {
+	n, err := g.sequence.NextCodeSequence(ctx)
+	if err != nil {
+		return "", err
+	}
+	code := encodeBase62(n)
+	if len(code) < g.length {
+		code = strings.Repeat(base62Alphabet[:1], g.length-len(code)) + code
+	}
+	return code, nil
+}
+
+// HashCodeGenerator строит код из SHA-256 оригинального URL,
+// поэтому один и тот же URL при первой попытке всегда получает один и тот же код.
+type HashCodeGenerator struct {
+	length int
+}
+
+// NewHashCodeGenerator создаёт HashCodeGenerator для кодов длины length.
+func NewHashCodeGenerator(length int) *HashCodeGenerator {
+	return &HashCodeGenerator{length: length}
+}
+
+// Generate возвращает код из хеша originalURL; при attempt > 0 к URL подмешивается номер попытки.
+func (g *HashCodeGenerator) Generate(_ context.Context, originalURL string, attempt int) (string, error) {
+	input := originalURL
+	if attempt > 0 {
+		input += "#" + strconv.Itoa(attempt)
+	}
+	sum := sha256.Sum256([]byte(input))
+
+	var code strings.Builder
+	for i := 0; code.Len() < g.length; i += 8 {
+		if i+8 > len(sum) {
+			sum = sha256.Sum256(sum[:])
+			i = 0
+		}
+		code.WriteString(encodeBase62(binary.BigEndian.Uint64(sum[i : i+8])))
+	}
+	return code.String()[:g.length], nil
+}
+
+// WordCodeGenerator генерирует читаемые коды вида "brave-otter-42".
+type WordCodeGenerator struct {
+	words int
+}
+
+// NewWordCodeGenerator создаёт WordCodeGenerator, составляющий код из words слов.
+func NewWordCodeGenerator(words int) *WordCodeGenerator {
+	return &WordCodeGenerator{words: words}
+}
+
+// Generate возвращает words случайных слов и число через дефис.
+// С каждой попыткой диапазон числа растёт, чтобы уменьшить вероятность повторной коллизии.
+func (g *WordCodeGenerator) Generate(_ context.Context, _ string, attempt int) (string, error) {
+	parts := make([]string, 0, g.words+1)
+	for i := 0; i < g.words; i++ {
+		list := slugNouns
+		if i < g.words-1 {
+			list = slugAdjectives
+		}
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(len(list))))
+		if err != nil {
+			return "", err
+		}
+		parts = append(parts, list[n.Int64()])
+	}
+
+	limit := big.NewInt(100)
+	for i := 0; i < attempt; i++ {
+		limit.Mul(limit, big.NewInt(10))
+	}
+	n, err := rand.Int(rand.Reader, limit)
+	if err != nil {
+		return "", err
+	}
+	parts = append(parts, n.String())
+
+	return strings.Join(parts, "-"), nil
+}
+
+func encodeBase62(n uint64) string {
+	if n == 0 {
+		return base62Alphabet[:1]
+	}
+	var b []byte
+	for n > 0 {
+		b = append(b, base62Alphabet[n%62])
+		n /= 62
+	}
+	for i, j := 0, len(b)-1; i < j; i, j = i+1, j-1 {
+		b[i], b[j] = b[j], b[i]
+	}
+	return string(b)
+}
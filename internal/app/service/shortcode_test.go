@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"regexp"
+	"testing"
+)
+
+type memSequence struct {
+	n uint64
+}
+
+func (s *memSequence) NextCodeSequence(_ context.Context) (uint64, error) {
+	s.n++
+	return s.n, nil
+}
+
+func TestNewShortCodeGenerator(t *testing.T) {
+	for _, strategy := range []string{ShortCodeRandom, ShortCodeSequence, ShortCodeHash, ShortCodeWords} {
+		gen, err := NewShortCodeGenerator(strategy, 3, DefaultShortCodeWords, &memSequence{})
+		if err != nil {
+			t.Fatalf("strategy %q: unexpected error %v", strategy, err)
+		}
+		code, err := gen.Generate(context.Background(), "https://example.com", 0)
+		if err != nil {
+			t.Fatalf("strategy %q: unexpected error %v", strategy, err)
+		}
+		if err := ValidateAlias(code); err != nil {
+			t.Errorf("strategy %q generated invalid code %q: %v", strategy, code, err)
+		}
+	}
+
+	if _, err := NewShortCodeGenerator("unknown", 6, DefaultShortCodeWords, nil); err == nil {
+		t.Error("expected error for unknown strategy")
+	}
+	if _, err := NewShortCodeGenerator(ShortCodeRandom, 0, DefaultShortCodeWords, nil); err == nil {
+		t.Error("expected error for zero length")
+	}
+	if _, err := NewShortCodeGenerator(ShortCodeSequence, 6, DefaultShortCodeWords, nil); err == nil {
+		t.Error("expected error for sequence strategy without a sequence store")
+	}
+	if _, err := NewShortCodeGenerator(ShortCodeWords, 6, 0, nil); err == nil {
+		t.Error("expected error for zero word count")
+	}
+}
+
+func TestRandomCodeGenerator(t *testing.T) {
+	gen := NewRandomCodeGenerator(8)
+	code, _ := gen.Generate(context.Background(), "", 0)
+	if !regexp.MustCompile(`^[0-9a-zA-Z]{8}$`).MatchString(code) {
+		t.Errorf("unexpected code %q", code)
+	}
+}
+
+func TestSequenceCodeGenerator(t *testing.T) {
+	gen := NewSequenceCodeGenerator(4, &memSequence{n: 60})
+	first, _ := gen.Generate(context.Background(), "", 0)
+	second, _ := gen.Generate(context.Background(), "", 0)
+	if first != "000Z" || second != "0010" {
+		t.Errorf("expected 000Z and 0010, got %q and %q", first, second)
+	}
+}
+
+func TestHashCodeGenerator(t *testing.T) {
+	gen := NewHashCodeGenerator(10)
+	a, _ := gen.Generate(context.Background(), "https://example.com", 0)
+	b, _ := gen.Generate(context.Background(), "https://example.com", 0)
+	c, _ := gen.Generate(context.Background(), "https://example.com", 1)
+	if len(a) != 10 || a != b {
+		t.Errorf("expected stable 10-char code, got %q and %q", a, b)
+	}
+	if a == c {
+		t.Errorf("expected different code on retry, got %q", c)
+	}
+}
+
+func TestWordCodeGenerator(t *testing.T) {
+	gen := NewWordCodeGenerator(2)
+	code, _ := gen.Generate(context.Background(), "", 0)
+	if !regexp.MustCompile(`^[a-z]+-[a-z]+-[0-9]{1,2}$`).MatchString(code) {
+		t.Errorf("unexpected code %q", code)
+	}
+}
@@ -0,0 +1,25 @@
+package service
+
+// slugAdjectives и slugNouns — словари для WordCodeGenerator.
+// Слова состоят только из символов, допустимых в алиасах.
+var slugAdjectives = []string{
+	"able", "bold", "brave", "bright", "calm", "clever", "cool", "cozy",
+	"crisp", "curious", "daring", "eager", "early", "easy", "fair", "fancy",
+	"fast", "fierce", "fresh", "friendly", "gentle", "glad", "golden", "grand",
+	"happy", "honest", "humble", "jolly", "keen", "kind", "lively", "lucky",
+	"merry", "mighty", "modern", "neat", "nimble", "noble", "polite", "proud",
+	"quick", "quiet", "rapid", "rare", "ready", "royal", "shiny", "silent",
+	"simple", "smart", "smooth", "solid", "spicy", "steady", "sunny", "swift",
+	"tidy", "tiny", "vivid", "warm", "wise", "witty", "young", "zesty",
+}
+
+var slugNouns = []string{
+	"apple", "arrow", "badger", "beacon", "bear", "bison", "breeze", "brook",
+	"cactus", "canyon", "cedar", "cloud", "comet", "coral", "crane", "delta",
+	"dolphin", "dune", "eagle", "ember", "falcon", "fern", "field", "finch",
+	"forest", "fox", "garden", "glacier", "harbor", "hawk", "heron", "island",
+	"jaguar", "lagoon", "lake", "lantern", "lemon", "lion", "maple", "meadow",
+	"mango", "meteor", "moon", "otter", "owl", "panda", "pebble", "pine",
+	"planet", "prairie", "quartz", "rabbit", "raven", "reef", "river", "rocket",
+	"sparrow", "spruce", "star", "stone", "tiger", "tulip", "valley", "willow",
+}
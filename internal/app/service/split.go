@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"math/rand/v2"
+	"regexp"
+	"strings"
+)
+
+const (
+	// maxSplitVariants ограничивает число вариантов A/B-сплита у одной ссылки.
+	maxSplitVariants = 10
+	// maxSplitWeight ограничивает вес одного варианта.
+	maxSplitWeight = 10000
+)
+
+var variantNamePattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,32}$`)
+
+// SplitVariant — вариант A/B-сплита: адрес назначения и его доля трафика.
+// Доля варианта равна его весу, делённому на сумму весов всех вариантов ссылки;
+// вариант с весом 0 новым посетителям не выдаётся.
+type SplitVariant struct {
+	Name   string `json:"name"`
+	URL    string `json:"url"`
+	Weight int    `json:"weight"`
+}
+
+// pickVariant выбирает вариант для посетителя. Вариант remembered, выданный посетителю раньше,
+// сохраняется, пока он есть у ссылки и его вес больше нуля; иначе вариант выбирается случайно по весам.
+func (u URLDTO) pickVariant(remembered string) SplitVariant {
+	total := 0
+	for _, v := range u.Variants {
+		if v.Weight > 0 && v.Name == remembered {
+			return v
+		}
+		total += v.Weight
+	}
+	if total <= 0 {
+		return SplitVariant{}
+	}
+	n := rand.IntN(total)
+	for _, v := range u.Variants {
+		if n < v.Weight {
+			return v
+		}
+		n -= v.Weight
+	}
+	return SplitVariant{}
+}
+
+// StoreURLSplitter описывает чтение и замену вариантов A/B-сплита ссылки её владельцем.
+// Для чужих, удалённых и несуществующих ссылок методы возвращают ErrURLNotFound.
+type StoreURLSplitter interface {
+	GetSplitVariants(ctx context.Context, shortURL, userID string) ([]SplitVariant, error)
+	SetSplitVariants(ctx context.Context, shortURL, userID string, variants []SplitVariant) error
+}
+
+// URLSplitter позволяет владельцу настраивать A/B-сплит своих ссылок.
+type URLSplitter interface {
+	GetVariants(ctx context.Context, shortURL, userID string) ([]SplitVariant, error)
+	SetVariants(ctx context.Context, shortURL, userID string, variants []SplitVariant) ([]SplitVariant, error)
+}
+
+// SplitService реализует URLSplitter через StoreURLSplitter.
+type SplitService struct {
+	store  StoreURLSplitter
+	policy *DestinationPolicy
+}
+
+// NewSplitService создаёт SplitService. Адреса вариантов проверяются так же,
+// как основное назначение при создании ссылки.
+func NewSplitService(store StoreURLSplitter, policy *DestinationPolicy) *SplitService {
+	return &SplitService{store: store, policy: policy}
+}
+
+// GetVariants возвращает варианты A/B-сплита ссылки.
+func (s *SplitService) GetVariants(ctx context.Context, shortURL, userID string) ([]SplitVariant, error) {
+	variants, err := s.store.GetSplitVariants(ctx, shortURL, userID)
+	if err != nil {
+		return nil, err
+	}
+	if variants == nil {
+		variants = []SplitVariant{}
+	}
+	return variants, nil
+}
+
+// SetVariants заменяет варианты A/B-сплита ссылки и возвращает сохранённые варианты.
+// Пустой список отключает сплит. Имена вариантов должны быть уникальны, веса — от 0 до 10000,
+// а их сумма — больше нуля; иначе возвращается ErrInvalidSplit.
+// Посетители, которым уже выдан оставшийся вариант, продолжают получать его.
+func (s *SplitService) SetVariants(ctx context.Context, shortURL, userID string, variants []SplitVariant) ([]SplitVariant, error) {
+	if len(variants) > maxSplitVariants {
+		return nil, ErrInvalidSplit
+	}
+	normalized := make([]SplitVariant, 0, len(variants))
+	seen := make(map[string]struct{}, len(variants))
+	total := 0
+	for _, v := range variants {
+		v.Name = strings.TrimSpace(v.Name)
+		v.URL = strings.TrimSpace(v.URL)
+		if !variantNamePattern.MatchString(v.Name) || v.Weight < 0 || v.Weight > maxSplitWeight {
+			return nil, ErrInvalidSplit
+		}
+		if _, duplicate := seen[v.Name]; duplicate {
+			return nil, ErrInvalidSplit
+		}
+		seen[v.Name] = struct{}{}
+		if !isValidURL(v.URL) {
+			return nil, ErrInvalidURL
+		}
+		if err := s.policy.Check(v.URL); err != nil {
+			return nil, err
+		}
+		total += v.Weight
+		normalized = append(normalized, v)
+	}
+	if len(normalized) > 0 && total == 0 {
+		return nil, ErrInvalidSplit
+	}
+	if err := s.store.SetSplitVariants(ctx, shortURL, userID, normalized); err != nil {
+		return nil, err
+	}
+	return normalized, nil
+}
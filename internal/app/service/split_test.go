@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// memSplitStore хранит варианты ссылки "abc" пользователя "owner" в памяти.
+type memSplitStore struct {
+	variants []SplitVariant
+}
+
+func (m *memSplitStore) GetSplitVariants(_ context.Context, shortURL, userID string) ([]SplitVariant, error) {
+	if shortURL != "abc" || userID != "owner" {
+		return nil, ErrURLNotFound
+	}
+	return m.variants, nil
+}
+
+func (m *memSplitStore) SetSplitVariants(_ context.Context, shortURL, userID string, variants []SplitVariant) error {
+	if shortURL != "abc" || userID != "owner" {
+		return ErrURLNotFound
+	}
+	m.variants = variants
+	return nil
+}
+
+func TestURLDTO_PickVariant(t *testing.T) {
+	u := URLDTO{Variants: []SplitVariant{
+		{Name: "a", URL: "https://example.com/a", Weight: 3},
+		{Name: "b", URL: "https://example.com/b", Weight: 1},
+		{Name: "off", URL: "https://example.com/off", Weight: 0},
+	}}
+
+	counts := make(map[string]int)
+	for range 4000 {
+		counts[u.pickVariant("").Name]++
+	}
+	if counts["off"] != 0 {
+		t.Errorf("variant with zero weight was picked %d times", counts["off"])
+	}
+	if counts["a"] < 2700 || counts["a"] > 3300 {
+		t.Errorf("expected about 3000 picks of a, got %d", counts["a"])
+	}
+
+	if got := u.pickVariant("b").Name; got != "b" {
+		t.Errorf("expected remembered variant b, got %q", got)
+	}
+	if got := u.pickVariant("off").Name; got == "off" {
+		t.Error("remembered variant with zero weight must be replaced")
+	}
+	if got := (URLDTO{}).pickVariant("a"); got != (SplitVariant{}) {
+		t.Errorf("expected no variant without split, got %v", got)
+	}
+}
+
+func TestGetOriginalURL_Split(t *testing.T) {
+	store := &expiryStore{url: URLDTO{
+		OriginalURL: "https://example.com",
+		Rules:       []RedirectRule{{Match: RuleMatch{Device: DeviceIOS}, URL: "https://apps.apple.com/app/id1"}},
+		Variants:    []SplitVariant{{Name: "a", URL: "https://example.com/a", Weight: 1}, {Name: "b", URL: "https://example.com/b", Weight: 1}},
+	}}
+	svc := NewGetURLService(store, nil, nil)
+
+	got, err := svc.GetOriginalURL(context.Background(), "key", Visit{Variant: "b"})
+	if err != nil || got.URL != "https://example.com/b" || got.Variant != "b" {
+		t.Errorf("expected sticky variant b, got %+v, %v", got, err)
+	}
+
+	got, err = svc.GetOriginalURL(context.Background(), "key", Visit{UserAgent: "Mozilla/5.0 (iPhone)", Variant: "b"})
+	if err != nil || got.URL != "https://apps.apple.com/app/id1" || got.Variant != "" {
+		t.Errorf("expected rule to take precedence over split, got %+v, %v", got, err)
+	}
+}
+
+func TestSplitService_SetVariants(t *testing.T) {
+	store := &memSplitStore{}
+	svc := NewSplitService(store, nil)
+	ctx := context.Background()
+
+	got, err := svc.SetVariants(ctx, "abc", "owner", []SplitVariant{
+		{Name: " a ", URL: " https://example.com/a ", Weight: 70},
+		{Name: "b", URL: "https://example.com/b", Weight: 30},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []SplitVariant{
+		{Name: "a", URL: "https://example.com/a", Weight: 70},
+		{Name: "b", URL: "https://example.com/b", Weight: 30},
+	}
+	if !reflect.DeepEqual(got, want) || !reflect.DeepEqual(store.variants, want) {
+		t.Errorf("expected %v, got %v (stored %v)", want, got, store.variants)
+	}
+
+	tests := []struct {
+		name     string
+		variants []SplitVariant
+		want     error
+	}{
+		{"bad name", []SplitVariant{{Name: "a b", URL: "https://example.com", Weight: 1}}, ErrInvalidSplit},
+		{"duplicate name", []SplitVariant{{Name: "a", URL: "https://example.com", Weight: 1}, {Name: "a", URL: "https://example.org", Weight: 1}}, ErrInvalidSplit},
+		{"negative weight", []SplitVariant{{Name: "a", URL: "https://example.com", Weight: -1}}, ErrInvalidSplit},
+		{"zero total", []SplitVariant{{Name: "a", URL: "https://example.com"}}, ErrInvalidSplit},
+		{"bad url", []SplitVariant{{Name: "a", URL: "not a url", Weight: 1}}, ErrInvalidURL},
+		{"too many", make([]SplitVariant, maxSplitVariants+1), ErrInvalidSplit},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := svc.SetVariants(ctx, "abc", "owner", tt.variants); !errors.Is(err, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+
+	if _, err := svc.SetVariants(ctx, "abc", "stranger", nil); !errors.Is(err, ErrURLNotFound) {
+		t.Errorf("expected ErrURLNotFound, got %v", err)
+	}
+	if variants, err := svc.GetVariants(ctx, "abc", "owner"); err != nil || len(variants) != 2 {
+		t.Errorf("expected stored variants, got %v, %v", variants, err)
+	}
+}
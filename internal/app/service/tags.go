@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxTagLength ограничивает длину тега в символах.
+const maxTagLength = 32
+
+// maxTagsPerURL ограничивает число тегов у одной ссылки.
+const maxTagsPerURL = 20
+
+var tagPattern = regexp.MustCompile(`^[\p{L}\p{N}_-]+$`)
+
+// normalizeTags приводит теги к нижнему регистру без пробелов по краям,
+// убирает повторы и сортирует. Для некорректного тега возвращает ErrInvalidTag.
+func normalizeTags(tags []string) ([]string, error) {
+	if len(tags) == 0 {
+		return nil, nil
+	}
+	seen := make(map[string]struct{}, len(tags))
+	result := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if utf8.RuneCountInString(tag) > maxTagLength || !tagPattern.MatchString(tag) {
+			return nil, ErrInvalidTag
+		}
+		if _, duplicate := seen[tag]; duplicate {
+			continue
+		}
+		seen[tag] = struct{}{}
+		result = append(result, tag)
+	}
+	sort.Strings(result)
+	return result, nil
+}
+
+// hasAllTags сообщает, есть ли среди tags все теги из required.
+func hasAllTags(tags, required []string) bool {
+	for _, r := range required {
+		found := false
+		for _, tag := range tags {
+			if tag == r {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// TagCount содержит тег и число ссылок пользователя с этим тегом.
+type TagCount struct {
+	Tag   string
+	Count int
+}
+
+// StoreURLTagger описывает чтение и замену тегов ссылки её владельцем.
+// Для чужих, удалённых и несуществующих ссылок методы возвращают ErrURLNotFound.
+type StoreURLTagger interface {
+	GetURLTags(ctx context.Context, shortURL, userID string) ([]string, error)
+	SetURLTags(ctx context.Context, shortURL, userID string, tags []string) error
+	GetUserTags(ctx context.Context, userID string) ([]TagCount, error)
+}
+
+// URLTagger позволяет владельцу управлять тегами своих ссылок.
+// Методы изменения возвращают теги ссылки после изменения, отсортированные по имени.
+type URLTagger interface {
+	SetTags(ctx context.Context, shortURL, userID string, tags []string) ([]string, error)
+	AddTags(ctx context.Context, shortURL, userID string, tags []string) ([]string, error)
+	RemoveTags(ctx context.Context, shortURL, userID string, tags []string) ([]string, error)
+	ListTags(ctx context.Context, userID string) ([]TagCount, error)
+}
+
+// TagService реализует URLTagger через StoreURLTagger.
+type TagService struct {
+	store StoreURLTagger
+}
+
+// NewTagService создаёт новый TagService.
+func NewTagService(store StoreURLTagger) *TagService {
+	return &TagService{store: store}
+}
+
+// SetTags заменяет все теги ссылки на tags.
+func (s *TagService) SetTags(ctx context.Context, shortURL, userID string, tags []string) ([]string, error) {
+	tags, err := normalizeTags(tags)
+	if err != nil {
+		return nil, err
+	}
+	return s.save(ctx, shortURL, userID, tags)
+}
+
+// AddTags добавляет к ссылке теги tags, сохраняя уже назначенные.
+func (s *TagService) AddTags(ctx context.Context, shortURL, userID string, tags []string) ([]string, error) {
+	tags, err := normalizeTags(tags)
+	if err != nil {
+		return nil, err
+	}
+	current, err := s.store.GetURLTags(ctx, shortURL, userID)
+	if err != nil {
+		return nil, err
+	}
+	merged, _ := normalizeTags(append(current, tags...))
+	return s.save(ctx, shortURL, userID, merged)
+}
+
+// RemoveTags снимает со ссылки теги tags; отсутствующие теги пропускаются.
+func (s *TagService) RemoveTags(ctx context.Context, shortURL, userID string, tags []string) ([]string, error) {
+	tags, err := normalizeTags(tags)
+	if err != nil {
+		return nil, err
+	}
+	current, err := s.store.GetURLTags(ctx, shortURL, userID)
+	if err != nil {
+		return nil, err
+	}
+	remaining := make([]string, 0, len(current))
+	for _, tag := range current {
+		if !hasAllTags(tags, []string{tag}) {
+			remaining = append(remaining, tag)
+		}
+	}
+	return s.save(ctx, shortURL, userID, remaining)
+}
+
+// ListTags возвращает все теги пользователя с числом ссылок, отсортированные по имени.
+func (s *TagService) ListTags(ctx context.Context, userID string) ([]TagCount, error) {
+	return s.store.GetUserTags(ctx, userID)
+}
+
+// save проверяет лимит тегов и сохраняет tags как полный набор тегов ссылки.
+func (s *TagService) save(ctx context.Context, shortURL, userID string, tags []string) ([]string, error) {
+	if len(tags) > maxTagsPerURL {
+		return nil, ErrTooManyTags
+	}
+	if tags == nil {
+		tags = []string{}
+	}
+	if err := s.store.SetURLTags(ctx, shortURL, userID, tags); err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// memTagStore хранит теги ссылки "abc" пользователя "owner" в памяти.
+type memTagStore struct {
+	tags []string
+}
+
+func (m *memTagStore) GetURLTags(_ context.Context, shortURL, userID string) ([]string, error) {
+	if shortURL != "abc" || userID != "owner" {
+		return nil, ErrURLNotFound
+	}
+	return append([]string(nil), m.tags...), nil
+}
+
+func (m *memTagStore) SetURLTags(_ context.Context, shortURL, userID string, tags []string) error {
+	if shortURL != "abc" || userID != "owner" {
+		return ErrURLNotFound
+	}
+	m.tags = tags
+	return nil
+}
+
+func (m *memTagStore) GetUserTags(_ context.Context, _ string) ([]TagCount, error) {
+	counts := make([]TagCount, len(m.tags))
+	for i, tag := range m.tags {
+		counts[i] = TagCount{Tag: tag, Count: 1}
+	}
+	return counts, nil
+}
+
+func TestNormalizeTags(t *testing.T) {
+	got, err := normalizeTags([]string{" Work", "работа", "work ", "q1-2025", "a_b"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []string{"a_b", "q1-2025", "work", "работа"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+
+	for _, bad := range []string{"", "two words", "slash/tag", "x" + fmt.Sprintf("%032d", 0)} {
+		if _, err := normalizeTags([]string{bad}); !errors.Is(err, ErrInvalidTag) {
+			t.Errorf("tag %q: expected ErrInvalidTag, got %v", bad, err)
+		}
+	}
+}
+
+func TestTagService_AddRemove(t *testing.T) {
+	store := &memTagStore{tags: []string{"news"}}
+	svc := NewTagService(store)
+	ctx := context.Background()
+
+	got, err := svc.AddTags(ctx, "abc", "owner", []string{"Work", "news"})
+	if err != nil {
+		t.Fatalf("AddTags: unexpected error: %v", err)
+	}
+	if want := []string{"news", "work"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("AddTags: expected %v, got %v", want, got)
+	}
+
+	got, err = svc.RemoveTags(ctx, "abc", "owner", []string{"NEWS", "missing"})
+	if err != nil {
+		t.Fatalf("RemoveTags: unexpected error: %v", err)
+	}
+	if want := []string{"work"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("RemoveTags: expected %v, got %v", want, got)
+	}
+
+	got, err = svc.SetTags(ctx, "abc", "owner", nil)
+	if err != nil {
+		t.Fatalf("SetTags: unexpected error: %v", err)
+	}
+	if got == nil || len(got) != 0 || len(store.tags) != 0 {
+		t.Errorf("SetTags(nil): expected empty tags, got %v (stored %v)", got, store.tags)
+	}
+
+	if _, err := svc.AddTags(ctx, "abc", "intruder", []string{"x"}); !errors.Is(err, ErrURLNotFound) {
+		t.Errorf("expected ErrURLNotFound for foreign link, got %v", err)
+	}
+}
+
+func TestTagService_Limit(t *testing.T) {
+	store := &memTagStore{}
+	svc := NewTagService(store)
+
+	tags := make([]string, maxTagsPerURL+1)
+	for i := range tags {
+		tags[i] = fmt.Sprintf("tag%d", i)
+	}
+	if _, err := svc.AddTags(context.Background(), "abc", "owner", tags); !errors.Is(err, ErrTooManyTags) {
+		t.Fatalf("expected ErrTooManyTags, got %v", err)
+	}
+	if len(store.tags) != 0 {
+		t.Errorf("tags must not be saved when the limit is exceeded, got %v", store.tags)
+	}
+}
@@ -4,77 +4,433 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net/url"
-
-	"github.com/aseptimu/url-shortener/internal/app/utils"
+	"strings"
+	"time"
+	"unicode/utf8"
 )
 
+// maxTitleLength ограничивает длину заголовка ссылки в символах.
+const maxTitleLength = 200
+
 // Store объединяет интерфейсы для получения, создания и удаления URL.
 type Store interface {
 	StoreURLGetter
 	StoreURLSetter
 	StoreURLDeleter
+	StoreClickTracker
+	StoreURLEditor
+	StoreURLTagger
+	StoreURLExpander
+	StoreURLRules
+	StoreURLSplitter
+	StoreUTMTemplates
+	StoreWorkspaces
+	StoreAPIKeys
+	StoreAccounts
+	StoreRevokedTokens
+	StoreCodeSequence
+	StoreLegacyURLs
 }
 
 // StoreURLSetter описывает методы сохранения одного или нескольких URL.
 type StoreURLSetter interface {
-	Set(ctx context.Context, shortURL, originalURL, userID string) (string, error)
-	BatchSet(ctx context.Context, urls map[string]string, userID string) (map[string]string, error)
+	Set(ctx context.Context, url URLDTO, userID string) (string, error)
+	BatchSet(ctx context.Context, urls []URLDTO, userID string) (map[string]string, error)
+}
+
+// ShortenRequest описывает параметры создания одной короткой ссылки.
+// Alias — необязательный пользовательский короткий код вместо сгенерированного.
+// Domain — короткий домен ссылки, уже проверенный вызывающим кодом; пустая строка означает основной домен.
+// Коды и дубликаты на разных доменах не пересекаются.
+// ExpiresAt — необязательный момент, после которого ссылка перестаёт работать.
+// MaxUses — число разрешённых переходов; 0 означает отсутствие лимита.
+// Password — необязательный пароль, который потребуется ввести перед переходом.
+// Tags — необязательные теги для группировки ссылок.
+// Title — необязательный заголовок для страницы предпросмотра.
+// RedirectCode — код ответа при переходе (301, 302, 307 или 308); 0 означает DefaultRedirectCode.
+// PassQuery переносит строку запроса короткой ссылки в адрес назначения,
+// PassPath — остаток пути после короткого кода.
+// UTMTemplate — необязательное имя UTM-шаблона пользователя, UTM — UTM-параметры,
+// которые дополняют и переопределяют параметры шаблона. Они дописываются к OriginalURL,
+// не затрагивая параметры, которые в нём уже есть.
+// Запрос с алиасом, паролем, лимитом переходов или сроком жизни всегда создаёт новую ссылку:
+// существующая ссылка на тот же URL не подходит, потому что у неё другие параметры.
+type ShortenRequest struct {
+	OriginalURL  string
+	Alias        string
+	Domain       string
+	ExpiresAt    *time.Time
+	MaxUses      int
+	Password     string
+	Tags         []string
+	Title        string
+	RedirectCode int
+	PassQuery    bool
+	PassPath     bool
+	UTMTemplate  string
+	UTM          UTM
+
+	normalizedURL string
+	passwordHash  string
+	// utmParams — имена UTM-параметров, дописанных из шаблона и запроса: они задают кампанию
+	// и поэтому учитываются при поиске дубликатов, даже если трекинговые параметры удаляются.
+	utmParams []string
+}
+
+// toDTO возвращает запись для сохранения в хранилище под кодом code на домене запроса.
+func (r ShortenRequest) toDTO(code string) URLDTO {
+	url := URLDTO{
+		ShortURL:      LinkKey(r.Domain, code),
+		OriginalURL:   r.OriginalURL,
+		NormalizedURL: r.normalizedURL,
+		ExpiresAt:     r.ExpiresAt,
+		PasswordHash:  r.passwordHash,
+		Tags:          r.Tags,
+		Title:         strings.TrimSpace(r.Title),
+		RedirectCode:  r.RedirectCode,
+		PassQuery:     r.PassQuery,
+		PassPath:      r.PassPath,
+		Standalone:    r.standalone(),
+	}
+	if url.RedirectCode == 0 {
+		url.RedirectCode = DefaultRedirectCode
+	}
+	if r.MaxUses > 0 {
+		remaining := r.MaxUses
+		url.RemainingUses = &remaining
+	}
+	return url
+}
+
+// standalone сообщает, что запрос задаёт собственные параметры ссылки
+// и не должен возвращать уже существующую ссылку на тот же URL.
+func (r ShortenRequest) standalone() bool {
+	return r.Alias != "" || r.Password != "" || r.MaxUses > 0 || r.ExpiresAt != nil
+}
+
+// validate проверяет параметры запроса, не зависящие от хранилища.
+func (r ShortenRequest) validate() error {
+	if r.MaxUses < 0 {
+		return ErrInvalidMaxUses
+	}
+	if len(r.Password) > maxPasswordLength {
+		return ErrPasswordTooLong
+	}
+	if len(r.Tags) > maxTagsPerURL {
+		return ErrTooManyTags
+	}
+	if utf8.RuneCountInString(strings.TrimSpace(r.Title)) > maxTitleLength {
+		return ErrTitleTooLong
+	}
+	if _, ok := redirectCodes[r.RedirectCode]; r.RedirectCode != 0 && !ok {
+		return ErrInvalidRedirectCode
+	}
+	if r.Alias != "" {
+		return ValidateAlias(r.Alias)
+	}
+	return nil
+}
+
+// prepare проверяет запрос и URL по policy, вычисляет канонический вид URL, нормализует теги
+// и вычисляет хэш пароля, если он задан.
+func (r ShortenRequest) prepare(normalizer *URLNormalizer, policy *DestinationPolicy) (ShortenRequest, error) {
+	if err := r.validate(); err != nil {
+		return r, err
+	}
+	tags, err := normalizeTags(r.Tags)
+	if err != nil {
+		return r, err
+	}
+	r.Tags = tags
+	if err := policy.Check(r.OriginalURL); err != nil {
+		return r, err
+	}
+	normalized, err := normalizer.normalize(r.OriginalURL, r.utmParams)
+	if err != nil {
+		return r, fmt.Errorf("invalid URL format: %w", err)
+	}
+	r.normalizedURL = normalized
+	if r.Password != "" {
+		hash, err := hashPassword(r.Password)
+		if err != nil {
+			return r, err
+		}
+		r.passwordHash = hash
+	}
+	return r, nil
 }
 
 // URLShortener предоставляет методы для сокращения одного или нескольких URL.
 type URLShortener interface {
-	ShortenURL(ctx context.Context, input string, userID string) (string, error)
-	ShortenURLs(ctx context.Context, inputs []string, userID string) (map[string]string, error)
+	ShortenURL(ctx context.Context, req ShortenRequest, userID string) (string, error)
+	ShortenURLs(ctx context.Context, reqs []ShortenRequest, userID string) ([]string, error)
 }
 
+// maxShortCodeAttempts ограничивает число попыток сгенерировать незанятый короткий код.
+const maxShortCodeAttempts = 5
+
+// ErrShortCodeExhausted возвращается, если за maxShortCodeAttempts попыток
+// не удалось сгенерировать незанятый короткий код.
+var ErrShortCodeExhausted = errors.New("failed to generate a unique short URL")
+
 // URLService реализует URLShortener через StoreURLSetter.
 type URLService struct {
-	store StoreURLSetter
+	store      StoreURLSetter
+	generator  ShortCodeGenerator
+	normalizer *URLNormalizer
+	policy     *DestinationPolicy
+	templates  StoreUTMTemplates
 }
 
-// NewURLService создаёт новый URLService.
-func NewURLService(store StoreURLSetter) *URLService {
-	return &URLService{store: store}
+// NewURLService создаёт новый URLService, генерирующий короткие коды через generator.
+// Дубликаты ищутся по URL, приведённому к каноническому виду normalizer;
+// если normalizer равен nil, трекинговые параметры не удаляются.
+// URL, нарушающие policy, отклоняются с *PolicyError; nil policy пропускает любой корректный URL.
+// UTM-шаблоны, на которые ссылаются запросы, читаются из templates; при nil templates
+// ссылка на шаблон отклоняется с ErrUTMTemplateNotFound.
+func NewURLService(store StoreURLSetter, generator ShortCodeGenerator, normalizer *URLNormalizer, policy *DestinationPolicy, templates StoreUTMTemplates) *URLService {
+	return &URLService{store: store, generator: generator, normalizer: normalizer, policy: policy, templates: templates}
 }
 
-func (s *URLService) isValidURL(input string) bool {
+// applyUTM дописывает к OriginalURL запроса параметры UTM-шаблона и UTM-параметры самого запроса.
+// Дубликаты затем ищутся уже по итоговому URL вместе с дописанными параметрами.
+func (s *URLService) applyUTM(ctx context.Context, req ShortenRequest, userID string) (ShortenRequest, error) {
+	var utm UTM
+	if req.UTMTemplate != "" {
+		if s.templates == nil {
+			return req, ErrUTMTemplateNotFound
+		}
+		template, err := s.templates.GetUTMTemplate(ctx, userID, req.UTMTemplate)
+		if err != nil {
+			return req, err
+		}
+		utm = template.UTM
+	}
+	utm, err := utm.override(req.UTM).normalize()
+	if err != nil {
+		return req, err
+	}
+	if req.OriginalURL, req.utmParams, err = applyUTM(req.OriginalURL, utm); err != nil {
+		return req, err
+	}
+	return req, nil
+}
+
+// isValidURL проверяет, что input — абсолютный URL со схемой и хостом.
+func isValidURL(input string) bool {
 	parsedURI, err := url.ParseRequestURI(input)
 	return err == nil && parsedURI.Scheme != "" && parsedURI.Host != ""
 }
 
+// generateCode возвращает сгенерированный короткий код, не совпадающий с зарезервированными словами.
+func (s *URLService) generateCode(ctx context.Context, originalURL string, attempt int) (string, error) {
+	code, err := s.generator.Generate(ctx, originalURL, attempt)
+	if err != nil {
+		return "", err
+	}
+	if _, reserved := reservedAliases[strings.ToLower(code)]; reserved {
+		return s.generator.Generate(ctx, originalURL, attempt+maxShortCodeAttempts)
+	}
+	return code, nil
+}
+
 // ErrConflict возвращается, если оригинальный URL уже существует.
 var ErrConflict = errors.New("URL already exists")
 
-// ShortenURL создаёт короткий URL для данного входа или возвращает ErrConflict
-func (s *URLService) ShortenURL(ctx context.Context, input string, userID string) (string, error) {
-	if !s.isValidURL(input) {
-		return "", errors.New("invalid URL format")
+// ShortenURL создаёт короткий URL для данного входа или возвращает ErrConflict.
+// Возвращает ключ ссылки (см. LinkKey).
+// Если в запросе указан алиас, а он уже занят, возвращает ErrShortURLTaken.
+// Если занят сгенерированный код, генерирует новый, но не более maxShortCodeAttempts раз.
+func (s *URLService) ShortenURL(ctx context.Context, req ShortenRequest, userID string) (string, error) {
+	if !isValidURL(req.OriginalURL) {
+		return "", ErrInvalidURL
 	}
 
-	shortURL := utils.RandomString(6)
-	storeURL, err := s.store.Set(ctx, shortURL, input, userID)
+	req, err := s.applyUTM(ctx, req, userID)
 	if err != nil {
 		return "", err
 	}
+	req, err = req.prepare(s.normalizer, s.policy)
+	if err != nil {
+		return "", err
+	}
+
+	if req.Alias != "" {
+		return s.saveURL(ctx, req.toDTO(req.Alias), userID)
+	}
+
+	for attempt := 0; attempt < maxShortCodeAttempts; attempt++ {
+		shortURL, err := s.generateCode(ctx, req.normalizedURL, attempt)
+		if err != nil {
+			return "", err
+		}
+
+		storeURL, err := s.saveURL(ctx, req.toDTO(shortURL), userID)
+		if errors.Is(err, ErrShortURLTaken) {
+			continue
+		}
+		return storeURL, err
+	}
+
+	return "", ErrShortCodeExhausted
+}
 
-	if storeURL != shortURL {
+// saveURL сохраняет ссылку и возвращает ErrConflict, если оригинальный URL уже был сокращён.
+func (s *URLService) saveURL(ctx context.Context, url URLDTO, userID string) (string, error) {
+	storeURL, err := s.store.Set(ctx, url, userID)
+	if err != nil {
+		return "", err
+	}
+
+	if storeURL != url.ShortURL {
 		return storeURL, ErrConflict
 	}
 
-	return shortURL, nil
+	return url.ShortURL, nil
 }
 
-// ShortenURLs создаёт короткие ссылки для нескольких URL, возвращая карту shortURL→originalURL.
-func (s *URLService) ShortenURLs(ctx context.Context, inputs []string, userID string) (map[string]string, error) {
-	urls := make(map[string]string)
-	for _, input := range inputs {
-		if !s.isValidURL(input) {
+// ShortenURLs создаёт короткие ссылки для нескольких URL и возвращает их короткие коды
+// в порядке запросов. Запросы с одинаковым каноническим URL получают одну и ту же ссылку,
+// кроме запросов с собственными параметрами ссылки (см. ShortenRequest).
+// При коллизии сгенерированного кода весь batch повторяется с новыми кодами;
+// алиасы при этом не меняются, поэтому занятый алиас в итоге приводит к ErrShortURLTaken.
+func (s *URLService) ShortenURLs(ctx context.Context, reqs []ShortenRequest, userID string) ([]string, error) {
+	generated := 0
+	prepared := make([]ShortenRequest, len(reqs))
+	for i, req := range reqs {
+		if !isValidURL(req.OriginalURL) {
 			return nil, errors.New("one or more URLs are invalid")
 		}
-		shortURL := utils.RandomString(6)
-		urls[shortURL] = input
+		req, err := s.applyUTM(ctx, req, userID)
+		if err != nil {
+			return nil, err
+		}
+		req, err = req.prepare(s.normalizer, s.policy)
+		if err != nil {
+			return nil, err
+		}
+		prepared[i] = req
+		if req.Alias == "" {
+			generated++
+		}
+	}
+	reqs = prepared
+
+	var err error
+	for attempt := 0; attempt < maxShortCodeAttempts; attempt++ {
+		var urls []URLDTO
+		var index []int
+		urls, index, err = s.batchCodes(ctx, reqs, attempt)
+		if err != nil {
+			return nil, err
+		}
+
+		var result map[string]string
+		result, err = s.store.BatchSet(ctx, urls, userID)
+		if err == nil {
+			return batchShortURLs(reqs, urls, index, result)
+		}
+		if !errors.Is(err, ErrShortURLTaken) || generated == 0 {
+			return nil, err
+		}
+	}
+
+	return nil, err
+}
+
+// dedupKey возвращает ключ, по которому ищутся дубликаты: канонический URL на домене запроса.
+func (r ShortenRequest) dedupKey() string {
+	return r.Domain + " " + r.normalizedURL
+}
+
+// batchShortURLs сопоставляет каждому запросу ключ ссылки из ответа хранилища
+// (shortURL→originalURL). index[i] — номер записи urls, сохранённой для запроса i.
+// Отдельные ссылки сохраняются под своим ключом, остальные сопоставляются
+// через домен и канонический URL, потому что хранилище могло вернуть существующую ссылку.
+func batchShortURLs(reqs []ShortenRequest, urls []URLDTO, index []int, stored map[string]string) ([]string, error) {
+	standalone := make(map[string]struct{}, len(urls))
+	for _, url := range urls {
+		if url.Standalone {
+			standalone[url.ShortURL] = struct{}{}
+		}
+	}
+	byOriginal := make(map[string]string, len(stored))
+	for short, orig := range stored {
+		if _, ok := standalone[short]; ok {
+			continue
+		}
+		domain, _ := SplitLinkKey(short)
+		byOriginal[domain+" "+orig] = short
 	}
 
-	return s.store.BatchSet(ctx, urls, userID)
+	shortURLs := make([]string, len(reqs))
+	for i, req := range reqs {
+		url := urls[index[i]]
+		var shortURL string
+		if url.Standalone {
+			if _, ok := stored[url.ShortURL]; ok {
+				shortURL = url.ShortURL
+			}
+		} else {
+			domain, _ := SplitLinkKey(url.ShortURL)
+			shortURL = byOriginal[domain+" "+url.OriginalURL]
+		}
+		if shortURL == "" {
+			return nil, fmt.Errorf("no short URL stored for %q", req.OriginalURL)
+		}
+		shortURLs[i] = shortURL
+	}
+	return shortURLs, nil
+}
+
+// batchCodes сопоставляет каждому каноническому URL из запросов короткий код: алиас или сгенерированный,
+// и возвращает записи для сохранения вместе с номером записи для каждого запроса.
+// Повторы канонического URL на том же домене пропускаются: они получат код первого вхождения.
+// Запросы с собственными параметрами ссылки всегда получают отдельную запись.
+func (s *URLService) batchCodes(ctx context.Context, reqs []ShortenRequest, attempt int) ([]URLDTO, []int, error) {
+	urls := make([]URLDTO, 0, len(reqs))
+	index := make([]int, len(reqs))
+	used := make(map[string]struct{}, len(reqs))
+	seen := make(map[string]int, len(reqs))
+	for i, req := range reqs {
+		if !req.standalone() {
+			if first, duplicate := seen[req.dedupKey()]; duplicate {
+				index[i] = first
+				continue
+			}
+			seen[req.dedupKey()] = len(urls)
+		}
+		index[i] = len(urls)
+
+		if req.Alias != "" {
+			key := LinkKey(req.Domain, req.Alias)
+			if _, duplicate := used[key]; duplicate {
+				return nil, nil, ErrShortURLTaken
+			}
+			used[key] = struct{}{}
+			urls = append(urls, req.toDTO(req.Alias))
+			continue
+		}
+
+		code, err := s.generateCode(ctx, req.normalizedURL, attempt)
+		if err != nil {
+			return nil, nil, err
+		}
+		for retry := 1; ; retry++ {
+			if _, duplicate := used[LinkKey(req.Domain, code)]; !duplicate {
+				break
+			}
+			if retry == maxShortCodeAttempts {
+				return nil, nil, ErrShortCodeExhausted
+			}
+			if code, err = s.generateCode(ctx, req.normalizedURL, attempt+retry*maxShortCodeAttempts); err != nil {
+				return nil, nil, err
+			}
+		}
+		used[LinkKey(req.Domain, code)] = struct{}{}
+		urls = append(urls, req.toDTO(code))
+	}
+	return urls, index, nil
 }
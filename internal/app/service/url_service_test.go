@@ -5,6 +5,7 @@ import (
 	"errors"
 	"reflect"
 	"testing"
+	"time"
 )
 
 type stubStore struct {
@@ -12,9 +13,11 @@ type stubStore struct {
 	batchFn       func(ctx context.Context, urls map[string]string) (map[string]string, error)
 	getFn         func(ctx context.Context, shortURL string) (string, bool)
 	getUserURLsFn func(ctx context.Context, userID string) ([]URLDTO, error)
+	gotQuery      URLQuery
 }
 
-func (s *stubStore) GetUserURLs(ctx context.Context, userID string) ([]URLDTO, error) {
+func (s *stubStore) GetUserURLs(ctx context.Context, userID string, query URLQuery) ([]URLDTO, error) {
+	s.gotQuery = query
 	if s.getUserURLsFn != nil {
 		return s.getUserURLsFn(ctx, userID)
 	}
@@ -25,26 +28,42 @@ func (s *stubStore) GetStats(_ context.Context) (int, int, error) {
 	return 0, 0, nil
 }
 
-func (s *stubStore) Set(ctx context.Context, shortURL, originalURL string, _ string) (string, error) {
+func (s *stubStore) GetTotalClicks(_ context.Context) (int, error) {
+	return 0, nil
+}
+
+func (s *stubStore) GetExpiredURLs(_ context.Context, _ time.Time) (map[string][]string, error) {
+	return nil, nil
+}
+
+func (s *stubStore) ConsumeUse(_ context.Context, _ string) error {
+	return nil
+}
+
+func (s *stubStore) Set(ctx context.Context, url URLDTO, _ string) (string, error) {
 	if s.setFn == nil {
 		return "", nil
 	}
-	return s.setFn(ctx, shortURL, originalURL)
+	return s.setFn(ctx, url.ShortURL, url.OriginalURL)
 }
 
-func (s *stubStore) BatchSet(ctx context.Context, urls map[string]string, _ string) (map[string]string, error) {
+func (s *stubStore) BatchSet(ctx context.Context, urls []URLDTO, _ string) (map[string]string, error) {
 	if s.batchFn == nil {
 		return nil, nil
 	}
-	return s.batchFn(ctx, urls)
+	m := make(map[string]string, len(urls))
+	for _, url := range urls {
+		m[url.ShortURL] = url.OriginalURL
+	}
+	return s.batchFn(ctx, m)
 }
 
-func (s *stubStore) Get(ctx context.Context, shortURL string) (originalURL string, err error) {
+func (s *stubStore) Get(ctx context.Context, shortURL string) (URLDTO, error) {
 	if s.getFn == nil {
-		return "", nil
+		return URLDTO{}, nil
 	}
 	url, _ := s.getFn(ctx, shortURL)
-	return url, ErrURLDeleted
+	return URLDTO{ShortURL: shortURL, OriginalURL: url}, ErrURLDeleted
 }
 
 func TestShortenURL_Success(t *testing.T) {
@@ -57,9 +76,9 @@ func TestShortenURL_Success(t *testing.T) {
 			return shortURL, nil
 		},
 	}
-	svc := NewURLService(store)
+	svc := NewURLService(store, NewRandomCodeGenerator(DefaultShortCodeLength), nil, nil, nil)
 	input := "https://example.com"
-	shortURL, err := svc.ShortenURL(context.Background(), input, "")
+	shortURL, err := svc.ShortenURL(context.Background(), ShortenRequest{OriginalURL: input}, "")
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
@@ -75,8 +94,8 @@ func TestShortenURL_Success(t *testing.T) {
 }
 
 func TestShortenURL_InvalidURL(t *testing.T) {
-	svc := NewURLService(&stubStore{})
-	_, err := svc.ShortenURL(context.Background(), "invalid-url", "")
+	svc := NewURLService(&stubStore{}, NewRandomCodeGenerator(DefaultShortCodeLength), nil, nil, nil)
+	_, err := svc.ShortenURL(context.Background(), ShortenRequest{OriginalURL: "invalid-url"}, "")
 	if err == nil || err.Error() != "invalid URL format" {
 		t.Fatalf("expected invalid URL format error, got %v", err)
 	}
@@ -89,9 +108,9 @@ func TestShortenURL_Conflict(t *testing.T) {
 			return expected, nil
 		},
 	}
-	svc := NewURLService(store)
+	svc := NewURLService(store, NewRandomCodeGenerator(DefaultShortCodeLength), nil, nil, nil)
 	input := "https://example.com"
-	got, err := svc.ShortenURL(context.Background(), input, "")
+	got, err := svc.ShortenURL(context.Background(), ShortenRequest{OriginalURL: input}, "")
 	if !errors.Is(err, ErrConflict) {
 		t.Fatalf("expected ErrConflict, got %v", err)
 	}
@@ -101,14 +120,15 @@ func TestShortenURL_Conflict(t *testing.T) {
 }
 
 func TestShortenURLs_Success(t *testing.T) {
-	inputs := []string{"https://a", "https://b"}
-	want := map[string]string{"shortA": "https://a", "shortB": "https://b"}
+	inputs := []ShortenRequest{{OriginalURL: "https://a"}, {OriginalURL: "https://b"}}
+	stored := map[string]string{"shortA": "https://a", "shortB": "https://b"}
+	want := []string{"shortA", "shortB"}
 	store := &stubStore{
 		batchFn: func(ctx context.Context, urls map[string]string) (map[string]string, error) {
-			return want, nil
+			return stored, nil
 		},
 	}
-	svc := NewURLService(store)
+	svc := NewURLService(store, NewRandomCodeGenerator(DefaultShortCodeLength), nil, nil, nil)
 	got, err := svc.ShortenURLs(context.Background(), inputs, "")
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
@@ -119,14 +139,135 @@ func TestShortenURLs_Success(t *testing.T) {
 }
 
 func TestShortenURLs_InvalidURL(t *testing.T) {
-	svc := NewURLService(&stubStore{})
-	inputs := []string{"invalid", "https://example.com"}
+	svc := NewURLService(&stubStore{}, NewRandomCodeGenerator(DefaultShortCodeLength), nil, nil, nil)
+	inputs := []ShortenRequest{{OriginalURL: "invalid"}, {OriginalURL: "https://example.com"}}
 	_, err := svc.ShortenURLs(context.Background(), inputs, "")
 	if err == nil || err.Error() != "one or more URLs are invalid" {
 		t.Fatalf("expected invalid URL error, got %v", err)
 	}
 }
 
+func TestShortenURL_Alias(t *testing.T) {
+	var passedShort string
+	store := &stubStore{
+		setFn: func(ctx context.Context, shortURL, originalURL string) (string, error) {
+			passedShort = shortURL
+			return shortURL, nil
+		},
+	}
+	svc := NewURLService(store, NewRandomCodeGenerator(DefaultShortCodeLength), nil, nil, nil)
+	got, err := svc.ShortenURL(context.Background(), ShortenRequest{OriginalURL: "https://example.com", Alias: "spring-sale"}, "")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if got != "spring-sale" || passedShort != "spring-sale" {
+		t.Errorf("expected alias to be used as short URL, got %q (store got %q)", got, passedShort)
+	}
+}
+
+func TestShortenURL_InvalidAlias(t *testing.T) {
+	tests := []struct {
+		alias string
+		want  error
+	}{
+		{alias: "ab", want: ErrInvalidAlias},
+		{alias: "with space", want: ErrInvalidAlias},
+		{alias: "слово", want: ErrInvalidAlias},
+		{alias: "ping", want: ErrReservedAlias},
+		{alias: "API", want: ErrReservedAlias},
+	}
+	svc := NewURLService(&stubStore{}, NewRandomCodeGenerator(DefaultShortCodeLength), nil, nil, nil)
+	for _, tt := range tests {
+		_, err := svc.ShortenURL(context.Background(), ShortenRequest{OriginalURL: "https://example.com", Alias: tt.alias}, "")
+		if !errors.Is(err, tt.want) {
+			t.Errorf("alias %q: expected %v, got %v", tt.alias, tt.want, err)
+		}
+	}
+}
+
+func TestShortenURL_AliasTaken(t *testing.T) {
+	store := &stubStore{
+		setFn: func(ctx context.Context, shortURL, originalURL string) (string, error) {
+			return "", ErrShortURLTaken
+		},
+	}
+	svc := NewURLService(store, NewRandomCodeGenerator(DefaultShortCodeLength), nil, nil, nil)
+	_, err := svc.ShortenURL(context.Background(), ShortenRequest{OriginalURL: "https://example.com", Alias: "taken"}, "")
+	if !errors.Is(err, ErrShortURLTaken) {
+		t.Fatalf("expected ErrShortURLTaken, got %v", err)
+	}
+}
+
+func TestShortenURL_RetriesOnCollision(t *testing.T) {
+	var attempts []string
+	store := &stubStore{
+		setFn: func(ctx context.Context, shortURL, originalURL string) (string, error) {
+			attempts = append(attempts, shortURL)
+			if len(attempts) < 3 {
+				return "", ErrShortURLTaken
+			}
+			return shortURL, nil
+		},
+	}
+	svc := NewURLService(store, NewHashCodeGenerator(DefaultShortCodeLength), nil, nil, nil)
+	got, err := svc.ShortenURL(context.Background(), ShortenRequest{OriginalURL: "https://example.com"}, "")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(attempts) != 3 || got != attempts[2] {
+		t.Fatalf("expected success on third attempt, got %q after %v", got, attempts)
+	}
+	if attempts[0] == attempts[1] || attempts[1] == attempts[2] {
+		t.Errorf("expected a new code on every attempt, got %v", attempts)
+	}
+}
+
+func TestShortenURL_CollisionExhausted(t *testing.T) {
+	store := &stubStore{
+		setFn: func(ctx context.Context, shortURL, originalURL string) (string, error) {
+			return "", ErrShortURLTaken
+		},
+	}
+	svc := NewURLService(store, NewRandomCodeGenerator(DefaultShortCodeLength), nil, nil, nil)
+	_, err := svc.ShortenURL(context.Background(), ShortenRequest{OriginalURL: "https://example.com"}, "")
+	if !errors.Is(err, ErrShortCodeExhausted) {
+		t.Fatalf("expected ErrShortCodeExhausted, got %v", err)
+	}
+}
+
+func TestShortenURLs_RetriesOnCollision(t *testing.T) {
+	calls := 0
+	store := &stubStore{
+		batchFn: func(ctx context.Context, urls map[string]string) (map[string]string, error) {
+			calls++
+			if calls == 1 {
+				return nil, ErrShortURLTaken
+			}
+			return urls, nil
+		},
+	}
+	svc := NewURLService(store, NewRandomCodeGenerator(DefaultShortCodeLength), nil, nil, nil)
+	got, err := svc.ShortenURLs(context.Background(), []ShortenRequest{{OriginalURL: "https://a"}, {OriginalURL: "https://b", Alias: "bee"}}, "")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if calls != 2 || len(got) != 2 || got[1] != "bee" {
+		t.Errorf("expected retry to keep alias, got %v after %d calls", got, calls)
+	}
+}
+
+func TestShortenURLs_DuplicateAlias(t *testing.T) {
+	svc := NewURLService(&stubStore{}, NewRandomCodeGenerator(DefaultShortCodeLength), nil, nil, nil)
+	inputs := []ShortenRequest{
+		{OriginalURL: "https://a", Alias: "same"},
+		{OriginalURL: "https://b", Alias: "same"},
+	}
+	_, err := svc.ShortenURLs(context.Background(), inputs, "")
+	if !errors.Is(err, ErrShortURLTaken) {
+		t.Fatalf("expected ErrShortURLTaken, got %v", err)
+	}
+}
+
 func TestGetOriginalURL(t *testing.T) {
 	expected := "origURL"
 	store := &stubStore{
@@ -134,13 +275,13 @@ func TestGetOriginalURL(t *testing.T) {
 			return expected, true
 		},
 	}
-	svc := NewGetURLService(store)
-	got, err := svc.GetOriginalURL(context.Background(), "key")
+	svc := NewGetURLService(store, nil, nil)
+	got, err := svc.GetOriginalURL(context.Background(), "key", Visit{})
 	if !errors.Is(err, ErrURLDeleted) {
 		t.Fatal(err)
 	}
-	if got != expected {
-		t.Errorf("expected %q, got %q", expected, got)
+	if got.URL != expected {
+		t.Errorf("expected %q, got %q", expected, got.URL)
 	}
 }
 func TestGetUserURLs_Success(t *testing.T) {
@@ -157,14 +298,17 @@ func TestGetUserURLs_Success(t *testing.T) {
 			return dummy, nil
 		},
 	}
-	svc := NewGetURLService(store)
+	svc := NewGetURLService(store, nil, nil)
 
-	got, err := svc.GetUserURLs(context.Background(), "u1")
+	got, err := svc.GetUserURLs(context.Background(), "u1", URLFilter{})
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
-	if !reflect.DeepEqual(got, dummy) {
-		t.Errorf("expected %v, got %v", dummy, got)
+	if !reflect.DeepEqual(got.URLs, dummy) {
+		t.Errorf("expected %v, got %v", dummy, got.URLs)
+	}
+	if got.NextPageToken != "" {
+		t.Errorf("expected no next page, got token %q", got.NextPageToken)
 	}
 }
 
@@ -175,13 +319,183 @@ func TestGetUserURLs_Error(t *testing.T) {
 			return nil, expectedErr
 		},
 	}
-	svc := NewGetURLService(store)
+	svc := NewGetURLService(store, nil, nil)
 
-	got, err := svc.GetUserURLs(context.Background(), "any")
+	got, err := svc.GetUserURLs(context.Background(), "any", URLFilter{})
 	if !errors.Is(err, expectedErr) {
 		t.Fatalf("expected error %v, got %v", expectedErr, err)
 	}
-	if got != nil {
-		t.Errorf("expected nil slice on error, got %v", got)
+	if got.URLs != nil {
+		t.Errorf("expected nil slice on error, got %v", got.URLs)
+	}
+}
+
+func TestGetUserURLs_NormalizesTagFilter(t *testing.T) {
+	store := &stubStore{}
+	svc := NewGetURLService(store, nil, nil)
+
+	if _, err := svc.GetUserURLs(context.Background(), "u1", URLFilter{Tags: []string{" Work ", "news", "work"}}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if want := []string{"news", "work"}; !reflect.DeepEqual(store.gotQuery.Tags, want) {
+		t.Errorf("expected filter tags %v, got %v", want, store.gotQuery.Tags)
+	}
+
+	if _, err := svc.GetUserURLs(context.Background(), "u1", URLFilter{Tags: []string{"bad tag"}}); !errors.Is(err, ErrInvalidTag) {
+		t.Errorf("expected ErrInvalidTag, got %v", err)
+	}
+}
+
+type limitedStore struct {
+	stubStore
+	remaining int
+}
+
+func (s *limitedStore) Get(_ context.Context, _ string) (URLDTO, error) {
+	remaining := s.remaining
+	return URLDTO{OriginalURL: "https://example.com", RemainingUses: &remaining}, nil
+}
+
+func (s *limitedStore) ConsumeUse(_ context.Context, _ string) error {
+	if s.remaining <= 0 {
+		return ErrURLExhausted
+	}
+	s.remaining--
+	return nil
+}
+
+func TestGetOriginalURL_MaxUses(t *testing.T) {
+	svc := NewGetURLService(&limitedStore{remaining: 2}, nil, nil)
+	for i := 0; i < 2; i++ {
+		if _, err := svc.GetOriginalURL(context.Background(), "key", Visit{}); err != nil {
+			t.Fatalf("use %d: unexpected error %v", i+1, err)
+		}
+	}
+	if _, err := svc.GetOriginalURL(context.Background(), "key", Visit{}); !errors.Is(err, ErrURLExhausted) {
+		t.Fatalf("expected ErrURLExhausted, got %v", err)
+	}
+}
+
+func TestShortenURL_InvalidMaxUses(t *testing.T) {
+	svc := NewURLService(&stubStore{}, NewRandomCodeGenerator(DefaultShortCodeLength), nil, nil, nil)
+	_, err := svc.ShortenURL(context.Background(), ShortenRequest{OriginalURL: "https://example.com", MaxUses: -1}, "")
+	if !errors.Is(err, ErrInvalidMaxUses) {
+		t.Fatalf("expected ErrInvalidMaxUses, got %v", err)
+	}
+}
+
+type protectedStore struct {
+	stubStore
+	url URLDTO
+}
+
+func (s *protectedStore) Get(_ context.Context, _ string) (URLDTO, error) {
+	return s.url, nil
+}
+
+func (s *protectedStore) Set(_ context.Context, url URLDTO, _ string) (string, error) {
+	s.url = url
+	return url.ShortURL, nil
+}
+
+func TestUnlockURL(t *testing.T) {
+	store := &protectedStore{}
+	shortener := NewURLService(store, NewRandomCodeGenerator(DefaultShortCodeLength), nil, nil, nil)
+	if _, err := shortener.ShortenURL(context.Background(), ShortenRequest{OriginalURL: "https://example.com", Password: "secret"}, ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if store.url.PasswordHash == "" || store.url.PasswordHash == "secret" {
+		t.Fatalf("expected bcrypt hash, got %q", store.url.PasswordHash)
+	}
+
+	svc := NewGetURLService(store, nil, nil)
+	if _, err := svc.GetOriginalURL(context.Background(), "key", Visit{}); !errors.Is(err, ErrPasswordRequired) {
+		t.Fatalf("expected ErrPasswordRequired, got %v", err)
+	}
+	if _, err := svc.UnlockURL(context.Background(), "key", "wrong", Visit{}); !errors.Is(err, ErrInvalidPassword) {
+		t.Fatalf("expected ErrInvalidPassword, got %v", err)
+	}
+	got, err := svc.UnlockURL(context.Background(), "key", "secret", Visit{})
+	if err != nil || got.URL != "https://example.com" {
+		t.Fatalf("expected unlocked URL, got %q, %v", got, err)
+	}
+}
+
+func TestUnlockURL_TooManyAttempts(t *testing.T) {
+	hash, err := hashPassword("secret")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	svc := NewGetURLService(&protectedStore{url: URLDTO{OriginalURL: "https://example.com", PasswordHash: hash}}, nil, nil)
+	for i := 0; i < maxPasswordAttempts; i++ {
+		if _, err := svc.UnlockURL(context.Background(), "key", "wrong", Visit{}); !errors.Is(err, ErrInvalidPassword) {
+			t.Fatalf("attempt %d: expected ErrInvalidPassword, got %v", i+1, err)
+		}
+	}
+	if _, err := svc.UnlockURL(context.Background(), "key", "secret", Visit{}); !errors.Is(err, ErrTooManyAttempts) {
+		t.Fatalf("expected ErrTooManyAttempts, got %v", err)
+	}
+	if _, err := svc.UnlockURL(context.Background(), "other", "secret", Visit{}); err != nil {
+		t.Fatalf("limit must be per link, got %v", err)
+	}
+}
+
+func TestShortenURL_StandaloneSkipsDedup(t *testing.T) {
+	expires := time.Now().Add(time.Hour)
+	tests := []struct {
+		name string
+		req  ShortenRequest
+		want bool
+	}{
+		{name: "plain", req: ShortenRequest{}, want: false},
+		{name: "alias", req: ShortenRequest{Alias: "promo"}, want: true},
+		{name: "password", req: ShortenRequest{Password: "secret"}, want: true},
+		{name: "max uses", req: ShortenRequest{MaxUses: 1}, want: true},
+		{name: "expires", req: ShortenRequest{ExpiresAt: &expires}, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &protectedStore{}
+			svc := NewURLService(store, NewRandomCodeGenerator(DefaultShortCodeLength), nil, nil, nil)
+			tt.req.OriginalURL = "https://example.com"
+			if _, err := svc.ShortenURL(context.Background(), tt.req, ""); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if store.url.Standalone != tt.want {
+				t.Errorf("expected Standalone %v, got %v", tt.want, store.url.Standalone)
+			}
+		})
+	}
+}
+
+func TestShortenURLs_StandaloneNotMerged(t *testing.T) {
+	var stored []URLDTO
+	store := &batchDTOStore{batchFn: func(urls []URLDTO) map[string]string {
+		stored = urls
+		result := make(map[string]string, len(urls))
+		for _, url := range urls {
+			result[url.ShortURL] = url.OriginalURL
+		}
+		return result
+	}}
+	svc := NewURLService(store, NewRandomCodeGenerator(DefaultShortCodeLength), nil, nil, nil)
+	reqs := []ShortenRequest{
+		{OriginalURL: "https://example.com"},
+		{OriginalURL: "https://example.com", Password: "secret"},
+		{OriginalURL: "https://example.com"},
+		{OriginalURL: "https://example.com", MaxUses: 1},
+	}
+	got, err := svc.ShortenURLs(context.Background(), reqs, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(stored) != 3 {
+		t.Fatalf("expected 3 stored records, got %+v", stored)
+	}
+	if got[0] != got[2] {
+		t.Errorf("expected plain duplicates to share a short URL, got %v", got)
+	}
+	if got[1] == got[0] || got[3] == got[0] || got[1] == got[3] {
+		t.Errorf("expected standalone links to get their own short URLs, got %v", got)
 	}
 }
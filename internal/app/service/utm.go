@@ -0,0 +1,167 @@
+package service
+
+import (
+	"context"
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxUTMValueLength ограничивает длину значения UTM-параметра в символах.
+const maxUTMValueLength = 200
+
+var utmTemplateNamePattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+
+// UTM содержит значения UTM-параметров; пустое значение означает, что параметр не задан.
+type UTM struct {
+	Source   string
+	Medium   string
+	Campaign string
+	Term     string
+	Content  string
+}
+
+// UTMTemplate — именованный набор UTM-параметров пользователя.
+type UTMTemplate struct {
+	Name string
+	UTM  UTM
+}
+
+// params возвращает заданные UTM-параметры в порядке source, medium, campaign, term, content.
+func (u UTM) params() [][2]string {
+	all := [][2]string{
+		{"utm_source", u.Source},
+		{"utm_medium", u.Medium},
+		{"utm_campaign", u.Campaign},
+		{"utm_term", u.Term},
+		{"utm_content", u.Content},
+	}
+	params := all[:0]
+	for _, p := range all {
+		if p[1] != "" {
+			params = append(params, p)
+		}
+	}
+	return params
+}
+
+// override возвращает u, в котором значения заменены непустыми значениями over.
+func (u UTM) override(over UTM) UTM {
+	for _, f := range []struct{ dst, src *string }{
+		{&u.Source, &over.Source},
+		{&u.Medium, &over.Medium},
+		{&u.Campaign, &over.Campaign},
+		{&u.Term, &over.Term},
+		{&u.Content, &over.Content},
+	} {
+		if *f.src != "" {
+			*f.dst = *f.src
+		}
+	}
+	return u
+}
+
+// normalize убирает пробелы по краям значений и проверяет их длину.
+func (u UTM) normalize() (UTM, error) {
+	for _, v := range []*string{&u.Source, &u.Medium, &u.Campaign, &u.Term, &u.Content} {
+		*v = strings.TrimSpace(*v)
+		if utf8.RuneCountInString(*v) > maxUTMValueLength {
+			return UTM{}, ErrInvalidUTM
+		}
+	}
+	return u, nil
+}
+
+// applyUTM дописывает к строке запроса rawURL заданные UTM-параметры и возвращает
+// имена дописанных параметров. Параметры, которые уже есть в rawURL, не меняются;
+// порядок остальных параметров и фрагмент сохраняются.
+func applyUTM(rawURL string, utm UTM) (string, []string, error) {
+	params := utm.params()
+	if len(params) == 0 {
+		return rawURL, nil, nil
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", nil, ErrInvalidURL
+	}
+	existing := u.Query()
+	var names, added []string
+	for _, p := range params {
+		if !existing.Has(p[0]) {
+			names = append(names, p[0])
+			added = append(added, p[0]+"="+url.QueryEscape(p[1]))
+		}
+	}
+	if len(added) == 0 {
+		return rawURL, nil, nil
+	}
+	if u.RawQuery != "" {
+		added = append([]string{u.RawQuery}, added...)
+	}
+	u.RawQuery = strings.Join(added, "&")
+	return u.String(), names, nil
+}
+
+// StoreUTMTemplates описывает хранение UTM-шаблонов пользователя.
+// GetUTMTemplate и DeleteUTMTemplate возвращают ErrUTMTemplateNotFound для отсутствующего шаблона.
+type StoreUTMTemplates interface {
+	GetUTMTemplates(ctx context.Context, userID string) ([]UTMTemplate, error)
+	GetUTMTemplate(ctx context.Context, userID, name string) (UTMTemplate, error)
+	SaveUTMTemplate(ctx context.Context, userID string, template UTMTemplate) error
+	DeleteUTMTemplate(ctx context.Context, userID, name string) error
+}
+
+// UTMTemplater позволяет пользователю управлять своими UTM-шаблонами.
+type UTMTemplater interface {
+	ListTemplates(ctx context.Context, userID string) ([]UTMTemplate, error)
+	SaveTemplate(ctx context.Context, userID string, template UTMTemplate) (UTMTemplate, error)
+	DeleteTemplate(ctx context.Context, userID, name string) error
+}
+
+// UTMService реализует UTMTemplater через StoreUTMTemplates.
+type UTMService struct {
+	store StoreUTMTemplates
+}
+
+// NewUTMService создаёт новый UTMService.
+func NewUTMService(store StoreUTMTemplates) *UTMService {
+	return &UTMService{store: store}
+}
+
+// ListTemplates возвращает шаблоны пользователя, отсортированные по имени.
+func (s *UTMService) ListTemplates(ctx context.Context, userID string) ([]UTMTemplate, error) {
+	templates, err := s.store.GetUTMTemplates(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if templates == nil {
+		templates = []UTMTemplate{}
+	}
+	return templates, nil
+}
+
+// SaveTemplate создаёт шаблон или заменяет шаблон с тем же именем и возвращает сохранённый шаблон.
+// Шаблон без единого параметра и шаблон с некорректным именем отклоняются с ErrInvalidUTM.
+func (s *UTMService) SaveTemplate(ctx context.Context, userID string, template UTMTemplate) (UTMTemplate, error) {
+	if !utmTemplateNamePattern.MatchString(template.Name) {
+		return UTMTemplate{}, ErrInvalidUTM
+	}
+	utm, err := template.UTM.normalize()
+	if err != nil {
+		return UTMTemplate{}, err
+	}
+	if utm == (UTM{}) {
+		return UTMTemplate{}, ErrInvalidUTM
+	}
+	template.UTM = utm
+	if err = s.store.SaveUTMTemplate(ctx, userID, template); err != nil {
+		return UTMTemplate{}, err
+	}
+	return template, nil
+}
+
+// DeleteTemplate удаляет шаблон пользователя. Ссылки, созданные по шаблону, не меняются.
+func (s *UTMService) DeleteTemplate(ctx context.Context, userID, name string) error {
+	return s.store.DeleteUTMTemplate(ctx, userID, name)
+}
@@ -0,0 +1,184 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// memUTMStore хранит UTM-шаблоны пользователя "owner" в памяти.
+type memUTMStore struct {
+	templates map[string]UTM
+}
+
+func (m *memUTMStore) GetUTMTemplates(_ context.Context, userID string) ([]UTMTemplate, error) {
+	var result []UTMTemplate
+	for name, utm := range m.templates {
+		if userID == "owner" {
+			result = append(result, UTMTemplate{Name: name, UTM: utm})
+		}
+	}
+	return result, nil
+}
+
+func (m *memUTMStore) GetUTMTemplate(_ context.Context, userID, name string) (UTMTemplate, error) {
+	utm, ok := m.templates[name]
+	if !ok || userID != "owner" {
+		return UTMTemplate{}, ErrUTMTemplateNotFound
+	}
+	return UTMTemplate{Name: name, UTM: utm}, nil
+}
+
+func (m *memUTMStore) SaveUTMTemplate(_ context.Context, _ string, template UTMTemplate) error {
+	m.templates[template.Name] = template.UTM
+	return nil
+}
+
+func (m *memUTMStore) DeleteUTMTemplate(_ context.Context, _, name string) error {
+	if _, ok := m.templates[name]; !ok {
+		return ErrUTMTemplateNotFound
+	}
+	delete(m.templates, name)
+	return nil
+}
+
+func TestApplyUTM(t *testing.T) {
+	tests := []struct {
+		name string
+		url  string
+		utm  UTM
+		want string
+	}{
+		{"no params", "https://example.com/a", UTM{}, "https://example.com/a"},
+		{"appended", "https://example.com/a", UTM{Source: "news", Medium: "email", Campaign: "spring sale"},
+			"https://example.com/a?utm_source=news&utm_medium=email&utm_campaign=spring+sale"},
+		{"existing kept", "https://example.com/a?b=2&a=1&utm_source=blog#top", UTM{Source: "news", Medium: "email"},
+			"https://example.com/a?b=2&a=1&utm_source=blog&utm_medium=email#top"},
+		{"all present", "https://example.com/?utm_source=blog", UTM{Source: "news"}, "https://example.com/?utm_source=blog"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _, err := applyUTM(tt.url, tt.utm)
+			if err != nil || got != tt.want {
+				t.Errorf("expected %q, got %q, %v", tt.want, got, err)
+			}
+		})
+	}
+}
+
+func TestShortenURL_UTM(t *testing.T) {
+	var stored string
+	store := &stubStore{setFn: func(_ context.Context, shortURL, originalURL string) (string, error) {
+		stored = originalURL
+		return shortURL, nil
+	}}
+	templates := &memUTMStore{templates: map[string]UTM{"newsletter": {Source: "newsletter", Medium: "email", Campaign: "weekly"}}}
+	svc := NewURLService(store, NewRandomCodeGenerator(DefaultShortCodeLength), nil, nil, templates)
+	ctx := context.Background()
+
+	_, err := svc.ShortenURL(ctx, ShortenRequest{
+		OriginalURL: "https://example.com/?utm_medium=social",
+		UTMTemplate: "newsletter",
+		UTM:         UTM{Campaign: "launch"},
+	}, "owner")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "https://example.com/?utm_medium=social&utm_source=newsletter&utm_campaign=launch"; stored != want {
+		t.Errorf("expected %q, got %q", want, stored)
+	}
+
+	if _, err = svc.ShortenURL(ctx, ShortenRequest{OriginalURL: "https://example.com", UTMTemplate: "missing"}, "owner"); !errors.Is(err, ErrUTMTemplateNotFound) {
+		t.Errorf("expected ErrUTMTemplateNotFound, got %v", err)
+	}
+	if _, err = svc.ShortenURL(ctx, ShortenRequest{OriginalURL: "https://example.com", UTMTemplate: "newsletter"}, "stranger"); !errors.Is(err, ErrUTMTemplateNotFound) {
+		t.Errorf("expected ErrUTMTemplateNotFound for another user's template, got %v", err)
+	}
+}
+
+func TestShortenURLs_DedupByMergedURL(t *testing.T) {
+	var stored []URLDTO
+	store := &batchDTOStore{batchFn: func(urls []URLDTO) map[string]string {
+		stored = urls
+		result := make(map[string]string, len(urls))
+		for _, url := range urls {
+			result[url.ShortURL] = url.OriginalURL
+		}
+		return result
+	}}
+	templates := &memUTMStore{templates: map[string]UTM{"newsletter": {Source: "newsletter", Medium: "email"}}}
+	svc := NewURLService(store, NewRandomCodeGenerator(DefaultShortCodeLength), nil, nil, templates)
+
+	got, err := svc.ShortenURLs(context.Background(), []ShortenRequest{
+		{OriginalURL: "https://example.com/a", UTMTemplate: "newsletter"},
+		{OriginalURL: "https://example.com/a?utm_medium=email&utm_source=newsletter"},
+		{OriginalURL: "https://example.com/a", UTM: UTM{Source: "newsletter", Medium: "social"}},
+	}, "owner")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(stored) != 2 || got[0] != got[1] || got[0] == got[2] {
+		t.Fatalf("expected the template and hand-appended URLs to share a link, got %v (stored %+v)", got, stored)
+	}
+}
+
+func TestShortenURLs_StripTrackingKeepsMergedUTM(t *testing.T) {
+	var stored []URLDTO
+	store := &batchDTOStore{batchFn: func(urls []URLDTO) map[string]string {
+		stored = urls
+		result := make(map[string]string, len(urls))
+		for _, url := range urls {
+			result[url.ShortURL] = url.OriginalURL
+		}
+		return result
+	}}
+	svc := NewURLService(store, NewRandomCodeGenerator(DefaultShortCodeLength), NewURLNormalizer(true), nil, nil)
+
+	got, err := svc.ShortenURLs(context.Background(), []ShortenRequest{
+		{OriginalURL: "https://example.com/a", UTM: UTM{Campaign: "spring"}},
+		{OriginalURL: "https://example.com/a", UTM: UTM{Campaign: "autumn"}},
+		{OriginalURL: "https://example.com/a?fbclid=1", UTM: UTM{Campaign: "spring"}},
+		{OriginalURL: "https://example.com/a?utm_campaign=summer"},
+		{OriginalURL: "https://example.com/a"},
+	}, "owner")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(stored) != 3 || got[0] == got[1] || got[0] != got[2] || got[3] != got[4] {
+		t.Fatalf("expected one link per campaign and stripped hand-written tracking params, got %v (stored %+v)", got, stored)
+	}
+}
+
+func TestUTMService_SaveTemplate(t *testing.T) {
+	store := &memUTMStore{templates: map[string]UTM{}}
+	svc := NewUTMService(store)
+	ctx := context.Background()
+
+	got, err := svc.SaveTemplate(ctx, "owner", UTMTemplate{Name: "spring", UTM: UTM{Source: " news ", Campaign: "spring"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := (UTM{Source: "news", Campaign: "spring"}); got.UTM != want || store.templates["spring"] != want {
+		t.Errorf("expected %+v, got %+v (stored %+v)", want, got.UTM, store.templates["spring"])
+	}
+
+	tests := []struct {
+		name     string
+		template UTMTemplate
+	}{
+		{"bad name", UTMTemplate{Name: "spring sale", UTM: UTM{Source: "news"}}},
+		{"empty", UTMTemplate{Name: "empty", UTM: UTM{Source: "  "}}},
+		{"too long", UTMTemplate{Name: "long", UTM: UTM{Source: string(make([]byte, maxUTMValueLength+1))}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := svc.SaveTemplate(ctx, "owner", tt.template); !errors.Is(err, ErrInvalidUTM) {
+				t.Errorf("expected ErrInvalidUTM, got %v", err)
+			}
+		})
+	}
+
+	if err := svc.DeleteTemplate(ctx, "owner", "missing"); !errors.Is(err, ErrUTMTemplateNotFound) {
+		t.Errorf("expected ErrUTMTemplateNotFound, got %v", err)
+	}
+}
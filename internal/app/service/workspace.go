@@ -0,0 +1,216 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Роли участников рабочего пространства. Каждая следующая роль включает права предыдущей:
+// viewer видит ссылки и их статистику, editor создаёт, меняет и удаляет ссылки,
+// owner вдобавок управляет участниками.
+const (
+	RoleViewer = "viewer"
+	RoleEditor = "editor"
+	RoleOwner  = "owner"
+)
+
+var roleRank = map[string]int{RoleViewer: 1, RoleEditor: 2, RoleOwner: 3}
+
+const (
+	// workspaceIDPrefix отличает идентификаторы рабочих пространств от идентификаторов пользователей.
+	workspaceIDPrefix = "ws_"
+	// maxWorkspaceNameLength ограничивает длину названия рабочего пространства в символах.
+	maxWorkspaceNameLength = 100
+)
+
+// Workspace — рабочее пространство, которому принадлежат общие ссылки команды.
+// Ссылки пространства хранятся с его ID вместо userID, поэтому переживают уход любого участника.
+// Role — роль пользователя, запросившего пространство.
+type Workspace struct {
+	ID        string
+	Name      string
+	Role      string
+	CreatedAt time.Time
+}
+
+// WorkspaceMember — участник рабочего пространства и его роль.
+type WorkspaceMember struct {
+	UserID string
+	Role   string
+}
+
+// StoreWorkspaces описывает хранение рабочих пространств и их участников.
+// GetWorkspaceRole возвращает ErrWorkspaceNotFound, если пользователь не участник пространства,
+// DeleteWorkspaceMember — если такого участника нет.
+type StoreWorkspaces interface {
+	CreateWorkspace(ctx context.Context, workspace Workspace, ownerID string) error
+	GetWorkspaces(ctx context.Context, userID string) ([]Workspace, error)
+	GetWorkspaceRole(ctx context.Context, workspaceID, userID string) (string, error)
+	GetWorkspaceMembers(ctx context.Context, workspaceID string) ([]WorkspaceMember, error)
+	SetWorkspaceMember(ctx context.Context, workspaceID string, member WorkspaceMember) error
+	DeleteWorkspaceMember(ctx context.Context, workspaceID, userID string) error
+}
+
+// WorkspaceAuthorizer определяет, от имени какого владельца пользователь работает со ссылками.
+type WorkspaceAuthorizer interface {
+	Authorize(ctx context.Context, userID, workspaceID, role string) (string, error)
+}
+
+// WorkspaceManager позволяет создавать рабочие пространства и управлять их участниками.
+type WorkspaceManager interface {
+	WorkspaceAuthorizer
+	CreateWorkspace(ctx context.Context, userID, name string) (Workspace, error)
+	ListWorkspaces(ctx context.Context, userID string) ([]Workspace, error)
+	ListMembers(ctx context.Context, userID, workspaceID string) ([]WorkspaceMember, error)
+	SetMember(ctx context.Context, userID, workspaceID string, member WorkspaceMember) (WorkspaceMember, error)
+	RemoveMember(ctx context.Context, userID, workspaceID, memberID string) error
+}
+
+// WorkspaceService реализует WorkspaceManager через StoreWorkspaces.
+type WorkspaceService struct {
+	store StoreWorkspaces
+}
+
+// NewWorkspaceService создаёт новый WorkspaceService.
+func NewWorkspaceService(store StoreWorkspaces) *WorkspaceService {
+	return &WorkspaceService{store: store}
+}
+
+// Authorize возвращает владельца ссылок, от имени которого действует пользователь userID:
+// его самого, если workspaceID пуст или совпадает с userID (личное пространство),
+// иначе workspaceID, если у пользователя в нём есть роль не ниже role.
+// Не участнику возвращается ErrWorkspaceNotFound, участнику с меньшей ролью — ErrWorkspaceForbidden.
+func (s *WorkspaceService) Authorize(ctx context.Context, userID, workspaceID, role string) (string, error) {
+	if workspaceID == "" || workspaceID == userID {
+		return userID, nil
+	}
+	if !strings.HasPrefix(workspaceID, workspaceIDPrefix) {
+		return "", ErrWorkspaceNotFound
+	}
+	current, err := s.store.GetWorkspaceRole(ctx, workspaceID, userID)
+	if err != nil {
+		return "", err
+	}
+	if roleRank[current] < roleRank[role] {
+		return "", ErrWorkspaceForbidden
+	}
+	return workspaceID, nil
+}
+
+// CreateWorkspace создаёт рабочее пространство с названием name и делает userID его владельцем.
+func (s *WorkspaceService) CreateWorkspace(ctx context.Context, userID, name string) (Workspace, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || utf8.RuneCountInString(name) > maxWorkspaceNameLength {
+		return Workspace{}, ErrInvalidWorkspace
+	}
+	id, err := newWorkspaceID()
+	if err != nil {
+		return Workspace{}, err
+	}
+	workspace := Workspace{ID: id, Name: name, Role: RoleOwner, CreatedAt: time.Now().UTC()}
+	if err = s.store.CreateWorkspace(ctx, workspace, userID); err != nil {
+		return Workspace{}, err
+	}
+	return workspace, nil
+}
+
+// ListWorkspaces возвращает рабочие пространства, в которых состоит пользователь, с его ролями.
+func (s *WorkspaceService) ListWorkspaces(ctx context.Context, userID string) ([]Workspace, error) {
+	workspaces, err := s.store.GetWorkspaces(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if workspaces == nil {
+		workspaces = []Workspace{}
+	}
+	return workspaces, nil
+}
+
+// ListMembers возвращает участников рабочего пространства; доступно любому его участнику.
+func (s *WorkspaceService) ListMembers(ctx context.Context, userID, workspaceID string) ([]WorkspaceMember, error) {
+	if err := s.authorizeShared(ctx, userID, workspaceID, RoleViewer); err != nil {
+		return nil, err
+	}
+	return s.store.GetWorkspaceMembers(ctx, workspaceID)
+}
+
+// SetMember добавляет участника или меняет его роль; доступно только владельцам.
+// Понизить последнего владельца нельзя: возвращается ErrLastWorkspaceOwner.
+func (s *WorkspaceService) SetMember(ctx context.Context, userID, workspaceID string, member WorkspaceMember) (WorkspaceMember, error) {
+	member.UserID = strings.TrimSpace(member.UserID)
+	member.Role = strings.ToLower(strings.TrimSpace(member.Role))
+	if member.UserID == "" || roleRank[member.Role] == 0 || strings.HasPrefix(member.UserID, workspaceIDPrefix) {
+		return WorkspaceMember{}, ErrInvalidWorkspace
+	}
+	if err := s.authorizeShared(ctx, userID, workspaceID, RoleOwner); err != nil {
+		return WorkspaceMember{}, err
+	}
+	if member.Role != RoleOwner {
+		if err := s.keepOwner(ctx, workspaceID, member.UserID); err != nil {
+			return WorkspaceMember{}, err
+		}
+	}
+	if err := s.store.SetWorkspaceMember(ctx, workspaceID, member); err != nil {
+		return WorkspaceMember{}, err
+	}
+	return member, nil
+}
+
+// RemoveMember исключает участника memberID из рабочего пространства. Владельцы исключают
+// любого участника, остальные могут только выйти сами. Ссылки пространства остаются в нём.
+// Исключить последнего владельца нельзя: возвращается ErrLastWorkspaceOwner.
+func (s *WorkspaceService) RemoveMember(ctx context.Context, userID, workspaceID, memberID string) error {
+	role := RoleOwner
+	if memberID == userID {
+		role = RoleViewer
+	}
+	if err := s.authorizeShared(ctx, userID, workspaceID, role); err != nil {
+		return err
+	}
+	if err := s.keepOwner(ctx, workspaceID, memberID); err != nil {
+		return err
+	}
+	return s.store.DeleteWorkspaceMember(ctx, workspaceID, memberID)
+}
+
+// authorizeShared проверяет роль пользователя в общем рабочем пространстве workspaceID;
+// у личного пространства участников нет.
+func (s *WorkspaceService) authorizeShared(ctx context.Context, userID, workspaceID, role string) error {
+	if !strings.HasPrefix(workspaceID, workspaceIDPrefix) {
+		return ErrWorkspaceNotFound
+	}
+	_, err := s.Authorize(ctx, userID, workspaceID, role)
+	return err
+}
+
+// keepOwner возвращает ErrLastWorkspaceOwner, если memberID — единственный владелец пространства.
+func (s *WorkspaceService) keepOwner(ctx context.Context, workspaceID, memberID string) error {
+	members, err := s.store.GetWorkspaceMembers(ctx, workspaceID)
+	if err != nil {
+		return err
+	}
+	owners, isOwner := 0, false
+	for _, m := range members {
+		if m.Role == RoleOwner {
+			owners++
+			isOwner = isOwner || m.UserID == memberID
+		}
+	}
+	if isOwner && owners == 1 {
+		return ErrLastWorkspaceOwner
+	}
+	return nil
+}
+
+// newWorkspaceID генерирует случайный идентификатор рабочего пространства.
+func newWorkspaceID() (string, error) {
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return workspaceIDPrefix + hex.EncodeToString(b), nil
+}
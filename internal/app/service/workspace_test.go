@@ -0,0 +1,153 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// memWorkspaceStore хранит роли участников по пространствам в памяти.
+type memWorkspaceStore struct {
+	members map[string]map[string]string
+}
+
+func (m *memWorkspaceStore) CreateWorkspace(_ context.Context, workspace Workspace, ownerID string) error {
+	m.members[workspace.ID] = map[string]string{ownerID: RoleOwner}
+	return nil
+}
+
+func (m *memWorkspaceStore) GetWorkspaces(_ context.Context, userID string) ([]Workspace, error) {
+	var result []Workspace
+	for id, members := range m.members {
+		if role, ok := members[userID]; ok {
+			result = append(result, Workspace{ID: id, Role: role})
+		}
+	}
+	return result, nil
+}
+
+func (m *memWorkspaceStore) GetWorkspaceRole(_ context.Context, workspaceID, userID string) (string, error) {
+	role, ok := m.members[workspaceID][userID]
+	if !ok {
+		return "", ErrWorkspaceNotFound
+	}
+	return role, nil
+}
+
+func (m *memWorkspaceStore) GetWorkspaceMembers(_ context.Context, workspaceID string) ([]WorkspaceMember, error) {
+	var result []WorkspaceMember
+	for userID, role := range m.members[workspaceID] {
+		result = append(result, WorkspaceMember{UserID: userID, Role: role})
+	}
+	return result, nil
+}
+
+func (m *memWorkspaceStore) SetWorkspaceMember(_ context.Context, workspaceID string, member WorkspaceMember) error {
+	m.members[workspaceID][member.UserID] = member.Role
+	return nil
+}
+
+func (m *memWorkspaceStore) DeleteWorkspaceMember(_ context.Context, workspaceID, userID string) error {
+	if _, ok := m.members[workspaceID][userID]; !ok {
+		return ErrWorkspaceNotFound
+	}
+	delete(m.members[workspaceID], userID)
+	return nil
+}
+
+func newTestWorkspace(t *testing.T) (*WorkspaceService, string) {
+	t.Helper()
+	svc := NewWorkspaceService(&memWorkspaceStore{members: make(map[string]map[string]string)})
+	ctx := context.Background()
+	ws, err := svc.CreateWorkspace(ctx, "alice", " Marketing ")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ws.Name != "Marketing" || ws.Role != RoleOwner || !strings.HasPrefix(ws.ID, workspaceIDPrefix) {
+		t.Fatalf("unexpected workspace %+v", ws)
+	}
+	for member, role := range map[string]string{"bob": RoleEditor, "carol": RoleViewer} {
+		if _, err = svc.SetMember(ctx, "alice", ws.ID, WorkspaceMember{UserID: member, Role: role}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	return svc, ws.ID
+}
+
+func TestWorkspaceService_Authorize(t *testing.T) {
+	svc, wsID := newTestWorkspace(t)
+
+	tests := []struct {
+		name        string
+		userID      string
+		workspaceID string
+		role        string
+		want        string
+		wantErr     error
+	}{
+		{name: "personal", userID: "dave", role: RoleOwner, want: "dave"},
+		{name: "own id", userID: "dave", workspaceID: "dave", role: RoleOwner, want: "dave"},
+		{name: "editor edits", userID: "bob", workspaceID: wsID, role: RoleEditor, want: wsID},
+		{name: "viewer views", userID: "carol", workspaceID: wsID, role: RoleViewer, want: wsID},
+		{name: "viewer cannot edit", userID: "carol", workspaceID: wsID, role: RoleEditor, wantErr: ErrWorkspaceForbidden},
+		{name: "stranger", userID: "dave", workspaceID: wsID, role: RoleViewer, wantErr: ErrWorkspaceNotFound},
+		{name: "other user's links", userID: "dave", workspaceID: "alice", role: RoleViewer, wantErr: ErrWorkspaceNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := svc.Authorize(context.Background(), tt.userID, tt.workspaceID, tt.role)
+			if !errors.Is(err, tt.wantErr) || got != tt.want {
+				t.Errorf("expected %q, %v, got %q, %v", tt.want, tt.wantErr, got, err)
+			}
+		})
+	}
+}
+
+func TestWorkspaceService_Members(t *testing.T) {
+	svc, wsID := newTestWorkspace(t)
+	ctx := context.Background()
+
+	if _, err := svc.SetMember(ctx, "bob", wsID, WorkspaceMember{UserID: "dave", Role: RoleEditor}); !errors.Is(err, ErrWorkspaceForbidden) {
+		t.Errorf("expected editor to be unable to add members, got %v", err)
+	}
+	if _, err := svc.SetMember(ctx, "alice", wsID, WorkspaceMember{UserID: "dave", Role: "admin"}); !errors.Is(err, ErrInvalidWorkspace) {
+		t.Errorf("expected ErrInvalidWorkspace, got %v", err)
+	}
+	if _, err := svc.SetMember(ctx, "alice", wsID, WorkspaceMember{UserID: "alice", Role: RoleEditor}); !errors.Is(err, ErrLastWorkspaceOwner) {
+		t.Errorf("expected ErrLastWorkspaceOwner on demotion, got %v", err)
+	}
+	if err := svc.RemoveMember(ctx, "alice", wsID, "alice"); !errors.Is(err, ErrLastWorkspaceOwner) {
+		t.Errorf("expected ErrLastWorkspaceOwner on leave, got %v", err)
+	}
+	if err := svc.RemoveMember(ctx, "carol", wsID, "bob"); !errors.Is(err, ErrWorkspaceForbidden) {
+		t.Errorf("expected viewer to be unable to remove others, got %v", err)
+	}
+	if err := svc.RemoveMember(ctx, "carol", wsID, "carol"); err != nil {
+		t.Errorf("expected member to be able to leave, got %v", err)
+	}
+
+	// Уход сотрудника не лишает пространство ссылок: после передачи прав прежний владелец может уйти.
+	if _, err := svc.SetMember(ctx, "alice", wsID, WorkspaceMember{UserID: "bob", Role: RoleOwner}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := svc.RemoveMember(ctx, "bob", wsID, "alice"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if owner, err := svc.Authorize(ctx, "bob", wsID, RoleOwner); err != nil || owner != wsID {
+		t.Errorf("expected bob to own the workspace, got %q, %v", owner, err)
+	}
+	members, err := svc.ListMembers(ctx, "bob", wsID)
+	if err != nil || len(members) != 1 {
+		t.Errorf("expected a single member, got %v, %v", members, err)
+	}
+}
+
+func TestWorkspaceService_CreateWorkspace_InvalidName(t *testing.T) {
+	svc := NewWorkspaceService(&memWorkspaceStore{members: make(map[string]map[string]string)})
+	for _, name := range []string{"", "   ", strings.Repeat("x", maxWorkspaceNameLength+1)} {
+		if _, err := svc.CreateWorkspace(context.Background(), "alice", name); !errors.Is(err, ErrInvalidWorkspace) {
+			t.Errorf("%q: expected ErrInvalidWorkspace, got %v", name, err)
+		}
+	}
+}
@@ -6,9 +6,14 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"slices"
+	"strings"
+	"time"
+
 	"github.com/aseptimu/url-shortener/internal/app/config"
 	"github.com/aseptimu/url-shortener/internal/app/service"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 
 	"go.uber.org/zap"
@@ -45,40 +50,127 @@ func (db *Database) Ping(ctx context.Context) error {
 	return nil
 }
 
-// GetURLQuery содержит SQL-запрос для получения оригинального URL и флага удаления.
-const GetURLQuery = "SELECT original_url, is_deleted FROM urls WHERE short_url = $1"
+// GetURLQuery содержит SQL-запрос для получения оригинального URL, флага удаления, срока жизни, заголовка,
+// параметров, правил перенаправления и вариантов A/B-сплита.
+const GetURLQuery = `SELECT original_url, is_deleted, expires_at, remaining_uses, COALESCE(password_hash, ''), title, created_at,
+                redirect_code, pass_query, pass_path, redirect_rules, split_variants
+         FROM urls WHERE short_url = $1`
 
-// Get возвращает originalURL, признак существования и признак удаления для shortURL.
-func (db *Database) Get(ctx context.Context, shortURL string) (string, error) {
+// Get возвращает запись для shortURL.
+// Для отсутствующей ссылки возвращает service.ErrURLNotFound, для удалённой — service.ErrURLDeleted.
+func (db *Database) Get(ctx context.Context, shortURL string) (service.URLDTO, error) {
 	ctx, cancel := context.WithTimeout(ctx, config.DBTimeout)
 	defer cancel()
 
-	var originalURL string
+	url := service.URLDTO{ShortURL: shortURL}
 	var deleted bool
 
 	row := db.dbpool.QueryRow(ctx, GetURLQuery, shortURL)
-	err := row.Scan(&originalURL, &deleted)
+	err := row.Scan(&url.OriginalURL, &deleted, &url.ExpiresAt, &url.RemainingUses, &url.PasswordHash, &url.Title, &url.CreatedAt,
+		&url.RedirectCode, &url.PassQuery, &url.PassPath, &url.Rules, &url.Variants)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) || errors.Is(err, sql.ErrNoRows) {
-			return "", service.ErrURLNotFound
+			return service.URLDTO{}, service.ErrURLNotFound
 		}
 		db.logger.Errorw("failed to query url", "shortURL", shortURL, "err", err)
-		return "", fmt.Errorf("database error: %w", err)
+		return service.URLDTO{}, fmt.Errorf("database error: %w", err)
 	}
 
 	if deleted {
-		return "", service.ErrURLDeleted
+		return service.URLDTO{}, service.ErrURLDeleted
+	}
+
+	return url, nil
+}
+
+// ConsumeUseQuery содержит условный SQL-запрос, уменьшающий счётчик переходов,
+// только если он ещё положительный.
+const ConsumeUseQuery = `UPDATE urls SET remaining_uses = remaining_uses - 1
+         WHERE short_url = $1 AND remaining_uses > 0 AND is_deleted = FALSE
+         RETURNING remaining_uses`
+
+// ConsumeUse атомарно уменьшает remaining_uses для shortURL.
+// Если счётчик уже равен нулю, возвращает service.ErrURLExhausted.
+func (db *Database) ConsumeUse(ctx context.Context, shortURL string) error {
+	ctx, cancel := context.WithTimeout(ctx, config.DBTimeout)
+	defer cancel()
+
+	var remaining int
+	err := db.dbpool.QueryRow(ctx, ConsumeUseQuery, shortURL).Scan(&remaining)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return service.ErrURLExhausted
+	}
+	if err != nil {
+		db.logger.Errorw("Failed to consume URL use", "shortURL", shortURL, "err", err)
+		return err
 	}
 
-	return originalURL, nil
+	db.logger.Debugw("URL use consumed", "shortURL", shortURL, "remaining", remaining)
+	return nil
+}
+
+// GetURLsByUserID содержит SQL-запрос для получения не удалённых URL пользователя с тегами.
+// Если массив $2 не пуст, возвращаются только ссылки, у которых есть все теги из него.
+// Непустой $3 — шаблон ILIKE для оригинального URL, непустой $4 — канонический URL.
+// Условие на позицию курсора, порядок и лимит дописывает GetUserURLs.
+const GetURLsByUserID = `SELECT u.short_url, u.original_url, u.normalized_url, u.expires_at, u.remaining_uses,
+             COALESCE(r.clicks, 0), u.created_at, u.title,
+             ARRAY(SELECT t.name FROM url_tags ut JOIN tags t ON t.id = ut.tag_id
+                   WHERE ut.short_url = u.short_url ORDER BY t.name)
+         FROM urls u
+         LEFT JOIN (SELECT short_url, SUM(clicks)::BIGINT AS clicks FROM click_rollups WHERE period = 'day' GROUP BY short_url) r
+             ON r.short_url = u.short_url
+         WHERE u.user_id = $1 AND u.is_deleted = FALSE
+           AND (SELECT COUNT(*) FROM url_tags ut JOIN tags t ON t.id = ut.tag_id
+                WHERE ut.short_url = u.short_url AND t.name = ANY($2)) = cardinality($2::TEXT[])
+           AND ($3 = '' OR u.original_url ILIKE $3)
+           AND ($4 = '' OR u.normalized_url = $4)`
+
+// userURLsSortColumns сопоставляет поле сортировки списка ссылок с колонкой таблицы.
+var userURLsSortColumns = map[string]string{
+	service.URLSortCreated:     "u.created_at",
+	service.URLSortDestination: "u.original_url",
 }
 
-// GetURLsByUserID содержит SQL-запрос для получения всех URL пользователя.
-const GetURLsByUserID = "SELECT short_url, original_url FROM urls WHERE user_id = $1"
+// likeEscaper экранирует спецсимволы шаблона LIKE.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
 
-// GetUserURLs возвращает список service.URLRecord для заданного userID.
-func (db *Database) GetUserURLs(ctx context.Context, userID string) ([]service.URLDTO, error) {
-	rows, err := db.dbpool.Query(ctx, GetURLsByUserID, userID)
+// GetUserURLs возвращает не удалённые ссылки пользователя userID, подходящие под query, в порядке query.
+func (db *Database) GetUserURLs(ctx context.Context, userID string, query service.URLQuery) ([]service.URLDTO, error) {
+	tags := query.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	var search string
+	if query.Search != "" {
+		search = "%" + likeEscaper.Replace(query.Search) + "%"
+	}
+	args := []any{userID, tags, search, query.NormalizedURL}
+
+	column := userURLsSortColumns[query.Sort]
+	if column == "" {
+		column = userURLsSortColumns[service.URLSortCreated]
+	}
+	cmp, direction := ">", "ASC"
+	if query.Desc {
+		cmp, direction = "<", "DESC"
+	}
+
+	sqlQuery := GetURLsByUserID
+	if after := query.After; after != nil {
+		var key any = after.CreatedAt
+		if query.Sort == service.URLSortDestination {
+			key = after.OriginalURL
+		}
+		args = append(args, key, after.ShortURL)
+		sqlQuery += fmt.Sprintf(" AND (%s, u.short_url) %s ($5, $6)", column, cmp)
+	}
+	sqlQuery += fmt.Sprintf(" ORDER BY %[1]s %[2]s, u.short_url %[2]s", column, direction)
+	if query.Limit > 0 {
+		sqlQuery += fmt.Sprintf(" LIMIT %d", query.Limit)
+	}
+
+	rows, err := db.dbpool.Query(ctx, sqlQuery, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -87,32 +179,63 @@ func (db *Database) GetUserURLs(ctx context.Context, userID string) ([]service.U
 	var results []service.URLDTO
 	for rows.Next() {
 		var rec service.URLDTO
-		if err := rows.Scan(&rec.ShortURL, &rec.OriginalURL); err != nil {
+		if err := rows.Scan(&rec.ShortURL, &rec.OriginalURL, &rec.NormalizedURL, &rec.ExpiresAt, &rec.RemainingUses,
+			&rec.Clicks, &rec.CreatedAt, &rec.Title, &rec.Tags); err != nil {
 			return nil, err
 		}
 		results = append(results, rec)
 	}
-	return results, nil
+	return results, rows.Err()
 }
 
 // SetURLQuery содержит SQL-запрос для вставки новой записи или пропуска при конфликте.
-const SetURLQuery = `INSERT INTO urls (short_url, original_url, user_id) 
-         VALUES ($1, $2, $3) 
-         ON CONFLICT (original_url) DO NOTHING 
+// Удалённые и отдельные (standalone) ссылки в поиске дубликатов не участвуют.
+const SetURLQuery = `INSERT INTO urls (short_url, original_url, user_id, expires_at, remaining_uses, password_hash, normalized_url, title,
+                           redirect_code, pass_query, pass_path, domain, standalone) 
+         VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''), $7, $8, $9, $10, $11, $12, $13) 
+         ON CONFLICT ((COALESCE(user_id, '')), domain, normalized_url) WHERE is_deleted = FALSE AND standalone = FALSE DO NOTHING 
          RETURNING short_url`
 
-// GetExistingURLQuery содержит SQL-запрос для получения существующего short_url по original_url.
-const GetExistingURLQuery = "SELECT short_url FROM urls WHERE original_url = $1"
+// GetExistingURLQuery содержит SQL-запрос для получения существующего short_url пользователя
+// по каноническому URL на коротком домене.
+const GetExistingURLQuery = `SELECT short_url FROM urls
+         WHERE normalized_url = $1 AND COALESCE(user_id, '') = $2 AND domain = $3 AND is_deleted = FALSE AND standalone = FALSE`
+
+// uniqueViolationCode — код ошибки PostgreSQL при нарушении ограничения уникальности.
+const uniqueViolationCode = "23505"
+
+// isUniqueViolation сообщает, что err вызвана нарушением ограничения уникальности.
+// Конфликт по original_url обрабатывается через ON CONFLICT, поэтому такая ошибка
+// при вставке означает, что занят short_url.
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == uniqueViolationCode
+}
 
-// Set сохраняет пару shortURL→originalURL и возвращает фактический ключ.
+// Set сохраняет url и возвращает фактический ключ.
 // В случае конфликта возвращает уже существующий shortURL.
-func (db *Database) Set(ctx context.Context, shortURL, originalURL string, userID string) (string, error) {
+// Если shortURL уже занят другой ссылкой, возвращает service.ErrShortURLTaken.
+func (db *Database) Set(ctx context.Context, url service.URLDTO, userID string) (string, error) {
 	ctx, cancel := context.WithTimeout(ctx, config.DBTimeout)
 	defer cancel()
 
+	shortURL, originalURL := url.ShortURL, url.OriginalURL
 	db.logger.Debugw("Attempting to insert URL", "shortURL", shortURL, "originalURL", originalURL)
 
-	err := db.dbpool.QueryRow(ctx, SetURLQuery, shortURL, originalURL, userID).Scan(&shortURL)
+	tx, err := db.dbpool.Begin(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback(ctx)
+
+	domain, _ := service.SplitLinkKey(shortURL)
+	err = tx.QueryRow(ctx, SetURLQuery, shortURL, originalURL, userID, url.ExpiresAt, url.RemainingUses, url.PasswordHash, url.NormalizedURL, url.Title,
+		url.RedirectCode, url.PassQuery, url.PassPath, domain, url.Standalone).Scan(&shortURL)
+
+	if isUniqueViolation(err) {
+		db.logger.Debugw("Short URL is already taken", "shortURL", shortURL)
+		return "", service.ErrShortURLTaken
+	}
 
 	if err != nil && !errors.Is(err, sql.ErrNoRows) {
 		db.logger.Errorw("Failed to insert URL", "shortURL", shortURL, "originalURL", originalURL, "err", err)
@@ -122,12 +245,18 @@ func (db *Database) Set(ctx context.Context, shortURL, originalURL string, userI
 	if errors.Is(err, sql.ErrNoRows) {
 		db.logger.Debugw("URL already exists, fetching short URL from DB", "originalURL", originalURL)
 
-		err = db.dbpool.QueryRow(ctx, GetExistingURLQuery, originalURL).Scan(&shortURL)
+		err = tx.QueryRow(ctx, GetExistingURLQuery, url.NormalizedURL, userID, domain).Scan(&shortURL)
 
 		if err != nil {
 			db.logger.Errorw("Failed to retrieve existing short URL", "originalURL", originalURL, "err", err)
 			return "", err
 		}
+	} else if err = addURLTags(ctx, tx, shortURL, url.Tags); err != nil {
+		return "", err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return "", err
 	}
 
 	db.logger.Debugw("Successfully stored short URL", "shortURL", shortURL, "originalURL", originalURL)
@@ -135,7 +264,8 @@ func (db *Database) Set(ctx context.Context, shortURL, originalURL string, userI
 }
 
 // BatchSet сохраняет несколько URL в рамках одной транзакции и возвращает мапу shortURL→originalURL.
-func (db *Database) BatchSet(ctx context.Context, urls map[string]string, userID string) (map[string]string, error) {
+// Если хотя бы один shortURL уже занят, транзакция откатывается и возвращается service.ErrShortURLTaken.
+func (db *Database) BatchSet(ctx context.Context, urls []service.URLDTO, userID string) (map[string]string, error) {
 	ctx, cancel := context.WithTimeout(ctx, config.DBTimeout)
 	defer cancel()
 
@@ -147,9 +277,17 @@ func (db *Database) BatchSet(ctx context.Context, urls map[string]string, userID
 
 	result := make(map[string]string)
 
-	for shortURL, originalURL := range urls {
+	for _, url := range urls {
+		shortURL, originalURL := url.ShortURL, url.OriginalURL
 		var storedShortURL string
-		err = tx.QueryRow(ctx, SetURLQuery, shortURL, originalURL, userID).Scan(&storedShortURL)
+		domain, _ := service.SplitLinkKey(shortURL)
+		err = tx.QueryRow(ctx, SetURLQuery, shortURL, originalURL, userID, url.ExpiresAt, url.RemainingUses, url.PasswordHash, url.NormalizedURL, url.Title,
+			url.RedirectCode, url.PassQuery, url.PassPath, domain, url.Standalone).Scan(&storedShortURL)
+
+		if isUniqueViolation(err) {
+			db.logger.Debugw("Short URL is already taken", "shortURL", shortURL)
+			return nil, service.ErrShortURLTaken
+		}
 
 		if err != nil && err != pgx.ErrNoRows {
 			db.logger.Errorw("Failed to insert URL", "shortURL", shortURL, "originalURL", originalURL, "err", err)
@@ -158,11 +296,13 @@ func (db *Database) BatchSet(ctx context.Context, urls map[string]string, userID
 
 		// Если вставка не сработала (конфликт), получаем уже существующую короткую ссылку
 		if err == pgx.ErrNoRows || storedShortURL == "" {
-			err = tx.QueryRow(ctx, GetExistingURLQuery, originalURL).Scan(&storedShortURL)
+			err = tx.QueryRow(ctx, GetExistingURLQuery, url.NormalizedURL, userID, domain).Scan(&storedShortURL)
 			if err != nil {
 				db.logger.Errorw("Failed to retrieve existing short URL", "originalURL", originalURL, "err", err)
 				return nil, err
 			}
+		} else if err = addURLTags(ctx, tx, storedShortURL, url.Tags); err != nil {
+			return nil, err
 		}
 
 		result[storedShortURL] = originalURL
@@ -176,7 +316,8 @@ func (db *Database) BatchSet(ctx context.Context, urls map[string]string, userID
 }
 
 // BatchDeleteQuery содержит SQL-запрос для пометки URL как удалённых.
-const BatchDeleteQuery = "UPDATE urls SET is_deleted = TRUE WHERE short_url = ANY($1) AND user_id = $2"
+// Повторное удаление не сдвигает deleted_at, чтобы не продлевать льготный период.
+const BatchDeleteQuery = "UPDATE urls SET is_deleted = TRUE, deleted_at = now() WHERE short_url = ANY($1) AND user_id = $2 AND is_deleted = FALSE"
 
 // BatchDelete помечает указанные shortURLs как удалённые для заданного userID.
 func (db *Database) BatchDelete(ctx context.Context, shortURLs []string, userID string) error {
@@ -190,6 +331,56 @@ func (db *Database) BatchDelete(ctx context.Context, shortURLs []string, userID
 	return nil
 }
 
+// RestoreURLsQuery содержит SQL-запрос для снятия пометки удаления со ссылок, удалённых позже $3.
+// Если пользователь успел заново сократить тот же URL, восстановленная ссылка становится отдельной,
+// чтобы не нарушать уникальность дубликатов.
+const RestoreURLsQuery = `UPDATE urls u SET is_deleted = FALSE, deleted_at = NULL,
+             standalone = u.standalone OR EXISTS (
+                 SELECT 1 FROM urls o WHERE o.user_id = u.user_id AND o.domain = u.domain AND o.normalized_url = u.normalized_url
+                     AND o.is_deleted = FALSE AND o.standalone = FALSE)
+         WHERE u.short_url = ANY($1) AND u.user_id = $2 AND u.is_deleted = TRUE AND u.deleted_at > $3
+         RETURNING u.short_url`
+
+// RestoreURLs восстанавливает ссылки userID, удалённые позже deletedAfter, и возвращает их shortURLs.
+func (db *Database) RestoreURLs(ctx context.Context, shortURLs []string, userID string, deletedAfter time.Time) ([]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, config.DBTimeout)
+	defer cancel()
+
+	rows, err := db.dbpool.Query(ctx, RestoreURLsQuery, shortURLs, userID, deletedAfter)
+	if err != nil {
+		db.logger.Errorw("Failed to restore URLs", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	restored := make([]string, 0, len(shortURLs))
+	for rows.Next() {
+		var shortURL string
+		if err := rows.Scan(&shortURL); err != nil {
+			return nil, err
+		}
+		restored = append(restored, shortURL)
+	}
+	return restored, rows.Err()
+}
+
+// PurgeDeletedURLsQuery содержит SQL-запрос для окончательного удаления ссылок,
+// удалённых не позже $1. Переходы и история назначений удаляются каскадно.
+const PurgeDeletedURLsQuery = "DELETE FROM urls WHERE is_deleted = TRUE AND deleted_at <= $1"
+
+// PurgeDeletedURLs безвозвратно удаляет ссылки, помеченные удалёнными не позже deletedBefore.
+func (db *Database) PurgeDeletedURLs(ctx context.Context, deletedBefore time.Time) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, config.DBTimeout)
+	defer cancel()
+
+	cmdTag, err := db.dbpool.Exec(ctx, PurgeDeletedURLsQuery, deletedBefore)
+	if err != nil {
+		db.logger.Errorw("Failed to purge deleted URLs", "error", err)
+		return 0, err
+	}
+	return int(cmdTag.RowsAffected()), nil
+}
+
 // GetStatQuery возвращает количество
 const GetStatQuery = "SELECT COUNT(short_url), COUNT(DISTINCT(user_id)) FROM urls;"
 
@@ -205,3 +396,898 @@ func (db *Database) GetStats(ctx context.Context) (users, urls int, err error) {
 
 	return users, urls, nil
 }
+
+// GetExpiredURLsQuery содержит SQL-запрос для выборки просроченных, но ещё не удалённых ссылок.
+const GetExpiredURLsQuery = `SELECT user_id, short_url FROM urls
+         WHERE expires_at <= $1 AND is_deleted = FALSE AND user_id IS NOT NULL`
+
+// GetExpiredURLs возвращает просроченные к моменту now ссылки, сгруппированные по userID.
+func (db *Database) GetExpiredURLs(ctx context.Context, now time.Time) (map[string][]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, config.DBTimeout)
+	defer cancel()
+
+	rows, err := db.dbpool.Query(ctx, GetExpiredURLsQuery, now)
+	if err != nil {
+		db.logger.Errorw("Failed to query expired URLs", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	expired := make(map[string][]string)
+	for rows.Next() {
+		var userID, shortURL string
+		if err := rows.Scan(&userID, &shortURL); err != nil {
+			return nil, err
+		}
+		expired[userID] = append(expired[userID], shortURL)
+	}
+	return expired, rows.Err()
+}
+
+// UpsertClickRollupQuery содержит SQL-запрос, прибавляющий приращение к агрегату переходов.
+const UpsertClickRollupQuery = `INSERT INTO click_rollups (short_url, period, bucket_start, clicks)
+         VALUES ($1, $2, $3, $4)
+         ON CONFLICT (short_url, period, bucket_start) DO UPDATE SET clicks = click_rollups.clicks + EXCLUDED.clicks`
+
+// LockClickedURLsQuery содержит SQL-запрос, который выбирает существующие ссылки из $1
+// и блокирует их от удаления до конца транзакции.
+const LockClickedURLsQuery = "SELECT short_url FROM urls WHERE short_url = ANY($1) FOR KEY SHARE"
+
+// SaveClicks в одной транзакции копирует переходы в таблицу clicks
+// и прибавляет приращения к почасовым и суточным агрегатам в click_rollups.
+// Переходы по ссылкам, удалённым, пока переходы ждали в очереди, пропускаются,
+// чтобы не нарушить внешний ключ и не потерять из-за них остальные переходы.
+func (db *Database) SaveClicks(ctx context.Context, clicks []service.ClickEvent, rollups []service.ClickRollup) error {
+	ctx, cancel := context.WithTimeout(ctx, config.DBTimeout)
+	defer cancel()
+
+	tx, err := db.dbpool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	seen := make(map[string]struct{})
+	var keys []string
+	for _, c := range clicks {
+		if _, ok := seen[c.ShortURL]; !ok {
+			seen[c.ShortURL] = struct{}{}
+			keys = append(keys, c.ShortURL)
+		}
+	}
+	rows, err := tx.Query(ctx, LockClickedURLsQuery, keys)
+	if err != nil {
+		db.logger.Errorw("Failed to lock clicked URLs", "err", err)
+		return err
+	}
+	existing, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return err
+	}
+	if len(existing) < len(keys) {
+		alive := make(map[string]struct{}, len(existing))
+		for _, key := range existing {
+			alive[key] = struct{}{}
+		}
+		total := len(clicks)
+		clicks = slices.DeleteFunc(slices.Clone(clicks), func(c service.ClickEvent) bool {
+			_, ok := alive[c.ShortURL]
+			return !ok
+		})
+		rollups = slices.DeleteFunc(slices.Clone(rollups), func(r service.ClickRollup) bool {
+			_, ok := alive[r.ShortURL]
+			return !ok
+		})
+		db.logger.Warnw("Skipped clicks for deleted URLs", "count", total-len(clicks))
+	}
+
+	_, err = tx.CopyFrom(ctx,
+		pgx.Identifier{"clicks"},
+		[]string{"short_url", "clicked_at", "referrer", "user_agent", "ip_hash", "variant"},
+		pgx.CopyFromSlice(len(clicks), func(i int) ([]any, error) {
+			c := clicks[i]
+			return []any{c.ShortURL, c.ClickedAt, c.Referrer, c.UserAgent, c.IPHash, c.Variant}, nil
+		}),
+	)
+	if err != nil {
+		db.logger.Errorw("Failed to copy clicks", "count", len(clicks), "err", err)
+		return err
+	}
+
+	batch := &pgx.Batch{}
+	for _, r := range rollups {
+		batch.Queue(UpsertClickRollupQuery, r.ShortURL, r.Period, r.BucketStart, r.Count)
+	}
+	if err = tx.SendBatch(ctx, batch).Close(); err != nil {
+		db.logger.Errorw("Failed to update click rollups", "count", len(rollups), "err", err)
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// GetTotalClicksQuery содержит SQL-запрос для подсчёта переходов по суточным агрегатам.
+const GetTotalClicksQuery = "SELECT COALESCE(SUM(clicks), 0)::BIGINT FROM click_rollups WHERE period = 'day'"
+
+// GetTotalClicks возвращает число переходов по всем ссылкам.
+func (db *Database) GetTotalClicks(ctx context.Context) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, config.DBTimeout)
+	defer cancel()
+
+	var total int
+	if err := db.dbpool.QueryRow(ctx, GetTotalClicksQuery).Scan(&total); err != nil {
+		db.logger.Errorw("Failed to count clicks", "error", err)
+		return 0, err
+	}
+	return total, nil
+}
+
+// GetClickCountQuery содержит SQL-запрос для подсчёта переходов по ссылке по суточным агрегатам.
+const GetClickCountQuery = "SELECT COALESCE(SUM(clicks), 0)::BIGINT FROM click_rollups WHERE short_url = $1 AND period = 'day'"
+
+// GetClickCount возвращает число переходов по shortURL.
+func (db *Database) GetClickCount(ctx context.Context, shortURL string) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, config.DBTimeout)
+	defer cancel()
+
+	var count int
+	if err := db.dbpool.QueryRow(ctx, GetClickCountQuery, shortURL).Scan(&count); err != nil {
+		db.logger.Errorw("Failed to count clicks", "shortURL", shortURL, "error", err)
+		return 0, err
+	}
+	return count, nil
+}
+
+// GetClicksQuery содержит SQL-запрос для получения всех переходов по ссылке.
+const GetClicksQuery = `SELECT clicked_at, referrer, user_agent, ip_hash, variant FROM clicks
+         WHERE short_url = $1 ORDER BY clicked_at`
+
+// GetClicks возвращает переходы по shortURL в хронологическом порядке.
+func (db *Database) GetClicks(ctx context.Context, shortURL string) ([]service.ClickEvent, error) {
+	ctx, cancel := context.WithTimeout(ctx, config.DBTimeout)
+	defer cancel()
+
+	rows, err := db.dbpool.Query(ctx, GetClicksQuery, shortURL)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var clicks []service.ClickEvent
+	for rows.Next() {
+		click := service.ClickEvent{ShortURL: shortURL}
+		if err := rows.Scan(&click.ClickedAt, &click.Referrer, &click.UserAgent, &click.IPHash, &click.Variant); err != nil {
+			return nil, err
+		}
+		clicks = append(clicks, click)
+	}
+	return clicks, rows.Err()
+}
+
+// GetOwnerQuery содержит SQL-запрос для получения владельца ссылки.
+const GetOwnerQuery = "SELECT COALESCE(user_id, '') FROM urls WHERE short_url = $1 AND is_deleted = FALSE"
+
+// GetOwner возвращает userID владельца shortURL или service.ErrURLNotFound.
+func (db *Database) GetOwner(ctx context.Context, shortURL string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, config.DBTimeout)
+	defer cancel()
+
+	var owner string
+	err := db.dbpool.QueryRow(ctx, GetOwnerQuery, shortURL).Scan(&owner)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", service.ErrURLNotFound
+	}
+	return owner, err
+}
+
+// GetCurrentVersionQuery содержит SQL-запрос для получения текущего назначения и версии ссылки пользователя.
+const GetCurrentVersionQuery = `SELECT original_url, version FROM urls
+         WHERE short_url = $1 AND user_id = $2 AND is_deleted = FALSE`
+
+// InsertHistoryQuery содержит SQL-запрос для сохранения заменяемого назначения в истории.
+const InsertHistoryQuery = `INSERT INTO url_history (short_url, version, original_url, replaced_at)
+         VALUES ($1, $2, $3, $4)`
+
+// UpdateURLQuery содержит SQL-запрос для замены назначения ссылки с увеличением версии.
+const UpdateURLQuery = `UPDATE urls SET original_url = $2, normalized_url = $3, needs_normalization = FALSE, version = version + 1
+         WHERE short_url = $1
+         RETURNING version`
+
+// UpdateURL заменяет назначение ссылки shortURL пользователя userID в одной транзакции
+// с записью прежнего назначения в url_history.
+// Строка ссылки блокируется на время транзакции, поэтому параллельные изменения выполняются по очереди.
+func (db *Database) UpdateURL(ctx context.Context, shortURL, userID string, url service.URLDTO, ifVersion int) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, config.DBTimeout)
+	defer cancel()
+
+	tx, err := db.dbpool.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	var current service.URLVersion
+	err = tx.QueryRow(ctx, GetCurrentVersionQuery+" FOR UPDATE", shortURL, userID).Scan(&current.OriginalURL, &current.Version)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, service.ErrURLNotFound
+	}
+	if err != nil {
+		return 0, err
+	}
+	if ifVersion > 0 && ifVersion != current.Version {
+		return 0, service.ErrVersionMismatch
+	}
+
+	if _, err = tx.Exec(ctx, InsertHistoryQuery, shortURL, current.Version, current.OriginalURL, time.Now()); err != nil {
+		return 0, err
+	}
+
+	var version int
+	err = tx.QueryRow(ctx, UpdateURLQuery, shortURL, url.OriginalURL, url.NormalizedURL).Scan(&version)
+	if isUniqueViolation(err) {
+		return 0, service.ErrConflict
+	}
+	if err != nil {
+		db.logger.Errorw("Failed to update URL", "shortURL", shortURL, "err", err)
+		return 0, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+	return version, nil
+}
+
+// GetHistoryQuery содержит SQL-запрос для получения прежних назначений ссылки от новых к старым.
+const GetHistoryQuery = `SELECT version, original_url, replaced_at FROM url_history
+         WHERE short_url = $1
+         ORDER BY version DESC`
+
+// GetURLHistory возвращает текущее и прежние назначения ссылки shortURL пользователя userID.
+func (db *Database) GetURLHistory(ctx context.Context, shortURL, userID string) (service.URLHistory, error) {
+	ctx, cancel := context.WithTimeout(ctx, config.DBTimeout)
+	defer cancel()
+
+	var history service.URLHistory
+	err := db.dbpool.QueryRow(ctx, GetCurrentVersionQuery, shortURL, userID).Scan(&history.Current.OriginalURL, &history.Current.Version)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return service.URLHistory{}, service.ErrURLNotFound
+	}
+	if err != nil {
+		return service.URLHistory{}, err
+	}
+
+	rows, err := db.dbpool.Query(ctx, GetHistoryQuery, shortURL)
+	if err != nil {
+		return service.URLHistory{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var v service.URLVersion
+		if err := rows.Scan(&v.Version, &v.OriginalURL, &v.ReplacedAt); err != nil {
+			return service.URLHistory{}, err
+		}
+		history.Previous = append(history.Previous, v)
+	}
+	return history, rows.Err()
+}
+
+// InsertTagsQuery содержит SQL-запрос, создающий отсутствующие теги.
+const InsertTagsQuery = "INSERT INTO tags (name) SELECT unnest($1::TEXT[]) ON CONFLICT (name) DO NOTHING"
+
+// InsertURLTagsQuery содержит SQL-запрос, привязывающий теги $2 к ссылке $1.
+const InsertURLTagsQuery = `INSERT INTO url_tags (short_url, tag_id)
+         SELECT $1, id FROM tags WHERE name = ANY($2)
+         ON CONFLICT DO NOTHING`
+
+// addURLTags привязывает теги к ссылке shortURL в рамках транзакции tx, создавая недостающие.
+func addURLTags(ctx context.Context, tx pgx.Tx, shortURL string, tags []string) error {
+	if len(tags) == 0 {
+		return nil
+	}
+	if _, err := tx.Exec(ctx, InsertTagsQuery, tags); err != nil {
+		return err
+	}
+	_, err := tx.Exec(ctx, InsertURLTagsQuery, shortURL, tags)
+	return err
+}
+
+// GetURLTagsQuery содержит SQL-запрос для получения тегов ссылки, отсортированных по имени.
+const GetURLTagsQuery = `SELECT t.name FROM url_tags ut JOIN tags t ON t.id = ut.tag_id
+         WHERE ut.short_url = $1
+         ORDER BY t.name`
+
+// OwnedURLQuery содержит SQL-запрос, проверяющий, что ссылка принадлежит пользователю и не удалена.
+const OwnedURLQuery = "SELECT short_url FROM urls WHERE short_url = $1 AND user_id = $2 AND is_deleted = FALSE"
+
+// GetURLTags возвращает теги ссылки shortURL пользователя userID.
+func (db *Database) GetURLTags(ctx context.Context, shortURL, userID string) ([]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, config.DBTimeout)
+	defer cancel()
+
+	err := db.dbpool.QueryRow(ctx, OwnedURLQuery, shortURL, userID).Scan(&shortURL)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, service.ErrURLNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.dbpool.Query(ctx, GetURLTagsQuery, shortURL)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowTo[string])
+}
+
+// DeleteURLTagsQuery содержит SQL-запрос, отвязывающий от ссылки $1 все теги, кроме $2.
+const DeleteURLTagsQuery = `DELETE FROM url_tags
+         WHERE short_url = $1 AND tag_id NOT IN (SELECT id FROM tags WHERE name = ANY($2))`
+
+// SetURLTags заменяет теги ссылки shortURL пользователя userID на tags в одной транзакции.
+func (db *Database) SetURLTags(ctx context.Context, shortURL, userID string, tags []string) error {
+	ctx, cancel := context.WithTimeout(ctx, config.DBTimeout)
+	defer cancel()
+
+	tx, err := db.dbpool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	err = tx.QueryRow(ctx, OwnedURLQuery+" FOR UPDATE", shortURL, userID).Scan(&shortURL)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return service.ErrURLNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	if tags == nil {
+		tags = []string{}
+	}
+	if _, err = tx.Exec(ctx, DeleteURLTagsQuery, shortURL, tags); err != nil {
+		return err
+	}
+	if err = addURLTags(ctx, tx, shortURL, tags); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// GetUserTagsQuery содержит SQL-запрос для получения тегов пользователя с числом его ссылок.
+const GetUserTagsQuery = `SELECT t.name, COUNT(*) FROM url_tags ut
+         JOIN tags t ON t.id = ut.tag_id
+         JOIN urls u ON u.short_url = ut.short_url
+         WHERE u.user_id = $1 AND u.is_deleted = FALSE
+         GROUP BY t.name
+         ORDER BY t.name`
+
+// GetUserTags возвращает теги ссылок пользователя userID с числом ссылок для каждого.
+func (db *Database) GetUserTags(ctx context.Context, userID string) ([]service.TagCount, error) {
+	ctx, cancel := context.WithTimeout(ctx, config.DBTimeout)
+	defer cancel()
+
+	rows, err := db.dbpool.Query(ctx, GetUserTagsQuery, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []service.TagCount
+	for rows.Next() {
+		var tc service.TagCount
+		if err := rows.Scan(&tc.Tag, &tc.Count); err != nil {
+			return nil, err
+		}
+		result = append(result, tc)
+	}
+	return result, rows.Err()
+}
+
+// GetRedirectRulesQuery содержит SQL-запрос для получения правил перенаправления ссылки пользователя.
+const GetRedirectRulesQuery = "SELECT redirect_rules FROM urls WHERE short_url = $1 AND user_id = $2 AND is_deleted = FALSE"
+
+// GetRedirectRules возвращает правила перенаправления ссылки shortURL пользователя userID.
+func (db *Database) GetRedirectRules(ctx context.Context, shortURL, userID string) ([]service.RedirectRule, error) {
+	ctx, cancel := context.WithTimeout(ctx, config.DBTimeout)
+	defer cancel()
+
+	var rules []service.RedirectRule
+	err := db.dbpool.QueryRow(ctx, GetRedirectRulesQuery, shortURL, userID).Scan(&rules)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, service.ErrURLNotFound
+	}
+	return rules, err
+}
+
+// SetRedirectRulesQuery содержит SQL-запрос, заменяющий правила перенаправления ссылки пользователя.
+const SetRedirectRulesQuery = "UPDATE urls SET redirect_rules = $3 WHERE short_url = $1 AND user_id = $2 AND is_deleted = FALSE"
+
+// SetRedirectRules заменяет правила перенаправления ссылки shortURL пользователя userID на rules.
+func (db *Database) SetRedirectRules(ctx context.Context, shortURL, userID string, rules []service.RedirectRule) error {
+	ctx, cancel := context.WithTimeout(ctx, config.DBTimeout)
+	defer cancel()
+
+	if rules == nil {
+		rules = []service.RedirectRule{}
+	}
+	cmdTag, err := db.dbpool.Exec(ctx, SetRedirectRulesQuery, shortURL, userID, rules)
+	if err != nil {
+		return err
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return service.ErrURLNotFound
+	}
+	return nil
+}
+
+// GetSplitVariantsQuery содержит SQL-запрос для получения вариантов A/B-сплита ссылки пользователя.
+const GetSplitVariantsQuery = "SELECT split_variants FROM urls WHERE short_url = $1 AND user_id = $2 AND is_deleted = FALSE"
+
+// GetSplitVariants возвращает варианты A/B-сплита ссылки shortURL пользователя userID.
+func (db *Database) GetSplitVariants(ctx context.Context, shortURL, userID string) ([]service.SplitVariant, error) {
+	ctx, cancel := context.WithTimeout(ctx, config.DBTimeout)
+	defer cancel()
+
+	var variants []service.SplitVariant
+	err := db.dbpool.QueryRow(ctx, GetSplitVariantsQuery, shortURL, userID).Scan(&variants)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, service.ErrURLNotFound
+	}
+	return variants, err
+}
+
+// SetSplitVariantsQuery содержит SQL-запрос, заменяющий варианты A/B-сплита ссылки пользователя.
+const SetSplitVariantsQuery = "UPDATE urls SET split_variants = $3 WHERE short_url = $1 AND user_id = $2 AND is_deleted = FALSE"
+
+// SetSplitVariants заменяет варианты A/B-сплита ссылки shortURL пользователя userID на variants.
+func (db *Database) SetSplitVariants(ctx context.Context, shortURL, userID string, variants []service.SplitVariant) error {
+	ctx, cancel := context.WithTimeout(ctx, config.DBTimeout)
+	defer cancel()
+
+	if variants == nil {
+		variants = []service.SplitVariant{}
+	}
+	cmdTag, err := db.dbpool.Exec(ctx, SetSplitVariantsQuery, shortURL, userID, variants)
+	if err != nil {
+		return err
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return service.ErrURLNotFound
+	}
+	return nil
+}
+
+// GetUTMTemplatesQuery содержит SQL-запрос для получения UTM-шаблонов пользователя.
+const GetUTMTemplatesQuery = `SELECT name, utm_source, utm_medium, utm_campaign, utm_term, utm_content
+         FROM utm_templates WHERE user_id = $1 ORDER BY name`
+
+// GetUTMTemplates возвращает UTM-шаблоны пользователя userID, отсортированные по имени.
+func (db *Database) GetUTMTemplates(ctx context.Context, userID string) ([]service.UTMTemplate, error) {
+	ctx, cancel := context.WithTimeout(ctx, config.DBTimeout)
+	defer cancel()
+
+	rows, err := db.dbpool.Query(ctx, GetUTMTemplatesQuery, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []service.UTMTemplate
+	for rows.Next() {
+		var t service.UTMTemplate
+		if err := rows.Scan(&t.Name, &t.UTM.Source, &t.UTM.Medium, &t.UTM.Campaign, &t.UTM.Term, &t.UTM.Content); err != nil {
+			return nil, err
+		}
+		result = append(result, t)
+	}
+	return result, rows.Err()
+}
+
+// GetUTMTemplateQuery содержит SQL-запрос для получения UTM-шаблона пользователя по имени.
+const GetUTMTemplateQuery = `SELECT utm_source, utm_medium, utm_campaign, utm_term, utm_content
+         FROM utm_templates WHERE user_id = $1 AND name = $2`
+
+// GetUTMTemplate возвращает UTM-шаблон name пользователя userID.
+func (db *Database) GetUTMTemplate(ctx context.Context, userID, name string) (service.UTMTemplate, error) {
+	ctx, cancel := context.WithTimeout(ctx, config.DBTimeout)
+	defer cancel()
+
+	t := service.UTMTemplate{Name: name}
+	err := db.dbpool.QueryRow(ctx, GetUTMTemplateQuery, userID, name).
+		Scan(&t.UTM.Source, &t.UTM.Medium, &t.UTM.Campaign, &t.UTM.Term, &t.UTM.Content)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return service.UTMTemplate{}, service.ErrUTMTemplateNotFound
+	}
+	return t, err
+}
+
+// SaveUTMTemplateQuery содержит SQL-запрос, создающий или заменяющий UTM-шаблон пользователя.
+const SaveUTMTemplateQuery = `INSERT INTO utm_templates (user_id, name, utm_source, utm_medium, utm_campaign, utm_term, utm_content)
+         VALUES ($1, $2, $3, $4, $5, $6, $7)
+         ON CONFLICT (user_id, name) DO UPDATE SET utm_source = EXCLUDED.utm_source, utm_medium = EXCLUDED.utm_medium,
+             utm_campaign = EXCLUDED.utm_campaign, utm_term = EXCLUDED.utm_term, utm_content = EXCLUDED.utm_content`
+
+// SaveUTMTemplate создаёт UTM-шаблон пользователя userID или заменяет шаблон с тем же именем.
+func (db *Database) SaveUTMTemplate(ctx context.Context, userID string, template service.UTMTemplate) error {
+	ctx, cancel := context.WithTimeout(ctx, config.DBTimeout)
+	defer cancel()
+
+	u := template.UTM
+	_, err := db.dbpool.Exec(ctx, SaveUTMTemplateQuery, userID, template.Name, u.Source, u.Medium, u.Campaign, u.Term, u.Content)
+	return err
+}
+
+// DeleteUTMTemplateQuery содержит SQL-запрос для удаления UTM-шаблона пользователя.
+const DeleteUTMTemplateQuery = "DELETE FROM utm_templates WHERE user_id = $1 AND name = $2"
+
+// DeleteUTMTemplate удаляет UTM-шаблон name пользователя userID.
+func (db *Database) DeleteUTMTemplate(ctx context.Context, userID, name string) error {
+	ctx, cancel := context.WithTimeout(ctx, config.DBTimeout)
+	defer cancel()
+
+	cmdTag, err := db.dbpool.Exec(ctx, DeleteUTMTemplateQuery, userID, name)
+	if err != nil {
+		return err
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return service.ErrUTMTemplateNotFound
+	}
+	return nil
+}
+
+// CreateWorkspaceQuery содержит SQL-запрос для создания рабочего пространства.
+const CreateWorkspaceQuery = "INSERT INTO workspaces (id, name, created_at) VALUES ($1, $2, $3)"
+
+// SetWorkspaceMemberQuery содержит SQL-запрос, добавляющий участника рабочего пространства или меняющий его роль.
+const SetWorkspaceMemberQuery = `INSERT INTO workspace_members (workspace_id, user_id, role) VALUES ($1, $2, $3)
+         ON CONFLICT (workspace_id, user_id) DO UPDATE SET role = EXCLUDED.role`
+
+// CreateWorkspace создаёт рабочее пространство и делает ownerID его владельцем в одной транзакции.
+func (db *Database) CreateWorkspace(ctx context.Context, workspace service.Workspace, ownerID string) error {
+	ctx, cancel := context.WithTimeout(ctx, config.DBTimeout)
+	defer cancel()
+
+	tx, err := db.dbpool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err = tx.Exec(ctx, CreateWorkspaceQuery, workspace.ID, workspace.Name, workspace.CreatedAt); err != nil {
+		return err
+	}
+	if _, err = tx.Exec(ctx, SetWorkspaceMemberQuery, workspace.ID, ownerID, service.RoleOwner); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// GetWorkspacesQuery содержит SQL-запрос для получения рабочих пространств пользователя с его ролями.
+const GetWorkspacesQuery = `SELECT w.id, w.name, m.role, w.created_at
+         FROM workspaces w JOIN workspace_members m ON m.workspace_id = w.id
+         WHERE m.user_id = $1 ORDER BY w.name, w.id`
+
+// GetWorkspaces возвращает рабочие пространства пользователя userID, отсортированные по названию.
+func (db *Database) GetWorkspaces(ctx context.Context, userID string) ([]service.Workspace, error) {
+	ctx, cancel := context.WithTimeout(ctx, config.DBTimeout)
+	defer cancel()
+
+	rows, err := db.dbpool.Query(ctx, GetWorkspacesQuery, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []service.Workspace
+	for rows.Next() {
+		var w service.Workspace
+		if err := rows.Scan(&w.ID, &w.Name, &w.Role, &w.CreatedAt); err != nil {
+			return nil, err
+		}
+		result = append(result, w)
+	}
+	return result, rows.Err()
+}
+
+// GetWorkspaceRoleQuery содержит SQL-запрос для получения роли пользователя в рабочем пространстве.
+const GetWorkspaceRoleQuery = "SELECT role FROM workspace_members WHERE workspace_id = $1 AND user_id = $2"
+
+// GetWorkspaceRole возвращает роль пользователя userID в рабочем пространстве workspaceID.
+func (db *Database) GetWorkspaceRole(ctx context.Context, workspaceID, userID string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, config.DBTimeout)
+	defer cancel()
+
+	var role string
+	err := db.dbpool.QueryRow(ctx, GetWorkspaceRoleQuery, workspaceID, userID).Scan(&role)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", service.ErrWorkspaceNotFound
+	}
+	return role, err
+}
+
+// GetWorkspaceMembersQuery содержит SQL-запрос для получения участников рабочего пространства.
+const GetWorkspaceMembersQuery = "SELECT user_id, role FROM workspace_members WHERE workspace_id = $1 ORDER BY user_id"
+
+// GetWorkspaceMembers возвращает участников рабочего пространства workspaceID, отсортированных по userID.
+func (db *Database) GetWorkspaceMembers(ctx context.Context, workspaceID string) ([]service.WorkspaceMember, error) {
+	ctx, cancel := context.WithTimeout(ctx, config.DBTimeout)
+	defer cancel()
+
+	rows, err := db.dbpool.Query(ctx, GetWorkspaceMembersQuery, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := []service.WorkspaceMember{}
+	for rows.Next() {
+		var m service.WorkspaceMember
+		if err := rows.Scan(&m.UserID, &m.Role); err != nil {
+			return nil, err
+		}
+		result = append(result, m)
+	}
+	return result, rows.Err()
+}
+
+// SetWorkspaceMember добавляет участника рабочего пространства workspaceID или меняет его роль.
+func (db *Database) SetWorkspaceMember(ctx context.Context, workspaceID string, member service.WorkspaceMember) error {
+	ctx, cancel := context.WithTimeout(ctx, config.DBTimeout)
+	defer cancel()
+
+	_, err := db.dbpool.Exec(ctx, SetWorkspaceMemberQuery, workspaceID, member.UserID, member.Role)
+	return err
+}
+
+// DeleteWorkspaceMemberQuery содержит SQL-запрос для исключения участника рабочего пространства.
+const DeleteWorkspaceMemberQuery = "DELETE FROM workspace_members WHERE workspace_id = $1 AND user_id = $2"
+
+// DeleteWorkspaceMember исключает участника userID из рабочего пространства workspaceID.
+func (db *Database) DeleteWorkspaceMember(ctx context.Context, workspaceID, userID string) error {
+	ctx, cancel := context.WithTimeout(ctx, config.DBTimeout)
+	defer cancel()
+
+	cmdTag, err := db.dbpool.Exec(ctx, DeleteWorkspaceMemberQuery, workspaceID, userID)
+	if err != nil {
+		return err
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return service.ErrWorkspaceNotFound
+	}
+	return nil
+}
+
+// CreateAPIKeyQuery содержит SQL-запрос для сохранения API-ключа.
+const CreateAPIKeyQuery = `INSERT INTO api_keys (id, user_id, name, key_hash, prefix, scopes, created_at)
+         VALUES ($1, $2, $3, $4, $5, $6, $7)`
+
+// CreateAPIKey сохраняет API-ключ пользователя userID под хешем hash.
+func (db *Database) CreateAPIKey(ctx context.Context, userID string, key service.APIKey, hash string) error {
+	ctx, cancel := context.WithTimeout(ctx, config.DBTimeout)
+	defer cancel()
+
+	_, err := db.dbpool.Exec(ctx, CreateAPIKeyQuery, key.ID, userID, key.Name, hash, key.Prefix, key.Scopes, key.CreatedAt)
+	return err
+}
+
+// GetAPIKeysQuery содержит SQL-запрос для получения API-ключей пользователя.
+const GetAPIKeysQuery = "SELECT id, name, prefix, scopes, created_at FROM api_keys WHERE user_id = $1 ORDER BY created_at, id"
+
+// GetAPIKeys возвращает API-ключи пользователя userID в порядке выпуска.
+func (db *Database) GetAPIKeys(ctx context.Context, userID string) ([]service.APIKey, error) {
+	ctx, cancel := context.WithTimeout(ctx, config.DBTimeout)
+	defer cancel()
+
+	rows, err := db.dbpool.Query(ctx, GetAPIKeysQuery, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []service.APIKey
+	for rows.Next() {
+		var k service.APIKey
+		if err := rows.Scan(&k.ID, &k.Name, &k.Prefix, &k.Scopes, &k.CreatedAt); err != nil {
+			return nil, err
+		}
+		result = append(result, k)
+	}
+	return result, rows.Err()
+}
+
+// GetAPIKeyByHashQuery содержит SQL-запрос для поиска API-ключа по хешу.
+const GetAPIKeyByHashQuery = "SELECT id, name, prefix, scopes, created_at, user_id FROM api_keys WHERE key_hash = $1"
+
+// GetAPIKeyByHash возвращает API-ключ с хешем hash и его владельца.
+func (db *Database) GetAPIKeyByHash(ctx context.Context, hash string) (service.APIKey, string, error) {
+	ctx, cancel := context.WithTimeout(ctx, config.DBTimeout)
+	defer cancel()
+
+	var k service.APIKey
+	var userID string
+	err := db.dbpool.QueryRow(ctx, GetAPIKeyByHashQuery, hash).Scan(&k.ID, &k.Name, &k.Prefix, &k.Scopes, &k.CreatedAt, &userID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return service.APIKey{}, "", service.ErrAPIKeyNotFound
+	}
+	return k, userID, err
+}
+
+// DeleteAPIKeyQuery содержит SQL-запрос для отзыва API-ключа.
+const DeleteAPIKeyQuery = "DELETE FROM api_keys WHERE user_id = $1 AND id = $2"
+
+// DeleteAPIKey отзывает API-ключ id пользователя userID.
+func (db *Database) DeleteAPIKey(ctx context.Context, userID, id string) error {
+	ctx, cancel := context.WithTimeout(ctx, config.DBTimeout)
+	defer cancel()
+
+	cmdTag, err := db.dbpool.Exec(ctx, DeleteAPIKeyQuery, userID, id)
+	if err != nil {
+		return err
+	}
+	if cmdTag.RowsAffected() == 0 {
+		return service.ErrAPIKeyNotFound
+	}
+	return nil
+}
+
+// CreateAccountQuery содержит SQL-запрос для создания учётной записи.
+const CreateAccountQuery = "INSERT INTO accounts (user_id, login, password_hash, created_at) VALUES ($1, $2, $3, $4)"
+
+// CreateAccount создаёт учётную запись с хешем пароля passwordHash.
+// Если логин уже занят, возвращает service.ErrLoginTaken.
+func (db *Database) CreateAccount(ctx context.Context, account service.Account, passwordHash string) error {
+	ctx, cancel := context.WithTimeout(ctx, config.DBTimeout)
+	defer cancel()
+
+	_, err := db.dbpool.Exec(ctx, CreateAccountQuery, account.UserID, account.Login, passwordHash, account.CreatedAt)
+	if isUniqueViolation(err) {
+		return service.ErrLoginTaken
+	}
+	return err
+}
+
+// GetAccountByLoginQuery содержит SQL-запрос для поиска учётной записи по логину.
+const GetAccountByLoginQuery = "SELECT user_id, login, created_at, password_hash FROM accounts WHERE login = $1"
+
+// GetAccountByLogin возвращает учётную запись с логином login и хеш её пароля.
+func (db *Database) GetAccountByLogin(ctx context.Context, login string) (service.Account, string, error) {
+	ctx, cancel := context.WithTimeout(ctx, config.DBTimeout)
+	defer cancel()
+
+	var a service.Account
+	var hash string
+	err := db.dbpool.QueryRow(ctx, GetAccountByLoginQuery, login).Scan(&a.UserID, &a.Login, &a.CreatedAt, &hash)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return service.Account{}, "", service.ErrAccountNotFound
+	}
+	return a, hash, err
+}
+
+// GetAccountQuery содержит SQL-запрос для поиска учётной записи по идентификатору пользователя.
+const GetAccountQuery = "SELECT user_id, login, created_at FROM accounts WHERE user_id = $1"
+
+// GetAccount возвращает учётную запись пользователя userID.
+func (db *Database) GetAccount(ctx context.Context, userID string) (service.Account, error) {
+	ctx, cancel := context.WithTimeout(ctx, config.DBTimeout)
+	defer cancel()
+
+	var a service.Account
+	err := db.dbpool.QueryRow(ctx, GetAccountQuery, userID).Scan(&a.UserID, &a.Login, &a.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return service.Account{}, service.ErrAccountNotFound
+	}
+	return a, err
+}
+
+// MergeUserURLsQuery содержит SQL-запрос, передающий ссылки одного пользователя другому,
+// кроме ссылок, которые стали бы дубликатами ссылок второго на том же домене.
+const MergeUserURLsQuery = `UPDATE urls u SET user_id = $2
+         WHERE u.user_id = $1 AND (u.is_deleted OR u.standalone OR NOT EXISTS (
+             SELECT 1 FROM urls o WHERE o.user_id = $2 AND o.domain = u.domain AND o.normalized_url = u.normalized_url
+                 AND o.is_deleted = FALSE AND o.standalone = FALSE))`
+
+// MergeUserURLs передаёт ссылки пользователя fromUserID пользователю toUserID.
+func (db *Database) MergeUserURLs(ctx context.Context, fromUserID, toUserID string) error {
+	ctx, cancel := context.WithTimeout(ctx, config.DBTimeout)
+	defer cancel()
+
+	_, err := db.dbpool.Exec(ctx, MergeUserURLsQuery, fromUserID, toUserID)
+	return err
+}
+
+// RevokeTokenQuery содержит SQL-запрос для добавления JWT в список отозванных.
+const RevokeTokenQuery = "INSERT INTO revoked_tokens (id, expires_at) VALUES ($1, $2) ON CONFLICT (id) DO NOTHING"
+
+// PurgeRevokedTokensQuery содержит SQL-запрос для удаления истёкших токенов из списка отозванных.
+const PurgeRevokedTokensQuery = "DELETE FROM revoked_tokens WHERE expires_at < $1"
+
+// RevokeToken добавляет JWT id в список отозванных до expiresAt и удаляет из списка истёкшие токены.
+func (db *Database) RevokeToken(ctx context.Context, id string, expiresAt time.Time) error {
+	ctx, cancel := context.WithTimeout(ctx, config.DBTimeout)
+	defer cancel()
+
+	if _, err := db.dbpool.Exec(ctx, PurgeRevokedTokensQuery, time.Now()); err != nil {
+		return err
+	}
+	_, err := db.dbpool.Exec(ctx, RevokeTokenQuery, id, expiresAt)
+	return err
+}
+
+// IsTokenRevokedQuery содержит SQL-запрос для проверки, отозван ли JWT.
+const IsTokenRevokedQuery = "SELECT EXISTS (SELECT 1 FROM revoked_tokens WHERE id = $1)"
+
+// IsTokenRevoked сообщает, есть ли JWT id в списке отозванных.
+func (db *Database) IsTokenRevoked(ctx context.Context, id string) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, config.DBTimeout)
+	defer cancel()
+
+	var revoked bool
+	err := db.dbpool.QueryRow(ctx, IsTokenRevokedQuery, id).Scan(&revoked)
+	return revoked, err
+}
+
+// NextCodeSequenceQuery содержит SQL-запрос для получения следующего значения счётчика коротких кодов.
+const NextCodeSequenceQuery = "SELECT nextval('short_code_seq')"
+
+// NextCodeSequence возвращает следующее значение счётчика коротких кодов.
+func (db *Database) NextCodeSequence(ctx context.Context) (uint64, error) {
+	ctx, cancel := context.WithTimeout(ctx, config.DBTimeout)
+	defer cancel()
+
+	var n int64
+	if err := db.dbpool.QueryRow(ctx, NextCodeSequenceQuery).Scan(&n); err != nil {
+		db.logger.Errorw("Failed to get next short code sequence value", "err", err)
+		return 0, err
+	}
+	return uint64(n), nil
+}
+
+// UnnormalizedURLsQuery содержит SQL-запрос для выборки ссылок, канонический вид которых ещё не пересчитан.
+const UnnormalizedURLsQuery = "SELECT short_url, original_url FROM urls WHERE needs_normalization LIMIT $1"
+
+// UnnormalizedURLs возвращает до limit ссылок, сохранённых до появления нормализации.
+func (db *Database) UnnormalizedURLs(ctx context.Context, limit int) ([]service.URLDTO, error) {
+	ctx, cancel := context.WithTimeout(ctx, config.DBTimeout)
+	defer cancel()
+
+	rows, err := db.dbpool.Query(ctx, UnnormalizedURLsQuery, limit)
+	if err != nil {
+		db.logger.Errorw("Failed to get unnormalized URLs", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var urls []service.URLDTO
+	for rows.Next() {
+		var url service.URLDTO
+		if err := rows.Scan(&url.ShortURL, &url.OriginalURL); err != nil {
+			return nil, err
+		}
+		urls = append(urls, url)
+	}
+	return urls, rows.Err()
+}
+
+// SetNormalizedURLQuery содержит SQL-запрос для сохранения канонического вида ссылки $1.
+// Если у владельца уже есть действующая ссылка на тот же канонический URL, ссылка становится отдельной,
+// чтобы не нарушать уникальность дубликатов.
+const SetNormalizedURLQuery = `UPDATE urls u SET normalized_url = $2, needs_normalization = FALSE,
+             standalone = u.standalone OR (u.is_deleted = FALSE AND EXISTS (
+                 SELECT 1 FROM urls o WHERE COALESCE(o.user_id, '') = COALESCE(u.user_id, '') AND o.domain = u.domain
+                     AND o.normalized_url = $2 AND o.short_url <> u.short_url AND o.is_deleted = FALSE AND o.standalone = FALSE))
+         WHERE u.short_url = $1`
+
+// SetNormalizedURL сохраняет канонический вид normalizedURL ссылки shortURL.
+func (db *Database) SetNormalizedURL(ctx context.Context, shortURL, normalizedURL string) error {
+	ctx, cancel := context.WithTimeout(ctx, config.DBTimeout)
+	defer cancel()
+
+	if _, err := db.dbpool.Exec(ctx, SetNormalizedURLQuery, shortURL, normalizedURL); err != nil {
+		db.logger.Errorw("Failed to set normalized URL", "shortURL", shortURL, "error", err)
+		return err
+	}
+	return nil
+}
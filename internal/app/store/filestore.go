@@ -8,31 +8,254 @@ import (
 	"github.com/aseptimu/url-shortener/internal/app/service"
 	"log"
 	"os"
+	"sort"
+	"strconv"
+	"strings"
 	"sync"
+	"time"
 )
 
+// URLRecord — строка файла хранилища в формате JSON Lines.
 type URLRecord struct {
 	UUID        string
 	ShortURL    string
 	OriginalURL string
-	UserID      string
-	DeletedFlag bool
+	// NormalizedURL — канонический вид OriginalURL; пуст в записях, сохранённых до его появления.
+	NormalizedURL string `json:",omitempty"`
+	UserID        string
+	DeletedFlag   bool
+	// DeletedAt — момент мягкого удаления, от которого отсчитывается льготный период восстановления.
+	DeletedAt *time.Time `json:",omitempty"`
+	ExpiresAt *time.Time `json:",omitempty"`
+	// RemainingUses — оставшееся число переходов; nil для ссылок без лимита.
+	RemainingUses *int `json:",omitempty"`
+	// PasswordHash — bcrypt-хэш пароля защищённой ссылки.
+	PasswordHash string `json:",omitempty"`
+	// Version — номер текущей версии назначения; 0 в записях, сохранённых до появления истории.
+	Version int `json:",omitempty"`
+	// Tags — теги ссылки, отсортированные по имени.
+	Tags []string `json:",omitempty"`
+	// CreatedAt — момент создания ссылки; нулевой в записях, сохранённых до его появления.
+	CreatedAt time.Time `json:",omitzero"`
+	// Title — заголовок ссылки для страницы предпросмотра.
+	Title string `json:",omitempty"`
+	// RedirectCode — код перенаправления; 0 в записях, сохранённых до его появления, означает 307.
+	RedirectCode int  `json:",omitempty"`
+	PassQuery    bool `json:",omitempty"`
+	PassPath     bool `json:",omitempty"`
+	// Rules — правила перенаправления в порядке проверки.
+	Rules []service.RedirectRule `json:",omitempty"`
+	// Variants — варианты A/B-сплита.
+	Variants []service.SplitVariant `json:",omitempty"`
+	// Standalone — ссылка не участвует в поиске дубликатов (см. service.URLDTO).
+	Standalone bool `json:",omitempty"`
+}
+
+// version возвращает номер текущей версии назначения; у записей без Version это первая версия.
+func (r URLRecord) version() int {
+	if r.Version == 0 {
+		return 1
+	}
+	return r.Version
+}
+
+// dedupKey возвращает канонический URL, по которому ищутся дубликаты записи;
+// для старых записей без NormalizedURL это OriginalURL.
+func (r URLRecord) dedupKey() string {
+	if r.NormalizedURL == "" {
+		return r.OriginalURL
+	}
+	return r.NormalizedURL
+}
+
+// toDTO преобразует запись файла в service.URLDTO.
+func (r URLRecord) toDTO() service.URLDTO {
+	return service.URLDTO{
+		ShortURL:      r.ShortURL,
+		OriginalURL:   r.OriginalURL,
+		NormalizedURL: r.NormalizedURL,
+		ExpiresAt:     r.ExpiresAt,
+		// Копируем счётчик, чтобы вызывающий код не видел последующих изменений записи.
+		RemainingUses: copyInt(r.RemainingUses),
+		PasswordHash:  r.PasswordHash,
+		Tags:          copyStrings(r.Tags),
+		CreatedAt:     r.CreatedAt,
+		Title:         r.Title,
+		RedirectCode:  r.RedirectCode,
+		PassQuery:     r.PassQuery,
+		PassPath:      r.PassPath,
+		Rules:         copyRules(r.Rules),
+		Variants:      copyVariants(r.Variants),
+		Standalone:    r.Standalone,
+	}
+}
+
+// domainOf возвращает короткий домен ссылки с ключом key.
+func domainOf(key string) string {
+	domain, _ := service.SplitLinkKey(key)
+	return domain
+}
+
+func copyStrings(v []string) []string {
+	if v == nil {
+		return nil
+	}
+	return append([]string(nil), v...)
+}
+
+func copyRules(v []service.RedirectRule) []service.RedirectRule {
+	if v == nil {
+		return nil
+	}
+	return append([]service.RedirectRule(nil), v...)
+}
+
+func copyVariants(v []service.SplitVariant) []service.SplitVariant {
+	if v == nil {
+		return nil
+	}
+	return append([]service.SplitVariant(nil), v...)
+}
+
+func copyInt(v *int) *int {
+	if v == nil {
+		return nil
+	}
+	c := *v
+	return &c
+}
+
+// newURLRecord создаёт запись файла для новой ссылки пользователя userID.
+func newURLRecord(url service.URLDTO, userID string) URLRecord {
+	return URLRecord{
+		UUID:          url.ShortURL,
+		ShortURL:      url.ShortURL,
+		OriginalURL:   url.OriginalURL,
+		NormalizedURL: url.NormalizedURL,
+		UserID:        userID,
+		DeletedFlag:   false,
+		ExpiresAt:     url.ExpiresAt,
+		RemainingUses: copyInt(url.RemainingUses),
+		PasswordHash:  url.PasswordHash,
+		Tags:          copyStrings(url.Tags),
+		CreatedAt:     time.Now(),
+		Title:         url.Title,
+		RedirectCode:  url.RedirectCode,
+		PassQuery:     url.PassQuery,
+		PassPath:      url.PassPath,
+		Standalone:    url.Standalone,
+	}
 }
 
 // FileStore хранит кэш URLRecord в памяти и синхронизирует его с файлом.
+// Переходы по ссылкам и прежние назначения ссылок дописываются в отдельные файлы
+// рядом с основным (см. clicksPath и historyPath), UTM-шаблоны пользователей хранятся
+// в файле templatesPath, рабочие пространства — в файле workspacesPath, API-ключи — в файле apiKeysPath,
+// учётные записи — в файле accountsPath, отозванные JWT — в файле revokedPath,
+// граница счётчика коротких кодов — в файле sequencePath.
 type FileStore struct {
 	mu       sync.RWMutex
 	filePath string
 	data     map[string]URLRecord
+	// history — прежние назначения ссылок в порядке изменения; защищена mu.
+	history map[string][]HistoryRecord
+	// templates — UTM-шаблоны по пользователям и именам; защищена mu.
+	templates map[string]map[string]service.UTM
+	// workspaces — рабочие пространства по ID; защищена mu.
+	workspaces map[string]WorkspaceRecord
+	// apiKeys — API-ключи по хешу; защищена mu.
+	apiKeys map[string]APIKeyRecord
+	// accounts — учётные записи по логину; защищена mu.
+	accounts map[string]AccountRecord
+	// revoked — сроки истечения отозванных JWT по их ID; защищена mu.
+	revoked map[string]time.Time
+
+	sequenceMu sync.Mutex
+	// sequenceNext — последнее выданное значение счётчика коротких кодов,
+	// sequenceLimit — сохранённая в файле граница зарезервированного блока; защищены sequenceMu.
+	sequenceNext  uint64
+	sequenceLimit uint64
+
+	clicksMu sync.Mutex
+	// clickCounts — суммарное число переходов по ссылкам, восстанавливается из файла переходов при запуске.
+	clickCounts map[string]int
+}
+
+// ClickRecord — строка файла переходов в формате JSON Lines.
+type ClickRecord struct {
+	ShortURL  string
+	ClickedAt time.Time
+	Referrer  string `json:",omitempty"`
+	UserAgent string `json:",omitempty"`
+	IPHash    string `json:",omitempty"`
+	Variant   string `json:",omitempty"`
+}
+
+// UTMTemplateRecord — строка файла UTM-шаблонов в формате JSON Lines.
+type UTMTemplateRecord struct {
+	UserID string
+	Name   string
+	UTM    service.UTM
+}
+
+// WorkspaceRecord — строка файла рабочих пространств в формате JSON Lines.
+// Members содержит роли участников по userID.
+type WorkspaceRecord struct {
+	ID        string
+	Name      string
+	CreatedAt time.Time
+	Members   map[string]string
+}
+
+// APIKeyRecord — строка файла API-ключей в формате JSON Lines.
+type APIKeyRecord struct {
+	UserID string
+	Hash   string
+	Key    service.APIKey
+}
+
+// AccountRecord — строка файла учётных записей в формате JSON Lines.
+type AccountRecord struct {
+	Account      service.Account
+	PasswordHash string
+}
+
+// RevokedTokenRecord — строка файла отозванных JWT в формате JSON Lines.
+type RevokedTokenRecord struct {
+	ID        string
+	ExpiresAt time.Time
+}
+
+// HistoryRecord — строка файла истории назначений в формате JSON Lines.
+type HistoryRecord struct {
+	ShortURL    string
+	Version     int
+	OriginalURL string
+	ReplacedAt  time.Time
 }
 
 // NewFileStore создаёт FileStore, загружая данные из указанного файла при наличии.
 func NewFileStore(filePath string) *FileStore {
 	store := &FileStore{
-		filePath: filePath,
-		data:     make(map[string]URLRecord),
+		filePath:    filePath,
+		data:        make(map[string]URLRecord),
+		history:     make(map[string][]HistoryRecord),
+		templates:   make(map[string]map[string]service.UTM),
+		workspaces:  make(map[string]WorkspaceRecord),
+		apiKeys:     make(map[string]APIKeyRecord),
+		accounts:    make(map[string]AccountRecord),
+		revoked:     make(map[string]time.Time),
+		clickCounts: make(map[string]int),
 	}
 	store.loadFromFile()
+	store.loadHistory()
+	store.loadTemplates()
+	store.loadWorkspaces()
+	store.loadAPIKeys()
+	store.loadAccounts()
+	store.loadRevokedTokens()
+	store.loadCodeSequence()
+	store.loadClickCounts()
 	return store
 }
 
@@ -47,9 +270,19 @@ func (fs *FileStore) loadFromFile() {
 	defer file.Close()
 
 	scanner := bufio.NewScanner(file)
+	loadedAt := time.Now()
 	for scanner.Scan() {
 		var record URLRecord
 		if err := json.Unmarshal(scanner.Bytes(), &record); err == nil {
+			// Записи, удалённые до появления DeletedAt, получают полный льготный период с момента загрузки.
+			if record.DeletedFlag && record.DeletedAt == nil {
+				record.DeletedAt = &loadedAt
+			}
+			// Ссылки с паролем, лимитом переходов или сроком жизни, сохранённые до появления Standalone,
+			// тоже не участвуют в поиске дубликатов.
+			if record.PasswordHash != "" || record.RemainingUses != nil || record.ExpiresAt != nil {
+				record.Standalone = true
+			}
 			fs.data[record.ShortURL] = record
 		}
 	}
@@ -83,98 +316,158 @@ func (fs *FileStore) rewriteFile() error {
 	return writer.Flush()
 }
 
-// Get возвращает originalURL, признак существования и признак удаления для shortURL.
-func (fs *FileStore) Get(_ context.Context, shortURL string) (string, error) {
+// Get возвращает запись для shortURL.
+// Для отсутствующей ссылки возвращает service.ErrURLNotFound, для удалённой — service.ErrURLDeleted.
+func (fs *FileStore) Get(_ context.Context, shortURL string) (service.URLDTO, error) {
 	fs.mu.RLock()
 	defer fs.mu.RUnlock()
 
 	record, exists := fs.data[shortURL]
 	if !exists {
-		return "", service.ErrURLNotFound
+		return service.URLDTO{}, service.ErrURLNotFound
+	}
+	if record.DeletedFlag {
+		return service.URLDTO{}, service.ErrURLDeleted
+	}
+	return record.toDTO(), nil
+}
+
+// ConsumeUse уменьшает счётчик оставшихся переходов shortURL под блокировкой
+// и дописывает обновлённую запись в файл: при загрузке побеждает последняя строка.
+// Если счётчик уже равен нулю, возвращает service.ErrURLExhausted.
+func (fs *FileStore) ConsumeUse(_ context.Context, shortURL string) error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	record, exists := fs.data[shortURL]
+	if !exists {
+		return service.ErrURLNotFound
 	}
 	if record.DeletedFlag {
-		return "", service.ErrURLDeleted
+		return service.ErrURLDeleted
+	}
+	if record.RemainingUses == nil {
+		return nil
 	}
-	return record.OriginalURL, nil
+	if *record.RemainingUses <= 0 {
+		return service.ErrURLExhausted
+	}
+
+	remaining := *record.RemainingUses - 1
+	record.RemainingUses = &remaining
+	fs.data[shortURL] = record
+	fs.saveToFile(record)
+	return nil
 }
 
-// GetUserURLs возвращает список всех не удалённых service.URLRecord для заданного userID.
-func (fs *FileStore) GetUserURLs(_ context.Context, userID string) ([]service.URLDTO, error) {
+// GetUserURLs возвращает не удалённые ссылки пользователя userID, подходящие под query, в порядке query.
+func (fs *FileStore) GetUserURLs(_ context.Context, userID string, query service.URLQuery) ([]service.URLDTO, error) {
 	fs.mu.RLock()
 	defer fs.mu.RUnlock()
 
-	var results []service.URLDTO
+	var owned []service.URLDTO
 	for _, record := range fs.data {
 		if record.UserID == userID && !record.DeletedFlag {
-			results = append(results, service.URLDTO{
-				ShortURL:    record.ShortURL,
-				OriginalURL: record.OriginalURL,
-			})
+			url := record.toDTO()
+			if url.NormalizedURL == "" {
+				url.NormalizedURL = url.OriginalURL
+			}
+			owned = append(owned, url)
 		}
 	}
+	results := query.Apply(owned)
+
+	fs.clicksMu.Lock()
+	defer fs.clicksMu.Unlock()
+	for i := range results {
+		results[i].Clicks = fs.clickCounts[results[i].ShortURL]
+	}
 	return results, nil
 }
 
-// Set сохраняет originalURL с ключом shortURL, если он ещё не существует,
+// Set сохраняет url, если пользователь userID ещё не сокращал этот URL,
 // и возвращает фактический shortURL (новый или уже существующий).
-func (fs *FileStore) Set(_ context.Context, shortURL, originalURL, userID string) (string, error) {
+// Если shortURL уже занят другой ссылкой, возвращает service.ErrShortURLTaken.
+func (fs *FileStore) Set(_ context.Context, url service.URLDTO, userID string) (string, error) {
 	fs.mu.Lock()
 	defer fs.mu.Unlock()
 
-	for _, record := range fs.data {
-		if record.OriginalURL == originalURL {
-			return record.ShortURL, nil
+	if !url.Standalone {
+		if existing, found := fs.findByNormalizedURL(url.NormalizedURL, userID, domainOf(url.ShortURL)); found {
+			return existing, nil
 		}
 	}
 
-	newRecord := URLRecord{
-		UUID:        shortURL,
-		ShortURL:    shortURL,
-		OriginalURL: originalURL,
-		UserID:      userID,
-		DeletedFlag: false,
+	if _, taken := fs.data[url.ShortURL]; taken {
+		return "", service.ErrShortURLTaken
 	}
-	fs.data[shortURL] = newRecord
+
+	newRecord := newURLRecord(url, userID)
+	fs.data[url.ShortURL] = newRecord
 	fs.saveToFile(newRecord)
 
-	return shortURL, nil
+	return url.ShortURL, nil
+}
+
+// findByNormalizedURL ищет короткий ключ, уже сохранённый пользователем userID для канонического URL
+// на домене domain. Удалённые и отдельные ссылки пропускаются.
+// Для старых записей без NormalizedURL сравнивается OriginalURL.
+// Вызывающий код должен удерживать fs.mu.
+func (fs *FileStore) findByNormalizedURL(normalizedURL, userID, domain string) (string, bool) {
+	for _, record := range fs.data {
+		if record.UserID != userID || record.DeletedFlag || record.Standalone || domainOf(record.ShortURL) != domain {
+			continue
+		}
+		if record.dedupKey() == normalizedURL {
+			return record.ShortURL, true
+		}
+	}
+	return "", false
 }
 
-// BatchSet сохраняет несколько пар originalURL→shortURL и возвращает мапу originalURL→shortURL.
-func (fs *FileStore) BatchSet(_ context.Context, urls map[string]string, userID string) (map[string]string, error) {
+// BatchSet сохраняет несколько пар shortURL→originalURL и возвращает мапу shortURL→originalURL.
+// Если хотя бы один новый shortURL уже занят, ничего не сохраняет и возвращает service.ErrShortURLTaken.
+func (fs *FileStore) BatchSet(_ context.Context, urls []service.URLDTO, userID string) (map[string]string, error) {
 	fs.mu.Lock()
 	defer fs.mu.Unlock()
 
-	file, err := os.OpenFile(fs.filePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
-	if err != nil {
-		return nil, err
-	}
-	defer file.Close()
-
 	shortenedURLs := make(map[string]string)
+	newRecords := make([]URLRecord, 0, len(urls))
+	// accepted — короткие ключи новых ссылок пачки по домену и каноническому URL:
+	// повтор адреса в той же пачке получает ключ первой ссылки, как и в базе данных.
+	accepted := make(map[[2]string]string)
 
-	for shortURL, originalURL := range urls {
-		found := false
-		for _, record := range fs.data {
-			if record.OriginalURL == originalURL {
-				shortenedURLs[originalURL] = record.ShortURL
-				found = true
-				break
+	for _, url := range urls {
+		dedup := [2]string{domainOf(url.ShortURL), url.NormalizedURL}
+		if !url.Standalone {
+			if existing, found := fs.findByNormalizedURL(url.NormalizedURL, userID, dedup[0]); found {
+				shortenedURLs[existing] = url.OriginalURL
+				continue
+			}
+			if existing, found := accepted[dedup]; found {
+				shortenedURLs[existing] = url.OriginalURL
+				continue
 			}
 		}
-		if found {
-			continue
+		if _, taken := fs.data[url.ShortURL]; taken {
+			return nil, service.ErrShortURLTaken
 		}
 
-		newRecord := URLRecord{
-			UUID:        shortURL,
-			ShortURL:    shortURL,
-			OriginalURL: originalURL,
-			UserID:      userID,
-			DeletedFlag: false,
+		newRecords = append(newRecords, newURLRecord(url, userID))
+		shortenedURLs[url.ShortURL] = url.OriginalURL
+		if !url.Standalone {
+			accepted[dedup] = url.ShortURL
 		}
-		fs.data[shortURL] = newRecord
-		shortenedURLs[originalURL] = shortURL
+	}
+
+	file, err := os.OpenFile(fs.filePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	for _, newRecord := range newRecords {
+		fs.data[newRecord.ShortURL] = newRecord
 
 		jsonData, _ := json.Marshal(newRecord)
 		file.Write(jsonData)
@@ -189,10 +482,12 @@ func (fs *FileStore) BatchDelete(_ context.Context, shortURLs []string, userID s
 	fs.mu.Lock()
 	defer fs.mu.Unlock()
 
+	now := time.Now()
 	for _, shortURL := range shortURLs {
 		record, exists := fs.data[shortURL]
-		if exists && record.UserID == userID {
+		if exists && record.UserID == userID && !record.DeletedFlag {
 			record.DeletedFlag = true
+			record.DeletedAt = &now
 			fs.data[shortURL] = record
 		}
 	}
@@ -200,6 +495,59 @@ func (fs *FileStore) BatchDelete(_ context.Context, shortURLs []string, userID s
 	return fs.rewriteFile()
 }
 
+// RestoreURLs снимает пометку удаления со ссылок userID, удалённых позже deletedAfter,
+// перезаписывает файл и возвращает восстановленные shortURLs.
+// Если пользователь успел заново сократить тот же URL, восстановленная ссылка становится отдельной.
+func (fs *FileStore) RestoreURLs(_ context.Context, shortURLs []string, userID string, deletedAfter time.Time) ([]string, error) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	restored := make([]string, 0, len(shortURLs))
+	for _, shortURL := range shortURLs {
+		record, exists := fs.data[shortURL]
+		if !exists || record.UserID != userID || !record.DeletedFlag || !record.DeletedAt.After(deletedAfter) {
+			continue
+		}
+		if _, taken := fs.findByNormalizedURL(record.dedupKey(), userID, domainOf(shortURL)); taken {
+			record.Standalone = true
+		}
+		record.DeletedFlag = false
+		record.DeletedAt = nil
+		fs.data[shortURL] = record
+		restored = append(restored, shortURL)
+	}
+	if len(restored) == 0 {
+		return restored, nil
+	}
+	return restored, fs.rewriteFile()
+}
+
+// PurgeDeletedURLs безвозвратно удаляет ссылки, помеченные удалёнными не позже deletedBefore,
+// и уплотняет основной файл, файл истории и файл переходов, чтобы в них не осталось строк удалённых ссылок.
+func (fs *FileStore) PurgeDeletedURLs(_ context.Context, deletedBefore time.Time) (int, error) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	purged := make(map[string]struct{})
+	for shortURL, record := range fs.data {
+		if record.DeletedFlag && !record.DeletedAt.After(deletedBefore) {
+			delete(fs.data, shortURL)
+			delete(fs.history, shortURL)
+			purged[shortURL] = struct{}{}
+		}
+	}
+	if len(purged) == 0 {
+		return 0, nil
+	}
+	if err := fs.rewriteFile(); err != nil {
+		return 0, err
+	}
+	if err := fs.rewriteHistory(); err != nil {
+		return 0, err
+	}
+	return len(purged), fs.purgeClicks(purged)
+}
+
 // GetStats возвращает количество пользователей и url
 func (fs *FileStore) GetStats(_ context.Context) (int, int, error) {
 	fs.mu.Lock()
@@ -213,3 +561,1001 @@ func (fs *FileStore) GetStats(_ context.Context) (int, int, error) {
 
 	return len(users), urls, nil
 }
+
+// clicksPath возвращает путь к файлу переходов, который хранится рядом с основным файлом.
+func (fs *FileStore) clicksPath() string {
+	return fs.filePath + ".clicks"
+}
+
+// loadClickCounts подсчитывает переходы по каждой ссылке из файла переходов.
+func (fs *FileStore) loadClickCounts() {
+	file, err := os.Open(fs.clicksPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return
+		}
+		log.Panic(err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var record ClickRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err == nil {
+			fs.clickCounts[record.ShortURL]++
+		}
+	}
+}
+
+// SaveClicks дописывает пачку переходов в файл переходов.
+// Файл остаётся единственным источником истины, поэтому агрегаты в файловом режиме
+// не сохраняются отдельно, а только увеличивают счётчики в памяти.
+func (fs *FileStore) SaveClicks(_ context.Context, clicks []service.ClickEvent, rollups []service.ClickRollup) error {
+	fs.clicksMu.Lock()
+	defer fs.clicksMu.Unlock()
+
+	file, err := os.OpenFile(fs.clicksPath(), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	writer := bufio.NewWriter(file)
+	for _, click := range clicks {
+		jsonData, err := json.Marshal(ClickRecord(click))
+		if err != nil {
+			return err
+		}
+		writer.Write(jsonData)
+		writer.WriteString("\n")
+	}
+	if err = writer.Flush(); err != nil {
+		return err
+	}
+
+	for _, r := range rollups {
+		if r.Period == service.ClickPeriodDay {
+			fs.clickCounts[r.ShortURL] += r.Count
+		}
+	}
+	return nil
+}
+
+// purgeClicks удаляет счётчики и строки файла переходов по ссылкам purged.
+// Файл переписывается через временный, чтобы сбой не оставил его обрезанным.
+func (fs *FileStore) purgeClicks(purged map[string]struct{}) error {
+	fs.clicksMu.Lock()
+	defer fs.clicksMu.Unlock()
+
+	for shortURL := range purged {
+		delete(fs.clickCounts, shortURL)
+	}
+
+	src, err := os.Open(fs.clicksPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	defer src.Close()
+
+	tmp := fs.clicksPath() + ".tmp"
+	dst, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	defer dst.Close()
+
+	writer := bufio.NewWriter(dst)
+	scanner := bufio.NewScanner(src)
+	for scanner.Scan() {
+		var record ClickRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err == nil {
+			if _, ok := purged[record.ShortURL]; ok {
+				continue
+			}
+		}
+		writer.Write(scanner.Bytes())
+		writer.WriteString("\n")
+	}
+	if err = scanner.Err(); err != nil {
+		return err
+	}
+	if err = writer.Flush(); err != nil {
+		return err
+	}
+	if err = dst.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp, fs.clicksPath())
+}
+
+// GetTotalClicks возвращает число переходов по всем ссылкам.
+func (fs *FileStore) GetTotalClicks(_ context.Context) (int, error) {
+	fs.clicksMu.Lock()
+	defer fs.clicksMu.Unlock()
+
+	total := 0
+	for _, n := range fs.clickCounts {
+		total += n
+	}
+	return total, nil
+}
+
+// GetClickCount возвращает число переходов по shortURL.
+func (fs *FileStore) GetClickCount(_ context.Context, shortURL string) (int, error) {
+	fs.clicksMu.Lock()
+	defer fs.clicksMu.Unlock()
+
+	return fs.clickCounts[shortURL], nil
+}
+
+// GetClicks читает из файла переходов все события для shortURL.
+func (fs *FileStore) GetClicks(_ context.Context, shortURL string) ([]service.ClickEvent, error) {
+	fs.clicksMu.Lock()
+	defer fs.clicksMu.Unlock()
+
+	file, err := os.Open(fs.clicksPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	var clicks []service.ClickEvent
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var record ClickRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err == nil && record.ShortURL == shortURL {
+			clicks = append(clicks, service.ClickEvent(record))
+		}
+	}
+	return clicks, scanner.Err()
+}
+
+// GetOwner возвращает userID владельца shortURL или service.ErrURLNotFound.
+func (fs *FileStore) GetOwner(_ context.Context, shortURL string) (string, error) {
+	fs.mu.RLock()
+	defer fs.mu.RUnlock()
+
+	record, exists := fs.data[shortURL]
+	if !exists || record.DeletedFlag {
+		return "", service.ErrURLNotFound
+	}
+	return record.UserID, nil
+}
+
+// GetExpiredURLs возвращает просроченные к моменту now и ещё не удалённые ссылки,
+// сгруппированные по userID.
+func (fs *FileStore) GetExpiredURLs(_ context.Context, now time.Time) (map[string][]string, error) {
+	fs.mu.RLock()
+	defer fs.mu.RUnlock()
+
+	expired := make(map[string][]string)
+	for _, record := range fs.data {
+		if record.DeletedFlag || record.ExpiresAt == nil || now.Before(*record.ExpiresAt) {
+			continue
+		}
+		expired[record.UserID] = append(expired[record.UserID], record.ShortURL)
+	}
+	return expired, nil
+}
+
+// historyPath возвращает путь к файлу истории назначений, который хранится рядом с основным файлом.
+func (fs *FileStore) historyPath() string {
+	return fs.filePath + ".history"
+}
+
+// loadHistory восстанавливает прежние назначения ссылок из файла истории.
+func (fs *FileStore) loadHistory() {
+	file, err := os.Open(fs.historyPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return
+		}
+		log.Panic(err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var record HistoryRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err == nil {
+			fs.history[record.ShortURL] = append(fs.history[record.ShortURL], record)
+		}
+	}
+}
+
+// rewriteHistory перезаписывает файл истории назначений содержимым fs.history.
+// Вызывающий код должен удерживать fs.mu.
+func (fs *FileStore) rewriteHistory() error {
+	file, err := os.OpenFile(fs.historyPath(), os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	writer := bufio.NewWriter(file)
+	for _, records := range fs.history {
+		for _, record := range records {
+			jsonData, _ := json.Marshal(record)
+			writer.Write(jsonData)
+			writer.WriteString("\n")
+		}
+	}
+	return writer.Flush()
+}
+
+// UpdateURL заменяет назначение ссылки shortURL пользователя userID.
+// Прежнее назначение дописывается в файл истории, обновлённая запись — в основной файл.
+func (fs *FileStore) UpdateURL(_ context.Context, shortURL, userID string, url service.URLDTO, ifVersion int) (int, error) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	record, exists := fs.data[shortURL]
+	if !exists || record.DeletedFlag || record.UserID != userID {
+		return 0, service.ErrURLNotFound
+	}
+	if ifVersion > 0 && ifVersion != record.version() {
+		return 0, service.ErrVersionMismatch
+	}
+	if existing, found := fs.findByNormalizedURL(url.NormalizedURL, userID, domainOf(shortURL)); found && existing != shortURL && !record.Standalone {
+		return 0, service.ErrConflict
+	}
+
+	previous := HistoryRecord{
+		ShortURL:    shortURL,
+		Version:     record.version(),
+		OriginalURL: record.OriginalURL,
+		ReplacedAt:  time.Now(),
+	}
+	file, err := os.OpenFile(fs.historyPath(), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+	jsonData, err := json.Marshal(previous)
+	if err != nil {
+		return 0, err
+	}
+	if _, err = file.Write(append(jsonData, '\n')); err != nil {
+		return 0, err
+	}
+	fs.history[shortURL] = append(fs.history[shortURL], previous)
+
+	record.OriginalURL = url.OriginalURL
+	record.NormalizedURL = url.NormalizedURL
+	record.Version = previous.Version + 1
+	fs.data[shortURL] = record
+	fs.saveToFile(record)
+
+	return record.Version, nil
+}
+
+// GetURLHistory возвращает текущее и прежние назначения ссылки shortURL пользователя userID.
+func (fs *FileStore) GetURLHistory(_ context.Context, shortURL, userID string) (service.URLHistory, error) {
+	fs.mu.RLock()
+	defer fs.mu.RUnlock()
+
+	record, exists := fs.data[shortURL]
+	if !exists || record.DeletedFlag || record.UserID != userID {
+		return service.URLHistory{}, service.ErrURLNotFound
+	}
+
+	history := service.URLHistory{
+		Current: service.URLVersion{Version: record.version(), OriginalURL: record.OriginalURL},
+	}
+	previous := fs.history[shortURL]
+	for i := len(previous) - 1; i >= 0; i-- {
+		replacedAt := previous[i].ReplacedAt
+		history.Previous = append(history.Previous, service.URLVersion{
+			Version:     previous[i].Version,
+			OriginalURL: previous[i].OriginalURL,
+			ReplacedAt:  &replacedAt,
+		})
+	}
+	return history, nil
+}
+
+// ownedRecord возвращает не удалённую запись shortURL, принадлежащую userID.
+// Вызывающий код должен удерживать fs.mu.
+func (fs *FileStore) ownedRecord(shortURL, userID string) (URLRecord, error) {
+	record, exists := fs.data[shortURL]
+	if !exists || record.DeletedFlag || record.UserID != userID {
+		return URLRecord{}, service.ErrURLNotFound
+	}
+	return record, nil
+}
+
+// GetURLTags возвращает теги ссылки shortURL пользователя userID.
+func (fs *FileStore) GetURLTags(_ context.Context, shortURL, userID string) ([]string, error) {
+	fs.mu.RLock()
+	defer fs.mu.RUnlock()
+
+	record, err := fs.ownedRecord(shortURL, userID)
+	if err != nil {
+		return nil, err
+	}
+	return copyStrings(record.Tags), nil
+}
+
+// SetURLTags заменяет теги ссылки shortURL пользователя userID и дописывает обновлённую запись в файл.
+func (fs *FileStore) SetURLTags(_ context.Context, shortURL, userID string, tags []string) error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	record, err := fs.ownedRecord(shortURL, userID)
+	if err != nil {
+		return err
+	}
+	record.Tags = copyStrings(tags)
+	fs.data[shortURL] = record
+	fs.saveToFile(record)
+	return nil
+}
+
+// GetRedirectRules возвращает правила перенаправления ссылки shortURL пользователя userID.
+func (fs *FileStore) GetRedirectRules(_ context.Context, shortURL, userID string) ([]service.RedirectRule, error) {
+	fs.mu.RLock()
+	defer fs.mu.RUnlock()
+
+	record, err := fs.ownedRecord(shortURL, userID)
+	if err != nil {
+		return nil, err
+	}
+	return copyRules(record.Rules), nil
+}
+
+// SetRedirectRules заменяет правила перенаправления ссылки shortURL пользователя userID
+// и дописывает обновлённую запись в файл.
+func (fs *FileStore) SetRedirectRules(_ context.Context, shortURL, userID string, rules []service.RedirectRule) error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	record, err := fs.ownedRecord(shortURL, userID)
+	if err != nil {
+		return err
+	}
+	record.Rules = copyRules(rules)
+	fs.data[shortURL] = record
+	fs.saveToFile(record)
+	return nil
+}
+
+// GetSplitVariants возвращает варианты A/B-сплита ссылки shortURL пользователя userID.
+func (fs *FileStore) GetSplitVariants(_ context.Context, shortURL, userID string) ([]service.SplitVariant, error) {
+	fs.mu.RLock()
+	defer fs.mu.RUnlock()
+
+	record, err := fs.ownedRecord(shortURL, userID)
+	if err != nil {
+		return nil, err
+	}
+	return copyVariants(record.Variants), nil
+}
+
+// SetSplitVariants заменяет варианты A/B-сплита ссылки shortURL пользователя userID
+// и дописывает обновлённую запись в файл.
+func (fs *FileStore) SetSplitVariants(_ context.Context, shortURL, userID string, variants []service.SplitVariant) error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	record, err := fs.ownedRecord(shortURL, userID)
+	if err != nil {
+		return err
+	}
+	record.Variants = copyVariants(variants)
+	fs.data[shortURL] = record
+	fs.saveToFile(record)
+	return nil
+}
+
+// GetUserTags возвращает теги не удалённых ссылок пользователя userID с числом ссылок для каждого.
+func (fs *FileStore) GetUserTags(_ context.Context, userID string) ([]service.TagCount, error) {
+	fs.mu.RLock()
+	defer fs.mu.RUnlock()
+
+	counts := make(map[string]int)
+	for _, record := range fs.data {
+		if record.UserID != userID || record.DeletedFlag {
+			continue
+		}
+		for _, tag := range record.Tags {
+			counts[tag]++
+		}
+	}
+
+	result := make([]service.TagCount, 0, len(counts))
+	for tag, count := range counts {
+		result = append(result, service.TagCount{Tag: tag, Count: count})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Tag < result[j].Tag })
+	return result, nil
+}
+
+// templatesPath возвращает путь к файлу UTM-шаблонов, который хранится рядом с основным файлом.
+func (fs *FileStore) templatesPath() string {
+	return fs.filePath + ".utm"
+}
+
+// loadTemplates восстанавливает UTM-шаблоны пользователей из файла шаблонов.
+func (fs *FileStore) loadTemplates() {
+	file, err := os.Open(fs.templatesPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return
+		}
+		log.Panic(err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var record UTMTemplateRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err == nil {
+			if fs.templates[record.UserID] == nil {
+				fs.templates[record.UserID] = make(map[string]service.UTM)
+			}
+			fs.templates[record.UserID][record.Name] = record.UTM
+		}
+	}
+}
+
+// rewriteTemplates перезаписывает файл UTM-шаблонов содержимым fs.templates.
+// Вызывающий код должен удерживать fs.mu.
+func (fs *FileStore) rewriteTemplates() error {
+	file, err := os.OpenFile(fs.templatesPath(), os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	writer := bufio.NewWriter(file)
+	for userID, templates := range fs.templates {
+		for name, utm := range templates {
+			jsonData, _ := json.Marshal(UTMTemplateRecord{UserID: userID, Name: name, UTM: utm})
+			writer.Write(jsonData)
+			writer.WriteString("\n")
+		}
+	}
+	return writer.Flush()
+}
+
+// GetUTMTemplates возвращает UTM-шаблоны пользователя userID, отсортированные по имени.
+func (fs *FileStore) GetUTMTemplates(_ context.Context, userID string) ([]service.UTMTemplate, error) {
+	fs.mu.RLock()
+	defer fs.mu.RUnlock()
+
+	result := make([]service.UTMTemplate, 0, len(fs.templates[userID]))
+	for name, utm := range fs.templates[userID] {
+		result = append(result, service.UTMTemplate{Name: name, UTM: utm})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result, nil
+}
+
+// GetUTMTemplate возвращает UTM-шаблон name пользователя userID.
+func (fs *FileStore) GetUTMTemplate(_ context.Context, userID, name string) (service.UTMTemplate, error) {
+	fs.mu.RLock()
+	defer fs.mu.RUnlock()
+
+	utm, ok := fs.templates[userID][name]
+	if !ok {
+		return service.UTMTemplate{}, service.ErrUTMTemplateNotFound
+	}
+	return service.UTMTemplate{Name: name, UTM: utm}, nil
+}
+
+// SaveUTMTemplate создаёт UTM-шаблон пользователя userID или заменяет шаблон с тем же именем.
+func (fs *FileStore) SaveUTMTemplate(_ context.Context, userID string, template service.UTMTemplate) error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	if fs.templates[userID] == nil {
+		fs.templates[userID] = make(map[string]service.UTM)
+	}
+	fs.templates[userID][template.Name] = template.UTM
+	return fs.rewriteTemplates()
+}
+
+// DeleteUTMTemplate удаляет UTM-шаблон name пользователя userID.
+func (fs *FileStore) DeleteUTMTemplate(_ context.Context, userID, name string) error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	if _, ok := fs.templates[userID][name]; !ok {
+		return service.ErrUTMTemplateNotFound
+	}
+	delete(fs.templates[userID], name)
+	return fs.rewriteTemplates()
+}
+
+// workspacesPath возвращает путь к файлу рабочих пространств, который хранится рядом с основным файлом.
+func (fs *FileStore) workspacesPath() string {
+	return fs.filePath + ".workspaces"
+}
+
+// loadWorkspaces восстанавливает рабочие пространства из файла рабочих пространств.
+func (fs *FileStore) loadWorkspaces() {
+	file, err := os.Open(fs.workspacesPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return
+		}
+		log.Panic(err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var record WorkspaceRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err == nil {
+			fs.workspaces[record.ID] = record
+		}
+	}
+}
+
+// rewriteWorkspaces перезаписывает файл рабочих пространств содержимым fs.workspaces.
+// Вызывающий код должен удерживать fs.mu.
+func (fs *FileStore) rewriteWorkspaces() error {
+	file, err := os.OpenFile(fs.workspacesPath(), os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	writer := bufio.NewWriter(file)
+	for _, record := range fs.workspaces {
+		jsonData, _ := json.Marshal(record)
+		writer.Write(jsonData)
+		writer.WriteString("\n")
+	}
+	return writer.Flush()
+}
+
+// CreateWorkspace создаёт рабочее пространство и делает ownerID его владельцем.
+func (fs *FileStore) CreateWorkspace(_ context.Context, workspace service.Workspace, ownerID string) error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	fs.workspaces[workspace.ID] = WorkspaceRecord{
+		ID:        workspace.ID,
+		Name:      workspace.Name,
+		CreatedAt: workspace.CreatedAt,
+		Members:   map[string]string{ownerID: service.RoleOwner},
+	}
+	return fs.rewriteWorkspaces()
+}
+
+// GetWorkspaces возвращает рабочие пространства пользователя userID, отсортированные по названию.
+func (fs *FileStore) GetWorkspaces(_ context.Context, userID string) ([]service.Workspace, error) {
+	fs.mu.RLock()
+	defer fs.mu.RUnlock()
+
+	var result []service.Workspace
+	for _, record := range fs.workspaces {
+		if role, ok := record.Members[userID]; ok {
+			result = append(result, service.Workspace{ID: record.ID, Name: record.Name, Role: role, CreatedAt: record.CreatedAt})
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Name != result[j].Name {
+			return result[i].Name < result[j].Name
+		}
+		return result[i].ID < result[j].ID
+	})
+	return result, nil
+}
+
+// GetWorkspaceRole возвращает роль пользователя userID в рабочем пространстве workspaceID.
+func (fs *FileStore) GetWorkspaceRole(_ context.Context, workspaceID, userID string) (string, error) {
+	fs.mu.RLock()
+	defer fs.mu.RUnlock()
+
+	role, ok := fs.workspaces[workspaceID].Members[userID]
+	if !ok {
+		return "", service.ErrWorkspaceNotFound
+	}
+	return role, nil
+}
+
+// GetWorkspaceMembers возвращает участников рабочего пространства workspaceID, отсортированных по userID.
+func (fs *FileStore) GetWorkspaceMembers(_ context.Context, workspaceID string) ([]service.WorkspaceMember, error) {
+	fs.mu.RLock()
+	defer fs.mu.RUnlock()
+
+	members := fs.workspaces[workspaceID].Members
+	result := make([]service.WorkspaceMember, 0, len(members))
+	for userID, role := range members {
+		result = append(result, service.WorkspaceMember{UserID: userID, Role: role})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].UserID < result[j].UserID })
+	return result, nil
+}
+
+// SetWorkspaceMember добавляет участника рабочего пространства workspaceID или меняет его роль.
+func (fs *FileStore) SetWorkspaceMember(_ context.Context, workspaceID string, member service.WorkspaceMember) error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	record, ok := fs.workspaces[workspaceID]
+	if !ok {
+		return service.ErrWorkspaceNotFound
+	}
+	record.Members[member.UserID] = member.Role
+	return fs.rewriteWorkspaces()
+}
+
+// DeleteWorkspaceMember исключает участника userID из рабочего пространства workspaceID.
+func (fs *FileStore) DeleteWorkspaceMember(_ context.Context, workspaceID, userID string) error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	if _, ok := fs.workspaces[workspaceID].Members[userID]; !ok {
+		return service.ErrWorkspaceNotFound
+	}
+	delete(fs.workspaces[workspaceID].Members, userID)
+	return fs.rewriteWorkspaces()
+}
+
+// apiKeysPath возвращает путь к файлу API-ключей, который хранится рядом с основным файлом.
+func (fs *FileStore) apiKeysPath() string {
+	return fs.filePath + ".apikeys"
+}
+
+// loadAPIKeys восстанавливает API-ключи из файла API-ключей.
+func (fs *FileStore) loadAPIKeys() {
+	file, err := os.Open(fs.apiKeysPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return
+		}
+		log.Panic(err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var record APIKeyRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err == nil {
+			fs.apiKeys[record.Hash] = record
+		}
+	}
+}
+
+// rewriteAPIKeys перезаписывает файл API-ключей содержимым fs.apiKeys.
+// Вызывающий код должен удерживать fs.mu.
+func (fs *FileStore) rewriteAPIKeys() error {
+	file, err := os.OpenFile(fs.apiKeysPath(), os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	writer := bufio.NewWriter(file)
+	for _, record := range fs.apiKeys {
+		jsonData, _ := json.Marshal(record)
+		writer.Write(jsonData)
+		writer.WriteString("\n")
+	}
+	return writer.Flush()
+}
+
+// CreateAPIKey сохраняет API-ключ пользователя userID под хешем hash.
+func (fs *FileStore) CreateAPIKey(_ context.Context, userID string, key service.APIKey, hash string) error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	fs.apiKeys[hash] = APIKeyRecord{UserID: userID, Hash: hash, Key: key}
+	return fs.rewriteAPIKeys()
+}
+
+// GetAPIKeys возвращает API-ключи пользователя userID в порядке выпуска.
+func (fs *FileStore) GetAPIKeys(_ context.Context, userID string) ([]service.APIKey, error) {
+	fs.mu.RLock()
+	defer fs.mu.RUnlock()
+
+	var result []service.APIKey
+	for _, record := range fs.apiKeys {
+		if record.UserID == userID {
+			result = append(result, record.Key)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].CreatedAt.Equal(result[j].CreatedAt) {
+			return result[i].CreatedAt.Before(result[j].CreatedAt)
+		}
+		return result[i].ID < result[j].ID
+	})
+	return result, nil
+}
+
+// GetAPIKeyByHash возвращает API-ключ с хешем hash и его владельца.
+func (fs *FileStore) GetAPIKeyByHash(_ context.Context, hash string) (service.APIKey, string, error) {
+	fs.mu.RLock()
+	defer fs.mu.RUnlock()
+
+	record, ok := fs.apiKeys[hash]
+	if !ok {
+		return service.APIKey{}, "", service.ErrAPIKeyNotFound
+	}
+	return record.Key, record.UserID, nil
+}
+
+// DeleteAPIKey отзывает API-ключ id пользователя userID.
+func (fs *FileStore) DeleteAPIKey(_ context.Context, userID, id string) error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	for hash, record := range fs.apiKeys {
+		if record.UserID == userID && record.Key.ID == id {
+			delete(fs.apiKeys, hash)
+			return fs.rewriteAPIKeys()
+		}
+	}
+	return service.ErrAPIKeyNotFound
+}
+
+// accountsPath возвращает путь к файлу учётных записей, который хранится рядом с основным файлом.
+func (fs *FileStore) accountsPath() string {
+	return fs.filePath + ".accounts"
+}
+
+// loadAccounts восстанавливает учётные записи из файла учётных записей.
+func (fs *FileStore) loadAccounts() {
+	file, err := os.Open(fs.accountsPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return
+		}
+		log.Panic(err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var record AccountRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err == nil {
+			fs.accounts[record.Account.Login] = record
+		}
+	}
+}
+
+// rewriteAccounts перезаписывает файл учётных записей содержимым fs.accounts.
+// Вызывающий код должен удерживать fs.mu.
+func (fs *FileStore) rewriteAccounts() error {
+	file, err := os.OpenFile(fs.accountsPath(), os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	writer := bufio.NewWriter(file)
+	for _, record := range fs.accounts {
+		jsonData, _ := json.Marshal(record)
+		writer.Write(jsonData)
+		writer.WriteString("\n")
+	}
+	return writer.Flush()
+}
+
+// CreateAccount создаёт учётную запись с хешем пароля passwordHash.
+// Если логин уже занят, возвращает service.ErrLoginTaken.
+func (fs *FileStore) CreateAccount(_ context.Context, account service.Account, passwordHash string) error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	if _, ok := fs.accounts[account.Login]; ok {
+		return service.ErrLoginTaken
+	}
+	fs.accounts[account.Login] = AccountRecord{Account: account, PasswordHash: passwordHash}
+	return fs.rewriteAccounts()
+}
+
+// GetAccountByLogin возвращает учётную запись с логином login и хеш её пароля.
+func (fs *FileStore) GetAccountByLogin(_ context.Context, login string) (service.Account, string, error) {
+	fs.mu.RLock()
+	defer fs.mu.RUnlock()
+
+	record, ok := fs.accounts[login]
+	if !ok {
+		return service.Account{}, "", service.ErrAccountNotFound
+	}
+	return record.Account, record.PasswordHash, nil
+}
+
+// GetAccount возвращает учётную запись пользователя userID.
+func (fs *FileStore) GetAccount(_ context.Context, userID string) (service.Account, error) {
+	fs.mu.RLock()
+	defer fs.mu.RUnlock()
+
+	for _, record := range fs.accounts {
+		if record.Account.UserID == userID {
+			return record.Account, nil
+		}
+	}
+	return service.Account{}, service.ErrAccountNotFound
+}
+
+// MergeUserURLs передаёт ссылки пользователя fromUserID пользователю toUserID,
+// кроме ссылок, которые стали бы дубликатами ссылок toUserID на том же домене.
+func (fs *FileStore) MergeUserURLs(_ context.Context, fromUserID, toUserID string) error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	for key, record := range fs.data {
+		if record.UserID != fromUserID {
+			continue
+		}
+		if !record.DeletedFlag && !record.Standalone {
+			if _, taken := fs.findByNormalizedURL(record.dedupKey(), toUserID, domainOf(record.ShortURL)); taken {
+				continue
+			}
+		}
+		record.UserID = toUserID
+		fs.data[key] = record
+	}
+	return fs.rewriteFile()
+}
+
+// revokedPath возвращает путь к файлу отозванных JWT, который хранится рядом с основным файлом.
+func (fs *FileStore) revokedPath() string {
+	return fs.filePath + ".revoked"
+}
+
+// loadRevokedTokens восстанавливает список отозванных JWT, пропуская истёкшие.
+func (fs *FileStore) loadRevokedTokens() {
+	file, err := os.Open(fs.revokedPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return
+		}
+		log.Panic(err)
+	}
+	defer file.Close()
+
+	now := time.Now()
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var record RevokedTokenRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err == nil && record.ExpiresAt.After(now) {
+			fs.revoked[record.ID] = record.ExpiresAt
+		}
+	}
+}
+
+// rewriteRevokedTokens удаляет истёкшие токены из fs.revoked и перезаписывает файл отозванных JWT.
+// Вызывающий код должен удерживать fs.mu.
+func (fs *FileStore) rewriteRevokedTokens() error {
+	file, err := os.OpenFile(fs.revokedPath(), os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	now := time.Now()
+	writer := bufio.NewWriter(file)
+	for id, expiresAt := range fs.revoked {
+		if !expiresAt.After(now) {
+			delete(fs.revoked, id)
+			continue
+		}
+		jsonData, _ := json.Marshal(RevokedTokenRecord{ID: id, ExpiresAt: expiresAt})
+		writer.Write(jsonData)
+		writer.WriteString("\n")
+	}
+	return writer.Flush()
+}
+
+// RevokeToken добавляет JWT id в список отозванных до expiresAt.
+func (fs *FileStore) RevokeToken(_ context.Context, id string, expiresAt time.Time) error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	fs.revoked[id] = expiresAt
+	return fs.rewriteRevokedTokens()
+}
+
+// IsTokenRevoked сообщает, есть ли JWT id в списке отозванных.
+func (fs *FileStore) IsTokenRevoked(_ context.Context, id string) (bool, error) {
+	fs.mu.RLock()
+	defer fs.mu.RUnlock()
+
+	_, ok := fs.revoked[id]
+	return ok, nil
+}
+
+// codeSequenceBlock — число значений счётчика коротких кодов, резервируемых одной записью в файл.
+const codeSequenceBlock = 1000
+
+// sequencePath возвращает путь к файлу счётчика коротких кодов, который хранится рядом с основным файлом.
+func (fs *FileStore) sequencePath() string {
+	return fs.filePath + ".sequence"
+}
+
+// loadCodeSequence продолжает счётчик коротких кодов с сохранённой границы.
+// Без файла счёт начинается с текущего времени в секундах, как у прежнего счётчика в памяти,
+// чтобы не повторять уже выданные им коды.
+func (fs *FileStore) loadCodeSequence() {
+	data, err := os.ReadFile(fs.sequencePath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			fs.sequenceNext = uint64(time.Now().Unix())
+			fs.sequenceLimit = fs.sequenceNext
+			return
+		}
+		log.Panic(err)
+	}
+	limit, err := strconv.ParseUint(strings.TrimSpace(string(data)), 10, 64)
+	if err != nil {
+		log.Panic(err)
+	}
+	fs.sequenceNext, fs.sequenceLimit = limit, limit
+}
+
+// NextCodeSequence возвращает следующее значение счётчика коротких кодов.
+// В файл записывается только граница зарезервированного блока, поэтому после перезапуска
+// счёт продолжается с неё, а невыданный остаток блока пропускается.
+func (fs *FileStore) NextCodeSequence(_ context.Context) (uint64, error) {
+	fs.sequenceMu.Lock()
+	defer fs.sequenceMu.Unlock()
+
+	if fs.sequenceNext >= fs.sequenceLimit {
+		limit := fs.sequenceNext + codeSequenceBlock
+		tmp := fs.sequencePath() + ".tmp"
+		if err := os.WriteFile(tmp, []byte(strconv.FormatUint(limit, 10)+"\n"), 0644); err != nil {
+			return 0, err
+		}
+		if err := os.Rename(tmp, fs.sequencePath()); err != nil {
+			return 0, err
+		}
+		fs.sequenceLimit = limit
+	}
+	fs.sequenceNext++
+	return fs.sequenceNext, nil
+}
+
+// UnnormalizedURLs возвращает до limit ссылок, сохранённых до появления NormalizedURL.
+func (fs *FileStore) UnnormalizedURLs(_ context.Context, limit int) ([]service.URLDTO, error) {
+	fs.mu.RLock()
+	defer fs.mu.RUnlock()
+
+	var urls []service.URLDTO
+	for _, record := range fs.data {
+		if len(urls) == limit {
+			break
+		}
+		if record.NormalizedURL == "" {
+			urls = append(urls, record.toDTO())
+		}
+	}
+	return urls, nil
+}
+
+// SetNormalizedURL сохраняет канонический вид normalizedURL ссылки shortURL и дописывает запись в файл.
+// Если у владельца уже есть действующая ссылка на тот же канонический URL, ссылка становится отдельной.
+func (fs *FileStore) SetNormalizedURL(_ context.Context, shortURL, normalizedURL string) error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	record, exists := fs.data[shortURL]
+	if !exists {
+		return service.ErrURLNotFound
+	}
+	if !record.DeletedFlag && !record.Standalone {
+		for _, other := range fs.data {
+			if other.ShortURL != shortURL && other.UserID == record.UserID && !other.DeletedFlag && !other.Standalone &&
+				domainOf(other.ShortURL) == domainOf(shortURL) && other.dedupKey() == normalizedURL {
+				record.Standalone = true
+				break
+			}
+		}
+	}
+	record.NormalizedURL = normalizedURL
+	fs.data[shortURL] = record
+	fs.saveToFile(record)
+	return nil
+}
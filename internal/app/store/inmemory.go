@@ -1,7 +1,11 @@
 // Package store содержит различные реализации хранилищ для URL.
 package store
 
-import "sync"
+import (
+	"sync"
+
+	"github.com/aseptimu/url-shortener/internal/app/service"
+)
 
 // InMemoryStore хранит URL-пары в памяти,
 // используя мапы для прямого и обратного поиска.
@@ -30,6 +34,7 @@ func (m *InMemoryStore) Get(shortURL string) (string, bool) {
 
 // Set сохраняет пару shortURL→originalURL.
 // Если originalURL уже был сохранён, возвращает существующий короткий URL.
+// Если shortURL занят другой ссылкой, возвращает service.ErrShortURLTaken.
 func (m *InMemoryStore) Set(shortURL, originalURL string) (string, error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -38,6 +43,10 @@ func (m *InMemoryStore) Set(shortURL, originalURL string) (string, error) {
 		return existingShort, nil
 	}
 
+	if _, taken := m.data[shortURL]; taken {
+		return "", service.ErrShortURLTaken
+	}
+
 	m.data[shortURL] = originalURL
 	m.rev[originalURL] = shortURL
 	return shortURL, nil
@@ -0,0 +1,117 @@
+package workers
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/aseptimu/url-shortener/internal/app/service"
+	"go.uber.org/zap"
+)
+
+// ClickSaver описывает пакетное сохранение переходов и их агрегатов.
+type ClickSaver interface {
+	SaveClicks(ctx context.Context, clicks []service.ClickEvent, rollups []service.ClickRollup) error
+}
+
+const (
+	// maxBufferedBatches ограничивает буфер воркера, пока хранилище недоступно:
+	// в нём остаётся не больше maxBufferedBatches*flushSize переходов, самые старые отбрасываются.
+	maxBufferedBatches = 10
+	// maxRetryDelay ограничивает паузу между повторными попытками сохранить буфер.
+	maxRetryDelay = time.Minute
+	// maxShutdownAttempts — сколько раз воркер пытается сохранить остаток буфера при остановке.
+	maxShutdownAttempts = 3
+	// shutdownRetryDelay — пауза между попытками сохранить остаток буфера при остановке.
+	shutdownRetryDelay = time.Second
+)
+
+// retryDelay возвращает паузу перед повторным сохранением после failures неудач подряд:
+// flushInterval, удваивающийся с каждой неудачей, но не больше maxRetryDelay.
+func retryDelay(flushInterval time.Duration, failures int) time.Duration {
+	delay := flushInterval
+	for i := 1; i < failures && delay < maxRetryDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, maxRetryDelay)
+}
+
+// StartClickAggregator запускает пул из numWorkers воркеров, каждый из которых:
+//  1. читает переходы из канала events и копит их в буфере;
+//  2. сбрасывает буфер через saver.SaveClicks вместе с почасовыми и суточными
+//     агрегатами каждые flushInterval или при накоплении flushSize событий;
+//     если сохранить не удалось, буфер не очищается, а следующая попытка откладывается
+//     с экспоненциально растущей паузой; пока хранилище недоступно, буфер ограничен
+//     maxBufferedBatches*flushSize переходами и самые старые из них отбрасываются;
+//  3. после закрытия канала events сбрасывает остаток буфера и завершается.
+//
+// Канал закрывается вызывающим кодом после остановки серверов, чтобы не потерять
+// переходы из запросов, завершающихся во время graceful shutdown.
+// Возвращённый WaitGroup позволяет дождаться последнего сброса.
+func StartClickAggregator(numWorkers int, events <-chan service.ClickEvent, saver ClickSaver, flushInterval time.Duration, flushSize int, logger *zap.SugaredLogger) *sync.WaitGroup {
+	maxBuffered := maxBufferedBatches * flushSize
+	var wg sync.WaitGroup
+	for i := 0; i < numWorkers; i++ {
+		wg.Add(1)
+		go func(workerID int) {
+			defer wg.Done()
+
+			ticker := time.NewTicker(flushInterval)
+			defer ticker.Stop()
+
+			buffer := make([]service.ClickEvent, 0, flushSize)
+			failures := 0
+			// retryAt — момент, раньше которого после неудачного сохранения буфер не сбрасывается.
+			var retryAt time.Time
+			flush := func() bool {
+				if len(buffer) == 0 {
+					return true
+				}
+				if err := saver.SaveClicks(context.Background(), buffer, service.RollupClicks(buffer)); err != nil {
+					failures++
+					delay := retryDelay(flushInterval, failures)
+					retryAt = time.Now().Add(delay)
+					logger.Warnw("Click aggregator failed to save clicks, will retry", "workerID", workerID, "count", len(buffer), "attempt", failures, "retryIn", delay, "error", err)
+					return false
+				}
+				logger.Debugw("Click aggregator saved clicks", "workerID", workerID, "count", len(buffer))
+				failures = 0
+				retryAt = time.Time{}
+				buffer = make([]service.ClickEvent, 0, flushSize)
+				return true
+			}
+
+			logger.Infow("Click aggregator worker started", "workerID", workerID)
+			for {
+				select {
+				case click, ok := <-events:
+					if !ok {
+						for attempt := 1; !flush(); attempt++ {
+							if attempt == maxShutdownAttempts {
+								logger.Errorw("Click aggregator dropped clicks on shutdown after repeated failures", "workerID", workerID, "count", len(buffer))
+								break
+							}
+							time.Sleep(shutdownRetryDelay)
+						}
+						logger.Infow("Click aggregator worker stopping", "workerID", workerID)
+						return
+					}
+					buffer = append(buffer, click)
+					if len(buffer) > maxBuffered {
+						dropped := len(buffer) - maxBuffered
+						buffer = append(buffer[:0], buffer[dropped:]...)
+						logger.Errorw("Click aggregator buffer is full, dropped oldest clicks", "workerID", workerID, "count", dropped)
+					}
+					if len(buffer) >= flushSize && !time.Now().Before(retryAt) {
+						flush()
+					}
+				case <-ticker.C:
+					if !time.Now().Before(retryAt) {
+						flush()
+					}
+				}
+			}
+		}(i)
+	}
+	return &wg
+}
@@ -12,11 +12,14 @@ import (
 // StartDeleteWorkerPool запускает пул из numWorkers воркеров, каждый из которых:
 //  1. слушает контекст ctx на завершение работы;
 //  2. читает задачи удаления URL из канала shortenurlhandlers.DeleteTaskCh;
-//  3. при получении задачи вызывает deleter.DeleteURLs для пакетного удаления;
-//  4. логирует успешное или ошибочное выполнение.
+//  3. если задачу поставил пользователь (MemberID), заново проверяет через workspaces,
+//     что он всё ещё может редактировать ссылки владельца UserID: права могли отозвать,
+//     пока задача ждала в очереди;
+//  4. вызывает deleter.DeleteURLs для пакетного удаления;
+//  5. логирует успешное или ошибочное выполнение.
 //
 // workerID используется в логах для идентификации конкретного воркера.
-func StartDeleteWorkerPool(ctx context.Context, numWorkers int, deleter service.URLDeleter, logger *zap.SugaredLogger) {
+func StartDeleteWorkerPool(ctx context.Context, numWorkers int, deleter service.URLDeleter, workspaces service.WorkspaceAuthorizer, logger *zap.SugaredLogger) {
 	for i := 0; i < numWorkers; i++ {
 		go func(workerID int) {
 			logger.Infow("Delete worker started", "workerID", workerID)
@@ -26,6 +29,14 @@ func StartDeleteWorkerPool(ctx context.Context, numWorkers int, deleter service.
 					logger.Infow("Delete worker stopping", "workerID", workerID)
 					return
 				case task := <-shortenurlhandlers.DeleteTaskCh:
+					if task.MemberID != "" {
+						owner, err := workspaces.Authorize(context.Background(), task.MemberID, task.UserID, service.RoleEditor)
+						if err != nil || owner != task.UserID {
+							logger.Warnw("Worker dropped delete task of unauthorized member", "workerID", workerID,
+								"memberID", task.MemberID, "owner", task.UserID, "error", err)
+							continue
+						}
+					}
 					if err := deleter.DeleteURLs(context.Background(), task.URLs, task.UserID); err != nil {
 						logger.Errorw("Worker failed to delete URLs", "workerID", workerID, "error", err)
 					} else {
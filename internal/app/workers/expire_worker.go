@@ -0,0 +1,51 @@
+// Package workers содержит фоновые рабочие горутины для обработки задач удаления URL.
+package workers
+
+import (
+	"context"
+	"time"
+
+	"github.com/aseptimu/url-shortener/internal/app/handlers/http/shortenurlhandlers"
+	"go.uber.org/zap"
+)
+
+// ExpiredURLFinder описывает поиск просроченных ссылок, сгруппированных по userID.
+type ExpiredURLFinder interface {
+	GetExpiredURLs(ctx context.Context) (map[string][]string, error)
+}
+
+// StartExpiryReaper запускает горутину, которая каждые interval:
+//  1. ищет просроченные, но ещё не удалённые ссылки через finder;
+//  2. для каждого владельца отправляет DeleteTask в shortenurlhandlers.DeleteTaskCh,
+//     так что мягкое удаление выполняют те же воркеры, что и DELETE /api/user/urls.
+//
+// Горутина завершается при отмене ctx.
+func StartExpiryReaper(ctx context.Context, interval time.Duration, finder ExpiredURLFinder, logger *zap.SugaredLogger) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		logger.Infow("Expiry reaper started", "interval", interval)
+		for {
+			select {
+			case <-ctx.Done():
+				logger.Infow("Expiry reaper stopping")
+				return
+			case <-ticker.C:
+				expired, err := finder.GetExpiredURLs(ctx)
+				if err != nil {
+					logger.Errorw("Expiry reaper failed to find expired URLs", "error", err)
+					continue
+				}
+				for userID, urls := range expired {
+					select {
+					case shortenurlhandlers.DeleteTaskCh <- shortenurlhandlers.DeleteTask{URLs: urls, UserID: userID}:
+						logger.Debugw("Expiry reaper queued expired URLs", "userID", userID, "count", len(urls))
+					case <-ctx.Done():
+						return
+					}
+				}
+			}
+		}
+	}()
+}
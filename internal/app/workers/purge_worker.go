@@ -0,0 +1,42 @@
+package workers
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// DeletedURLPurger описывает окончательное удаление ссылок с истёкшим льготным периодом.
+type DeletedURLPurger interface {
+	PurgeDeletedURLs(ctx context.Context) (int, error)
+}
+
+// StartRetentionPurger запускает горутину, которая каждые interval безвозвратно удаляет
+// через purger ссылки, удалённые раньше льготного периода восстановления.
+//
+// Горутина завершается при отмене ctx.
+func StartRetentionPurger(ctx context.Context, interval time.Duration, purger DeletedURLPurger, logger *zap.SugaredLogger) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		logger.Infow("Retention purger started", "interval", interval)
+		for {
+			select {
+			case <-ctx.Done():
+				logger.Infow("Retention purger stopping")
+				return
+			case <-ticker.C:
+				purged, err := purger.PurgeDeletedURLs(ctx)
+				if err != nil {
+					logger.Errorw("Retention purger failed to purge deleted URLs", "error", err)
+					continue
+				}
+				if purged > 0 {
+					logger.Infow("Retention purger removed deleted URLs", "count", purged)
+				}
+			}
+		}
+	}()
+}
@@ -0,0 +1,2 @@
+ALTER TABLE urls
+ADD COLUMN IF NOT EXISTS remaining_uses INTEGER CHECK (remaining_uses >= 0);
@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS click_rollups (
+    short_url TEXT NOT NULL REFERENCES urls (short_url) ON DELETE CASCADE,
+    period TEXT NOT NULL CHECK (period IN ('hour', 'day')),
+    bucket_start TIMESTAMPTZ NOT NULL,
+    clicks BIGINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (short_url, period, bucket_start)
+);
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS urls_normalized_url_key;
+
+ALTER TABLE urls
+ADD CONSTRAINT urls_original_url_key UNIQUE (original_url);
+
+ALTER TABLE urls
+DROP COLUMN normalized_url;
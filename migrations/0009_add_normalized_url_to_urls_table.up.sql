@@ -0,0 +1,13 @@
+ALTER TABLE urls
+ADD COLUMN IF NOT EXISTS normalized_url TEXT;
+
+UPDATE urls SET normalized_url = original_url WHERE normalized_url IS NULL;
+
+ALTER TABLE urls
+ALTER COLUMN normalized_url SET NOT NULL;
+
+-- Дубликаты теперь ищутся по каноническому URL, а original_url хранит ввод пользователя как есть.
+ALTER TABLE urls
+DROP CONSTRAINT IF EXISTS urls_original_url_key;
+
+CREATE UNIQUE INDEX IF NOT EXISTS urls_normalized_url_key ON urls (normalized_url);
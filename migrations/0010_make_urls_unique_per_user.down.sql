@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS urls_user_id_normalized_url_key;
+
+CREATE UNIQUE INDEX IF NOT EXISTS urls_normalized_url_key ON urls (normalized_url);
@@ -0,0 +1,5 @@
+-- Одинаковый URL могут сократить разные пользователи, каждый получает собственную ссылку.
+-- COALESCE нужен, чтобы ссылки без владельца тоже не дублировались.
+DROP INDEX IF EXISTS urls_normalized_url_key;
+
+CREATE UNIQUE INDEX IF NOT EXISTS urls_user_id_normalized_url_key ON urls ((COALESCE(user_id, '')), normalized_url);
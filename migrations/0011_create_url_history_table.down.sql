@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS url_history;
+
+ALTER TABLE urls
+DROP COLUMN IF EXISTS version;
@@ -0,0 +1,10 @@
+ALTER TABLE urls
+ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
+
+CREATE TABLE IF NOT EXISTS url_history (
+    short_url TEXT NOT NULL REFERENCES urls (short_url) ON DELETE CASCADE,
+    version INTEGER NOT NULL,
+    original_url TEXT NOT NULL,
+    replaced_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (short_url, version)
+);
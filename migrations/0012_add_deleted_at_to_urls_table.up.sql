@@ -0,0 +1,7 @@
+ALTER TABLE urls
+ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+
+-- Ссылки, удалённые до появления колонки, получают полный льготный период с момента миграции.
+UPDATE urls SET deleted_at = now() WHERE is_deleted = TRUE AND deleted_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS urls_deleted_at_idx ON urls (deleted_at) WHERE is_deleted = TRUE;
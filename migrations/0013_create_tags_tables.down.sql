@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS url_tags;
+
+DROP TABLE IF EXISTS tags;
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS urls_original_url_trgm_idx;
+
+DROP INDEX IF EXISTS urls_user_id_created_at_idx;
+
+ALTER TABLE urls
+DROP COLUMN IF EXISTS created_at;
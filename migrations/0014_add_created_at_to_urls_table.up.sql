@@ -0,0 +1,19 @@
+ALTER TABLE urls
+ADD COLUMN IF NOT EXISTS created_at TIMESTAMPTZ NOT NULL DEFAULT now();
+
+-- Постраничный вывод ссылок пользователя по времени создания.
+CREATE INDEX IF NOT EXISTS urls_user_id_created_at_idx ON urls (user_id, created_at, short_url);
+
+-- Поиск по подстроке оригинального URL ускоряет триграммный индекс. Для CREATE EXTENSION нужны
+-- права суперпользователя или владельца базы; если их нет или pg_trgm не установлен на сервере,
+-- миграция продолжается без индекса, и поиск выполняется через ILIKE полным просмотром ссылок пользователя.
+-- Как создать индекс позже, описано в README.
+DO $$
+BEGIN
+    CREATE EXTENSION IF NOT EXISTS pg_trgm;
+    CREATE INDEX IF NOT EXISTS urls_original_url_trgm_idx ON urls USING gin (original_url gin_trgm_ops);
+EXCEPTION
+    WHEN insufficient_privilege OR undefined_file OR feature_not_supported THEN
+        RAISE NOTICE 'pg_trgm is unavailable, URL search will run without a trigram index: %', SQLERRM;
+END
+$$;
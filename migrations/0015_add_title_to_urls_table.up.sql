@@ -0,0 +1,2 @@
+ALTER TABLE urls
+ADD COLUMN IF NOT EXISTS title TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,4 @@
+ALTER TABLE urls
+DROP COLUMN IF EXISTS redirect_code,
+DROP COLUMN IF EXISTS pass_query,
+DROP COLUMN IF EXISTS pass_path;
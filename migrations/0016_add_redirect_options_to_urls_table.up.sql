@@ -0,0 +1,4 @@
+ALTER TABLE urls
+ADD COLUMN IF NOT EXISTS redirect_code SMALLINT NOT NULL DEFAULT 307,
+ADD COLUMN IF NOT EXISTS pass_query BOOLEAN NOT NULL DEFAULT FALSE,
+ADD COLUMN IF NOT EXISTS pass_path BOOLEAN NOT NULL DEFAULT FALSE;
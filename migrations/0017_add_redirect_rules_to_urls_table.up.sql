@@ -0,0 +1,3 @@
+-- Правила перенаправления ссылки в порядке проверки: [{"match": {...}, "url": "..."}].
+ALTER TABLE urls
+ADD COLUMN IF NOT EXISTS redirect_rules JSONB NOT NULL DEFAULT '[]';
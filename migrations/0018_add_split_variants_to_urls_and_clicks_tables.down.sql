@@ -0,0 +1,5 @@
+ALTER TABLE clicks
+DROP COLUMN IF EXISTS variant;
+
+ALTER TABLE urls
+DROP COLUMN IF EXISTS split_variants;
@@ -0,0 +1,7 @@
+-- Варианты A/B-сплита ссылки: [{"name": "...", "url": "...", "weight": 1}].
+ALTER TABLE urls
+ADD COLUMN IF NOT EXISTS split_variants JSONB NOT NULL DEFAULT '[]';
+
+-- Вариант, выданный при переходе; пусто для ссылок без сплита.
+ALTER TABLE clicks
+ADD COLUMN IF NOT EXISTS variant TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS utm_templates (
+    user_id TEXT NOT NULL,
+    name TEXT NOT NULL,
+    utm_source TEXT NOT NULL DEFAULT '',
+    utm_medium TEXT NOT NULL DEFAULT '',
+    utm_campaign TEXT NOT NULL DEFAULT '',
+    utm_term TEXT NOT NULL DEFAULT '',
+    utm_content TEXT NOT NULL DEFAULT '',
+    PRIMARY KEY (user_id, name)
+);
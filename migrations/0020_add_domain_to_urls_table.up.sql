@@ -0,0 +1,10 @@
+-- Короткий домен ссылки; пустая строка — основной домен.
+-- Ссылки дополнительных доменов хранятся под ключом "домен/код" (см. service.LinkKey),
+-- поэтому уникальность short_url означает уникальность пары (domain, код).
+ALTER TABLE urls
+ADD COLUMN IF NOT EXISTS domain TEXT NOT NULL DEFAULT '';
+
+-- Один и тот же URL можно сократить на каждом домене отдельно.
+DROP INDEX IF EXISTS urls_user_id_normalized_url_key;
+
+CREATE UNIQUE INDEX IF NOT EXISTS urls_user_id_domain_normalized_url_key ON urls ((COALESCE(user_id, '')), domain, normalized_url);
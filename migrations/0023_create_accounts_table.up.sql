@@ -0,0 +1,7 @@
+-- user_id совпадает с идентификатором пользователя в JWT и в колонке urls.user_id.
+CREATE TABLE IF NOT EXISTS accounts (
+    user_id TEXT PRIMARY KEY,
+    login TEXT NOT NULL UNIQUE,
+    password_hash TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
@@ -0,0 +1,7 @@
+-- Отозванные при выходе JWT; строка не нужна после expires_at, когда токен истекает сам.
+CREATE TABLE IF NOT EXISTS revoked_tokens (
+    id TEXT PRIMARY KEY,
+    expires_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS revoked_tokens_expires_at_idx ON revoked_tokens (expires_at);
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS urls_user_id_domain_normalized_url_key;
+
+CREATE UNIQUE INDEX IF NOT EXISTS urls_user_id_domain_normalized_url_key ON urls ((COALESCE(user_id, '')), domain, normalized_url);
+
+ALTER TABLE urls
+DROP COLUMN IF EXISTS standalone;
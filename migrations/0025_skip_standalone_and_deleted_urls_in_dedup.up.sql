@@ -0,0 +1,13 @@
+-- Ссылки с алиасом, паролем, лимитом переходов или сроком жизни создаются отдельно
+-- и не участвуют в поиске дубликатов.
+ALTER TABLE urls
+ADD COLUMN IF NOT EXISTS standalone BOOLEAN NOT NULL DEFAULT FALSE;
+
+UPDATE urls SET standalone = TRUE
+WHERE password_hash IS NOT NULL OR remaining_uses IS NOT NULL OR expires_at IS NOT NULL;
+
+-- Удалённые и отдельные ссылки не мешают сократить тот же URL заново.
+DROP INDEX IF EXISTS urls_user_id_domain_normalized_url_key;
+
+CREATE UNIQUE INDEX IF NOT EXISTS urls_user_id_domain_normalized_url_key ON urls ((COALESCE(user_id, '')), domain, normalized_url)
+WHERE is_deleted = FALSE AND standalone = FALSE;
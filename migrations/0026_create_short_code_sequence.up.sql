@@ -0,0 +1,6 @@
+-- Счётчик стратегии коротких кодов sequence, общий для всех экземпляров сервиса.
+-- Прежний счётчик в памяти начинался с текущего времени в секундах, поэтому новый
+-- начинается с него же, чтобы не повторять уже выданные коды.
+CREATE SEQUENCE IF NOT EXISTS short_code_seq;
+
+SELECT setval('short_code_seq', GREATEST(extract(epoch FROM now())::BIGINT, 1));
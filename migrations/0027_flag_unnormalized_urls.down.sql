@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS urls_needs_normalization_idx;
+
+ALTER TABLE urls
+DROP COLUMN IF EXISTS needs_normalization;
@@ -0,0 +1,8 @@
+-- Миграция 0009 заполнила normalized_url значением original_url. Такие ссылки помечаются,
+-- и сервис при запуске пересчитывает их канонический вид тем же нормализатором, что и для новых.
+ALTER TABLE urls
+ADD COLUMN IF NOT EXISTS needs_normalization BOOLEAN NOT NULL DEFAULT FALSE;
+
+UPDATE urls SET needs_normalization = TRUE WHERE normalized_url = original_url;
+
+CREATE INDEX IF NOT EXISTS urls_needs_normalization_idx ON urls (short_url) WHERE needs_normalization;
@@ -0,0 +1,8 @@
+ALTER TABLE urls
+DROP CONSTRAINT IF EXISTS urls_domain_code_key;
+
+ALTER TABLE urls
+DROP CONSTRAINT IF EXISTS urls_domain_matches_short_url;
+
+ALTER TABLE urls
+DROP COLUMN IF EXISTS code;
@@ -0,0 +1,23 @@
+-- Ссылки дополнительных доменов хранятся под ключом "домен/код" (см. service.LinkKey).
+-- Колонка code выделяет из ключа код, а проверка не даёт колонке domain разойтись с ключом,
+-- поэтому пара (domain, code) уникальна явно, а не только через кодировку short_url.
+
+-- Строки, у которых domain разошёлся с ключом, исправляются и выводятся из поиска дубликатов,
+-- чтобы исправление не нарушило уникальность канонических URL.
+UPDATE urls
+SET domain = CASE WHEN strpos(short_url, '/') > 0 THEN split_part(short_url, '/', 1) ELSE '' END,
+    standalone = TRUE
+WHERE domain <> CASE WHEN strpos(short_url, '/') > 0 THEN split_part(short_url, '/', 1) ELSE '' END;
+
+ALTER TABLE urls
+ADD COLUMN IF NOT EXISTS code TEXT GENERATED ALWAYS AS (
+    CASE WHEN strpos(short_url, '/') > 0 THEN substr(short_url, strpos(short_url, '/') + 1) ELSE short_url END
+) STORED;
+
+ALTER TABLE urls
+ADD CONSTRAINT urls_domain_matches_short_url CHECK (
+    domain = CASE WHEN strpos(short_url, '/') > 0 THEN split_part(short_url, '/', 1) ELSE '' END
+);
+
+ALTER TABLE urls
+ADD CONSTRAINT urls_domain_code_key UNIQUE (domain, code);